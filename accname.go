@@ -0,0 +1,258 @@
+package w3pilot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ariaRoleFunction defines ariaRole(el), which returns the element's
+// explicit role or its implicit role from HTML-AAM, or "" if it has none.
+// Snapshot and the accname functions share it so that they agree on roles.
+const ariaRoleFunction = `
+const ariaInputRoles = {button: 'button', submit: 'button', reset: 'button', image: 'button',
+	checkbox: 'checkbox', radio: 'radio', range: 'slider', number: 'spinbutton', search: 'searchbox'};
+const ariaLandmarkTags = {nav: 'navigation', main: 'main', aside: 'complementary', form: 'form',
+	dialog: 'dialog', search: 'search'};
+
+function ariaRole(el) {
+	const explicit = (el.getAttribute('role') || '').trim().split(/\s+/)[0];
+	if (explicit) return explicit;
+	const tag = el.tagName.toLowerCase();
+	if (tag === 'a' || tag === 'area') return el.hasAttribute('href') ? 'link' : '';
+	if (tag === 'button' || tag === 'summary') return 'button';
+	if (tag === 'input') {
+		const type = (el.getAttribute('type') || 'text').toLowerCase();
+		if (type === 'hidden') return '';
+		return ariaInputRoles[type] || 'textbox';
+	}
+	if (tag === 'textarea') return 'textbox';
+	if (tag === 'select') return el.multiple || el.size > 1 ? 'listbox' : 'combobox';
+	if (tag === 'option') return 'option';
+	if (/^h[1-6]$/.test(tag)) return 'heading';
+	if (tag === 'td') return 'cell';
+	if (tag === 'th') return 'columnheader';
+	if (tag === 'tr') return 'row';
+	const sectioned = el.closest && el.closest('article, aside, main, nav, section');
+	if (tag === 'header' && !sectioned) return 'banner';
+	if (tag === 'footer' && !sectioned) return 'contentinfo';
+	if (tag === 'section' && (el.hasAttribute('aria-label') || el.hasAttribute('aria-labelledby'))) return 'region';
+	if (ariaLandmarkTags[tag]) return ariaLandmarkTags[tag];
+	if (el.isContentEditable && !(el.parentElement && el.parentElement.isContentEditable)) return 'textbox';
+	return '';
+}`
+
+// accessibleNameFunction defines accessibleName(el) and
+// accessibleDescription(el), which follow the W3C Accessible Name and
+// Description Computation (accname 1.2) and HTML-AAM as screen readers and
+// axe apply them: aria-labelledby, aria-label, native labels (label, alt,
+// legend, caption, button values), name from content for roles that allow
+// it, and title and placeholder as fallbacks. Hidden nodes are skipped
+// unless referenced directly by aria-labelledby or aria-describedby.
+const accessibleNameFunction = ariaRoleFunction + `
+const accnameContentRoles = new Set(['button', 'cell', 'checkbox', 'columnheader', 'gridcell', 'heading',
+	'link', 'menuitem', 'menuitemcheckbox', 'menuitemradio', 'option', 'radio', 'row', 'rowheader',
+	'switch', 'tab', 'tooltip', 'treeitem']);
+
+function accnameHidden(el) {
+	if (el.hidden || el.getAttribute('aria-hidden') === 'true') return true;
+	const style = getComputedStyle(el);
+	return style.display === 'none' || style.visibility === 'hidden';
+}
+
+function accnameClean(s) {
+	return (s || '').replace(/\s+/g, ' ').trim();
+}
+
+function accnameRefs(el, attr) {
+	return (el.getAttribute(attr) || '').split(/\s+/).filter(Boolean)
+		.map(id => el.ownerDocument.getElementById(id)).filter(Boolean);
+}
+
+function accnameText(node, state) {
+	if (node.nodeType === Node.TEXT_NODE) return node.textContent;
+	if (node.nodeType !== Node.ELEMENT_NODE || state.visited.has(node)) return '';
+	state.visited.add(node);
+	const el = node;
+	const referenced = state.referenced === el;
+	if (!referenced && state.root !== el && accnameHidden(el)) return '';
+
+	const tag = el.tagName.toLowerCase();
+	const role = ariaRole(el);
+
+	// aria-labelledby, unless already following a labelledby reference
+	if (!state.inLabelledBy) {
+		const refs = accnameRefs(el, 'aria-labelledby');
+		if (refs.length) {
+			const text = refs.map(ref => accnameText(ref, {
+				visited: state.visited, inLabelledBy: true, inContent: true, referenced: ref,
+			})).join(' ');
+			if (accnameClean(text)) return text;
+		}
+	}
+
+	// Embedded controls contribute their value to an enclosing name
+	const embedded = state.inContent && !referenced;
+	if (embedded && (role === 'textbox' || role === 'searchbox')) {
+		return tag === 'input' || tag === 'textarea' ? el.value : el.textContent;
+	}
+	if (embedded && (role === 'combobox' || role === 'listbox')) {
+		if (tag === 'select') return Array.from(el.selectedOptions, o => o.textContent).join(' ');
+		return Array.from(el.querySelectorAll('[aria-selected="true"]'), o => o.textContent).join(' ');
+	}
+	if (embedded && ['slider', 'spinbutton', 'progressbar', 'scrollbar'].includes(role)) {
+		return el.getAttribute('aria-valuetext') || el.getAttribute('aria-valuenow') || el.value || '';
+	}
+
+	const ariaLabel = accnameClean(el.getAttribute('aria-label'));
+	if (ariaLabel) return ariaLabel;
+
+	// Native labeling from the host language
+	const type = (el.getAttribute('type') || '').toLowerCase();
+	const labeled = ['input', 'textarea', 'select', 'button', 'meter', 'output', 'progress'].includes(tag);
+	if (labeled && type !== 'hidden' && el.labels && el.labels.length) {
+		const text = Array.from(el.labels, label => accnameText(label, {
+			visited: state.visited, inLabelledBy: state.inLabelledBy, inContent: true,
+		})).join(' ');
+		if (accnameClean(text)) return text;
+	}
+	if (tag === 'input') {
+		if (type === 'button' || type === 'submit' || type === 'reset') {
+			if (el.value) return el.value;
+			if (type === 'submit') return 'Submit';
+			if (type === 'reset') return 'Reset';
+		}
+		if (type === 'image') {
+			return el.getAttribute('alt') || el.value || el.getAttribute('title') || 'Submit';
+		}
+	}
+	if (tag === 'img' || tag === 'area' || (tag === 'input' && type === 'image')) {
+		const alt = el.getAttribute('alt');
+		if (alt) return alt;
+	}
+	const caption = {fieldset: 'legend', figure: 'figcaption', table: 'caption'}[tag];
+	if (caption) {
+		const child = Array.from(el.children).find(c => c.tagName.toLowerCase() === caption);
+		if (child) {
+			const text = accnameText(child, {visited: state.visited, inLabelledBy: state.inLabelledBy, inContent: true});
+			if (accnameClean(text)) return text;
+		}
+	}
+
+	// Name from content
+	if (accnameContentRoles.has(role) || state.inContent || referenced) {
+		const text = accnameContent(el, state);
+		if (accnameClean(text)) return text;
+	}
+
+	// Tooltip and placeholder fallbacks
+	const title = el.getAttribute('title');
+	if (title) return title;
+	if (tag === 'input' || tag === 'textarea') {
+		return el.getAttribute('placeholder') || el.getAttribute('aria-placeholder') || '';
+	}
+	return el.getAttribute('aria-placeholder') || '';
+}
+
+function accnameContent(el, state) {
+	const child = {visited: state.visited, inLabelledBy: state.inLabelledBy, inContent: true};
+	const pseudo = which => {
+		const content = getComputedStyle(el, which).content;
+		return content && content !== 'none' && content !== 'normal' && /^".*"$/.test(content) ? content.slice(1, -1) : '';
+	};
+	const parts = [pseudo('::before')];
+	const nodes = el.shadowRoot ? el.shadowRoot.childNodes : el.childNodes;
+	for (const node of nodes) {
+		const text = accnameText(node, child);
+		const block = node.nodeType === Node.ELEMENT_NODE && !/^inline/.test(getComputedStyle(node).display);
+		parts.push(block ? ' ' + text + ' ' : text);
+	}
+	parts.push(pseudo('::after'));
+	return parts.join('');
+}
+
+function accessibleName(el) {
+	return accnameClean(accnameText(el, {visited: new Set(), inLabelledBy: false, inContent: false, root: el}));
+}
+
+function accessibleDescription(el) {
+	const refs = accnameRefs(el, 'aria-describedby');
+	if (refs.length) {
+		const text = accnameClean(refs.map(ref => accnameText(ref, {
+			visited: new Set(), inLabelledBy: true, inContent: true, referenced: ref,
+		})).join(' '));
+		if (text) return text;
+	}
+	const description = accnameClean(el.getAttribute('aria-description'));
+	if (description) return description;
+	const title = accnameClean(el.getAttribute('title'));
+	return title && title !== accessibleName(el) ? title : '';
+}`
+
+// AccessibleName returns the element's accessible name as computed by the
+// accname algorithm that screen readers and axe use. Unlike Label, it
+// considers aria-labelledby, aria-label, native labels, alt text, text
+// content, and title and placeholder fallbacks, in that order, which helps
+// explain why a role or label selector missed.
+func (e *Element) AccessibleName(ctx context.Context) (string, error) {
+	return e.accname(ctx, "accessibleName")
+}
+
+// AccessibleDescription returns the element's accessible description,
+// computed from aria-describedby, aria-description, or a title that is
+// not already used as the name.
+func (e *Element) AccessibleDescription(ctx context.Context) (string, error) {
+	return e.accname(ctx, "accessibleDescription")
+}
+
+// accname evaluates fn, accessibleName or accessibleDescription, on the element.
+func (e *Element) accname(ctx context.Context, fn string) (string, error) {
+	result, err := e.Eval(ctx, "(el) => {"+accessibleNameFunction+"\n\treturn "+fn+"(el);\n}")
+	if err != nil {
+		return "", fmt.Errorf("failed to compute %s: %w", fn, err)
+	}
+	s, _ := result.(string)
+	return s, nil
+}
+
+// elementRolesScript returns the ariaRole of the element matching each
+// selector as a JSON array, with "" for selectors that match nothing.
+const elementRolesScript = `(selectors) => {` + ariaRoleFunction + `
+
+	return JSON.stringify(selectors.map(selector => {
+		let el = null;
+		try {
+			el = document.querySelector(selector);
+		} catch (e) {}
+		return el ? ariaRole(el) : '';
+	}));
+}`
+
+// ElementRoles returns the ARIA role of each element, explicit or implicit,
+// in a single script evaluation instead of one command per element. An
+// element that is no longer in the page has the role "".
+func (p *Pilot) ElementRoles(ctx context.Context, elements []*Element) ([]string, error) {
+	if len(elements) == 0 {
+		return nil, nil
+	}
+
+	selectors := make([]interface{}, len(elements))
+	for i, elem := range elements {
+		selectors[i] = elem.Selector()
+	}
+
+	result, err := p.EvaluateWithArgs(ctx, elementRolesScript, selectors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute roles: %w", err)
+	}
+	s, _ := result.(string)
+
+	var roles []string
+	if err := json.Unmarshal([]byte(s), &roles); err != nil {
+		return nil, fmt.Errorf("failed to parse roles: %w", err)
+	}
+	if len(roles) != len(elements) {
+		return nil, fmt.Errorf("expected %d roles, got %d", len(elements), len(roles))
+	}
+	return roles, nil
+}
@@ -0,0 +1,73 @@
+package w3pilot
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestElementAccessibleName verifies that AccessibleName and
+// AccessibleDescription evaluate the accname functions on the element.
+func TestElementAccessibleName(t *testing.T) {
+	mock := newMockTransport()
+	mock.setMethodResponse("vibium:element.eval", json.RawMessage(`{"value":"Email address"}`))
+	elem := NewElement(NewBiDiClient(mock), "ctx-123", "#email", ElementInfo{})
+	ctx := context.Background()
+
+	name, err := elem.AccessibleName(ctx)
+	if err != nil {
+		t.Fatalf("AccessibleName failed: %v", err)
+	}
+	if name != "Email address" {
+		t.Errorf("Expected name 'Email address', got %q", name)
+	}
+
+	if _, err := elem.AccessibleDescription(ctx); err != nil {
+		t.Fatalf("AccessibleDescription failed: %v", err)
+	}
+
+	calls := mock.getCalls()
+	if len(calls) != 2 {
+		t.Fatalf("Expected 2 calls, got %d", len(calls))
+	}
+	for i, want := range []string{"return accessibleName(el);", "return accessibleDescription(el);"} {
+		params := calls[i].Params.(map[string]interface{})
+		if params["selector"] != "#email" {
+			t.Errorf("Expected selector #email, got %v", params["selector"])
+		}
+		if fn, _ := params["fn"].(string); !strings.Contains(fn, want) {
+			t.Errorf("Expected script to contain %q", want)
+		}
+	}
+}
+
+// TestPilotElementRoles verifies that ElementRoles looks up every role in
+// one script evaluation.
+func TestPilotElementRoles(t *testing.T) {
+	mock := newMockTransport()
+	mock.setMethodResponse("script.callFunction", json.RawMessage(`{"result":{"type":"string","value":"[\"link\",\"\"]"}}`))
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+	elements := []*Element{
+		NewElement(pilot.client, "ctx-123", "a:nth-of-type(1)", ElementInfo{}),
+		NewElement(pilot.client, "ctx-123", "div:nth-of-type(2)", ElementInfo{}),
+	}
+
+	roles, err := pilot.ElementRoles(context.Background(), elements)
+	if err != nil {
+		t.Fatalf("ElementRoles failed: %v", err)
+	}
+	if len(roles) != 2 || roles[0] != "link" || roles[1] != "" {
+		t.Errorf("Expected roles [link \"\"], got %q", roles)
+	}
+
+	calls := mock.getCalls()
+	if len(calls) != 1 {
+		t.Fatalf("Expected 1 call, got %d", len(calls))
+	}
+	args := calls[0].Params.(map[string]interface{})["arguments"].([]interface{})
+	list := args[0].(map[string]interface{})["value"].([]interface{})
+	if len(list) != 2 || list[1].(map[string]interface{})["value"] != "div:nth-of-type(2)" {
+		t.Errorf("Expected both selectors as the argument, got %v", list)
+	}
+}
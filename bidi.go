@@ -3,7 +3,9 @@ package w3pilot
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"sync"
+	"time"
 )
 
 // BiDiCommand represents a WebDriver BiDi command.
@@ -55,6 +57,21 @@ type BiDiClient struct {
 	transport BiDiTransport
 	handlers  map[string][]EventHandler // Event method -> handlers
 	handlerMu sync.RWMutex
+	slowMo    time.Duration // Pause before user-input actions (see LaunchOptions.SlowMo)
+	onCommand func(method string, dur time.Duration, err error)
+	tracer    Tracer
+
+	// Network event watcher for WaitForRequest/WaitForResponse (lazy-initialized)
+	network   *networkWatcher
+	networkMu sync.Mutex
+
+	// Navigation event watcher for WaitForNavigation (lazy-initialized)
+	navigation   *navigationWatcher
+	navigationMu sync.Mutex
+
+	// Request interception for Route handlers (lazy-initialized)
+	routes   *router
+	routerMu sync.Mutex
 }
 
 // NewBiDiClient creates a new BiDi client wrapping the given transport.
@@ -92,7 +109,79 @@ func (c *BiDiClient) Close() error {
 	return c.transport.Close()
 }
 
+// SetSlowMo sets a pause inserted before every element, mouse, keyboard,
+// and touch action sent through this client. Zero disables the pause.
+func (c *BiDiClient) SetSlowMo(d time.Duration) {
+	c.slowMo = d
+}
+
+// SetCommandHook sets a function called after every command completes with
+// the command's round-trip duration and error. The SlowMo pause is not
+// included in the duration. Nil removes the hook.
+func (c *BiDiClient) SetCommandHook(hook func(method string, dur time.Duration, err error)) {
+	c.onCommand = hook
+}
+
+// SetTracer sets a Tracer that wraps every command in a span. Nil disables
+// tracing.
+func (c *BiDiClient) SetTracer(t Tracer) {
+	c.tracer = t
+}
+
 // Send sends a command and waits for the response.
-func (c *BiDiClient) Send(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
-	return c.transport.Send(ctx, method, params)
+func (c *BiDiClient) Send(ctx context.Context, method string, params interface{}) (result json.RawMessage, err error) {
+	if c.tracer != nil {
+		var span Span
+		ctx, span = c.tracer.StartSpan(ctx, method, spanAttributes(method, params))
+		defer func() { span.End(err) }()
+	}
+
+	if c.slowMo > 0 && isSlowMoAction(method) {
+		select {
+		case <-time.After(c.slowMo):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if c.onCommand == nil {
+		return c.transport.Send(ctx, method, params)
+	}
+
+	start := time.Now()
+	result, err = c.transport.Send(ctx, method, params)
+	c.onCommand(method, time.Since(start), err)
+	return result, err
+}
+
+// slowMoElementActions lists the element commands that simulate user input.
+// Queries such as text, attr, or isVisible are not slowed down.
+var slowMoElementActions = map[string]bool{
+	"vibium:element.click":          true,
+	"vibium:element.dblclick":       true,
+	"vibium:element.fill":           true,
+	"vibium:element.type":           true,
+	"vibium:element.press":          true,
+	"vibium:element.clear":          true,
+	"vibium:element.check":          true,
+	"vibium:element.uncheck":        true,
+	"vibium:element.selectOption":   true,
+	"vibium:element.setFiles":       true,
+	"vibium:element.hover":          true,
+	"vibium:element.focus":          true,
+	"vibium:element.dragTo":         true,
+	"vibium:element.tap":            true,
+	"vibium:element.scrollIntoView": true,
+	"vibium:element.dispatchEvent":  true,
+}
+
+// isSlowMoAction reports whether method is a user-input action subject to SlowMo.
+func isSlowMoAction(method string) bool {
+	if slowMoElementActions[method] {
+		return true
+	}
+	// Raw BiDi pointer actions back gestures such as Touch.LongPress
+	return method == "input.performActions" ||
+		strings.HasPrefix(method, "vibium:mouse.") ||
+		strings.HasPrefix(method, "vibium:keyboard.") ||
+		strings.HasPrefix(method, "vibium:touch.")
 }
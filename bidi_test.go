@@ -1,10 +1,16 @@
 package w3pilot
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 // mockTransport records all calls for verification.
@@ -16,6 +22,9 @@ type mockTransport struct {
 	// Response to return for Send calls
 	response json.RawMessage
 	err      error
+
+	// Per-method responses, taking precedence over response
+	methodResponses map[string]json.RawMessage
 }
 
 type mockCall struct {
@@ -35,6 +44,9 @@ func (m *mockTransport) Send(ctx context.Context, method string, params interfac
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.calls = append(m.calls, mockCall{Method: method, Params: params})
+	if resp, ok := m.methodResponses[method]; ok {
+		return resp, m.err
+	}
 	return m.response, m.err
 }
 
@@ -62,6 +74,15 @@ func (m *mockTransport) getCalls() []mockCall {
 	return result
 }
 
+func (m *mockTransport) setMethodResponse(method string, resp json.RawMessage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.methodResponses == nil {
+		m.methodResponses = make(map[string]json.RawMessage)
+	}
+	m.methodResponses[method] = resp
+}
+
 func (m *mockTransport) setResponse(resp json.RawMessage) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -116,6 +137,364 @@ func TestPilotFind_SendsVibiumPageFind(t *testing.T) {
 	}
 }
 
+// TestPilotFind_RespectsOuterDeadline verifies a shorter caller deadline caps the find timeout.
+func TestPilotFind_RespectsOuterDeadline(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"tag":"button"}`))
+
+	client := NewBiDiClient(mock)
+	pilot := &Pilot{client: client, browsingContext: "ctx-123"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if _, err := pilot.Find(ctx, "button", &FindOptions{Timeout: time.Minute}); err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+
+	calls := mock.getCalls()
+	if len(calls) != 1 {
+		t.Fatalf("Expected 1 call, got %d", len(calls))
+	}
+	timeout, _ := calls[0].Params.(map[string]interface{})["timeout"].(int64)
+	if timeout <= 0 || timeout > 2000 {
+		t.Errorf("Expected timeout capped at the 2s outer deadline, got %dms", timeout)
+	}
+}
+
+// TestPilotFind_ExpiredContext verifies no command is sent when the caller's context is already done.
+func TestPilotFind_ExpiredContext(t *testing.T) {
+	mock := newMockTransport()
+	client := NewBiDiClient(mock)
+	pilot := &Pilot{client: client, browsingContext: "ctx-123"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := pilot.Find(ctx, "button", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if calls := mock.getCalls(); len(calls) != 0 {
+		t.Errorf("Expected no calls, got %d", len(calls))
+	}
+
+	elem := NewElement(client, "ctx-123", "#submit", ElementInfo{})
+	if err := elem.Click(ctx, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected Click to fail with context.Canceled, got %v", err)
+	}
+}
+
+// TestPilot_Focused verifies the active element is resolved to a selector and found.
+func TestPilot_Focused(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"type":"success","result":{"type":"string","value":"#email"}}`))
+
+	client := NewBiDiClient(mock)
+	pilot := &Pilot{client: client, browsingContext: "ctx-123"}
+
+	ctx := context.Background()
+	elem, err := pilot.Focused(ctx)
+	if err != nil {
+		t.Fatalf("Focused failed: %v", err)
+	}
+	if elem == nil || elem.Selector() != "#email" {
+		t.Fatalf("Expected element #email, got %v", elem)
+	}
+
+	calls := mock.getCalls()
+	if len(calls) != 2 || calls[0].Method != "script.callFunction" || calls[1].Method != "vibium:page.find" {
+		t.Fatalf("Expected script.callFunction then vibium:page.find, got %v", calls)
+	}
+
+	// Focus on the body yields no element
+	mock.setResponse(json.RawMessage(`{"type":"success","result":{"type":"null"}}`))
+	elem, err = pilot.Focused(ctx)
+	if err != nil {
+		t.Fatalf("Focused failed: %v", err)
+	}
+	if elem != nil {
+		t.Errorf("Expected nil element when body has focus, got %v", elem.Selector())
+	}
+}
+
+// focusTransport answers each focus query from TabOrder with the next of
+// stops, given as JSON focusStop objects or null.
+type focusTransport struct {
+	*mockTransport
+	stops []string
+}
+
+func (f *focusTransport) Send(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	if method == "script.callFunction" {
+		f.mu.Lock()
+		stop := "null"
+		if len(f.stops) > 0 {
+			stop, f.stops = f.stops[0], f.stops[1:]
+		}
+		f.mu.Unlock()
+		result := `{"type":"null"}`
+		var fs *focusStop
+		if err := json.Unmarshal([]byte(stop), &fs); err != nil {
+			return nil, err
+		}
+		if fs != nil {
+			result = fmt.Sprintf(`{"type":"object","value":[["selector",{"type":"string","value":%q}],["key",{"type":"string","value":%q}],["opaque",{"type":"boolean","value":%t}]]}`,
+				fs.Selector, fs.Key, fs.Opaque)
+		}
+		return json.RawMessage(`{"type":"success","result":` + result + `}`), nil
+	}
+	return f.mockTransport.Send(ctx, method, params)
+}
+
+func tabOrderPilot(stops ...string) *Pilot {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"tag":"input"}`))
+	return &Pilot{client: NewBiDiClient(&focusTransport{mockTransport: mock, stops: stops}), browsingContext: "ctx-123"}
+}
+
+// TestPilot_TabOrder_KeyboardTrap verifies focus that does not advance is reported as a trap.
+func TestPilot_TabOrder_KeyboardTrap(t *testing.T) {
+	pilot := tabOrderPilot(
+		`{"selector":"#name","key":"#name"}`,
+		`{"selector":"#editor","key":"#editor"}`,
+		`{"selector":"#editor","key":"#editor"}`,
+	)
+
+	stops, err := pilot.TabOrder(context.Background(), 10)
+
+	var trap *KeyboardTrapError
+	if !errors.As(err, &trap) {
+		t.Fatalf("Expected KeyboardTrapError, got %v", err)
+	}
+	if trap.Selector != "#editor" || trap.Stop != 2 || trap.Cycle {
+		t.Errorf("Unexpected trap: %+v", trap)
+	}
+	if len(stops) != 2 {
+		t.Errorf("Expected 2 recorded stops before the trap, got %d", len(stops))
+	}
+}
+
+// TestPilot_TabOrder_FramesAndShadowRoots verifies that focus moving within
+// a frame or shadow host is not reported as a trap, and that returning to
+// the first stop ends the traversal.
+func TestPilot_TabOrder_FramesAndShadowRoots(t *testing.T) {
+	pilot := tabOrderPilot(
+		`{"selector":"#card","key":"#card >>> #number"}`,
+		`{"selector":"#card","key":"#card >>> #expiry"}`,
+		`{"selector":"#pay","key":"#pay","opaque":true}`,
+		`{"selector":"#pay","key":"#pay","opaque":true}`,
+		`{"selector":"#submit","key":"#submit"}`,
+		`{"selector":"#card","key":"#card >>> #number"}`,
+	)
+
+	stops, err := pilot.TabOrder(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("TabOrder failed: %v", err)
+	}
+	if len(stops) != 4 {
+		t.Errorf("Expected 4 stops, got %d", len(stops))
+	}
+
+	// A single focusable element that Tab returns to is not a trap
+	pilot = tabOrderPilot(`{"selector":"#only","key":"#only"}`, `{"selector":"#only","key":"#only"}`)
+	if stops, err := pilot.TabOrder(context.Background(), 10); err != nil || len(stops) != 1 {
+		t.Errorf("Expected 1 stop and no error, got %d, %v", len(stops), err)
+	}
+}
+
+// TestPilot_WaitForSelector verifies each state maps to the right commands.
+func TestPilot_WaitForSelector(t *testing.T) {
+	tests := []struct {
+		state   string
+		methods []string
+		elem    bool
+	}{
+		{"", []string{"vibium:page.find", "vibium:element.waitFor"}, true},
+		{"attached", []string{"vibium:page.find"}, true},
+		{"hidden", []string{"vibium:element.waitFor"}, false},
+		{"detached", []string{"vibium:element.waitFor"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			mock := newMockTransport()
+			mock.setResponse(json.RawMessage(`{"tag":"div"}`))
+			pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+			elem, err := pilot.WaitForSelector(context.Background(), ".toast", tt.state, time.Second)
+			if err != nil {
+				t.Fatalf("WaitForSelector failed: %v", err)
+			}
+			if (elem != nil) != tt.elem {
+				t.Errorf("Expected element=%v, got %v", tt.elem, elem)
+			}
+
+			calls := mock.getCalls()
+			if len(calls) != len(tt.methods) {
+				t.Fatalf("Expected %d calls, got %v", len(tt.methods), calls)
+			}
+			for i, method := range tt.methods {
+				if calls[i].Method != method {
+					t.Errorf("Call %d: expected %s, got %s", i, method, calls[i].Method)
+				}
+			}
+		})
+	}
+
+	pilot := &Pilot{client: NewBiDiClient(newMockTransport()), browsingContext: "ctx-123"}
+	if _, err := pilot.WaitForSelector(context.Background(), ".toast", "gone", time.Second); err == nil {
+		t.Error("Expected error for invalid state")
+	}
+}
+
+// TestPilot_WaitForSelector_HiddenUsesServerVisibility verifies that
+// waiting for a fixed-position element to hide defers to the server, which
+// keeps waiting while it stays visible, instead of a client-side check.
+func TestPilot_WaitForSelector_HiddenUsesServerVisibility(t *testing.T) {
+	mock := newMockTransport()
+	mock.err = errors.New("timeout waiting for #modal to be hidden")
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	if _, err := pilot.WaitForSelector(context.Background(), "#modal", "hidden", time.Second); err == nil {
+		t.Fatal("Expected an error while the fixed modal stays visible")
+	}
+
+	calls := mock.getCalls()
+	if len(calls) != 1 || calls[0].Method != "vibium:element.waitFor" {
+		t.Fatalf("Expected one vibium:element.waitFor call, got %v", calls)
+	}
+	params := calls[0].Params.(map[string]interface{})
+	if params["selector"] != "#modal" || params["state"] != "hidden" {
+		t.Errorf("Unexpected params: %v", params)
+	}
+}
+
+// TestPilot_ConcurrentUse exercises lazy initialization and Quit from many goroutines under -race.
+func TestPilot_ConcurrentUse(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"contexts":[{"context":"ctx-123"}]}`))
+	pilot := &Pilot{client: NewBiDiClient(mock)}
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	keyboards := make([]*Keyboard, 8)
+	for i := range keyboards {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			kb, err := pilot.Keyboard(ctx)
+			if err != nil {
+				t.Errorf("Keyboard failed: %v", err)
+				return
+			}
+			keyboards[i] = kb
+			_ = pilot.BrowsingContext()
+			_ = pilot.IsClosed()
+		}(i)
+	}
+	wg.Wait()
+
+	for _, kb := range keyboards[1:] {
+		if kb != keyboards[0] {
+			t.Fatal("Expected a single shared keyboard controller")
+		}
+	}
+	if pilot.BrowsingContext() != "ctx-123" {
+		t.Errorf("Expected browsing context ctx-123, got %q", pilot.BrowsingContext())
+	}
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := pilot.Quit(ctx); err != nil {
+				t.Errorf("Quit failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !pilot.IsClosed() {
+		t.Error("Expected pilot to be closed")
+	}
+	if _, err := pilot.Find(ctx, "button", nil); err != ErrConnectionClosed {
+		t.Errorf("Expected ErrConnectionClosed after Quit, got %v", err)
+	}
+}
+
+// TestPilot_FindByText verifies FindBy* helpers set the semantic option without mutating the caller's options.
+func TestPilot_FindByText(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"tag":"a","text":"Log in"}`))
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	opts := &FindOptions{ExactText: true}
+	if _, err := pilot.FindByText(context.Background(), "Log in", opts); err != nil {
+		t.Fatalf("FindByText failed: %v", err)
+	}
+	if _, err := pilot.FindByTestID(context.Background(), "login", nil); err != nil {
+		t.Fatalf("FindByTestID failed: %v", err)
+	}
+
+	// The text lookup is followed by an ambiguity check
+	calls := mock.getCalls()
+	if len(calls) != 3 || calls[0].Method != "vibium:page.find" || calls[2].Method != "vibium:page.find" {
+		t.Fatalf("Expected two vibium:page.find calls, got %v", calls)
+	}
+	params := calls[0].Params.(map[string]interface{})
+	if params["text"] != "Log in" || params["exact"] != true || params["selector"] != "" {
+		t.Errorf("Expected exact text match with no selector, got %v", params)
+	}
+	if opts.Text != "" {
+		t.Errorf("Expected caller's options to be unchanged, got Text=%q", opts.Text)
+	}
+	if params := calls[2].Params.(map[string]interface{}); params["testid"] != "login" {
+		t.Errorf("Expected testid login, got %v", params)
+	}
+}
+
+// TestPilotFind_AmbiguousText verifies a text selector matching several elements is an error.
+func TestPilotFind_AmbiguousText(t *testing.T) {
+	mock := newMockTransport()
+	mock.setMethodResponse("vibium:page.find", json.RawMessage(`{"tag":"a","text":"Log in"}`))
+	mock.setMethodResponse("vibium:page.findAll", json.RawMessage(`{"count":2,"elements":[
+		{"index":0,"tag":"a","text":"Log in"},
+		{"index":1,"tag":"a","text":"Log in with Google"}
+	]}`))
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	_, err := pilot.Find(context.Background(), "a", &FindOptions{Text: "Log in"})
+	var ambiguous *AmbiguousMatchError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("Expected AmbiguousMatchError, got %v", err)
+	}
+	if len(ambiguous.Candidates) != 2 || ambiguous.Candidates[1].Text != "Log in with Google" {
+		t.Errorf("Expected both candidates, got %+v", ambiguous.Candidates)
+	}
+	if !strings.Contains(err.Error(), `"Log in with Google"`) {
+		t.Errorf("Expected candidates in error message, got %q", err.Error())
+	}
+
+	// TextMatch is validated and forwarded
+	_, err = pilot.Find(context.Background(), "a", &FindOptions{Text: "Log in", ExactText: true, TextMatch: "regex"})
+	if err == nil {
+		t.Error("Expected error for ExactText with TextMatch regex")
+	}
+	_, err = pilot.Find(context.Background(), "a", &FindOptions{Text: "x", TextMatch: "fuzzy"})
+	if err == nil {
+		t.Error("Expected error for unknown TextMatch")
+	}
+
+	params := map[string]interface{}{}
+	if err := addFindOptions(params, &FindOptions{Text: "^Log in$", TextMatch: "regex"}); err != nil {
+		t.Fatalf("addFindOptions failed: %v", err)
+	}
+	if params["textMatch"] != "regex" {
+		t.Errorf("Expected textMatch=regex, got %v", params)
+	}
+}
+
 // TestPilotFindAll_SendsVibiumPageFindAll verifies that FindAll sends vibium:page.findAll.
 func TestPilotFindAll_SendsVibiumPageFindAll(t *testing.T) {
 	mock := newMockTransport()
@@ -147,6 +526,80 @@ func TestPilotFindAll_SendsVibiumPageFindAll(t *testing.T) {
 	}
 }
 
+// TestPilotFind_VisibilityFilter verifies that Visible/Hidden are forwarded to vibium:page.find.
+func TestPilotFind_VisibilityFilter(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"tag":"button","text":"Save","box":{"x":0,"y":0,"width":10,"height":10}}`))
+
+	client := NewBiDiClient(mock)
+	pilot := &Pilot{
+		client:          client,
+		browsingContext: "ctx-123",
+	}
+
+	ctx := context.Background()
+	_, err := pilot.Find(ctx, "button", &FindOptions{Text: "Save", Visible: true})
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+
+	// A text selector is followed by an ambiguity check
+	calls := mock.getCalls()
+	if len(calls) != 2 || calls[0].Method != "vibium:page.find" || calls[1].Method != "vibium:page.findAll" {
+		t.Fatalf("Expected vibium:page.find then vibium:page.findAll, got %v", calls)
+	}
+	params := calls[0].Params.(map[string]interface{})
+	if params["visible"] != true {
+		t.Errorf("Expected visible=true, got %v", params["visible"])
+	}
+	if _, ok := params["hidden"]; ok {
+		t.Errorf("Expected hidden to be omitted, got %v", params["hidden"])
+	}
+
+	// Visible and Hidden together are rejected before anything is sent
+	_, err = pilot.Find(ctx, "button", &FindOptions{Visible: true, Hidden: true})
+	if err == nil {
+		t.Error("Expected error for Visible and Hidden together")
+	}
+	if len(mock.getCalls()) != 2 {
+		t.Errorf("Expected no additional calls, got %d", len(mock.getCalls()))
+	}
+}
+
+// TestPilotFind_SpatialSelectors verifies that spatial options are forwarded to vibium:page.find.
+func TestPilotFind_SpatialSelectors(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"tag":"input","text":"","box":{"x":0,"y":0,"width":10,"height":10}}`))
+
+	client := NewBiDiClient(mock)
+	pilot := &Pilot{
+		client:          client,
+		browsingContext: "ctx-123",
+	}
+
+	ctx := context.Background()
+	_, err := pilot.Find(ctx, "input", &FindOptions{RightOf: "label.email", Below: "h1", MaxDistance: 200})
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+
+	params := mock.getCalls()[0].Params.(map[string]interface{})
+	if params["rightOf"] != "label.email" {
+		t.Errorf("Expected rightOf 'label.email', got %v", params["rightOf"])
+	}
+	if params["below"] != "h1" {
+		t.Errorf("Expected below 'h1', got %v", params["below"])
+	}
+	if params["maxDistance"] != float64(200) {
+		t.Errorf("Expected maxDistance 200, got %v", params["maxDistance"])
+	}
+	for _, key := range []string{"above", "leftOf", "near"} {
+		if _, ok := params[key]; ok {
+			t.Errorf("Expected %s to be omitted, got %v", key, params[key])
+		}
+	}
+}
+
 // TestPilotContent_SendsVibiumPageContent verifies Content sends vibium:page.content.
 func TestPilotContent_SendsVibiumPageContent(t *testing.T) {
 	mock := newMockTransport()
@@ -195,159 +648,1140 @@ func TestPilotSetContent_SendsVibiumPageSetContent(t *testing.T) {
 	}
 
 	calls := mock.getCalls()
-	found := false
-	for _, call := range calls {
-		if call.Method == "vibium:page.setContent" {
-			found = true
-			params, ok := call.Params.(map[string]interface{})
-			if ok && params["html"] != "<h1>Hello</h1>" {
-				t.Errorf("Expected html param, got %v", params)
-			}
-			break
-		}
+	found := false
+	for _, call := range calls {
+		if call.Method == "vibium:page.setContent" {
+			found = true
+			params, ok := call.Params.(map[string]interface{})
+			if ok && params["html"] != "<h1>Hello</h1>" {
+				t.Errorf("Expected html param, got %v", params)
+			}
+			break
+		}
+	}
+
+	if !found {
+		t.Errorf("Expected vibium:page.setContent call, got: %v", calls)
+	}
+}
+
+// TestPilotScroll_SendsVibiumPageScroll verifies Scroll sends correct method.
+func TestPilotScroll_SendsVibiumPageScroll(t *testing.T) {
+	mock := newMockTransport()
+
+	client := NewBiDiClient(mock)
+	pilot := &Pilot{
+		client:          client,
+		browsingContext: "ctx-123",
+	}
+
+	ctx := context.Background()
+	err := pilot.Scroll(ctx, "down", 500, nil)
+	if err != nil {
+		t.Fatalf("Scroll failed: %v", err)
+	}
+
+	calls := mock.getCalls()
+	found := false
+	for _, call := range calls {
+		if call.Method == "vibium:page.scroll" {
+			found = true
+			params, ok := call.Params.(map[string]interface{})
+			if ok {
+				if params["direction"] != "down" {
+					t.Errorf("Expected direction 'down', got %v", params["direction"])
+				}
+				if params["amount"] != 500 {
+					t.Errorf("Expected amount 500, got %v", params["amount"])
+				}
+			}
+			break
+		}
+	}
+
+	if !found {
+		t.Errorf("Expected vibium:page.scroll call, got: %v", calls)
+	}
+}
+
+// TestPilot_FrameScreenshot_ClipsTopLevelPage verifies a frame is captured by clipping its top-level page.
+func TestPilot_FrameScreenshot_ClipsTopLevelPage(t *testing.T) {
+	mock := newMockTransport()
+	mock.setMethodResponse("browsingContext.getTree", json.RawMessage(`{"contexts":[
+		{"context":"top","children":[{"context":"ad","children":[]},{"context":"widget","children":[]}]}
+	]}`))
+	mock.setMethodResponse("script.callFunction", json.RawMessage(`{"type":"success","result":{"type":"object","value":[
+		["x",{"type":"number","value":40}],["y",{"type":"number","value":300}],
+		["width",{"type":"number","value":320}],["height",{"type":"number","value":200}]
+	]}}`))
+	mock.setMethodResponse("browsingContext.captureScreenshot", json.RawMessage(`{"data":"iVBORw=="}`))
+
+	frame := &Pilot{client: NewBiDiClient(mock), browsingContext: "widget", isFrame: true}
+	if _, err := frame.Screenshot(context.Background()); err != nil {
+		t.Fatalf("Screenshot failed: %v", err)
+	}
+
+	var evalParams, shotParams map[string]interface{}
+	for _, call := range mock.getCalls() {
+		switch call.Method {
+		case "script.callFunction":
+			evalParams = call.Params.(map[string]interface{})
+		case "browsingContext.captureScreenshot":
+			shotParams = call.Params.(map[string]interface{})
+		}
+	}
+
+	target := evalParams["target"].(map[string]interface{})
+	index := evalParams["arguments"].([]interface{})[0].(map[string]interface{})
+	if target["context"] != "top" || index["value"] != 1 {
+		t.Errorf("Expected frame lookup of index 1 in top, got target=%v arg=%v", target, index)
+	}
+
+	if shotParams["context"] != "top" {
+		t.Errorf("Expected screenshot of top-level context, got %v", shotParams["context"])
+	}
+	clip, ok := shotParams["clip"].(map[string]interface{})
+	if !ok || clip["type"] != "box" || clip["x"] != 40.0 || clip["y"] != 300.0 ||
+		clip["width"] != 320.0 || clip["height"] != 200.0 {
+		t.Errorf("Expected box clip {40 300 320 200}, got %v", shotParams["clip"])
+	}
+}
+
+// TestPilot_ScreenshotFullPage verifies full-page screenshots capture from the document origin.
+func TestPilot_ScreenshotFullPage(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"data":"iVBORw=="}`))
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	if _, err := pilot.ScreenshotWithOptions(context.Background(), &ScreenshotOptions{FullPage: true}); err != nil {
+		t.Fatalf("ScreenshotWithOptions failed: %v", err)
+	}
+	params := mock.getCalls()[0].Params.(map[string]interface{})
+	if params["origin"] != "document" {
+		t.Errorf("Expected origin=document, got %v", params)
+	}
+
+	frame := &Pilot{client: NewBiDiClient(mock), browsingContext: "frame", isFrame: true}
+	if _, err := frame.ScreenshotWithOptions(context.Background(), &ScreenshotOptions{FullPage: true}); err == nil {
+		t.Error("Expected error for full-page frame screenshot")
+	}
+}
+
+// TestPilot_MainAndParentFrame verifies frames resolve their ancestors from the context tree.
+func TestPilot_MainAndParentFrame(t *testing.T) {
+	mock := newMockTransport()
+	mock.setMethodResponse("browsingContext.getTree", json.RawMessage(`{"contexts":[
+		{"context":"top","children":[{"context":"outer","children":[{"context":"inner","children":[]}]}]}
+	]}`))
+	ctx := context.Background()
+
+	inner := &Pilot{client: NewBiDiClient(mock), browsingContext: "inner", isFrame: true}
+	parent, err := inner.ParentFrame(ctx)
+	if err != nil || parent.browsingContext != "outer" || !parent.isFrame {
+		t.Fatalf("ParentFrame() = %+v, %v; want outer frame", parent, err)
+	}
+	main, err := inner.MainFrame(ctx)
+	if err != nil || main.browsingContext != "top" || main.isFrame {
+		t.Fatalf("MainFrame() = %+v, %v; want top page", main, err)
+	}
+
+	if _, err := main.ParentFrame(ctx); err == nil {
+		t.Error("Expected error for ParentFrame of a top-level page")
+	}
+	if same, err := main.MainFrame(ctx); err != nil || same != main {
+		t.Errorf("Expected MainFrame of a page to return itself, got %v, %v", same, err)
+	}
+}
+
+// TestPilot_OnFrame verifies frame attach, navigate, and detach events for the page are reported.
+func TestPilot_OnFrame(t *testing.T) {
+	mock := newMockTransport()
+	mock.setMethodResponse("browsingContext.getTree", json.RawMessage(`{"contexts":[
+		{"context":"top","children":[{"context":"ad","children":[]}]},
+		{"context":"other","children":[]}
+	]}`))
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "top"}
+
+	var events []*FrameEvent
+	if err := pilot.OnFrame(context.Background(), func(e *FrameEvent) { events = append(events, e) }); err != nil {
+		t.Fatalf("OnFrame failed: %v", err)
+	}
+
+	emitNetworkEvent(t, mock, "browsingContext.contextCreated", `{"context":"widget","parent":"ad","url":"about:blank"}`)
+	emitNetworkEvent(t, mock, "browsingContext.contextCreated", `{"context":"popup-frame","parent":"other"}`)
+	emitNetworkEvent(t, mock, "browsingContext.load", `{"context":"widget","url":"https://example.com/widget"}`)
+	emitNetworkEvent(t, mock, "browsingContext.load", `{"context":"other","url":"https://example.com/"}`)
+	emitNetworkEvent(t, mock, "browsingContext.contextDestroyed", `{"context":"ad"}`)
+
+	want := []FrameEvent{
+		{Type: FrameAttached, Context: "widget", Parent: "ad", URL: "about:blank"},
+		{Type: FrameNavigated, Context: "widget", Parent: "ad", URL: "https://example.com/widget"},
+		{Type: FrameDetached, Context: "ad", Parent: "top"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %d events, got %d: %+v", len(want), len(events), events)
+	}
+	for i, w := range want {
+		got := events[i]
+		if got.Type != w.Type || got.Context != w.Context || got.Parent != w.Parent || got.URL != w.URL {
+			t.Errorf("event %d = %+v, want %+v", i, got, w)
+		}
+		if (got.Frame == nil) != (w.Type == FrameDetached) {
+			t.Errorf("event %d: unexpected Frame %v", i, got.Frame)
+		}
+	}
+}
+
+// TestPilot_OnNavigation verifies only navigations of the page itself are reported.
+func TestPilot_OnNavigation(t *testing.T) {
+	mock := newMockTransport()
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "top"}
+
+	var urls []string
+	if err := pilot.OnNavigation(context.Background(), func(url string) { urls = append(urls, url) }); err != nil {
+		t.Fatalf("OnNavigation failed: %v", err)
+	}
+
+	emitNetworkEvent(t, mock, "browsingContext.navigationStarted", `{"context":"top","url":"https://example.com/next"}`)
+	emitNetworkEvent(t, mock, "browsingContext.navigationStarted", `{"context":"frame","url":"https://ads.example.com/"}`)
+
+	if len(urls) != 1 || urls[0] != "https://example.com/next" {
+		t.Errorf("Expected one navigation to /next, got %v", urls)
+	}
+}
+
+// TestPilot_PDFTo verifies the decoded PDF is streamed to the writer.
+func TestPilot_PDFTo(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"data":"JVBERi0xLjQK"}`))
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	var buf bytes.Buffer
+	if err := pilot.PDFTo(context.Background(), &buf, &PDFOptions{Landscape: true}); err != nil {
+		t.Fatalf("PDFTo failed: %v", err)
+	}
+	if buf.String() != "%PDF-1.4\n" {
+		t.Errorf("Expected decoded PDF header, got %q", buf.String())
+	}
+
+	calls := mock.getCalls()
+	if len(calls) != 1 || calls[0].Method != "vibium:page.pdf" {
+		t.Fatalf("Expected one vibium:page.pdf call, got %v", calls)
+	}
+	if params := calls[0].Params.(map[string]interface{}); params["landscape"] != true {
+		t.Errorf("Expected landscape=true, got %v", params)
+	}
+
+	data, err := pilot.PDF(context.Background(), nil)
+	if err != nil || string(data) != "%PDF-1.4\n" {
+		t.Errorf("PDF() = %q, %v", data, err)
+	}
+}
+
+// TestPilot_PDFTemplates verifies header/footer templates are forwarded and validated.
+func TestPilot_PDFTemplates(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"data":""}`))
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	footer := `<span class="pageNumber"></span> / <span class="totalPages"></span>`
+	if _, err := pilot.PDF(context.Background(), &PDFOptions{DisplayFooter: true, FooterTemplate: footer}); err != nil {
+		t.Fatalf("PDF failed: %v", err)
+	}
+	params := mock.getCalls()[0].Params.(map[string]interface{})
+	if params["footerTemplate"] != footer || params["displayFooter"] != true {
+		t.Errorf("Expected footer template to be forwarded, got %v", params)
+	}
+
+	if _, err := pilot.PDF(context.Background(), &PDFOptions{HeaderTemplate: "<div></div>"}); err == nil {
+		t.Error("Expected error for HeaderTemplate without DisplayHeader")
+	}
+	if len(mock.getCalls()) != 1 {
+		t.Errorf("Expected invalid options to be rejected before sending, got %d calls", len(mock.getCalls()))
+	}
+}
+
+// TestPilot_EvaluateWithOptions_Sandbox verifies the sandbox name is set on the script target.
+func TestPilot_EvaluateWithOptions_Sandbox(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"type":"success","result":{"type":"number","value":3}}`))
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+	ctx := context.Background()
+
+	result, err := pilot.EvaluateWithOptions(ctx, "document.links.length", &EvaluateOptions{Sandbox: "helpers"})
+	if err != nil {
+		t.Fatalf("EvaluateWithOptions failed: %v", err)
+	}
+	if result != 3.0 {
+		t.Errorf("Expected 3, got %v", result)
+	}
+	if _, err := pilot.Evaluate(ctx, "1"); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	calls := mock.getCalls()
+	sandboxed := calls[0].Params.(map[string]interface{})["target"].(map[string]interface{})
+	if sandboxed["sandbox"] != "helpers" || sandboxed["context"] != "ctx-123" {
+		t.Errorf("Expected sandboxed target, got %v", sandboxed)
+	}
+	main := calls[1].Params.(map[string]interface{})["target"].(map[string]interface{})
+	if _, ok := main["sandbox"]; ok {
+		t.Errorf("Expected main-world target, got %v", main)
+	}
+}
+
+// TestPilot_ReloadWithOptions verifies ignoreCache and wait are forwarded to browsingContext.reload.
+func TestPilot_ReloadWithOptions(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{}`))
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	if err := pilot.ReloadWithOptions(context.Background(), &ReloadOptions{IgnoreCache: true, WaitUntil: "interactive"}); err != nil {
+		t.Fatalf("ReloadWithOptions failed: %v", err)
+	}
+	if err := pilot.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+
+	calls := mock.getCalls()
+	if len(calls) != 2 {
+		t.Fatalf("Expected 2 calls, got %d", len(calls))
+	}
+	hard := calls[0].Params.(map[string]interface{})
+	if hard["ignoreCache"] != true || hard["wait"] != "interactive" {
+		t.Errorf("Unexpected hard reload params: %v", hard)
+	}
+	plain := calls[1].Params.(map[string]interface{})
+	if _, ok := plain["ignoreCache"]; ok || plain["wait"] != "complete" {
+		t.Errorf("Unexpected reload params: %v", plain)
+	}
+
+	if err := pilot.ReloadWithOptions(context.Background(), &ReloadOptions{WaitUntil: "load"}); err == nil {
+		t.Error("Expected error for invalid WaitUntil")
+	}
+}
+
+// TestPilot_Traverse verifies Back/Forward delegate to Traverse and HistoryLength parses the count.
+func TestPilot_Traverse(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{}`))
+	mock.setMethodResponse("script.callFunction", json.RawMessage(`{"type":"success","result":{"type":"number","value":4}}`))
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+	ctx := context.Background()
+
+	if err := pilot.Traverse(ctx, -3); err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	if err := pilot.Back(ctx); err != nil {
+		t.Fatalf("Back failed: %v", err)
+	}
+	if err := pilot.Forward(ctx); err != nil {
+		t.Fatalf("Forward failed: %v", err)
+	}
+	if err := pilot.Traverse(ctx, 0); err == nil {
+		t.Error("Expected error for zero delta")
+	}
+
+	calls := mock.getCalls()
+	if len(calls) != 3 {
+		t.Fatalf("Expected 3 calls, got %d", len(calls))
+	}
+	for i, want := range []int{-3, -1, 1} {
+		params := calls[i].Params.(map[string]interface{})
+		if calls[i].Method != "browsingContext.traverseHistory" || params["delta"] != want {
+			t.Errorf("call %d: got %s %v, want delta %d", i, calls[i].Method, params, want)
+		}
+	}
+
+	n, err := pilot.HistoryLength(ctx)
+	if err != nil || n != 4 {
+		t.Errorf("HistoryLength() = %d, %v; want 4", n, err)
+	}
+}
+
+// TestPilotEmulateMedia_SendsVibiumPageEmulateMedia verifies EmulateMedia sends correct method.
+func TestPilotEmulateMedia_SendsVibiumPageEmulateMedia(t *testing.T) {
+	mock := newMockTransport()
+
+	client := NewBiDiClient(mock)
+	pilot := &Pilot{
+		client:          client,
+		browsingContext: "ctx-123",
+	}
+
+	ctx := context.Background()
+	err := pilot.EmulateMedia(ctx, EmulateMediaOptions{ColorScheme: "dark"})
+	if err != nil {
+		t.Fatalf("EmulateMedia failed: %v", err)
+	}
+
+	calls := mock.getCalls()
+	found := false
+	for _, call := range calls {
+		if call.Method == "vibium:page.emulateMedia" {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		t.Errorf("Expected vibium:page.emulateMedia call, got: %v", calls)
+	}
+}
+
+// TestPilot_GrantAndClearPermissions verifies permissions are granted per origin and reset to prompt.
+func TestPilot_GrantAndClearPermissions(t *testing.T) {
+	mock := newMockTransport()
+	pilot := &Pilot{
+		client:          NewBiDiClient(mock),
+		browsingContext: "ctx-123",
+	}
+
+	ctx := context.Background()
+	origin := "https://example.com"
+	if err := pilot.GrantPermissions(ctx, []string{"geolocation", "notifications"}, origin); err != nil {
+		t.Fatalf("GrantPermissions failed: %v", err)
+	}
+	if err := pilot.ClearPermissions(ctx); err != nil {
+		t.Fatalf("ClearPermissions failed: %v", err)
+	}
+
+	calls := mock.getCalls()
+	want := []struct{ name, state string }{
+		{"geolocation", "granted"},
+		{"notifications", "granted"},
+		{"geolocation", "prompt"},
+		{"notifications", "prompt"},
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("Expected %d calls, got %d: %v", len(want), len(calls), calls)
+	}
+	for i, call := range calls {
+		if call.Method != "permissions.setPermission" {
+			t.Errorf("call %d: expected permissions.setPermission, got %s", i, call.Method)
+			continue
+		}
+		params := call.Params.(map[string]interface{})
+		descriptor := params["descriptor"].(map[string]interface{})
+		if descriptor["name"] != want[i].name || params["state"] != want[i].state || params["origin"] != origin {
+			t.Errorf("call %d: expected %s=%s for %s, got %v", i, want[i].name, want[i].state, origin, params)
+		}
+	}
+
+	// A second clear has nothing to reset.
+	if err := pilot.ClearPermissions(ctx); err != nil {
+		t.Fatalf("ClearPermissions failed: %v", err)
+	}
+	if n := len(mock.getCalls()); n != len(want) {
+		t.Errorf("Expected no additional calls, got %d total", n)
+	}
+
+	if err := pilot.GrantPermissions(ctx, []string{"geolocation"}, ""); err == nil {
+		t.Error("Expected error for empty origin")
+	}
+}
+
+// TestElement_Click_SendsVibiumElementClick verifies Element.Click sends vibium:element.click.
+func TestElement_Click_SendsVibiumElementClick(t *testing.T) {
+	mock := newMockTransport()
+
+	client := NewBiDiClient(mock)
+	elem := NewElement(client, "ctx-123", "button.submit", ElementInfo{
+		Tag:  "button",
+		Text: "Submit",
+		Box:  BoundingBox{X: 100, Y: 200, Width: 80, Height: 30},
+	})
+
+	ctx := context.Background()
+	err := elem.Click(ctx, nil)
+	if err != nil {
+		t.Fatalf("Click failed: %v", err)
+	}
+
+	calls := mock.getCalls()
+	found := false
+	for _, call := range calls {
+		if call.Method == "vibium:element.click" {
+			found = true
+			params, ok := call.Params.(map[string]interface{})
+			if ok && params["selector"] != "button.submit" {
+				t.Errorf("Expected selector 'button.submit', got %v", params["selector"])
+			}
+			break
+		}
+	}
+
+	if !found {
+		t.Errorf("Expected vibium:element.click call, got: %v", calls)
+	}
+}
+
+// TestElement_SetChecked verifies SetChecked only toggles when the state differs.
+func TestElement_SetChecked(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"checked": true}`))
+
+	client := NewBiDiClient(mock)
+	elem := NewElement(client, "ctx-123", "#terms", ElementInfo{})
+	ctx := context.Background()
+
+	if err := elem.SetChecked(ctx, true, nil); err != nil {
+		t.Fatalf("SetChecked failed: %v", err)
+	}
+	calls := mock.getCalls()
+	if len(calls) != 1 || calls[0].Method != "vibium:element.isChecked" {
+		t.Fatalf("Expected only vibium:element.isChecked, got %v", calls)
+	}
+
+	if err := elem.SetChecked(ctx, false, nil); err != nil {
+		t.Fatalf("SetChecked failed: %v", err)
+	}
+	calls = mock.getCalls()
+	if len(calls) != 3 || calls[2].Method != "vibium:element.uncheck" {
+		t.Errorf("Expected vibium:element.uncheck after state check, got %v", calls)
+	}
+}
+
+// TestElement_Type_SendsVibiumElementType verifies Element.Type sends vibium:element.type.
+func TestElement_Type_SendsVibiumElementType(t *testing.T) {
+	mock := newMockTransport()
+
+	client := NewBiDiClient(mock)
+	elem := NewElement(client, "ctx-123", "input[name='email']", ElementInfo{
+		Tag: "input",
+	})
+
+	ctx := context.Background()
+	err := elem.Type(ctx, "test@example.com", nil)
+	if err != nil {
+		t.Fatalf("Type failed: %v", err)
+	}
+
+	calls := mock.getCalls()
+	found := false
+	for _, call := range calls {
+		if call.Method == "vibium:element.type" {
+			found = true
+			params, ok := call.Params.(map[string]interface{})
+			if ok {
+				if params["text"] != "test@example.com" {
+					t.Errorf("Expected text 'test@example.com', got %v", params["text"])
+				}
+			}
+			break
+		}
+	}
+
+	if !found {
+		t.Errorf("Expected vibium:element.type call, got: %v", calls)
+	}
+}
+
+// TestElement_Type_WithDelay verifies that Delay checks and focuses the element
+// once, then types one vibium:keyboard.type per character.
+func TestElement_Type_WithDelay(t *testing.T) {
+	mock := newMockTransport()
+	client := NewBiDiClient(mock)
+
+	elem := NewElement(client, "ctx-123", "#search", ElementInfo{})
+
+	ctx := context.Background()
+	err := elem.Type(ctx, "héy", &ActionOptions{Delay: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Type failed: %v", err)
+	}
+
+	calls := mock.getCalls()
+	if len(calls) != 5 {
+		t.Fatalf("Expected 5 calls (trial, focus, one per character), got %d: %v", len(calls), calls)
+	}
+
+	trial := calls[0].Params.(map[string]interface{})
+	if calls[0].Method != "vibium:element.type" || trial["trial"] != true || trial["text"] != "héy" {
+		t.Errorf("Expected a type trial first, got %s %v", calls[0].Method, trial)
+	}
+	if calls[1].Method != "vibium:element.focus" {
+		t.Errorf("Expected vibium:element.focus, got %s", calls[1].Method)
+	}
+
+	want := []string{"h", "é", "y"}
+	for i, call := range calls[2:] {
+		if call.Method != "vibium:keyboard.type" {
+			t.Errorf("Call %d: expected vibium:keyboard.type, got %s", i, call.Method)
+		}
+		params := call.Params.(map[string]interface{})
+		if params["text"] != want[i] {
+			t.Errorf("Call %d: expected text %q, got %v", i, want[i], params["text"])
+		}
+	}
+}
+
+// TestElement_Type_WithDelayNotEditable verifies nothing is typed when the checks fail.
+func TestElement_Type_WithDelayNotEditable(t *testing.T) {
+	mock := newMockTransport()
+	mock.err = errors.New("element is not editable")
+
+	client := NewBiDiClient(mock)
+	elem := NewElement(client, "ctx-123", "#readonly", ElementInfo{})
+
+	if err := elem.Type(context.Background(), "abc", &ActionOptions{Delay: time.Millisecond}); err == nil {
+		t.Fatal("Expected Type to fail")
+	}
+	if calls := mock.getCalls(); len(calls) != 1 {
+		t.Errorf("Expected only the type trial, got %v", calls)
+	}
+}
+
+// TestElement_Highlight_PersistentLabel verifies Highlight evaluates the overlay script with its options.
+func TestElement_Highlight_PersistentLabel(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"value": true}`))
+
+	client := NewBiDiClient(mock)
+	elem := NewElement(client, "ctx-123", "#submit", ElementInfo{})
+
+	ctx := context.Background()
+	err := elem.Highlight(ctx, &HighlightOptions{Color: "blue", Label: "target", Persistent: true})
+	if err != nil {
+		t.Fatalf("Highlight failed: %v", err)
+	}
+	if err := elem.Unhighlight(ctx); err != nil {
+		t.Fatalf("Unhighlight failed: %v", err)
+	}
+
+	calls := mock.getCalls()
+	if len(calls) != 2 {
+		t.Fatalf("Expected 2 calls, got %d", len(calls))
+	}
+
+	params := calls[0].Params.(map[string]interface{})
+	if calls[0].Method != "vibium:element.eval" {
+		t.Errorf("Expected vibium:element.eval, got %s", calls[0].Method)
+	}
+	if params["selector"] != "#submit" {
+		t.Errorf("Expected selector #submit, got %v", params["selector"])
+	}
+	args, ok := params["args"].([]interface{})
+	if !ok || len(args) != 3 {
+		t.Fatalf("Expected 3 args, got %v", params["args"])
+	}
+	for i, want := range []interface{}{"blue", "target", 0} {
+		arg, _ := args[i].(map[string]interface{})
+		if arg["value"] != want {
+			t.Errorf("Arg %d: expected value %v, got %v", i, want, args[i])
+		}
+	}
+}
+
+// TestElement_LongPress verifies a long press is sent as a touch pointer sequence at the element center.
+func TestElement_LongPress(t *testing.T) {
+	mock := newMockTransport()
+	mock.setMethodResponse("vibium:element.bounds", json.RawMessage(`{"x":10,"y":20,"width":100,"height":40}`))
+
+	client := NewBiDiClient(mock)
+	elem := NewElement(client, "ctx-123", "#item", ElementInfo{})
+
+	if err := elem.LongPress(context.Background(), 0, nil); err != nil {
+		t.Fatalf("LongPress failed: %v", err)
+	}
+
+	calls := mock.getCalls()
+	if calls[0].Method != "vibium:element.tap" || calls[0].Params.(map[string]interface{})["trial"] != true {
+		t.Errorf("Expected a tap trial before the gesture, got %v", calls[0])
+	}
+	last := calls[len(calls)-1]
+	if last.Method != "input.performActions" {
+		t.Fatalf("Expected input.performActions, got %v", calls)
+	}
+	params := last.Params.(map[string]interface{})
+	source := params["actions"].([]interface{})[0].(map[string]interface{})
+	if source["parameters"].(map[string]interface{})["pointerType"] != "touch" {
+		t.Errorf("Expected touch pointer, got %v", source["parameters"])
+	}
+	actions := source["actions"].([]map[string]interface{})
+	if len(actions) != 4 || actions[0]["x"] != 60 || actions[0]["y"] != 40 {
+		t.Fatalf("Expected move to (60, 40) then press/hold/release, got %v", actions)
+	}
+	if actions[2]["type"] != "pause" || actions[2]["duration"] != DefaultLongPressDuration.Milliseconds() {
+		t.Errorf("Expected default hold pause, got %v", actions[2])
+	}
+}
+
+// TestElement_DoubleTapNotActionable verifies no gesture is sent when the checks fail.
+func TestElement_DoubleTapNotActionable(t *testing.T) {
+	mock := newMockTransport()
+	mock.err = errors.New("element is not enabled")
+
+	client := NewBiDiClient(mock)
+	elem := NewElement(client, "ctx-123", "#disabled", ElementInfo{})
+
+	if err := elem.DoubleTap(context.Background(), nil); err == nil {
+		t.Fatal("Expected DoubleTap to fail")
+	}
+	calls := mock.getCalls()
+	if len(calls) != 1 || calls[0].Method != "vibium:element.tap" {
+		t.Errorf("Expected only the tap trial, got %v", calls)
+	}
+}
+
+// TestElement_ActionPosition verifies ActionOptions.Position is forwarded for pointer actions.
+func TestElement_ActionPosition(t *testing.T) {
+	mock := newMockTransport()
+	client := NewBiDiClient(mock)
+	elem := NewElement(client, "ctx-123", "#slider", ElementInfo{})
+	ctx := context.Background()
+	opts := &ActionOptions{Position: &Point{X: 150, Y: 8}}
+
+	if err := elem.Click(ctx, opts); err != nil {
+		t.Fatalf("Click failed: %v", err)
+	}
+	if err := elem.DblClick(ctx, opts); err != nil {
+		t.Fatalf("DblClick failed: %v", err)
+	}
+	if err := elem.Hover(ctx, opts); err != nil {
+		t.Fatalf("Hover failed: %v", err)
+	}
+	if err := elem.Click(ctx, nil); err != nil {
+		t.Fatalf("Click failed: %v", err)
+	}
+
+	calls := mock.getCalls()
+	for _, call := range calls[:3] {
+		pos, ok := call.Params.(map[string]interface{})["position"].(map[string]interface{})
+		if !ok || pos["x"] != 150.0 || pos["y"] != 8.0 {
+			t.Errorf("%s: expected position {150 8}, got %v", call.Method, call.Params)
+		}
+	}
+	if _, ok := calls[3].Params.(map[string]interface{})["position"]; ok {
+		t.Error("Expected no position when not set")
+	}
+}
+
+// TestElement_ActionForce verifies ActionOptions.Force is forwarded to action commands.
+func TestElement_ActionForce(t *testing.T) {
+	mock := newMockTransport()
+	client := NewBiDiClient(mock)
+	elem := NewElement(client, "ctx-123", "#covered", ElementInfo{})
+	ctx := context.Background()
+	opts := &ActionOptions{Force: true}
+
+	if err := elem.Click(ctx, opts); err != nil {
+		t.Fatalf("Click failed: %v", err)
+	}
+	if err := elem.Fill(ctx, "hello", opts); err != nil {
+		t.Fatalf("Fill failed: %v", err)
+	}
+	if err := elem.Type(ctx, "hi", &ActionOptions{Force: true, Delay: time.Millisecond}); err != nil {
+		t.Fatalf("Type failed: %v", err)
+	}
+	if err := elem.Check(ctx, nil); err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+
+	// Type with a Delay checks the element once, then types through the keyboard
+	calls := mock.getCalls()
+	if len(calls) != 7 {
+		t.Fatalf("Expected 7 calls, got %d", len(calls))
+	}
+	for _, call := range calls[:6] {
+		if strings.HasPrefix(call.Method, "vibium:keyboard.") {
+			continue
+		}
+		if call.Params.(map[string]interface{})["force"] != true {
+			t.Errorf("%s: expected force=true, got %v", call.Method, call.Params)
+		}
+	}
+	if _, ok := calls[6].Params.(map[string]interface{})["force"]; ok {
+		t.Error("Expected no force param when not set")
+	}
+}
+
+// TestElement_ActionTrial verifies trial actions only send the checks, once.
+func TestElement_ActionTrial(t *testing.T) {
+	mock := newMockTransport()
+	client := NewBiDiClient(mock)
+	elem := NewElement(client, "ctx-123", "#submit", ElementInfo{})
+	ctx := context.Background()
+
+	if err := elem.Type(ctx, "abc", &ActionOptions{Trial: true, Delay: time.Second}); err != nil {
+		t.Fatalf("Type failed: %v", err)
+	}
+	if err := elem.LongPress(ctx, 0, &ActionOptions{Trial: true}); err != nil {
+		t.Fatalf("LongPress failed: %v", err)
+	}
+
+	calls := mock.getCalls()
+	if len(calls) != 2 {
+		t.Fatalf("Expected 2 calls, got %d: %v", len(calls), calls)
+	}
+	if calls[0].Method != "vibium:element.type" || calls[1].Method != "vibium:element.tap" {
+		t.Errorf("Unexpected methods: %s, %s", calls[0].Method, calls[1].Method)
+	}
+	for _, call := range calls {
+		if call.Params.(map[string]interface{})["trial"] != true {
+			t.Errorf("%s: expected trial=true, got %v", call.Method, call.Params)
+		}
+	}
+}
+
+// TestElement_FillMasked verifies masked fills type per character and verify the value.
+func TestElement_FillMasked(t *testing.T) {
+	mock := newMockTransport()
+	mock.setMethodResponse("vibium:element.value", json.RawMessage(`{"value": "(555) 123-4567"}`))
+	client := NewBiDiClient(mock)
+	elem := NewElement(client, "ctx-123", "#phone", ElementInfo{})
+	ctx := context.Background()
+
+	if err := elem.Fill(ctx, "5551234567", &ActionOptions{Masked: true}); err != nil {
+		t.Fatalf("Fill failed: %v", err)
+	}
+
+	var types, fills int
+	for _, call := range mock.getCalls() {
+		switch call.Method {
+		case "vibium:keyboard.type":
+			types++
+		case "vibium:element.fill":
+			fills++
+		}
+	}
+	if types != 10 || fills != 0 {
+		t.Errorf("Expected 10 per-character type calls and no fill, got %d types and %d fills", types, fills)
+	}
+
+	mock.setMethodResponse("vibium:element.value", json.RawMessage(`{"value": "(555) 123-45"}`))
+	before := len(mock.getCalls())
+	if err := elem.Fill(ctx, "5551234567", &ActionOptions{Masked: true}); err == nil {
+		t.Fatal("Expected error when the masked value does not match")
+	}
+	var clears int
+	for _, call := range mock.getCalls()[before:] {
+		if call.Method == "vibium:element.clear" {
+			clears++
+		}
+	}
+	if clears != 2 {
+		t.Errorf("Expected one retry (2 clears), got %d", clears)
+	}
+}
+
+// TestMaskedValueMatches verifies mask characters and fixed mask text are ignored.
+func TestMaskedValueMatches(t *testing.T) {
+	tests := []struct {
+		got, want string
+		match     bool
+	}{
+		{"(555) 123-4567", "5551234567", true},
+		{"+1 (555) 123-4567", "5551234567", true},
+		{"US 94105-1234", "941051234", true},
+		{"1,234.50 USD", "1234.50", true},
+		{"4111 1111 1111 1111", "4111111111111111", true},
+		{"(555) 123-45", "5551234567", false},
+		{"+1 (555) 123-4576", "5551234567", false},
+		{"555-1234", "", false},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		if got := maskedValueMatches(tt.got, tt.want); got != tt.match {
+			t.Errorf("maskedValueMatches(%q, %q) = %v, want %v", tt.got, tt.want, got, tt.match)
+		}
+	}
+}
+
+// TestElement_SelectOption_NoMatch verifies unmatched selections fail with the available labels.
+func TestElement_SelectOption_NoMatch(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{}`))
+	mock.setMethodResponse("vibium:element.eval", json.RawMessage(`{"value": {"matched": false, "available": ["Red", "Green"]}}`))
+
+	client := NewBiDiClient(mock)
+	elem := NewElement(client, "ctx-123", "#color", ElementInfo{})
+	ctx := context.Background()
+
+	err := elem.SelectOption(ctx, SelectOptionValues{Labels: []string{"Blue"}}, nil)
+	var notFound *OptionNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("Expected *OptionNotFoundError, got %v", err)
+	}
+	if len(notFound.Available) != 2 || !strings.Contains(err.Error(), `"Green"`) {
+		t.Errorf("Expected available labels in error, got %v", err)
+	}
+	for _, c := range mock.getCalls() {
+		if c.Method == "vibium:element.selectOption" {
+			t.Fatal("selectOption should not be sent when nothing matches")
+		}
+	}
+
+	if err := elem.SelectOption(ctx, SelectOptionValues{Labels: []string{"Blue"}, AllowNoMatch: true}, nil); err != nil {
+		t.Fatalf("Expected AllowNoMatch to succeed, got %v", err)
+	}
+	calls := mock.getCalls()
+	if last := calls[len(calls)-1]; last.Method != "vibium:element.selectOption" {
+		t.Errorf("Expected selectOption to be sent with AllowNoMatch, got %s", last.Method)
+	}
+}
+
+// TestElement_Attributes verifies all attributes and dataset values are read in one call each.
+func TestElement_Attributes(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"value": {"id": "buy", "href": "/cart", "data-testid": "buy-btn"}}`))
+
+	client := NewBiDiClient(mock)
+	elem := NewElement(client, "ctx-123", "a.buy", ElementInfo{})
+
+	attrs, err := elem.Attributes(context.Background())
+	if err != nil {
+		t.Fatalf("Attributes failed: %v", err)
+	}
+	if len(attrs) != 3 || attrs["href"] != "/cart" || attrs["data-testid"] != "buy-btn" {
+		t.Errorf("Unexpected attributes: %v", attrs)
+	}
+
+	mock.setResponse(json.RawMessage(`{"value": {}}`))
+	data, err := elem.Dataset(context.Background())
+	if err != nil {
+		t.Fatalf("Dataset failed: %v", err)
+	}
+	if data == nil || len(data) != 0 {
+		t.Errorf("Expected empty non-nil dataset, got %#v", data)
+	}
+
+	calls := mock.getCalls()
+	if len(calls) != 2 || calls[0].Method != "vibium:element.eval" || calls[1].Method != "vibium:element.eval" {
+		t.Fatalf("Expected two vibium:element.eval calls, got %v", calls)
+	}
+}
+
+// TestElement_ComputedStyles verifies the requested properties are passed to getComputedStyle.
+func TestElement_ComputedStyles(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"value": {"color": "rgb(255, 0, 0)", "font-size": "14px"}}`))
+
+	client := NewBiDiClient(mock)
+	elem := NewElement(client, "ctx-123", ".error", ElementInfo{})
+
+	styles, err := elem.ComputedStyles(context.Background(), []string{"color", "font-size", "display"})
+	if err != nil {
+		t.Fatalf("ComputedStyles failed: %v", err)
+	}
+	if styles["color"] != "rgb(255, 0, 0)" || styles["font-size"] != "14px" {
+		t.Errorf("Unexpected styles: %v", styles)
+	}
+	if v, ok := styles["display"]; !ok || v != "" {
+		t.Errorf("Expected missing property to map to empty string, got %q (present=%v)", v, ok)
+	}
+
+	calls := mock.getCalls()
+	if len(calls) != 1 || calls[0].Method != "vibium:element.eval" {
+		t.Fatalf("Expected one vibium:element.eval call, got %v", calls)
+	}
+	args, ok := calls[0].Params.(map[string]interface{})["args"].([]interface{})
+	if !ok || len(args) != 1 {
+		t.Fatalf("Expected properties as the single arg, got %v", args)
+	}
+
+	color, err := elem.ComputedStyle(context.Background(), "color")
+	if err != nil {
+		t.Fatalf("ComputedStyle failed: %v", err)
+	}
+	if color != "rgb(255, 0, 0)" {
+		t.Errorf("Expected rgb(255, 0, 0), got %q", color)
+	}
+}
+
+// TestPilot_ScrollTo verifies page scrolling passes coordinates as arguments.
+func TestPilot_ScrollTo(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"type":"success","result":{"type":"undefined"}}`))
+
+	client := NewBiDiClient(mock)
+	pilot := &Pilot{client: client, browsingContext: "ctx-123"}
+
+	ctx := context.Background()
+	if err := pilot.ScrollTo(ctx, 0, 1200); err != nil {
+		t.Fatalf("ScrollTo failed: %v", err)
+	}
+	if err := pilot.ScrollBy(ctx, 0, -300); err != nil {
+		t.Fatalf("ScrollBy failed: %v", err)
+	}
+
+	calls := mock.getCalls()
+	if len(calls) != 2 {
+		t.Fatalf("Expected 2 calls, got %d", len(calls))
+	}
+	for i, want := range []float64{1200, -300} {
+		params := calls[i].Params.(map[string]interface{})
+		args := params["arguments"].([]interface{})
+		y := args[1].(map[string]interface{})
+		if y["type"] != "number" || y["value"] != want {
+			t.Errorf("Call %d: expected y argument %v, got %v", i, want, y)
+		}
+	}
+}
+
+// TestElement_ScrollBy verifies the element's own container is scrolled.
+func TestElement_ScrollBy(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"value": null}`))
+
+	client := NewBiDiClient(mock)
+	elem := NewElement(client, "ctx-123", "#feed", ElementInfo{})
+
+	if err := elem.ScrollBy(context.Background(), 0, 500); err != nil {
+		t.Fatalf("ScrollBy failed: %v", err)
+	}
+
+	calls := mock.getCalls()
+	if len(calls) != 1 || calls[0].Method != "vibium:element.eval" {
+		t.Fatalf("Expected one vibium:element.eval call, got %v", calls)
+	}
+	params := calls[0].Params.(map[string]interface{})
+	if params["selector"] != "#feed" {
+		t.Errorf("Expected selector #feed, got %v", params["selector"])
+	}
+	args := params["args"].([]interface{})
+	dy, _ := args[1].(map[string]interface{})
+	if len(args) != 2 || dy["type"] != "number" || dy["value"] != 500.0 {
+		t.Errorf("Expected number args [0 500], got %v", args)
+	}
+}
+
+// TestElement_WaitForStable verifies stability requires two identical consecutive bounds samples.
+func TestElement_WaitForStable(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"x":10,"y":20,"width":100,"height":40}`))
+
+	client := NewBiDiClient(mock)
+	elem := NewElement(client, "ctx-123", "#panel", ElementInfo{})
+
+	if err := elem.WaitForStableInterval(context.Background(), time.Second, 5*time.Millisecond); err != nil {
+		t.Fatalf("WaitForStable failed: %v", err)
+	}
+	calls := mock.getCalls()
+	if len(calls) != 2 || calls[0].Method != "vibium:element.bounds" {
+		t.Errorf("Expected two vibium:element.bounds calls, got %v", calls)
 	}
 
-	if !found {
-		t.Errorf("Expected vibium:page.setContent call, got: %v", calls)
+	// An element whose bounds can never be read times out.
+	mock.err = errors.New("no such element")
+	err := elem.WaitForStableInterval(context.Background(), 30*time.Millisecond, 5*time.Millisecond)
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Errorf("Expected TimeoutError, got %v", err)
 	}
 }
 
-// TestPilotScroll_SendsVibiumPageScroll verifies Scroll sends correct method.
-func TestPilotScroll_SendsVibiumPageScroll(t *testing.T) {
+// TestEvaluateAs verifies evaluation results are decoded into the requested type.
+func TestEvaluateAs(t *testing.T) {
 	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"type":"success","result":{"type":"array","value":[
+		{"type":"string","value":"https://a.example"},
+		{"type":"string","value":"https://b.example"}
+	]}}`))
 
 	client := NewBiDiClient(mock)
-	pilot := &Pilot{
-		client:          client,
-		browsingContext: "ctx-123",
-	}
-
+	pilot := &Pilot{client: client, browsingContext: "ctx-123"}
 	ctx := context.Background()
-	err := pilot.Scroll(ctx, "down", 500, nil)
+
+	hrefs, err := EvaluateAs[[]string](ctx, pilot, "Array.from(document.links, a => a.href)")
 	if err != nil {
-		t.Fatalf("Scroll failed: %v", err)
+		t.Fatalf("EvaluateAs failed: %v", err)
+	}
+	if len(hrefs) != 2 || hrefs[0] != "https://a.example" || hrefs[1] != "https://b.example" {
+		t.Errorf("Unexpected hrefs: %v", hrefs)
 	}
 
-	calls := mock.getCalls()
-	found := false
-	for _, call := range calls {
-		if call.Method == "vibium:page.scroll" {
-			found = true
-			params, ok := call.Params.(map[string]interface{})
-			if ok {
-				if params["direction"] != "down" {
-					t.Errorf("Expected direction 'down', got %v", params["direction"])
-				}
-				if params["amount"] != 500 {
-					t.Errorf("Expected amount 500, got %v", params["amount"])
-				}
-			}
-			break
-		}
+	if _, err := EvaluateAs[int](ctx, pilot, "document.links"); err == nil {
+		t.Error("Expected decode error for mismatched type")
 	}
 
-	if !found {
-		t.Errorf("Expected vibium:page.scroll call, got: %v", calls)
+	mock.setResponse(json.RawMessage(`{"type":"success","result":{"type":"null"}}`))
+	count, err := EvaluateAs[int](ctx, pilot, "null")
+	if err != nil || count != 0 {
+		t.Errorf("Expected zero value for null, got %v (err %v)", count, err)
 	}
 }
 
-// TestPilotEmulateMedia_SendsVibiumPageEmulateMedia verifies EmulateMedia sends correct method.
-func TestPilotEmulateMedia_SendsVibiumPageEmulateMedia(t *testing.T) {
+// TestElementEvalAs verifies element eval arguments are wrapped as BiDi values and results decoded.
+func TestElementEvalAs(t *testing.T) {
 	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"value": true}`))
 
 	client := NewBiDiClient(mock)
-	pilot := &Pilot{
-		client:          client,
-		browsingContext: "ctx-123",
-	}
+	elem := NewElement(client, "ctx-123", "#tab", ElementInfo{})
 
-	ctx := context.Background()
-	err := pilot.EmulateMedia(ctx, EmulateMediaOptions{ColorScheme: "dark"})
+	active, err := ElementEvalAs[bool](context.Background(), elem, "(e, cls) => e.classList.contains(cls)", "active")
 	if err != nil {
-		t.Fatalf("EmulateMedia failed: %v", err)
+		t.Fatalf("ElementEvalAs failed: %v", err)
 	}
-
-	calls := mock.getCalls()
-	found := false
-	for _, call := range calls {
-		if call.Method == "vibium:page.emulateMedia" {
-			found = true
-			break
-		}
+	if !active {
+		t.Error("Expected true")
 	}
 
-	if !found {
-		t.Errorf("Expected vibium:page.emulateMedia call, got: %v", calls)
+	params := mock.getCalls()[0].Params.(map[string]interface{})
+	args := params["args"].([]interface{})
+	arg, _ := args[0].(map[string]interface{})
+	if len(args) != 1 || arg["type"] != "string" || arg["value"] != "active" {
+		t.Errorf("Expected string local value arg, got %v", args)
 	}
 }
 
-// TestElement_Click_SendsVibiumElementClick verifies Element.Click sends vibium:element.click.
-func TestElement_Click_SendsVibiumElementClick(t *testing.T) {
+// TestPilot_EvaluateWithArgs verifies arguments are sent as BiDi local values.
+func TestPilot_EvaluateWithArgs(t *testing.T) {
 	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"type":"success","result":{"type":"string","value":"it's ok"}}`))
 
-	client := NewBiDiClient(mock)
-	elem := NewElement(client, "ctx-123", "button.submit", ElementInfo{
-		Tag:  "button",
-		Text: "Submit",
-		Box:  BoundingBox{X: 100, Y: 200, Width: 80, Height: 30},
-	})
+	pilot := &Pilot{
+		client:          NewBiDiClient(mock),
+		browsingContext: "ctx-123",
+	}
 
-	ctx := context.Background()
-	err := elem.Click(ctx, nil)
+	result, err := pilot.EvaluateWithArgs(context.Background(), "(id, opts) => id",
+		`it's "quoted"`, map[string]interface{}{"n": 2, "tags": []string{"a"}})
 	if err != nil {
-		t.Fatalf("Click failed: %v", err)
+		t.Fatalf("EvaluateWithArgs failed: %v", err)
+	}
+	if result != "it's ok" {
+		t.Errorf("Expected deserialized string result, got %v", result)
 	}
 
 	calls := mock.getCalls()
-	found := false
-	for _, call := range calls {
-		if call.Method == "vibium:element.click" {
-			found = true
-			params, ok := call.Params.(map[string]interface{})
-			if ok && params["selector"] != "button.submit" {
-				t.Errorf("Expected selector 'button.submit', got %v", params["selector"])
-			}
-			break
-		}
+	if len(calls) != 1 || calls[0].Method != "script.callFunction" {
+		t.Fatalf("Expected one script.callFunction call, got %v", calls)
 	}
 
-	if !found {
-		t.Errorf("Expected vibium:element.click call, got: %v", calls)
+	params := calls[0].Params.(map[string]interface{})
+	data, _ := json.Marshal(params["arguments"])
+	want := `[{"type":"string","value":"it's \"quoted\""},` +
+		`{"type":"object","value":[["n",{"type":"number","value":2}],["tags",{"type":"array","value":[{"type":"string","value":"a"}]}]]}]`
+	if string(data) != want {
+		t.Errorf("Arguments mismatch:\n got: %s\nwant: %s", data, want)
 	}
 }
 
-// TestElement_Type_SendsVibiumElementType verifies Element.Type sends vibium:element.type.
-func TestElement_Type_SendsVibiumElementType(t *testing.T) {
+// TestKeyboard_TypeWithOptions verifies that Type sends the text in one
+// command and TypeWithOptions with a Delay sends one per character.
+func TestKeyboard_TypeWithOptions(t *testing.T) {
 	mock := newMockTransport()
-
-	client := NewBiDiClient(mock)
-	elem := NewElement(client, "ctx-123", "input[name='email']", ElementInfo{
-		Tag: "input",
-	})
+	kb := NewKeyboard(NewBiDiClient(mock), "ctx-123")
 
 	ctx := context.Background()
-	err := elem.Type(ctx, "test@example.com", nil)
-	if err != nil {
+	if err := kb.Type(ctx, "hi"); err != nil {
 		t.Fatalf("Type failed: %v", err)
 	}
+	if err := kb.TypeWithOptions(ctx, "hi", &TypeOptions{Delay: time.Millisecond}); err != nil {
+		t.Fatalf("TypeWithOptions failed: %v", err)
+	}
 
-	calls := mock.getCalls()
-	found := false
-	for _, call := range calls {
-		if call.Method == "vibium:element.type" {
-			found = true
-			params, ok := call.Params.(map[string]interface{})
-			if ok {
-				if params["text"] != "test@example.com" {
-					t.Errorf("Expected text 'test@example.com', got %v", params["text"])
-				}
-			}
-			break
+	var texts []interface{}
+	for _, call := range mock.getCalls() {
+		if call.Method != "vibium:keyboard.type" {
+			t.Errorf("Expected vibium:keyboard.type, got %s", call.Method)
 		}
+		texts = append(texts, call.Params.(map[string]interface{})["text"])
 	}
-
-	if !found {
-		t.Errorf("Expected vibium:element.type call, got: %v", calls)
+	if len(texts) != 3 || texts[0] != "hi" || texts[1] != "h" || texts[2] != "i" {
+		t.Errorf("Expected texts [hi h i], got %v", texts)
 	}
 }
 
@@ -573,3 +2007,326 @@ func TestVibiumMethodPrefix_DocumentedMethods(t *testing.T) {
 
 	// This is a documentation test - it always passes but serves as a reference
 }
+
+// TestBiDiClient_SlowMo verifies SlowMo delays actions but not queries.
+func TestBiDiClient_SlowMo(t *testing.T) {
+	mock := newMockTransport()
+	client := NewBiDiClient(mock)
+	client.SetSlowMo(20 * time.Millisecond)
+
+	ctx := context.Background()
+
+	start := time.Now()
+	if _, err := client.Send(ctx, "vibium:element.text", nil); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= 20*time.Millisecond {
+		t.Errorf("Expected query to skip SlowMo, took %v", elapsed)
+	}
+
+	start = time.Now()
+	if _, err := client.Send(ctx, "vibium:element.click", nil); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Expected click to wait for SlowMo, took %v", elapsed)
+	}
+
+	start = time.Now()
+	if _, err := client.Send(ctx, "input.performActions", nil); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Expected touch gesture to wait for SlowMo, took %v", elapsed)
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := client.Send(cctx, "vibium:mouse.move", nil); err == nil {
+		t.Error("Expected canceled context to abort SlowMo wait")
+	}
+}
+
+// TestBiDiClient_CommandHook verifies the hook sees every command with its error.
+func TestBiDiClient_CommandHook(t *testing.T) {
+	mock := newMockTransport()
+	client := NewBiDiClient(mock)
+
+	type observed struct {
+		method string
+		err    error
+	}
+	var got []observed
+	client.SetCommandHook(func(method string, dur time.Duration, err error) {
+		if dur < 0 {
+			t.Errorf("Expected non-negative duration, got %v", dur)
+		}
+		got = append(got, observed{method, err})
+	})
+
+	ctx := context.Background()
+	if _, err := client.Send(ctx, "vibium:page.find", nil); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	mock.err = errors.New("boom")
+	if _, err := client.Send(ctx, "vibium:element.click", nil); err == nil {
+		t.Fatal("Expected Send to fail")
+	}
+
+	if len(got) != 2 || got[0].method != "vibium:page.find" || got[0].err != nil ||
+		got[1].method != "vibium:element.click" || got[1].err == nil {
+		t.Errorf("Unexpected hook calls: %v", got)
+	}
+
+	client.SetCommandHook(nil)
+	_, _ = client.Send(ctx, "vibium:page.find", nil)
+	if len(got) != 2 {
+		t.Errorf("Expected no hook calls after removal, got %d", len(got))
+	}
+}
+
+// recordingTracer is a Tracer that records the spans it starts.
+type recordingTracer struct {
+	spans []*recordedSpan
+}
+
+type recordedSpan struct {
+	name  string
+	attrs map[string]string
+	ended bool
+	err   error
+}
+
+func (r *recordingTracer) StartSpan(ctx context.Context, name string, attrs map[string]string) (context.Context, Span) {
+	span := &recordedSpan{name: name, attrs: attrs}
+	r.spans = append(r.spans, span)
+	return ctx, span
+}
+
+func (s *recordedSpan) End(err error) {
+	s.ended = true
+	s.err = err
+}
+
+// TestBiDiClient_Tracer verifies that every command gets a span with the
+// selector and URL attributes and the command's error.
+func TestBiDiClient_Tracer(t *testing.T) {
+	mock := newMockTransport()
+	client := NewBiDiClient(mock)
+	tracer := &recordingTracer{}
+	client.SetTracer(tracer)
+
+	ctx := context.Background()
+	_, _ = client.Send(ctx, "browsingContext.navigate", map[string]interface{}{"context": "ctx-1", "url": "https://example.com"})
+	mock.err = errors.New("boom")
+	_, _ = client.Send(ctx, "vibium:element.click", map[string]interface{}{"selector": "#submit"})
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(tracer.spans))
+	}
+	nav, click := tracer.spans[0], tracer.spans[1]
+	if nav.name != "browsingContext.navigate" || nav.attrs[SpanAttrURL] != "https://example.com" ||
+		nav.attrs[SpanAttrContext] != "ctx-1" || !nav.ended || nav.err != nil {
+		t.Errorf("Unexpected navigate span: %+v", nav)
+	}
+	if click.attrs[SpanAttrSelector] != "#submit" || click.attrs[SpanAttrCommand] != "vibium:element.click" ||
+		!click.ended || click.err == nil {
+		t.Errorf("Unexpected click span: %+v", click)
+	}
+
+	client.SetTracer(nil)
+	_, _ = client.Send(ctx, "vibium:page.find", nil)
+	if len(tracer.spans) != 2 {
+		t.Errorf("Expected no spans after removal, got %d", len(tracer.spans))
+	}
+}
+
+// TestWithPage_ClosesPageOnPanic verifies WithPage closes the page even if fn panics.
+func TestWithPage_ClosesPageOnPanic(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"context":"page-2"}`))
+
+	pilot := &Pilot{
+		client:          NewBiDiClient(mock),
+		browsingContext: "ctx-123",
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("Expected panic to propagate")
+			}
+		}()
+		_ = WithPage(context.Background(), pilot, func(page *Pilot) error {
+			panic("boom")
+		})
+	}()
+
+	calls := mock.getCalls()
+	if len(calls) != 2 {
+		t.Fatalf("Expected create and close calls, got %v", calls)
+	}
+	if calls[1].Method != "browsingContext.close" {
+		t.Errorf("Expected browsingContext.close, got %s", calls[1].Method)
+	}
+	params := calls[1].Params.(map[string]interface{})
+	if params["context"] != "page-2" {
+		t.Errorf("Expected page-2 to be closed, got %v", params["context"])
+	}
+}
+
+// TestPilotCloseAll_ClosesSpawnedPagesAndContexts verifies CloseAll closes
+// pages and contexts created from the page, including nested pages, and
+// then the page itself.
+func TestPilotCloseAll_ClosesSpawnedPagesAndContexts(t *testing.T) {
+	mock := newMockTransport()
+	mock.setMethodResponse("browser.createUserContext", json.RawMessage(`{"userContext":"uc-1"}`))
+
+	pilot := &Pilot{
+		client:          NewBiDiClient(mock),
+		browsingContext: "ctx-123",
+	}
+	ctx := context.Background()
+
+	mock.setMethodResponse("browsingContext.create", json.RawMessage(`{"context":"page-2"}`))
+	page, err := pilot.NewPage(ctx)
+	if err != nil {
+		t.Fatalf("NewPage failed: %v", err)
+	}
+	mock.setMethodResponse("browsingContext.create", json.RawMessage(`{"context":"page-3"}`))
+	if _, err := page.NewPage(ctx); err != nil {
+		t.Fatalf("NewPage failed: %v", err)
+	}
+	if _, err := pilot.NewContext(ctx); err != nil {
+		t.Fatalf("NewContext failed: %v", err)
+	}
+
+	if err := pilot.CloseAll(ctx); err != nil {
+		t.Fatalf("CloseAll failed: %v", err)
+	}
+
+	var closed []string
+	for _, call := range mock.getCalls() {
+		params, _ := call.Params.(map[string]interface{})
+		switch call.Method {
+		case "browsingContext.close":
+			closed = append(closed, params["context"].(string))
+		case "browser.removeUserContext":
+			closed = append(closed, params["userContext"].(string))
+		}
+	}
+	want := []string{"page-3", "page-2", "uc-1", "ctx-123"}
+	if !slices.Equal(closed, want) {
+		t.Errorf("Expected close order %v, got %v", want, closed)
+	}
+
+	// Everything is closed and untracked, so a second call sends nothing.
+	before := len(mock.getCalls())
+	if err := pilot.CloseAll(ctx); err != nil {
+		t.Fatalf("Second CloseAll failed: %v", err)
+	}
+	if after := len(mock.getCalls()); after != before {
+		t.Errorf("Expected no commands from second CloseAll, got %d", after-before)
+	}
+}
+
+// TestPilotQuit_ClosesSpawnedPages verifies Quit closes pages created with
+// NewPage, skipping pages that were already closed.
+func TestPilotQuit_ClosesSpawnedPages(t *testing.T) {
+	mock := newMockTransport()
+	pilot := &Pilot{
+		client:          NewBiDiClient(mock),
+		browsingContext: "ctx-123",
+	}
+	ctx := context.Background()
+
+	mock.setMethodResponse("browsingContext.create", json.RawMessage(`{"context":"page-2"}`))
+	if _, err := pilot.NewPage(ctx); err != nil {
+		t.Fatalf("NewPage failed: %v", err)
+	}
+	mock.setMethodResponse("browsingContext.create", json.RawMessage(`{"context":"page-3"}`))
+	done, err := pilot.NewPage(ctx)
+	if err != nil {
+		t.Fatalf("NewPage failed: %v", err)
+	}
+	if err := done.Close(ctx); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if err := pilot.Quit(ctx); err != nil {
+		t.Fatalf("Quit failed: %v", err)
+	}
+
+	var closed []string
+	for _, call := range mock.getCalls() {
+		if call.Method == "browsingContext.close" {
+			closed = append(closed, call.Params.(map[string]interface{})["context"].(string))
+		}
+	}
+	if !slices.Equal(closed, []string{"page-3", "page-2"}) {
+		t.Errorf("Expected page-3 closed once and page-2 closed by Quit, got %v", closed)
+	}
+}
+
+// TestPilotSetDefaultTimeout verifies that Find, element actions, and new
+// pages use the timeout set with SetDefaultTimeout when options omit one.
+func TestPilotSetDefaultTimeout(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"tag":"button","text":"Go"}`))
+	pilot := &Pilot{
+		client:          NewBiDiClient(mock),
+		browsingContext: "ctx-123",
+	}
+	ctx := context.Background()
+
+	if got := pilot.DefaultTimeout(); got != DefaultTimeout {
+		t.Errorf("Expected DefaultTimeout before it is set, got %v", got)
+	}
+	pilot.SetDefaultTimeout(10 * time.Second)
+
+	lastTimeout := func(method string) interface{} {
+		calls := mock.getCalls()
+		for i := len(calls) - 1; i >= 0; i-- {
+			if calls[i].Method == method {
+				return calls[i].Params.(map[string]interface{})["timeout"]
+			}
+		}
+		t.Fatalf("No %s call", method)
+		return nil
+	}
+
+	elem, err := pilot.Find(ctx, "button", nil)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if got := lastTimeout("vibium:page.find"); got != int64(10000) {
+		t.Errorf("Expected find timeout 10000, got %v", got)
+	}
+
+	if err := elem.Click(ctx, nil); err != nil {
+		t.Fatalf("Click failed: %v", err)
+	}
+	if got := lastTimeout("vibium:element.click"); got != int64(10000) {
+		t.Errorf("Expected click timeout 10000, got %v", got)
+	}
+	if err := elem.Click(ctx, &ActionOptions{Timeout: time.Second}); err != nil {
+		t.Fatalf("Click failed: %v", err)
+	}
+	if got := lastTimeout("vibium:element.click"); got != int64(1000) {
+		t.Errorf("Expected explicit click timeout 1000, got %v", got)
+	}
+
+	mock.setMethodResponse("browsingContext.create", json.RawMessage(`{"context":"page-2"}`))
+	page, err := pilot.NewPage(ctx)
+	if err != nil {
+		t.Fatalf("NewPage failed: %v", err)
+	}
+	if got := page.DefaultTimeout(); got != 10*time.Second {
+		t.Errorf("Expected new page to inherit 10s, got %v", got)
+	}
+
+	pilot.SetDefaultTimeout(0)
+	if got := pilot.DefaultTimeout(); got != DefaultTimeout {
+		t.Errorf("Expected SetDefaultTimeout(0) to restore DefaultTimeout, got %v", got)
+	}
+}
@@ -33,3 +33,46 @@ func (c *Client) SetCPUThrottlingRate(ctx context.Context, rate int) error {
 func (c *Client) ClearCPUThrottling(ctx context.Context) error {
 	return c.SetCPUThrottlingRate(ctx, CPUNoThrottle)
 }
+
+// SetTimezoneOverride overrides the timezone (IANA ID, e.g. "America/New_York").
+// An empty timezone restores the host timezone.
+func (c *Client) SetTimezoneOverride(ctx context.Context, timezone string) error {
+	_, err := c.Send(ctx, EmulationSetTimezoneOverride, map[string]interface{}{
+		"timezoneId": timezone,
+	})
+	if err != nil {
+		return fmt.Errorf("cdp: failed to set timezone override: %w", err)
+	}
+	return nil
+}
+
+// SetLocaleOverride overrides the ICU locale (e.g. "de-DE").
+// An empty locale restores the host locale.
+func (c *Client) SetLocaleOverride(ctx context.Context, locale string) error {
+	params := map[string]interface{}{}
+	if locale != "" {
+		params["locale"] = locale
+	}
+	_, err := c.Send(ctx, EmulationSetLocaleOverride, params)
+	if err != nil {
+		return fmt.Errorf("cdp: failed to set locale override: %w", err)
+	}
+	return nil
+}
+
+// SetUserAgentOverride overrides the User-Agent request header and, if
+// acceptLanguage is set, the Accept-Language header and navigator.languages.
+// An empty userAgent clears the override.
+func (c *Client) SetUserAgentOverride(ctx context.Context, userAgent, acceptLanguage string) error {
+	params := map[string]interface{}{
+		"userAgent": userAgent,
+	}
+	if acceptLanguage != "" {
+		params["acceptLanguage"] = acceptLanguage
+	}
+	_, err := c.Send(ctx, NetworkSetUserAgentOverride, params)
+	if err != nil {
+		return fmt.Errorf("cdp: failed to set user agent override: %w", err)
+	}
+	return nil
+}
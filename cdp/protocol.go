@@ -44,13 +44,16 @@ const (
 	HeapProfilerAddHeapSnapshotChunk = "HeapProfiler.addHeapSnapshotChunk"
 
 	// Network domain
-	NetworkEnable            = "Network.enable"
-	NetworkDisable           = "Network.disable"
-	NetworkGetResponseBody   = "Network.getResponseBody"
-	NetworkEmulateConditions = "Network.emulateNetworkConditions"
+	NetworkEnable               = "Network.enable"
+	NetworkDisable              = "Network.disable"
+	NetworkGetResponseBody      = "Network.getResponseBody"
+	NetworkEmulateConditions    = "Network.emulateNetworkConditions"
+	NetworkSetUserAgentOverride = "Network.setUserAgentOverride"
 
 	// Emulation domain
 	EmulationSetCPUThrottlingRate = "Emulation.setCPUThrottlingRate"
+	EmulationSetTimezoneOverride  = "Emulation.setTimezoneOverride"
+	EmulationSetLocaleOverride    = "Emulation.setLocaleOverride"
 
 	// Profiler domain (for coverage)
 	ProfilerEnable               = "Profiler.enable"
@@ -0,0 +1,176 @@
+package w3pilot
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Chain is a fluent wrapper around an Element for short flows where
+// checking an error after every step is noise:
+//
+//	err := pilot.Chain(ctx, "#email", nil).
+//		Fill(ctx, "user@example.com").
+//		Press(ctx, "Enter").
+//		Err()
+//
+// Each method runs only if every earlier step succeeded; the first error
+// is kept and returned by Err, and later steps are skipped. Actions use
+// the options set with WithOptions, or the defaults.
+type Chain struct {
+	elem *Element
+	opts *ActionOptions
+	err  error
+}
+
+// Chain finds an element by CSS selector and returns a Chain for it.
+// A failed lookup is deferred to Err.
+func (p *Pilot) Chain(ctx context.Context, selector string, opts *FindOptions) *Chain {
+	elem, err := p.Find(ctx, selector, opts)
+	if err != nil {
+		return &Chain{err: fmt.Errorf("find %q: %w", selector, err)}
+	}
+	return &Chain{elem: elem}
+}
+
+// Chain returns a Chain for the element.
+func (e *Element) Chain() *Chain {
+	return &Chain{elem: e}
+}
+
+// Err returns the first error encountered in the chain, or nil.
+func (c *Chain) Err() error {
+	return c.err
+}
+
+// Element returns the underlying element, or nil if the chain failed
+// before an element was found.
+func (c *Chain) Element() *Element {
+	return c.elem
+}
+
+// WithOptions sets the action options used by subsequent steps.
+func (c *Chain) WithOptions(opts *ActionOptions) *Chain {
+	c.opts = opts
+	return c
+}
+
+// do runs step unless the chain has already failed, recording its error.
+func (c *Chain) do(name string, step func() error) *Chain {
+	if c.err != nil {
+		return c
+	}
+	if err := step(); err != nil {
+		c.err = fmt.Errorf("%s %q: %w", name, c.elem.selector, err)
+	}
+	return c
+}
+
+// Find finds a descendant element and returns a Chain for it. The new
+// chain carries over this chain's error and action options.
+func (c *Chain) Find(ctx context.Context, selector string, opts *FindOptions) *Chain {
+	if c.err != nil {
+		return &Chain{opts: c.opts, err: c.err}
+	}
+	elem, err := c.elem.Find(ctx, selector, opts)
+	if err != nil {
+		return &Chain{opts: c.opts, err: fmt.Errorf("find %q in %q: %w", selector, c.elem.selector, err)}
+	}
+	return &Chain{elem: elem, opts: c.opts}
+}
+
+// Click clicks the element.
+func (c *Chain) Click(ctx context.Context) *Chain {
+	return c.do("click", func() error { return c.elem.Click(ctx, c.opts) })
+}
+
+// DblClick double-clicks the element.
+func (c *Chain) DblClick(ctx context.Context) *Chain {
+	return c.do("dblclick", func() error { return c.elem.DblClick(ctx, c.opts) })
+}
+
+// Fill clears the element and sets its value.
+func (c *Chain) Fill(ctx context.Context, value string) *Chain {
+	return c.do("fill", func() error { return c.elem.Fill(ctx, value, c.opts) })
+}
+
+// Type types text into the element.
+func (c *Chain) Type(ctx context.Context, text string) *Chain {
+	return c.do("type", func() error { return c.elem.Type(ctx, text, c.opts) })
+}
+
+// Press presses a key on the element.
+func (c *Chain) Press(ctx context.Context, key string) *Chain {
+	return c.do("press", func() error { return c.elem.Press(ctx, key, c.opts) })
+}
+
+// Clear clears the element's value.
+func (c *Chain) Clear(ctx context.Context) *Chain {
+	return c.do("clear", func() error { return c.elem.Clear(ctx, c.opts) })
+}
+
+// Check checks a checkbox or radio button.
+func (c *Chain) Check(ctx context.Context) *Chain {
+	return c.do("check", func() error { return c.elem.Check(ctx, c.opts) })
+}
+
+// Uncheck unchecks a checkbox.
+func (c *Chain) Uncheck(ctx context.Context) *Chain {
+	return c.do("uncheck", func() error { return c.elem.Uncheck(ctx, c.opts) })
+}
+
+// SetChecked checks or unchecks a checkbox.
+func (c *Chain) SetChecked(ctx context.Context, checked bool) *Chain {
+	return c.do("setChecked", func() error { return c.elem.SetChecked(ctx, checked, c.opts) })
+}
+
+// SelectOption selects options in a select element.
+func (c *Chain) SelectOption(ctx context.Context, values SelectOptionValues) *Chain {
+	return c.do("selectOption", func() error { return c.elem.SelectOption(ctx, values, c.opts) })
+}
+
+// Focus focuses the element.
+func (c *Chain) Focus(ctx context.Context) *Chain {
+	return c.do("focus", func() error { return c.elem.Focus(ctx, c.opts) })
+}
+
+// Hover moves the mouse over the element.
+func (c *Chain) Hover(ctx context.Context) *Chain {
+	return c.do("hover", func() error { return c.elem.Hover(ctx, c.opts) })
+}
+
+// ScrollIntoView scrolls the element into view.
+func (c *Chain) ScrollIntoView(ctx context.Context) *Chain {
+	return c.do("scrollIntoView", func() error { return c.elem.ScrollIntoView(ctx, c.opts) })
+}
+
+// Tap taps the element.
+func (c *Chain) Tap(ctx context.Context) *Chain {
+	return c.do("tap", func() error { return c.elem.Tap(ctx, c.opts) })
+}
+
+// SetFiles sets the files of a file input.
+func (c *Chain) SetFiles(ctx context.Context, paths []string) *Chain {
+	return c.do("setFiles", func() error { return c.elem.SetFiles(ctx, paths, c.opts) })
+}
+
+// WaitUntil waits for the element to reach state ("attached", "detached",
+// "visible", "hidden").
+func (c *Chain) WaitUntil(ctx context.Context, state string, timeout time.Duration) *Chain {
+	return c.do("waitUntil "+state, func() error { return c.elem.WaitUntil(ctx, state, timeout) })
+}
+
+// VerifyText verifies the element's text; see Element.VerifyText.
+func (c *Chain) VerifyText(ctx context.Context, expected string, opts *VerifyTextOptions) *Chain {
+	return c.do("verifyText", func() error { return c.elem.VerifyText(ctx, expected, opts) })
+}
+
+// VerifyValue verifies the element's value; see Element.VerifyValue.
+func (c *Chain) VerifyValue(ctx context.Context, expected string) *Chain {
+	return c.do("verifyValue", func() error { return c.elem.VerifyValue(ctx, expected) })
+}
+
+// VerifyVisible verifies that the element is visible.
+func (c *Chain) VerifyVisible(ctx context.Context) *Chain {
+	return c.do("verifyVisible", func() error { return c.elem.VerifyVisible(ctx) })
+}
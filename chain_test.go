@@ -0,0 +1,53 @@
+package w3pilot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// TestChain_RunsSteps verifies that chained actions are sent in order.
+func TestChain_RunsSteps(t *testing.T) {
+	mock := newMockTransport()
+	mock.methodResponses = map[string]json.RawMessage{
+		"vibium:page.find": json.RawMessage(`{"tag":"input","text":""}`),
+	}
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	ctx := context.Background()
+	err := pilot.Chain(ctx, "#email", nil).Click(ctx).Fill(ctx, "x").Err()
+	if err != nil {
+		t.Fatalf("Chain failed: %v", err)
+	}
+
+	want := []string{"vibium:page.find", "vibium:element.click", "vibium:element.fill"}
+	if len(mock.calls) != len(want) {
+		t.Fatalf("Expected %d calls, got %d: %+v", len(want), len(mock.calls), mock.calls)
+	}
+	for i, m := range want {
+		if mock.calls[i].Method != m {
+			t.Errorf("call %d: expected %s, got %s", i, m, mock.calls[i].Method)
+		}
+	}
+}
+
+// TestChain_ShortCircuits verifies that the first error is kept and later
+// steps are skipped.
+func TestChain_ShortCircuits(t *testing.T) {
+	mock := newMockTransport()
+	mock.err = errors.New("no such element")
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	ctx := context.Background()
+	chain := pilot.Chain(ctx, "#missing", nil).Click(ctx).Fill(ctx, "x")
+	if !errors.Is(chain.Err(), mock.err) {
+		t.Errorf("Expected find error, got %v", chain.Err())
+	}
+	if chain.Element() != nil {
+		t.Error("Expected no element after failed find")
+	}
+	if len(mock.calls) != 1 {
+		t.Errorf("Expected only the find call, got %+v", mock.calls)
+	}
+}
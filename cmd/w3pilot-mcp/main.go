@@ -30,6 +30,7 @@ func main() {
 	headless := flag.Bool("headless", true, "Run browser in headless mode")
 	project := flag.String("project", "w3pilot-tests", "Project name for reports")
 	timeout := flag.Duration("timeout", 30*time.Second, "Default timeout for browser operations")
+	screenshots := flag.String("screenshots", "on-failure", "When to attach screenshots to step results: on-failure, always, never")
 	listTools := flag.Bool("list-tools", false, "Output tool definitions as JSON and exit")
 
 	var initScriptPaths stringSlice
@@ -47,6 +48,11 @@ func main() {
 		return
 	}
 
+	screenshotPolicy, err := mcp.ParseScreenshotPolicy(*screenshots)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Load init scripts from files
 	var initScripts []string
 	for _, path := range initScriptPaths {
@@ -58,10 +64,11 @@ func main() {
 	}
 
 	config := mcp.Config{
-		Headless:       *headless,
-		Project:        *project,
-		DefaultTimeout: *timeout,
-		InitScripts:    initScripts,
+		Headless:         *headless,
+		Project:          *project,
+		DefaultTimeout:   *timeout,
+		InitScripts:      initScripts,
+		ScreenshotPolicy: screenshotPolicy,
 	}
 
 	server := mcp.NewServer(config)
@@ -30,6 +30,9 @@ Examples:
   # Run with variables
   w3pilot-rpa run workflow.yaml --var username=admin --var password=secret
 
+  # Run a workflow every hour
+  w3pilot-rpa schedule --cron "0 * * * *" workflow.yaml
+
   # Validate a workflow
   w3pilot-rpa validate workflow.yaml
 
@@ -1,15 +1,17 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
-	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/plexusone/w3pilot/rpa"
 	"github.com/spf13/cobra"
@@ -19,6 +21,8 @@ var (
 	outputFile   string
 	outputFormat string
 	dryRun       bool
+	retries      int
+	retryDelay   time.Duration
 )
 
 var runCmd = &cobra.Command{
@@ -39,6 +43,9 @@ Examples:
   # Run and save results to JSON
   w3pilot-rpa run workflow.yaml --output results.json
 
+  # Retry every step up to twice on failure
+  w3pilot-rpa run workflow.yaml --retries 2
+
   # Dry run (validate without executing)
   w3pilot-rpa run workflow.yaml --dry-run
 `,
@@ -52,6 +59,8 @@ func init() {
 	runCmd.Flags().StringVarP(&outputFile, "output", "o", "", "Save results to file (format from extension)")
 	runCmd.Flags().StringVar(&outputFormat, "format", "", "Output format: json, markdown, html, junit")
 	runCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate workflow without executing")
+	runCmd.Flags().IntVar(&retries, "retries", 0, "Default retry attempts for steps without their own retry config")
+	runCmd.Flags().DurationVar(&retryDelay, "retry-delay", rpa.DefaultRetryDelay, "Delay between default retry attempts")
 }
 
 func runWorkflow(cmd *cobra.Command, args []string) error {
@@ -65,6 +74,7 @@ func runWorkflow(cmd *cobra.Command, args []string) error {
 	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel}))
 
 	// Create executor config
+	var executor *rpa.Executor
 	config := rpa.ExecutorConfig{
 		Headless:       headless,
 		WorkDir:        getWorkDir(),
@@ -73,9 +83,19 @@ func runWorkflow(cmd *cobra.Command, args []string) error {
 		Logger:         logger,
 		OnStepStart:    onStepStart,
 		OnStepComplete: onStepComplete,
+		OnPause: func(req rpa.PauseRequest) {
+			go promptResume(executor, req)
+		},
+	}
+
+	if retries > 0 {
+		config.DefaultRetry = &rpa.RetryConfig{
+			MaxAttempts: retries + 1,
+			Delay:       rpa.Duration(retryDelay),
+		}
 	}
 
-	executor := rpa.NewExecutor(config)
+	executor = rpa.NewExecutor(config)
 
 	// Set up context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
@@ -141,6 +161,50 @@ func onStepComplete(step *rpa.Step, result *rpa.StepResult) {
 	}
 }
 
+// stdinMu serializes approval prompts from parallel branches.
+var stdinMu sync.Mutex
+
+// stdinLines delivers lines read from stdin by a single goroutine, so a
+// prompt whose step stops waiting does not leave a reader blocked on stdin
+// that would swallow the answer to the next prompt. It is closed at EOF.
+var stdinLines = sync.OnceValue(func() <-chan string {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	return lines
+})
+
+// promptResume asks the operator on stdin whether a paused step may continue.
+// Anything other than "y" or "yes", including EOF, rejects the step. The
+// prompt is abandoned if the step stops waiting first, e.g. on timeout.
+func promptResume(executor *rpa.Executor, req rpa.PauseRequest) {
+	stdinMu.Lock()
+	defer stdinMu.Unlock()
+
+	select {
+	case <-req.Done:
+		return
+	default:
+	}
+
+	fmt.Printf("  ⏸ %s: %s\n", req.StepID, req.Message)
+	fmt.Print("    Continue? [y/N]: ")
+
+	select {
+	case line := <-stdinLines():
+		answer := strings.ToLower(strings.TrimSpace(line))
+		_ = executor.Resume(req.StepID, answer == "y" || answer == "yes")
+	case <-req.Done:
+		fmt.Println()
+		fmt.Printf("    %s is no longer waiting\n", req.StepID)
+	}
+}
+
 func printSummary(result *rpa.WorkflowResult) {
 	fmt.Println()
 	fmt.Printf("Workflow: %s\n", result.WorkflowName)
@@ -180,11 +244,11 @@ func writeOutput(result *rpa.WorkflowResult, path, format string) error {
 
 	switch format {
 	case "json":
-		data, err = json.MarshalIndent(result, "", "  ")
+		data, err = result.JSON()
 	case "markdown":
 		data = formatMarkdown(result)
 	case "html":
-		data = formatHTML(result)
+		data, err = result.HTML()
 	case "junit":
 		data = formatJUnit(result)
 	default:
@@ -240,49 +304,6 @@ func formatMarkdown(result *rpa.WorkflowResult) []byte {
 	return []byte(sb.String())
 }
 
-func formatHTML(result *rpa.WorkflowResult) []byte {
-	var sb strings.Builder
-
-	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n")
-	sb.WriteString("<title>Workflow Results: " + result.WorkflowName + "</title>\n")
-	sb.WriteString("<style>\n")
-	sb.WriteString("body { font-family: sans-serif; margin: 20px; }\n")
-	sb.WriteString("table { border-collapse: collapse; width: 100%; }\n")
-	sb.WriteString("th, td { border: 1px solid #ddd; padding: 8px; text-align: left; }\n")
-	sb.WriteString("th { background-color: #f2f2f2; }\n")
-	sb.WriteString(".success { color: green; }\n")
-	sb.WriteString(".failure { color: red; }\n")
-	sb.WriteString(".skipped { color: gray; }\n")
-	sb.WriteString("</style>\n</head>\n<body>\n")
-
-	sb.WriteString(fmt.Sprintf("<h1>Workflow: %s</h1>\n", result.WorkflowName))
-
-	statusClass := "success"
-	if result.Status == rpa.StatusFailure {
-		statusClass = "failure"
-	}
-	sb.WriteString(fmt.Sprintf("<p><strong>Status:</strong> <span class=\"%s\">%s</span></p>\n", statusClass, result.Status))
-	sb.WriteString(fmt.Sprintf("<p><strong>Duration:</strong> %s</p>\n", result.Duration.Round(1000000)))
-
-	sb.WriteString("<h2>Steps</h2>\n")
-	sb.WriteString("<table>\n<tr><th>Step</th><th>Activity</th><th>Status</th><th>Duration</th><th>Error</th></tr>\n")
-
-	for _, step := range result.Steps {
-		statusClass := "success"
-		if step.Status == rpa.StatusFailure {
-			statusClass = "failure"
-		} else if step.Status == rpa.StatusSkipped {
-			statusClass = "skipped"
-		}
-		sb.WriteString(fmt.Sprintf("<tr><td>%s</td><td>%s</td><td class=\"%s\">%s</td><td>%s</td><td>%s</td></tr>\n",
-			step.StepID, step.Activity, statusClass, step.Status, step.Duration.Round(1000000), step.Error))
-	}
-
-	sb.WriteString("</table>\n</body>\n</html>")
-
-	return []byte(sb.String())
-}
-
 func formatJUnit(result *rpa.WorkflowResult) []byte {
 	var sb strings.Builder
 
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/plexusone/w3pilot"
+	"github.com/plexusone/w3pilot/rpa"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cronExpr     string
+	onceOnStart  bool
+	reportDir    string
+	reportFormat string
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule <workflow-file>",
+	Short: "Run a workflow on a cron schedule",
+	Long: `Run an RPA workflow repeatedly on a cron schedule.
+
+The browser is launched once and kept warm between runs; each run gets a
+fresh page. The workflow file is re-read before every run, so edits take
+effect on the next run.
+
+The schedule is a five-field cron expression (minute hour day-of-month
+month day-of-week) in local time, one of @hourly, @daily, @weekly,
+@monthly, @yearly, or "@every <duration>".
+
+Press Ctrl+C once to stop after the current run finishes, twice to
+cancel the current run.
+
+Examples:
+  # Run at the top of every hour
+  w3pilot-rpa schedule --cron "0 * * * *" workflow.yaml
+
+  # Run every 15 minutes, starting immediately
+  w3pilot-rpa schedule --cron "*/15 * * * *" --once-on-start workflow.yaml
+
+  # Write an HTML report for every run
+  w3pilot-rpa schedule --cron @daily --report-dir reports workflow.yaml
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSchedule,
+}
+
+func init() {
+	rootCmd.AddCommand(scheduleCmd)
+
+	scheduleCmd.Flags().StringVar(&cronExpr, "cron", "", "Cron expression for the schedule (required)")
+	scheduleCmd.Flags().BoolVar(&onceOnStart, "once-on-start", false, "Run the workflow immediately before waiting for the schedule")
+	scheduleCmd.Flags().StringVar(&reportDir, "report-dir", "", "Write a report for each run to this directory")
+	scheduleCmd.Flags().StringVar(&reportFormat, "report-format", "html", "Report format: html, json")
+	scheduleCmd.Flags().IntVar(&retries, "retries", 0, "Default retry attempts for steps without their own retry config")
+	scheduleCmd.Flags().DurationVar(&retryDelay, "retry-delay", rpa.DefaultRetryDelay, "Delay between default retry attempts")
+	_ = scheduleCmd.MarkFlagRequired("cron")
+}
+
+func runSchedule(cmd *cobra.Command, args []string) error {
+	workflowPath := args[0]
+
+	schedule, err := rpa.ParseCron(cronExpr)
+	if err != nil {
+		return fmt.Errorf("invalid --cron: %w", err)
+	}
+	if reportFormat != "html" && reportFormat != "json" {
+		return fmt.Errorf("unsupported report format: %s", reportFormat)
+	}
+
+	// Validate up front so a broken file fails fast rather than at the first tick
+	wf, err := rpa.ParseFile(workflowPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse workflow: %w", err)
+	}
+
+	// Set up logging
+	logLevel := slog.LevelInfo
+	if verbose {
+		logLevel = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel}))
+
+	// stopCtx ends the schedule; runCtx additionally cancels an in-flight run
+	stopCtx, stop := context.WithCancel(context.Background())
+	defer stop()
+	runCtx, cancelRun := context.WithCancel(context.Background())
+	defer cancelRun()
+
+	sigChan := make(chan os.Signal, 2)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+	go func() {
+		<-sigChan
+		fmt.Fprintln(os.Stderr, "\nReceived interrupt, stopping after the current run (interrupt again to cancel it)...")
+		stop()
+		<-sigChan
+		fmt.Fprintln(os.Stderr, "\nCancelling current run...")
+		cancelRun()
+	}()
+
+	browserHeadless := headless || wf.Browser.Headless
+	var pilot *w3pilot.Pilot
+	defer func() {
+		if pilot != nil {
+			if err := pilot.Quit(context.Background()); err != nil {
+				logger.Warn("failed to quit browser", "error", err)
+			}
+		}
+	}()
+
+	run := 0
+	runOnce := func() {
+		run++
+
+		// Relaunch if the warm browser went away between runs
+		if pilot == nil || pilot.IsClosed() {
+			logger.Info("launching browser", "headless", browserHeadless)
+			pilot, err = w3pilot.Browser.Launch(runCtx, &w3pilot.LaunchOptions{Headless: browserHeadless})
+			if err != nil {
+				pilot = nil
+				logger.Error("run failed", "run", run, "error", fmt.Errorf("failed to launch browser: %w", err))
+				return
+			}
+		}
+
+		var executor *rpa.Executor
+		config := rpa.ExecutorConfig{
+			WorkDir:        getWorkDir(),
+			Variables:      parseVariables(),
+			Logger:         logger,
+			Pilot:          pilot,
+			OnStepStart:    onStepStart,
+			OnStepComplete: onStepComplete,
+			OnPause: func(req rpa.PauseRequest) {
+				go promptResume(executor, req)
+			},
+		}
+		if retries > 0 {
+			config.DefaultRetry = &rpa.RetryConfig{
+				MaxAttempts: retries + 1,
+				Delay:       rpa.Duration(retryDelay),
+			}
+		}
+		executor = rpa.NewExecutor(config)
+
+		fmt.Printf("Running workflow: %s (run %d)\n", workflowPath, run)
+		result, err := executor.RunFile(runCtx, workflowPath)
+		if err != nil {
+			logger.Error("run failed", "run", run, "error", err)
+			return
+		}
+
+		printSummary(result)
+		logger.Info("run complete",
+			"run", run,
+			"status", result.Status,
+			"duration", result.Duration.Round(time.Millisecond),
+			"steps", result.TotalSteps(),
+			"failed", result.FailureCount(),
+			"error", result.Error)
+
+		if result.Status != rpa.StatusSuccess && strings.HasPrefix(result.Error, "failed to open page") {
+			// Drop the browser so the next run starts a new one
+			_ = pilot.Quit(context.Background())
+			pilot = nil
+		}
+
+		if reportDir != "" {
+			path := filepath.Join(reportDir, fmt.Sprintf("%s-%03d.%s",
+				result.StartTime.Format("20060102-150405"), run, reportFormat))
+			if err := writeOutput(result, path, reportFormat); err != nil {
+				logger.Error("failed to write report", "run", run, "error", err)
+			} else {
+				logger.Info("report saved", "run", run, "path", path)
+			}
+		}
+	}
+
+	if onceOnStart {
+		runOnce()
+	}
+
+	for {
+		next := schedule.Next(time.Now())
+		if next.IsZero() {
+			return fmt.Errorf("cron expression %q never fires", cronExpr)
+		}
+		logger.Info("next run scheduled", "at", next.Format(time.RFC3339))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-stopCtx.Done():
+			timer.Stop()
+			logger.Info("schedule stopped", "runs", run)
+			return nil
+		case <-timer.C:
+		}
+
+		runOnce()
+
+		if stopCtx.Err() != nil {
+			logger.Info("schedule stopped", "runs", run)
+			return nil
+		}
+	}
+}
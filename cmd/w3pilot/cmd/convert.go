@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/plexusone/w3pilot/script"
+	"github.com/spf13/cobra"
+)
+
+var convertCmd = &cobra.Command{
+	Use:   "convert <in> <out>",
+	Short: "Convert an automation script between YAML and JSON",
+	Long: `Convert an automation script between YAML and JSON.
+
+The input and output formats are chosen by file extension (.json for JSON,
+anything else for YAML). The script is validated while converting, so a
+malformed input fails instead of producing a broken output. Fields are
+written in schema order and empty fields are omitted.
+
+Examples:
+  w3pilot convert login.yaml login.json
+  w3pilot convert login.json login.yaml`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		in, out := args[0], args[1]
+
+		data, err := os.ReadFile(in)
+		if err != nil {
+			return fmt.Errorf("failed to read script: %w", err)
+		}
+
+		inFormat := script.FormatFromPath(in)
+		errs, err := script.ValidateBytes(data, inFormat)
+		if err != nil {
+			return err
+		}
+		if len(errs) > 0 {
+			for _, e := range errs {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", in, e.Error())
+			}
+			return fmt.Errorf("%s is invalid (%d errors)", in, len(errs))
+		}
+
+		scr, err := script.Parse(data, inFormat)
+		if err != nil {
+			return err
+		}
+
+		converted, err := script.Marshal(scr, script.FormatFromPath(out))
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(out, converted, 0600); err != nil {
+			return fmt.Errorf("failed to write script: %w", err)
+		}
+
+		fmt.Printf("Converted %s -> %s (%d steps)\n", in, out, len(scr.Steps))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(convertCmd)
+}
@@ -7,9 +7,14 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+
+	w3pilot "github.com/plexusone/w3pilot"
 )
 
-var elementTypeTimeout time.Duration
+var (
+	elementTypeTimeout time.Duration
+	elementTypeDelay   time.Duration
+)
 
 var elementTypeCmd = &cobra.Command{
 	Use:   "type <selector> <text>",
@@ -19,7 +24,8 @@ This simulates real keyboard input with key events.
 
 Examples:
   w3pilot element type "#email" "test@example.com"
-  w3pilot element type "input[name='search']" "query"`,
+  w3pilot element type "input[name='search']" "query"
+  w3pilot element type "#search" "laptop" --delay 100ms`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		selector := args[0]
@@ -35,7 +41,7 @@ Examples:
 			return fmt.Errorf("element not found: %w", err)
 		}
 
-		if err := el.Type(ctx, text, nil); err != nil {
+		if err := el.Type(ctx, text, &w3pilot.ActionOptions{Delay: elementTypeDelay}); err != nil {
 			return fmt.Errorf("type failed: %w", err)
 		}
 
@@ -47,4 +53,5 @@ Examples:
 func init() {
 	elementCmd.AddCommand(elementTypeCmd)
 	elementTypeCmd.Flags().DurationVar(&elementTypeTimeout, "timeout", 10*time.Second, "Timeout")
+	elementTypeCmd.Flags().DurationVar(&elementTypeDelay, "delay", 0, "Delay between characters (e.g. 100ms)")
 }
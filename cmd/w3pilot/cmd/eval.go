@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	evalHeadless bool
+	evalWait     string
+	evalTimeout  time.Duration
+)
+
+var evalCmd = &cobra.Command{
+	Use:   "eval <url> <javascript|->",
+	Short: "Evaluate JavaScript on a URL and print the result as JSON",
+	Long: `Launch a browser, navigate to a URL, evaluate JavaScript, and print the
+result as JSON. The browser is started for this command only and closed
+afterwards; no running session is required.
+
+Pass - as the script to read it from stdin.
+
+Wait states: load, domcontentloaded, networkidle
+
+Examples:
+  w3pilot eval https://example.com "document.title"
+  w3pilot eval https://example.com "document.querySelectorAll('a').length" --headless
+  w3pilot eval https://example.com "performance.getEntriesByType('resource').length" --wait networkidle
+  echo "Array.from(document.links, a => a.href)" | w3pilot eval https://example.com -`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		url, script := args[0], args[1]
+
+		if script == "-" {
+			data, err := io.ReadAll(cmd.InOrStdin())
+			if err != nil {
+				return fmt.Errorf("failed to read script from stdin: %w", err)
+			}
+			script = strings.TrimSpace(string(data))
+		}
+		if script == "" {
+			return fmt.Errorf("script is empty")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), evalTimeout)
+		defer cancel()
+
+		pilot, err := launchBrowser(ctx, evalHeadless)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = pilot.Quit(context.Background())
+			_ = clearSession()
+		}()
+
+		if err := pilot.Go(ctx, url); err != nil {
+			return fmt.Errorf("navigation failed: %w", err)
+		}
+
+		if evalWait != "" {
+			if err := pilot.WaitForLoad(ctx, evalWait, 0); err != nil {
+				return fmt.Errorf("wait for %s failed: %w", evalWait, err)
+			}
+		}
+
+		result, err := pilot.Evaluate(ctx, script)
+		if err != nil {
+			return fmt.Errorf("eval failed: %w", err)
+		}
+
+		jsonBytes, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode result: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(jsonBytes))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(evalCmd)
+	evalCmd.Flags().BoolVar(&evalHeadless, "headless", false, "Run browser in headless mode")
+	evalCmd.Flags().StringVar(&evalWait, "wait", "", "Load state to wait for after navigation (load, domcontentloaded, networkidle)")
+	evalCmd.Flags().DurationVar(&evalTimeout, "timeout", 60*time.Second, "Overall timeout")
+}
@@ -17,6 +17,7 @@ var (
 	mcpDefaultTimeout time.Duration
 	mcpProject        string
 	mcpInitScripts    []string
+	mcpScreenshots    string
 	mcpListTools      bool
 )
 
@@ -32,6 +33,7 @@ Examples:
   w3pilot mcp
   w3pilot mcp --headless
   w3pilot mcp --timeout 60s
+  w3pilot mcp --screenshots always
   w3pilot mcp --init-script ./mock-api.js`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// If --list-tools is specified, output tools and exit
@@ -57,6 +59,11 @@ Examples:
 			cancel()
 		}()
 
+		screenshotPolicy, err := mcp.ParseScreenshotPolicy(mcpScreenshots)
+		if err != nil {
+			return err
+		}
+
 		// Load init scripts from files
 		var initScripts []string
 		for _, scriptPath := range mcpInitScripts {
@@ -71,10 +78,11 @@ Examples:
 		}
 
 		config := mcp.Config{
-			Headless:       mcpHeadless,
-			DefaultTimeout: mcpDefaultTimeout,
-			Project:        mcpProject,
-			InitScripts:    initScripts,
+			Headless:         mcpHeadless,
+			DefaultTimeout:   mcpDefaultTimeout,
+			Project:          mcpProject,
+			InitScripts:      initScripts,
+			ScreenshotPolicy: screenshotPolicy,
 		}
 
 		server := mcp.NewServer(config)
@@ -104,5 +112,6 @@ func init() {
 	mcpCmd.Flags().DurationVar(&mcpDefaultTimeout, "timeout", 30*time.Second, "Default timeout for operations")
 	mcpCmd.Flags().StringVar(&mcpProject, "project", "", "Project name for test reports")
 	mcpCmd.Flags().StringArrayVar(&mcpInitScripts, "init-script", nil, "JavaScript file to inject before page scripts (can be repeated)")
+	mcpCmd.Flags().StringVar(&mcpScreenshots, "screenshots", "on-failure", "When to attach screenshots to step results: on-failure, always, never")
 	mcpCmd.Flags().BoolVar(&mcpListTools, "list-tools", false, "Output tool definitions as JSON and exit")
 }
@@ -2,9 +2,9 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
@@ -12,14 +12,21 @@ import (
 	w3pilot "github.com/plexusone/w3pilot"
 	"github.com/plexusone/w3pilot/script"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var (
 	runHeadless bool
 	runTimeout  time.Duration
+	runVars     []string
+	runTags     []string
+
+	runFailureDir  string
+	runFailureHTML bool
 )
 
+// envVarPattern matches ${env:NAME} references in script values.
+var envVarPattern = regexp.MustCompile(`\$\{env:([A-Za-z_][A-Za-z0-9_]*)\}`)
+
 var runCmd = &cobra.Command{
 	Use:   "run <script.yaml|script.json>",
 	Short: "Run an automation script",
@@ -44,20 +51,40 @@ Script format:
         failOn: serious
     - action: screenshot
       file: result.png
+  teardown:
+    - action: click
+      selector: "#logout"
+
+Setup steps run before steps; teardown steps always run afterwards, even
+when a step fails. Teardown failures are reported but do not replace the
+original error.
+
+Steps can carry tags (tags: [smoke]); --tags runs only the steps with a
+matching tag and reports the rest as skipped. Setup and teardown always run.
+
+Variables:
+  ${name}      Value from the script's variables map, overridden by --var name=value
+  ${env:NAME}  Value of the NAME environment variable (may also be used
+               inside variables, e.g. password: ${env:APP_PASSWORD})
 
 Available actions:
   Navigation: navigate, go, back, forward, reload
   Form: fill, type, clear, press, check, uncheck, select
   Mouse: click, dblclick, hover, focus, tap, dragTo
   Capture: screenshot, pdf
-  Wait: wait, waitForSelector, waitForUrl, waitForLoad
+  Wait: wait, waitForSelector, waitForUrl, waitForLoad,
+        waitForRequest, waitForResponse
   Assert: assertText, assertElement, assertVisible, assertHidden,
           assertUrl, assertTitle, assertAttribute, assertAccessibility
+  State: saveStorageState, loadStorageState
   Other: eval, setViewport, keyboardPress, keyboardType
 
 Examples:
   w3pilot run test.yaml
   w3pilot run login.json --headless
+  w3pilot run login.yaml --var username=ci-bot --var baseUrl=https://staging.example.com
+  w3pilot run regression.yaml --tags smoke
+  w3pilot run checkout.yaml --headless --screenshot-on-failure artifacts/
   w3pilot run a11y-check.yaml --headless`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -68,15 +95,11 @@ Examples:
 			return fmt.Errorf("failed to read script: %w", err)
 		}
 
-		var scr script.Script
-		if strings.HasSuffix(scriptFile, ".json") {
-			if err := json.Unmarshal(data, &scr); err != nil {
-				return fmt.Errorf("failed to parse JSON script: %w", err)
-			}
-		} else {
-			if err := yaml.Unmarshal(data, &scr); err != nil {
-				return fmt.Errorf("failed to parse YAML script: %w", err)
-			}
+		// Parse strictly, as validate and convert do, so misspelled keys
+		// fail instead of silently skipping steps
+		scr, err := script.Parse(data, script.FormatFromPath(scriptFile))
+		if err != nil {
+			return err
 		}
 
 		// Override headless from CLI flag
@@ -84,6 +107,19 @@ Examples:
 			scr.Headless = runHeadless
 		}
 
+		// --var values take precedence over the script's variables
+		vars := make(map[string]string, len(scr.Variables)+len(runVars))
+		for k, v := range scr.Variables {
+			vars[k] = v
+		}
+		for _, kv := range runVars {
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok || k == "" {
+				return fmt.Errorf("invalid --var %q: expected key=value", kv)
+			}
+			vars[k] = v
+		}
+
 		ctx, cancel := context.WithTimeout(context.Background(), runTimeout)
 		defer cancel()
 
@@ -101,442 +137,259 @@ Examples:
 			fmt.Printf("Running: %s\n", scr.Name)
 		}
 
-		// Execute steps
-		for i, step := range scr.Steps {
-			stepNum := i + 1
-			stepName := step.Name
-			if stepName == "" {
-				stepName = describeStep(step)
-			}
-			if verbose {
-				fmt.Printf("[%d] %s\n", stepNum, stepName)
+		// Buffer network events so waitForRequest/waitForResponse steps
+		// can match requests triggered by the preceding step.
+		allSteps := append(append(append([]script.Step{}, scr.Setup...), scr.Steps...), scr.Teardown...)
+		for _, step := range allSteps {
+			if step.Action == script.ActionWaitForRequest || step.Action == script.ActionWaitForResponse {
+				if err := vibe.TrackNetwork(ctx); err != nil {
+					return fmt.Errorf("failed to track network: %w", err)
+				}
+				break
 			}
+		}
 
-			// Substitute variables
-			step = substituteVariables(step, scr.Variables)
+		if runFailureDir != "" {
+			if err := os.MkdirAll(runFailureDir, 0750); err != nil {
+				return fmt.Errorf("failed to create failure directory: %w", err)
+			}
+		}
 
-			if err := executeStep(ctx, vibe, step); err != nil {
-				if step.ContinueOnError {
-					fmt.Printf("[%d] Warning: %v (continuing)\n", stepNum, err)
-					continue
+		runner := &scriptRunner{
+			pilot:       vibe,
+			script:      scr,
+			vars:        vars,
+			failureDir:  runFailureDir,
+			failureHTML: runFailureHTML,
+		}
+
+		runErr := runner.run(ctx, "setup", scr.Setup, false)
+		if runErr == nil {
+			runner.tags = runTags
+			runErr = runner.run(ctx, "", scr.Steps, false)
+			runner.tags = nil
+		}
+
+		// Teardown always runs, on a fresh context so that a timed-out run
+		// can still clean up. Its failures never mask the original error.
+		if len(scr.Teardown) > 0 {
+			tdCtx, tdCancel := context.WithTimeout(context.Background(), teardownTimeout)
+			tdErr := runner.run(tdCtx, "teardown", scr.Teardown, true)
+			tdCancel()
+			if tdErr != nil {
+				if runErr != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", tdErr)
+				} else {
+					runErr = tdErr
 				}
-				return fmt.Errorf("step %d (%s) failed: %w", stepNum, stepName, err)
 			}
 		}
 
-		fmt.Printf("Completed %d steps\n", len(scr.Steps))
+		if runErr != nil {
+			return runErr
+		}
+
+		if runner.skipped > 0 {
+			fmt.Printf("Completed %d steps (%d skipped)\n", runner.ran, runner.skipped)
+		} else {
+			fmt.Printf("Completed %d steps\n", runner.ran)
+		}
 		return nil
 	},
 }
 
-func substituteVariables(step script.Step, vars map[string]string) script.Step {
-	if vars == nil {
-		return step
-	}
-
-	subst := func(s string) string {
-		for k, v := range vars {
-			s = strings.ReplaceAll(s, "${"+k+"}", v)
-		}
-		return s
-	}
-
-	step.URL = subst(step.URL)
-	step.Selector = subst(step.Selector)
-	step.Value = subst(step.Value)
-	step.Text = subst(step.Text)
-	step.Expected = subst(step.Expected)
-	step.Pattern = subst(step.Pattern)
-	step.File = subst(step.File)
-	step.Script = subst(step.Script)
-	step.Target = subst(step.Target)
-
-	return step
+// teardownTimeout bounds the teardown section, which runs outside the
+// overall --timeout so cleanup still happens after a timeout.
+const teardownTimeout = time.Minute
+
+// scriptRunner executes the step sections of a script in order.
+type scriptRunner struct {
+	pilot         *w3pilot.Pilot
+	script        *script.Script
+	vars          map[string]string
+	tags          []string // only steps matching these run; empty runs all
+	failureDir    string   // where to capture failed steps; empty disables
+	failureHTML   bool     // also capture page HTML on failure
+	prevStepStart time.Time
+
+	ran     int // main steps executed
+	skipped int // main steps skipped by the tag filter
 }
 
-func describeStep(step script.Step) string {
-	switch step.Action {
-	case script.ActionNavigate, script.ActionGo:
-		return fmt.Sprintf("navigate %s", step.URL)
-	case script.ActionClick:
-		return fmt.Sprintf("click %s", step.Selector)
-	case script.ActionDblClick:
-		return fmt.Sprintf("dblclick %s", step.Selector)
-	case script.ActionType:
-		return fmt.Sprintf("type %s", step.Selector)
-	case script.ActionFill:
-		return fmt.Sprintf("fill %s", step.Selector)
-	case script.ActionClear:
-		return fmt.Sprintf("clear %s", step.Selector)
-	case script.ActionPress:
-		return fmt.Sprintf("press %s on %s", step.Key, step.Selector)
-	case script.ActionCheck:
-		return fmt.Sprintf("check %s", step.Selector)
-	case script.ActionUncheck:
-		return fmt.Sprintf("uncheck %s", step.Selector)
-	case script.ActionSelect:
-		return fmt.Sprintf("select %s", step.Selector)
-	case script.ActionHover:
-		return fmt.Sprintf("hover %s", step.Selector)
-	case script.ActionFocus:
-		return fmt.Sprintf("focus %s", step.Selector)
-	case script.ActionScreenshot:
-		return fmt.Sprintf("screenshot %s", step.File)
-	case script.ActionPDF:
-		return fmt.Sprintf("pdf %s", step.File)
-	case script.ActionEval:
-		return "eval javascript"
-	case script.ActionWait:
-		return fmt.Sprintf("wait %s", step.Duration)
-	case script.ActionWaitForSelector:
-		return fmt.Sprintf("waitForSelector %s", step.Selector)
-	case script.ActionWaitForURL:
-		return fmt.Sprintf("waitForUrl %s", step.Pattern)
-	case script.ActionWaitForLoad:
-		return fmt.Sprintf("waitForLoad %s", step.LoadState)
-	case script.ActionAssertText:
-		return fmt.Sprintf("assertText %s", step.Selector)
-	case script.ActionAssertElement:
-		return fmt.Sprintf("assertElement %s", step.Selector)
-	case script.ActionAssertVisible:
-		return fmt.Sprintf("assertVisible %s", step.Selector)
-	case script.ActionAssertHidden:
-		return fmt.Sprintf("assertHidden %s", step.Selector)
-	case script.ActionAssertURL:
-		return fmt.Sprintf("assertUrl %s", step.Expected)
-	case script.ActionAssertTitle:
-		return fmt.Sprintf("assertTitle %s", step.Expected)
-	case script.ActionAssertAccessibility:
-		standard := "wcag22aa"
-		if step.A11y != nil && step.A11y.Standard != "" {
-			standard = step.A11y.Standard
+// run executes steps, labelling them with section ("" for the main steps).
+// With bestEffort, every step runs and the first failure is returned at the
+// end; otherwise the first failure stops the section unless the step sets
+// continueOnError.
+func (r *scriptRunner) run(ctx context.Context, section string, steps []script.Step, bestEffort bool) error {
+	label := func(n int) string {
+		if section == "" {
+			return fmt.Sprintf("%d", n)
 		}
-		return fmt.Sprintf("assertAccessibility (%s)", standard)
-	default:
-		return string(step.Action)
+		return fmt.Sprintf("%s %d", section, n)
 	}
-}
-
-func executeStep(ctx context.Context, vibe *w3pilot.Pilot, step script.Step) error {
-	switch step.Action {
-	case script.ActionNavigate, script.ActionGo:
-		return vibe.Go(ctx, step.URL)
-
-	case script.ActionBack:
-		return vibe.Back(ctx)
-
-	case script.ActionForward:
-		return vibe.Forward(ctx)
-
-	case script.ActionReload:
-		return vibe.Reload(ctx)
-
-	case script.ActionClick:
-		el, err := vibe.Find(ctx, step.Selector, nil)
-		if err != nil {
-			return err
-		}
-		return el.Click(ctx, nil)
-
-	case script.ActionDblClick:
-		el, err := vibe.Find(ctx, step.Selector, nil)
-		if err != nil {
-			return err
-		}
-		return el.DblClick(ctx, nil)
-
-	case script.ActionType:
-		el, err := vibe.Find(ctx, step.Selector, nil)
-		if err != nil {
-			return err
-		}
-		text := step.Text
-		if text == "" {
-			text = step.Value
-		}
-		return el.Type(ctx, text, nil)
-
-	case script.ActionFill:
-		el, err := vibe.Find(ctx, step.Selector, nil)
-		if err != nil {
-			return err
-		}
-		value := step.Value
-		if value == "" {
-			value = step.Text
-		}
-		return el.Fill(ctx, value, nil)
-
-	case script.ActionClear:
-		el, err := vibe.Find(ctx, step.Selector, nil)
-		if err != nil {
-			return err
-		}
-		return el.Clear(ctx, nil)
-
-	case script.ActionPress:
-		el, err := vibe.Find(ctx, step.Selector, nil)
-		if err != nil {
-			return err
-		}
-		return el.Press(ctx, step.Key, nil)
-
-	case script.ActionCheck:
-		el, err := vibe.Find(ctx, step.Selector, nil)
-		if err != nil {
-			return err
-		}
-		return el.Check(ctx, nil)
-
-	case script.ActionUncheck:
-		el, err := vibe.Find(ctx, step.Selector, nil)
-		if err != nil {
-			return err
-		}
-		return el.Uncheck(ctx, nil)
-
-	case script.ActionSelect:
-		el, err := vibe.Find(ctx, step.Selector, nil)
-		if err != nil {
-			return err
-		}
-		selectValues := w3pilot.SelectOptionValues{Values: []string{step.Value}}
-		return el.SelectOption(ctx, selectValues, nil)
-
-	case script.ActionHover:
-		el, err := vibe.Find(ctx, step.Selector, nil)
-		if err != nil {
-			return err
-		}
-		return el.Hover(ctx, nil)
-
-	case script.ActionFocus:
-		el, err := vibe.Find(ctx, step.Selector, nil)
-		if err != nil {
-			return err
-		}
-		return el.Focus(ctx, nil)
-
-	case script.ActionScrollIntoView:
-		el, err := vibe.Find(ctx, step.Selector, nil)
-		if err != nil {
-			return err
-		}
-		return el.ScrollIntoView(ctx, nil)
-
-	case script.ActionTap:
-		el, err := vibe.Find(ctx, step.Selector, nil)
-		if err != nil {
-			return err
-		}
-		return el.Tap(ctx, nil)
-
-	case script.ActionScreenshot:
-		data, err := vibe.Screenshot(ctx)
-		if err != nil {
-			return err
-		}
-		return os.WriteFile(step.File, data, 0600)
-
-	case script.ActionPDF:
-		data, err := vibe.PDF(ctx, nil)
-		if err != nil {
-			return err
-		}
-		return os.WriteFile(step.File, data, 0600)
-
-	case script.ActionEval:
-		_, err := vibe.Evaluate(ctx, step.Script)
-		return err
 
-	case script.ActionWait:
-		duration := step.Duration
-		if duration == "" {
-			duration = step.Timeout
+	var firstErr error
+	for i, step := range steps {
+		stepNum := i + 1
+		stepName := step.Name
+		if stepName == "" {
+			stepName = script.DescribeStep(step)
 		}
-		d, err := time.ParseDuration(duration)
-		if err != nil {
-			return fmt.Errorf("invalid duration: %w", err)
-		}
-		time.Sleep(d)
-		return nil
-
-	case script.ActionWaitForSelector:
-		_, err := vibe.Find(ctx, step.Selector, nil)
-		return err
-
-	case script.ActionWaitForURL:
-		timeout := 30 * time.Second
-		if step.Timeout != "" {
-			if d, err := time.ParseDuration(step.Timeout); err == nil {
-				timeout = d
+		if !step.MatchesTags(r.tags) {
+			if section == "" {
+				r.skipped++
 			}
+			fmt.Printf("[%s] Skipped: %s (tags)\n", label(stepNum), stepName)
+			continue
 		}
-		return vibe.WaitForURL(ctx, step.Pattern, timeout)
-
-	case script.ActionWaitForLoad:
-		state := step.LoadState
-		if state == "" {
-			state = "load"
+		if verbose {
+			fmt.Printf("[%s] %s\n", label(stepNum), stepName)
 		}
-		timeout := 30 * time.Second
-		if step.Timeout != "" {
-			if d, err := time.ParseDuration(step.Timeout); err == nil {
-				timeout = d
-			}
+		if section == "" {
+			r.ran++
 		}
-		return vibe.WaitForLoad(ctx, state, timeout)
 
-	case script.ActionSetViewport:
-		viewport := w3pilot.Viewport{Width: step.Width, Height: step.Height}
-		return vibe.SetViewport(ctx, viewport)
+		// Substitute variables and apply script defaults
+		step = r.script.ResolveStep(substituteVariables(step, r.vars))
 
-	case script.ActionKeyboardPress:
-		kb, err := vibe.Keyboard(ctx)
-		if err != nil {
-			return err
+		stepStart := time.Now()
+		if r.prevStepStart.IsZero() {
+			r.prevStepStart = stepStart
 		}
-		return kb.Press(ctx, step.Key)
-
-	case script.ActionKeyboardType:
-		kb, err := vibe.Keyboard(ctx)
-		if err != nil {
-			return err
+		err := script.ExecuteStep(ctx, r.pilot, step, r.prevStepStart)
+		r.prevStepStart = stepStart
+		if err == nil {
+			continue
 		}
-		text := step.Text
-		if text == "" {
-			text = step.Value
+		if r.failureDir != "" {
+			r.captureFailure(section, stepNum, stepName)
 		}
-		return kb.Type(ctx, text)
-
-	case script.ActionMouseClick:
-		mouse, err := vibe.Mouse(ctx)
-		if err != nil {
-			return err
-		}
-		return mouse.Click(ctx, step.X, step.Y, nil)
-
-	case script.ActionMouseMove:
-		mouse, err := vibe.Mouse(ctx)
-		if err != nil {
-			return err
+		if hint := r.selectorHint(step.Selector); hint != "" {
+			fmt.Printf("[%s] %s\n", label(stepNum), hint)
 		}
-		return mouse.Move(ctx, step.X, step.Y)
 
-	// Assertions
-	case script.ActionAssertText:
-		el, err := vibe.Find(ctx, step.Selector, nil)
-		if err != nil {
-			return err
-		}
-		text, err := el.Text(ctx)
-		if err != nil {
-			return err
-		}
-		if !strings.Contains(text, step.Expected) {
-			return fmt.Errorf("text assertion failed: expected %q, got %q", step.Expected, text)
+		if step.ContinueOnError || bestEffort {
+			fmt.Printf("[%s] Warning: %v (continuing)\n", label(stepNum), err)
+			if bestEffort && firstErr == nil {
+				firstErr = fmt.Errorf("%sstep %d (%s) failed: %w", sectionPrefix(section), stepNum, stepName, err)
+			}
+			continue
 		}
-		return nil
+		return fmt.Errorf("%sstep %d (%s) failed: %w", sectionPrefix(section), stepNum, stepName, err)
+	}
+	return firstErr
+}
 
-	case script.ActionAssertElement:
-		_, err := vibe.Find(ctx, step.Selector, nil)
-		return err
+// captureFailure saves a screenshot (and optionally the page HTML) of the
+// current page to the failure directory, named by section, step number,
+// and step name. It uses its own context so that a step that failed by
+// timing out can still be captured. Capture errors are only reported.
+func (r *scriptRunner) captureFailure(section string, stepNum int, stepName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	base := fmt.Sprintf("%s%02d-%s", strings.ReplaceAll(sectionPrefix(section), " ", "-"), stepNum, slugify(stepName))
+	base = filepath.Join(r.failureDir, base)
+
+	if data, err := r.pilot.Screenshot(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failure screenshot: %v\n", err)
+	} else if err := os.WriteFile(base+".png", data, 0600); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failure screenshot: %v\n", err)
+	} else {
+		fmt.Printf("Failure screenshot: %s.png\n", base)
+	}
 
-	case script.ActionAssertValue:
-		el, err := vibe.Find(ctx, step.Selector, nil)
-		if err != nil {
-			return err
-		}
-		value, err := el.Value(ctx)
-		if err != nil {
-			return err
-		}
-		if value != step.Expected {
-			return fmt.Errorf("value assertion failed: expected %q, got %q", step.Expected, value)
-		}
-		return nil
+	if !r.failureHTML {
+		return
+	}
+	if html, err := r.pilot.Content(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failure HTML: %v\n", err)
+	} else if err := os.WriteFile(base+".html", []byte(html), 0600); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failure HTML: %v\n", err)
+	} else {
+		fmt.Printf("Failure HTML: %s.html\n", base)
+	}
+}
 
-	case script.ActionAssertVisible:
-		el, err := vibe.Find(ctx, step.Selector, nil)
-		if err != nil {
-			return err
-		}
-		visible, err := el.IsVisible(ctx)
-		if err != nil {
-			return err
-		}
-		if !visible {
-			return fmt.Errorf("visibility assertion failed: element %s is not visible", step.Selector)
-		}
-		return nil
+// selectorHint returns a "did you mean" hint listing similar selectors when
+// selector matches nothing on the current page, or "" otherwise.
+func (r *scriptRunner) selectorHint(selector string) string {
+	if selector == "" {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	case script.ActionAssertHidden:
-		el, err := vibe.Find(ctx, step.Selector, nil)
-		if err != nil {
-			// Element not found is acceptable for assertHidden
-			return nil
-		}
-		hidden, err := el.IsHidden(ctx)
-		if err != nil {
-			return err
-		}
-		if !hidden {
-			return fmt.Errorf("hidden assertion failed: element %s is visible", step.Selector)
-		}
-		return nil
+	v, err := r.pilot.ValidateSelector(ctx, selector)
+	if err != nil || v.Found {
+		return ""
+	}
+	suggestions, err := r.pilot.SuggestSelectors(ctx, selector, 3)
+	if err != nil || len(suggestions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%q matches nothing; did you mean: %s", selector, strings.Join(suggestions, ", "))
+}
 
-	case script.ActionAssertURL:
-		url, err := vibe.URL(ctx)
-		if err != nil {
-			return err
+// slugify turns a step name into a short file-name-safe string.
+func slugify(s string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+			dash = false
+		} else if !dash && sb.Len() > 0 {
+			sb.WriteByte('-')
+			dash = true
+		}
+		if sb.Len() >= 48 {
+			break
 		}
-		if step.Pattern != "" {
-			matched, err := regexp.MatchString(step.Pattern, url)
-			if err != nil {
-				return fmt.Errorf("invalid URL pattern: %w", err)
-			}
-			if !matched {
-				return fmt.Errorf("URL assertion failed: %q does not match pattern %q", url, step.Pattern)
-			}
-		} else if !strings.Contains(url, step.Expected) {
-			return fmt.Errorf("URL assertion failed: expected %q in %q", step.Expected, url)
-		}
-		return nil
+	}
+	return strings.TrimRight(sb.String(), "-")
+}
 
-	case script.ActionAssertTitle:
-		title, err := vibe.Title(ctx)
-		if err != nil {
-			return err
-		}
-		if !strings.Contains(title, step.Expected) {
-			return fmt.Errorf("title assertion failed: expected %q, got %q", step.Expected, title)
-		}
-		return nil
+// sectionPrefix returns "setup " or "teardown " for error messages.
+func sectionPrefix(section string) string {
+	if section == "" {
+		return ""
+	}
+	return section + " "
+}
 
-	case script.ActionAssertAttribute:
-		el, err := vibe.Find(ctx, step.Selector, nil)
-		if err != nil {
-			return err
-		}
-		value, err := el.GetAttribute(ctx, step.Attribute)
-		if err != nil {
-			return err
-		}
-		if value != step.Expected {
-			return fmt.Errorf("attribute assertion failed: expected %s=%q, got %q", step.Attribute, step.Expected, value)
+// substituteVariables expands ${name} from vars and then ${env:NAME} from
+// the environment, so variable values may themselves reference the
+// environment. Unset environment variables expand to the empty string.
+func substituteVariables(step script.Step, vars map[string]string) script.Step {
+	subst := func(s string) string {
+		for k, v := range vars {
+			s = strings.ReplaceAll(s, "${"+k+"}", v)
 		}
-		return nil
+		return envVarPattern.ReplaceAllStringFunc(s, func(ref string) string {
+			return os.Getenv(envVarPattern.FindStringSubmatch(ref)[1])
+		})
+	}
 
-	case script.ActionAssertAccessibility:
-		return fmt.Errorf("assertAccessibility has moved to agent-a11y; use github.com/agentplexus/agent-a11y for accessibility testing")
+	step.URL = subst(step.URL)
+	step.Selector = subst(step.Selector)
+	step.Value = subst(step.Value)
+	step.Text = subst(step.Text)
+	step.Expected = subst(step.Expected)
+	step.Pattern = subst(step.Pattern)
+	step.File = subst(step.File)
+	step.Script = subst(step.Script)
+	step.Target = subst(step.Target)
 
-	default:
-		return fmt.Errorf("unknown action: %s", step.Action)
-	}
+	return step
 }
 
 func init() {
 	rootCmd.AddCommand(runCmd)
 	runCmd.Flags().BoolVar(&runHeadless, "headless", false, "Run browser in headless mode")
 	runCmd.Flags().DurationVar(&runTimeout, "timeout", 5*time.Minute, "Total script timeout")
+	runCmd.Flags().StringArrayVar(&runVars, "var", nil, "Set or override a script variable (key=value, can be repeated)")
+	runCmd.Flags().StringVar(&runFailureDir, "screenshot-on-failure", "", "Directory to save a screenshot of the page when a step fails")
+	runCmd.Flags().BoolVar(&runFailureHTML, "failure-html", false, "Also save the page HTML with --screenshot-on-failure")
+	runCmd.Flags().StringSliceVar(&runTags, "tags", nil, "Only run steps with one of these tags (comma-separated); setup and teardown always run")
 }
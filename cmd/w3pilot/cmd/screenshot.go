@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	w3pilot "github.com/plexusone/w3pilot"
+	"github.com/spf13/cobra"
+)
+
+var (
+	screenshotOutput   string
+	screenshotFullPage bool
+	screenshotViewport string
+	screenshotHeadless bool
+	screenshotWait     string
+	screenshotPDF      bool
+	screenshotTimeout  time.Duration
+)
+
+var screenshotCmd = &cobra.Command{
+	Use:   "screenshot <url>",
+	Short: "Capture a screenshot or PDF of a URL",
+	Long: `Launch a browser, navigate to a URL, and save a screenshot or PDF.
+
+The browser is started for this command only and closed afterwards; no
+running session is required.
+
+Wait states: load, domcontentloaded, networkidle
+
+Examples:
+  w3pilot screenshot https://example.com --output example.png
+  w3pilot screenshot https://example.com --output full.png --full-page --headless
+  w3pilot screenshot https://example.com --output mobile.png --viewport 390x844
+  w3pilot screenshot https://example.com --output app.png --wait networkidle
+  w3pilot screenshot https://example.com --output page.pdf --pdf`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		url := args[0]
+
+		var viewport *w3pilot.Viewport
+		if screenshotViewport != "" {
+			v, err := parseViewport(screenshotViewport)
+			if err != nil {
+				return err
+			}
+			viewport = &v
+		}
+
+		output := screenshotOutput
+		if output == "" {
+			output = "screenshot.png"
+			if screenshotPDF {
+				output = "page.pdf"
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), screenshotTimeout)
+		defer cancel()
+
+		pilot, err := launchBrowser(ctx, screenshotHeadless)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = pilot.Quit(context.Background())
+			_ = clearSession()
+		}()
+
+		if viewport != nil {
+			if err := pilot.SetViewport(ctx, *viewport); err != nil {
+				return fmt.Errorf("failed to set viewport: %w", err)
+			}
+		}
+
+		if err := pilot.Go(ctx, url); err != nil {
+			return fmt.Errorf("navigation failed: %w", err)
+		}
+
+		if screenshotWait != "" {
+			if err := pilot.WaitForLoad(ctx, screenshotWait, 0); err != nil {
+				return fmt.Errorf("wait for %s failed: %w", screenshotWait, err)
+			}
+		}
+
+		var data []byte
+		if screenshotPDF {
+			data, err = pilot.PDF(ctx, &w3pilot.PDFOptions{PrintBackground: true})
+			if err != nil {
+				return fmt.Errorf("PDF generation failed: %w", err)
+			}
+		} else {
+			data, err = pilot.ScreenshotWithOptions(ctx, &w3pilot.ScreenshotOptions{FullPage: screenshotFullPage})
+			if err != nil {
+				return fmt.Errorf("screenshot failed: %w", err)
+			}
+		}
+
+		if err := os.WriteFile(output, data, 0600); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+
+		kind := "Screenshot"
+		if screenshotPDF {
+			kind = "PDF"
+		}
+		fmt.Printf("%s saved: %s (%d bytes)\n", kind, output, len(data))
+		return nil
+	},
+}
+
+// parseViewport parses a "WIDTHxHEIGHT" string such as "1280x720".
+func parseViewport(s string) (w3pilot.Viewport, error) {
+	w, h, ok := strings.Cut(strings.ToLower(s), "x")
+	if ok {
+		width, errW := strconv.Atoi(w)
+		height, errH := strconv.Atoi(h)
+		if errW == nil && errH == nil && width > 0 && height > 0 {
+			return w3pilot.Viewport{Width: width, Height: height}, nil
+		}
+	}
+	return w3pilot.Viewport{}, fmt.Errorf("invalid viewport %q: expected WIDTHxHEIGHT, e.g. 1280x720", s)
+}
+
+func init() {
+	rootCmd.AddCommand(screenshotCmd)
+	// -o is the global output format flag, so the file has no shorthand
+	screenshotCmd.Flags().StringVar(&screenshotOutput, "output", "", "Output file (default: screenshot.png, or page.pdf with --pdf)")
+	screenshotCmd.Flags().BoolVar(&screenshotFullPage, "full-page", false, "Capture the full scrollable page")
+	screenshotCmd.Flags().StringVar(&screenshotViewport, "viewport", "", "Viewport size as WIDTHxHEIGHT, e.g. 1280x720")
+	screenshotCmd.Flags().BoolVar(&screenshotHeadless, "headless", false, "Run browser in headless mode")
+	screenshotCmd.Flags().StringVar(&screenshotWait, "wait", "", "Load state to wait for after navigation (load, domcontentloaded, networkidle)")
+	screenshotCmd.Flags().BoolVar(&screenshotPDF, "pdf", false, "Save a PDF instead of a PNG screenshot")
+	screenshotCmd.Flags().DurationVar(&screenshotTimeout, "timeout", 60*time.Second, "Overall timeout")
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/plexusone/w3pilot/mcp"
+	"github.com/plexusone/w3pilot/server"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveHost           string
+	servePort           int
+	serveHeadless       bool
+	serveDefaultTimeout time.Duration
+	serveMaxSessions    int
+	serveToken          string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Start an HTTP+JSON server exposing the browser tools",
+	Long: `Start an HTTP server that exposes the MCP tool set as JSON endpoints, so
+clients in any language can drive browsers without speaking MCP.
+
+Each session owns its own browser:
+
+  GET    /tools                        List available tools
+  POST   /sessions                     Create a session ({"headless": true} optional)
+  GET    /sessions                     List session IDs (requires --token)
+  DELETE /sessions/{id}                Close a session and its browser
+  POST   /sessions/{id}/tools/{tool}   Call a tool; the JSON body is its arguments
+
+Tool names and arguments match the MCP tools. POST requests must have
+Content-Type application/json.
+
+The server listens on localhost by default and, without a token, only
+serves requests addressed to localhost, so web pages cannot reach it
+through DNS rebinding. To serve other hosts, set a token with --token or
+the W3PILOT_SERVE_TOKEN environment variable; every request must then send
+"Authorization: Bearer <token>".
+
+Examples:
+  w3pilot serve
+  w3pilot serve --port 9000 --headless
+  W3PILOT_SERVE_TOKEN=s3cret w3pilot serve --host 0.0.0.0
+
+  curl -X POST -H 'Content-Type: application/json' localhost:8080/sessions
+  curl -X POST -H 'Content-Type: application/json' localhost:8080/sessions/<id>/tools/page_navigate -d '{"url": "https://example.com"}'
+  curl -X POST -H 'Content-Type: application/json' localhost:8080/sessions/<id>/tools/page_get_title`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config := mcp.DefaultConfig()
+		config.Headless = serveHeadless
+		config.DefaultTimeout = serveDefaultTimeout
+
+		token := serveToken
+		if token == "" {
+			token = os.Getenv("W3PILOT_SERVE_TOKEN")
+		}
+		if token == "" && !isLoopbackAddr(serveHost) {
+			return fmt.Errorf("listening on %s requires --token: without one, only requests to localhost are served", serveHost)
+		}
+
+		srv := server.New(server.Config{MCP: config, MaxSessions: serveMaxSessions, Token: token})
+		httpServer := &http.Server{
+			Addr:              net.JoinHostPort(serveHost, strconv.Itoa(servePort)),
+			Handler:           srv,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-sigCh
+			fmt.Fprintln(os.Stderr, "\nShutting down server...")
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			_ = httpServer.Shutdown(ctx)
+		}()
+
+		fmt.Fprintf(os.Stderr, "Listening on http://%s\n", httpServer.Addr)
+		err := httpServer.ListenAndServe()
+
+		if closeErr := srv.Close(context.Background()); closeErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: cleanup error: %v\n", closeErr)
+		}
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().StringVar(&serveHost, "host", "127.0.0.1", "Address to listen on")
+	serveCmd.Flags().IntVar(&servePort, "port", 8080, "Port to listen on")
+	serveCmd.Flags().BoolVar(&serveHeadless, "headless", false, "Run session browsers in headless mode by default")
+	serveCmd.Flags().DurationVar(&serveDefaultTimeout, "timeout", 30*time.Second, "Default timeout for operations")
+	serveCmd.Flags().IntVar(&serveMaxSessions, "max-sessions", 0, "Maximum concurrent sessions (0 = unlimited)")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "Bearer token required on every request (default: $W3PILOT_SERVE_TOKEN)")
+}
+
+// isLoopbackAddr reports whether host is localhost or a loopback IP.
+func isLoopbackAddr(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/plexusone/w3pilot/script"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate <script.yaml|script.json>...",
+	Short: "Validate automation scripts without running them",
+	Long: `Check one or more automation scripts for errors without launching a browser.
+
+Each script is parsed strictly (unknown fields are rejected) and checked for:
+  - Unknown actions
+  - Fields an action requires (e.g. selector for click, url for navigate)
+  - Enum values such as state and loadState
+  - Unparsable durations in timeout and duration
+
+Errors are reported as file:line so they can be used from editors and
+pre-commit hooks. The command exits non-zero if any script is invalid.
+
+Examples:
+  w3pilot validate test.yaml
+  w3pilot validate scripts/*.yaml scripts/*.json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		invalid := 0
+
+		for _, path := range args {
+			errs, err := validateScriptFile(path)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+				invalid++
+				continue
+			}
+			if len(errs) == 0 {
+				if verbose {
+					fmt.Printf("%s: ok\n", path)
+				}
+				continue
+			}
+			for _, e := range errs {
+				loc := path
+				if e.Line > 0 {
+					loc = fmt.Sprintf("%s:%d", path, e.Line)
+				}
+				e.Line = 0
+				fmt.Fprintf(os.Stderr, "%s: %s\n", loc, e.Error())
+			}
+			invalid++
+		}
+
+		if invalid > 0 {
+			return fmt.Errorf("%d of %d scripts invalid", invalid, len(args))
+		}
+		fmt.Printf("%d scripts valid\n", len(args))
+		return nil
+	},
+}
+
+// validateScriptFile reads a script and validates it, choosing the format
+// from the file extension (JSON for .json, YAML otherwise).
+func validateScriptFile(path string) ([]script.ValidationError, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read script: %w", err)
+	}
+	return script.ValidateBytes(data, script.FormatFromPath(path))
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
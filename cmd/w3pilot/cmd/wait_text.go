@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"time"
 
+	w3pilot "github.com/plexusone/w3pilot"
 	"github.com/spf13/cobra"
 )
 
 var (
 	waitTextTimeout  time.Duration
 	waitTextSelector string
+	waitTextMatch    string
 )
 
 var waitTextCmd = &cobra.Command{
@@ -18,9 +20,13 @@ var waitTextCmd = &cobra.Command{
 	Short: "Wait for text to appear",
 	Long: `Wait for specific text to appear on the page.
 
+Match modes: contains (default), exact, regex
+
 Examples:
   w3pilot wait text "Loading complete"
-  w3pilot wait text "Success" --selector "#status"`,
+  w3pilot wait text "Success" --selector "#status"
+  w3pilot wait text "Saved" --match exact
+  w3pilot wait text 'Completed in \d+s' --match regex`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		text := args[0]
@@ -30,20 +36,12 @@ Examples:
 
 		pilot := mustGetVibe(ctx)
 
-		// Build JavaScript to wait for text
-		var script string
-		if waitTextSelector != "" {
-			script = fmt.Sprintf(`
-				(function() {
-					const el = document.querySelector(%q);
-					return el && el.textContent.includes(%q);
-				})()
-			`, waitTextSelector, text)
-		} else {
-			script = fmt.Sprintf(`document.body.textContent.includes(%q)`, text)
-		}
-
-		if err := pilot.WaitForFunction(ctx, script, waitTextTimeout); err != nil {
+		err := pilot.WaitForTextWithOptions(ctx, text, &w3pilot.WaitForTextOptions{
+			Timeout:   waitTextTimeout,
+			Selector:  waitTextSelector,
+			TextMatch: waitTextMatch,
+		})
+		if err != nil {
 			return fmt.Errorf("text not found: %w", err)
 		}
 
@@ -56,4 +54,5 @@ func init() {
 	waitCmd.AddCommand(waitTextCmd)
 	waitTextCmd.Flags().DurationVar(&waitTextTimeout, "timeout", 30*time.Second, "Timeout")
 	waitTextCmd.Flags().StringVar(&waitTextSelector, "selector", "", "Limit search to element matching selector")
+	waitTextCmd.Flags().StringVar(&waitTextMatch, "match", "", "Match mode: contains, exact, or regex")
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"strings"
+	"sync/atomic"
 )
 
 // BrowserContext represents an isolated browser context (like an incognito window).
@@ -12,6 +13,7 @@ type BrowserContext struct {
 	client      *BiDiClient
 	clicker     *ClickerProcess
 	userContext string
+	closed      atomic.Bool
 	// TODO: Tracing requires vibium:tracing.* commands which are not implemented in clicker.
 	// tracing     *Tracing
 }
@@ -43,12 +45,19 @@ func (c *BrowserContext) NewPage(ctx context.Context) (*Pilot, error) {
 
 // Close closes the browser context and all pages within it.
 func (c *BrowserContext) Close(ctx context.Context) error {
+	if c.closed.Load() {
+		return nil
+	}
+
 	params := map[string]interface{}{
 		"userContext": c.userContext,
 	}
 
-	_, err := c.client.Send(ctx, "browser.removeUserContext", params)
-	return err
+	if _, err := c.client.Send(ctx, "browser.removeUserContext", params); err != nil {
+		return err
+	}
+	c.closed.Store(true)
+	return nil
 }
 
 // Cookies returns cookies matching the specified URLs.
@@ -0,0 +1,90 @@
+package w3pilot
+
+import (
+	"context"
+	"fmt"
+)
+
+// contrastScript computes the WCAG contrast ratio between the element's
+// text color and the background behind it. The background is found by
+// compositing the background colors of the element and its ancestors,
+// over white where all are transparent. It returns {ratio} or
+// {indeterminate: reason} when a background image or gradient, a filter,
+// a blend mode, or an unparseable color makes the result unreliable.
+const contrastScript = `(el) => {
+	const parse = value => {
+		const m = /^rgba?\(\s*([\d.]+)[\s,]+([\d.]+)[\s,]+([\d.]+)(?:\s*[,/]\s*([\d.]+)(%?))?\s*\)$/.exec(value);
+		if (!m) return null;
+		let a = m[4] === undefined ? 1 : parseFloat(m[4]);
+		if (m[5]) a /= 100;
+		return {r: +m[1], g: +m[2], b: +m[3], a};
+	};
+	const over = (top, bottom) => {
+		const a = top.a + bottom.a * (1 - top.a);
+		if (a === 0) return {r: 0, g: 0, b: 0, a: 0};
+		const mix = c => (top[c] * top.a + bottom[c] * bottom.a * (1 - top.a)) / a;
+		return {r: mix('r'), g: mix('g'), b: mix('b'), a};
+	};
+	const luminance = c => {
+		const channel = v => {
+			v /= 255;
+			return v <= 0.03928 ? v / 12.92 : Math.pow((v + 0.055) / 1.055, 2.4);
+		};
+		return 0.2126 * channel(c.r) + 0.7152 * channel(c.g) + 0.0722 * channel(c.b);
+	};
+
+	const style = getComputedStyle(el);
+	const fg = parse(style.color);
+	if (!fg) return {indeterminate: 'unsupported text color ' + style.color};
+
+	// Background layers from the element outwards, until one is opaque
+	const layers = [];
+	for (let node = el; node && node.nodeType === Node.ELEMENT_NODE; node = node.parentElement) {
+		const cs = getComputedStyle(node);
+		if (cs.backgroundImage && cs.backgroundImage !== 'none') {
+			return {indeterminate: 'background image or gradient on ' + node.tagName.toLowerCase()};
+		}
+		if (cs.filter && cs.filter !== 'none') {
+			return {indeterminate: 'filter on ' + node.tagName.toLowerCase()};
+		}
+		if (cs.mixBlendMode && cs.mixBlendMode !== 'normal') {
+			return {indeterminate: 'blend mode on ' + node.tagName.toLowerCase()};
+		}
+		const bg = parse(cs.backgroundColor);
+		if (!bg) return {indeterminate: 'unsupported background color ' + cs.backgroundColor};
+		if (bg.a > 0) layers.push(bg);
+		if (bg.a >= 1) break;
+	}
+
+	let bg = {r: 255, g: 255, b: 255, a: 1};
+	for (let i = layers.length - 1; i >= 0; i--) {
+		bg = over(layers[i], bg);
+	}
+	const text = over(fg, bg);
+
+	const l1 = luminance(text);
+	const l2 = luminance(bg);
+	return {ratio: (Math.max(l1, l2) + 0.05) / (Math.min(l1, l2) + 0.05)};
+}`
+
+// ContrastRatio returns the WCAG contrast ratio, from 1 to 21, between the
+// element's text color and the background behind it, for spot checks of
+// WCAG 1.4.3 (4.5:1 for normal text, 3:1 for large text) without a full
+// accessibility scan. The background is composited from the computed
+// background colors of the element and its ancestors, so an element
+// positioned over an unrelated sibling is not accounted for. If a
+// background image or gradient, a filter, or a blend mode makes the
+// result unreliable, it returns an error wrapping ErrContrastIndeterminate.
+func (e *Element) ContrastRatio(ctx context.Context) (float64, error) {
+	result, err := ElementEvalAs[struct {
+		Ratio         float64 `json:"ratio"`
+		Indeterminate string  `json:"indeterminate"`
+	}](ctx, e, contrastScript)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute contrast: %w", err)
+	}
+	if result.Indeterminate != "" {
+		return 0, fmt.Errorf("%w for %q: %s", ErrContrastIndeterminate, e.selector, result.Indeterminate)
+	}
+	return result.Ratio, nil
+}
@@ -0,0 +1,31 @@
+package w3pilot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// TestElementContrastRatio verifies that ContrastRatio returns the computed
+// ratio and reports an indeterminate background as ErrContrastIndeterminate.
+func TestElementContrastRatio(t *testing.T) {
+	mock := newMockTransport()
+	mock.setMethodResponse("vibium:element.eval", json.RawMessage(`{"value":{"ratio":4.48}}`))
+	elem := NewElement(NewBiDiClient(mock), "ctx-123", ".hint", ElementInfo{})
+	ctx := context.Background()
+
+	ratio, err := elem.ContrastRatio(ctx)
+	if err != nil {
+		t.Fatalf("ContrastRatio failed: %v", err)
+	}
+	if ratio != 4.48 {
+		t.Errorf("Expected ratio 4.48, got %v", ratio)
+	}
+
+	mock.setMethodResponse("vibium:element.eval", json.RawMessage(`{"value":{"indeterminate":"background image or gradient on div"}}`))
+	_, err = elem.ContrastRatio(ctx)
+	if !errors.Is(err, ErrContrastIndeterminate) {
+		t.Errorf("Expected ErrContrastIndeterminate, got %v", err)
+	}
+}
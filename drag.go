@@ -0,0 +1,177 @@
+package w3pilot
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// HTML5DragOptions configures DragToHTML5 and Drop.
+type HTML5DragOptions struct {
+	// Timeout bounds the whole drag. Default is the element's default timeout.
+	Timeout time.Duration
+
+	// Data is set on the DataTransfer before the drag starts, keyed by
+	// format (e.g. "text/plain"). Handlers of dragstart may add more.
+	Data map[string]string
+
+	// Files are local file paths added to the DataTransfer as File objects,
+	// as when files are dragged in from the desktop.
+	Files []string
+}
+
+// html5DragFunction defines html5Drag(source, target, data, files), which
+// dispatches the events of an HTML5 drag from source (or from outside the
+// page if source is null) to target, sharing one DataTransfer. It returns
+// "canceled" if dragstart was canceled, "rejected" if the target did not
+// cancel dragover (so a real drop would not happen), and "dropped" otherwise.
+const html5DragFunction = `function html5Drag(source, target, data, files) {
+	const dt = new DataTransfer();
+	for (const [format, value] of Object.entries(data || {})) {
+		dt.setData(format, value);
+	}
+	for (const f of files || []) {
+		const bytes = Uint8Array.from(atob(f.data), c => c.charCodeAt(0));
+		dt.items.add(new File([bytes], f.name, {type: f.type}));
+	}
+
+	const center = el => {
+		el.scrollIntoView({block: 'center', inline: 'center'});
+		const r = el.getBoundingClientRect();
+		return {clientX: r.x + r.width / 2, clientY: r.y + r.height / 2};
+	};
+	const fire = (el, type, point) => el.dispatchEvent(new DragEvent(type, {
+		bubbles: true, cancelable: true, composed: true, dataTransfer: dt, ...point,
+	}));
+
+	if (source) {
+		const from = center(source);
+		if (!fire(source, 'dragstart', from)) return 'canceled';
+		fire(source, 'drag', from);
+	}
+	const to = center(target);
+	fire(target, 'dragenter', to);
+	const accepted = !fire(target, 'dragover', to);
+	if (accepted) {
+		fire(target, 'drop', to);
+	} else {
+		fire(target, 'dragleave', to);
+	}
+	if (source) fire(source, 'dragend', to);
+	return accepted ? 'dropped' : 'rejected';
+}`
+
+// DragToHTML5 drags this element to target by dispatching HTML5 drag events
+// (dragstart, dragenter, dragover, drop, dragend) that share a populated
+// DataTransfer. Use it for drop zones and sortable lists that listen for
+// drag events, which the pointer-based DragTo does not trigger; see
+// IsHTML5Draggable. It fails if dragstart is canceled or target does not
+// accept the drop.
+func (e *Element) DragToHTML5(ctx context.Context, target *Element, opts *HTML5DragOptions) error {
+	script := `(el, targetSelector, data, files) => {
+		` + html5DragFunction + `
+		const target = document.querySelector(targetSelector);
+		if (!target) throw new Error('drop target not found: ' + targetSelector);
+		return html5Drag(el, target, data, files);
+	}`
+	return e.html5Drag(ctx, script, target.selector, opts)
+}
+
+// Drop dispatches HTML5 dragenter, dragover, and drop events on the element
+// as if data or files were dragged in from outside the page, as for file
+// drop zones. It fails if the element does not accept the drop.
+func (e *Element) Drop(ctx context.Context, opts *HTML5DragOptions) error {
+	script := `(el, data, files) => {
+		` + html5DragFunction + `
+		return html5Drag(null, el, data, files);
+	}`
+	return e.html5Drag(ctx, script, "", opts)
+}
+
+// IsHTML5Draggable reports whether the element or an ancestor has
+// draggable="true", meaning it is dragged with HTML5 drag events and
+// DragToHTML5 should be used instead of DragTo.
+func (e *Element) IsHTML5Draggable(ctx context.Context) (bool, error) {
+	result, err := e.Eval(ctx, `el => el.closest('[draggable="true"]') !== null`)
+	if err != nil {
+		return false, err
+	}
+	draggable, _ := result.(bool)
+	return draggable, nil
+}
+
+// html5Drag runs a drag script built on html5DragFunction. The target
+// selector is passed first when set.
+func (e *Element) html5Drag(ctx context.Context, script, targetSelector string, opts *HTML5DragOptions) error {
+	if opts == nil {
+		opts = &HTML5DragOptions{}
+	}
+	timeout := e.defaultTimeout()
+	if opts.Timeout > 0 {
+		timeout = opts.Timeout
+	}
+
+	ctx, cancel, _, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	data := make(map[string]interface{}, len(opts.Data))
+	for format, value := range opts.Data {
+		data[format] = value
+	}
+	files, err := dragFiles(opts.Files)
+	if err != nil {
+		return err
+	}
+
+	args := []interface{}{data, files}
+	if targetSelector != "" {
+		args = append([]interface{}{targetSelector}, args...)
+	}
+	result, err := e.Eval(ctx, script, args...)
+	if err != nil {
+		return fmt.Errorf("html5 drag failed: %w", err)
+	}
+
+	switch result {
+	case "dropped":
+		return nil
+	case "canceled":
+		return fmt.Errorf("html5 drag of %q was canceled by its dragstart handler", e.selector)
+	case "rejected":
+		if targetSelector == "" {
+			targetSelector = e.selector
+		}
+		return fmt.Errorf("drop target %q did not accept the drop: dragover was not canceled", targetSelector)
+	default:
+		return fmt.Errorf("html5 drag failed: unexpected result %v", result)
+	}
+}
+
+// dragFiles reads files for a DataTransfer as name, MIME type, and
+// base64 content.
+func dragFiles(paths []string) ([]interface{}, error) {
+	files := make([]interface{}, 0, len(paths))
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read drag file: %w", err)
+		}
+		mimeType := mime.TypeByExtension(filepath.Ext(path))
+		if mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+		files = append(files, map[string]interface{}{
+			"name": filepath.Base(path),
+			"type": mimeType,
+			"data": base64.StdEncoding.EncodeToString(content),
+		})
+	}
+	return files, nil
+}
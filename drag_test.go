@@ -0,0 +1,68 @@
+package w3pilot
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestElementDragToHTML5 verifies that DragToHTML5 evaluates the drag script
+// on the source with the target selector, data, and files.
+func TestElementDragToHTML5(t *testing.T) {
+	mock := newMockTransport()
+	mock.setMethodResponse("vibium:element.eval", json.RawMessage(`{"value":"dropped"}`))
+	client := NewBiDiClient(mock)
+	source := NewElement(client, "ctx-123", "#card", ElementInfo{})
+	target := NewElement(client, "ctx-123", "#done", ElementInfo{})
+
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := source.DragToHTML5(context.Background(), target, &HTML5DragOptions{
+		Data:  map[string]string{"text/plain": "card-1"},
+		Files: []string{path},
+	})
+	if err != nil {
+		t.Fatalf("DragToHTML5 failed: %v", err)
+	}
+
+	calls := mock.getCalls()
+	if len(calls) != 1 || calls[0].Method != "vibium:element.eval" {
+		t.Fatalf("Expected one vibium:element.eval call, got %v", calls)
+	}
+	params := calls[0].Params.(map[string]interface{})
+	if params["selector"] != "#card" {
+		t.Errorf("Expected the script to run on #card, got %v", params["selector"])
+	}
+	args := params["args"].([]interface{})
+	if len(args) != 3 {
+		t.Fatalf("Expected target, data, and files arguments, got %v", args)
+	}
+	if v := args[0].(map[string]interface{})["value"]; v != "#done" {
+		t.Errorf("Expected target selector #done, got %v", v)
+	}
+	encoded, _ := json.Marshal(args[1:])
+	for _, want := range []string{"card-1", "notes.txt", "text/plain", "aGVsbG8="} {
+		if !strings.Contains(string(encoded), want) {
+			t.Errorf("Expected %q in data and files arguments, got %s", want, encoded)
+		}
+	}
+}
+
+// TestElementDrop_Rejected verifies that Drop reports a target that did not
+// accept the drop.
+func TestElementDrop_Rejected(t *testing.T) {
+	mock := newMockTransport()
+	mock.setMethodResponse("vibium:element.eval", json.RawMessage(`{"value":"rejected"}`))
+	zone := NewElement(NewBiDiClient(mock), "ctx-123", "#dropzone", ElementInfo{})
+
+	err := zone.Drop(context.Background(), nil)
+	if err == nil || !strings.Contains(err.Error(), `"#dropzone" did not accept the drop`) {
+		t.Errorf("Expected rejected drop error, got %v", err)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // Element represents a DOM element that can be interacted with.
@@ -15,6 +17,7 @@ type Element struct {
 	context  string // browsing context ID
 	selector string
 	info     ElementInfo
+	timeout  time.Duration // default action timeout; 0 means DefaultTimeout
 }
 
 // NewElement creates a new Element instance.
@@ -32,6 +35,22 @@ func (e *Element) Info() ElementInfo {
 	return e.info
 }
 
+// newElement returns a descendant element that shares this element's
+// default timeout.
+func (e *Element) newElement(selector string, info ElementInfo) *Element {
+	elem := NewElement(e.client, e.context, selector, info)
+	elem.timeout = e.timeout
+	return elem
+}
+
+// defaultTimeout returns the timeout used when an action's options omit one.
+func (e *Element) defaultTimeout() time.Duration {
+	if e.timeout > 0 {
+		return e.timeout
+	}
+	return DefaultTimeout
+}
+
 // Selector returns the CSS selector used to find this element.
 func (e *Element) Selector() string {
 	return e.selector
@@ -40,12 +59,15 @@ func (e *Element) Selector() string {
 // Click clicks on the element. It waits for the element to be visible, stable,
 // able to receive events, and enabled before clicking.
 func (e *Element) Click(ctx context.Context, opts *ActionOptions) error {
-	timeout := DefaultTimeout
+	timeout := e.defaultTimeout()
 	if opts != nil && opts.Timeout > 0 {
 		timeout = opts.Timeout
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
 	params := map[string]interface{}{
@@ -54,19 +76,53 @@ func (e *Element) Click(ctx context.Context, opts *ActionOptions) error {
 		"timeout":  timeout.Milliseconds(),
 	}
 
-	_, err := e.client.Send(ctx, "vibium:element.click", params)
+	addActionPosition(params, opts)
+	addActionChecks(params, opts)
+
+	_, err = e.client.Send(ctx, "vibium:element.click", params)
 	return err
 }
 
+// addActionChecks adds the actionability overrides from opts to the params of an action.
+func addActionChecks(params map[string]interface{}, opts *ActionOptions) {
+	if opts != nil && opts.Force {
+		params["force"] = true
+	}
+	if opts != nil && opts.Trial {
+		params["trial"] = true
+	}
+}
+
+// addActionPosition adds opts.Position, if set, to the params of a pointer action.
+func addActionPosition(params map[string]interface{}, opts *ActionOptions) {
+	if opts != nil && opts.Position != nil {
+		params["position"] = map[string]interface{}{
+			"x": opts.Position.X,
+			"y": opts.Position.Y,
+		}
+	}
+}
+
 // Type types text into the element. It waits for the element to be visible,
 // stable, able to receive events, enabled, and editable before typing.
+// If opts.Delay is set, the element is checked and focused once, then each
+// character is typed separately with that pause in between.
 func (e *Element) Type(ctx context.Context, text string, opts *ActionOptions) error {
-	timeout := DefaultTimeout
+	timeout := e.defaultTimeout()
 	if opts != nil && opts.Timeout > 0 {
 		timeout = opts.Timeout
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	var delay time.Duration
+	if opts != nil && opts.Delay > 0 {
+		delay = opts.Delay
+	}
+
+	// Allow for the time spent between characters on top of the actionability timeout
+	ctx, cancel, _, err := withTimeout(ctx, timeout+delay*time.Duration(utf8.RuneCountInString(text)))
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
 	params := map[string]interface{}{
@@ -76,8 +132,23 @@ func (e *Element) Type(ctx context.Context, text string, opts *ActionOptions) er
 		"timeout":  timeout.Milliseconds(),
 	}
 
-	_, err := e.client.Send(ctx, "vibium:element.type", params)
-	return err
+	if delay == 0 || opts.Trial {
+		addActionChecks(params, opts)
+
+		_, err = e.client.Send(ctx, "vibium:element.type", params)
+		return err
+	}
+
+	// Check and focus the element once, then pace the characters through the
+	// keyboard, so each one does not re-run the checks or move the focus again
+	addActionChecks(params, trialOptions(opts))
+	if _, err := e.client.Send(ctx, "vibium:element.type", params); err != nil {
+		return err
+	}
+	if err := e.Focus(ctx, &ActionOptions{Timeout: timeout, Force: true}); err != nil {
+		return err
+	}
+	return NewKeyboard(e.client, e.context).TypeWithOptions(ctx, text, &TypeOptions{Delay: delay})
 }
 
 // Text returns the text content of the element.
@@ -128,6 +199,35 @@ func (e *Element) GetAttribute(ctx context.Context, name string) (string, error)
 	return *resp.Value, nil
 }
 
+// Attributes returns all of the element's attributes in a single round trip.
+func (e *Element) Attributes(ctx context.Context) (map[string]string, error) {
+	attrs, err := ElementEvalAs[map[string]string](ctx, e, `(el) => {
+	const out = {};
+	for (const attr of el.attributes) out[attr.name] = attr.value;
+	return out;
+}`)
+	if err != nil {
+		return nil, err
+	}
+	if attrs == nil {
+		attrs = map[string]string{}
+	}
+	return attrs, nil
+}
+
+// Dataset returns the element's data-* attributes keyed as in HTMLElement.dataset,
+// so data-test-id is returned under "testId".
+func (e *Element) Dataset(ctx context.Context) (map[string]string, error) {
+	data, err := ElementEvalAs[map[string]string](ctx, e, `(el) => Object.assign({}, el.dataset)`)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		data = map[string]string{}
+	}
+	return data, nil
+}
+
 // BoundingBox returns the element's bounding box.
 func (e *Element) BoundingBox(ctx context.Context) (BoundingBox, error) {
 	params := map[string]interface{}{
@@ -151,10 +251,13 @@ func (e *Element) BoundingBox(ctx context.Context) (BoundingBox, error) {
 // WaitFor waits for the element to appear in the DOM.
 func (e *Element) WaitFor(ctx context.Context, timeout time.Duration) error {
 	if timeout == 0 {
-		timeout = DefaultTimeout
+		timeout = e.defaultTimeout()
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
 	ticker := time.NewTicker(100 * time.Millisecond)
@@ -166,7 +269,7 @@ func (e *Element) WaitFor(ctx context.Context, timeout time.Duration) error {
 			return &TimeoutError{
 				Selector: e.selector,
 				Timeout:  timeout.Milliseconds(),
-				Reason:   "element did not appear",
+				Reason:   timeoutReasonNotFound,
 			}
 		case <-ticker.C:
 			script := `(selector) => document.querySelector(selector) !== null`
@@ -204,6 +307,56 @@ func (e *Element) WaitFor(ctx context.Context, timeout time.Duration) error {
 	}
 }
 
+// WaitForStable waits until the element's bounding box stops changing, such as
+// after a CSS transition or animation finishes. It samples the bounding box every
+// DefaultStableInterval and returns once two consecutive samples are identical.
+func (e *Element) WaitForStable(ctx context.Context, timeout time.Duration) error {
+	return e.WaitForStableInterval(ctx, timeout, DefaultStableInterval)
+}
+
+// WaitForStableInterval is like WaitForStable but samples the bounding box every interval.
+func (e *Element) WaitForStableInterval(ctx context.Context, timeout, interval time.Duration) error {
+	if timeout == 0 {
+		timeout = e.defaultTimeout()
+	}
+	if interval <= 0 {
+		interval = DefaultStableInterval
+	}
+
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last BoundingBox
+	sampled := false
+	for {
+		select {
+		case <-ctx.Done():
+			return &TimeoutError{
+				Selector: e.selector,
+				Timeout:  timeout.Milliseconds(),
+				Reason:   "element did not become stable",
+			}
+		case <-ticker.C:
+			box, err := e.BoundingBox(ctx)
+			if err != nil {
+				sampled = false
+				continue
+			}
+			if sampled && box == last {
+				return nil
+			}
+			last = box
+			sampled = true
+		}
+	}
+}
+
 // Center returns the center point of the element.
 func (e *Element) Center() (x, y float64) {
 	return e.info.Box.X + e.info.Box.Width/2, e.info.Box.Y + e.info.Box.Height/2
@@ -211,13 +364,21 @@ func (e *Element) Center() (x, y float64) {
 
 // Fill clears the input and fills it with the specified value.
 // It waits for the element to be visible, stable, enabled, and editable before filling.
+// Set opts.Masked for inputs that reformat their value as you type.
 func (e *Element) Fill(ctx context.Context, value string, opts *ActionOptions) error {
-	timeout := DefaultTimeout
+	if opts != nil && opts.Masked && !opts.Trial {
+		return e.fillMasked(ctx, value, opts)
+	}
+
+	timeout := e.defaultTimeout()
 	if opts != nil && opts.Timeout > 0 {
 		timeout = opts.Timeout
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
 	params := map[string]interface{}{
@@ -227,19 +388,79 @@ func (e *Element) Fill(ctx context.Context, value string, opts *ActionOptions) e
 		"timeout":  timeout.Milliseconds(),
 	}
 
-	_, err := e.client.Send(ctx, "vibium:element.fill", params)
+	addActionChecks(params, opts)
+
+	_, err = e.client.Send(ctx, "vibium:element.fill", params)
 	return err
 }
 
+// maskedFillDelay is the minimum pause between characters for a masked Fill,
+// giving the mask's input handler a tick to reformat after each keystroke.
+const maskedFillDelay = 10 * time.Millisecond
+
+// fillMasked clears the field, types value character by character, and
+// verifies the result, retrying once.
+func (e *Element) fillMasked(ctx context.Context, value string, opts *ActionOptions) error {
+	typeOpts := *opts
+	typeOpts.Masked = false
+	if typeOpts.Delay < maskedFillDelay {
+		typeOpts.Delay = maskedFillDelay
+	}
+
+	var got string
+	for attempt := 0; attempt < 2; attempt++ {
+		if err := e.Clear(ctx, &typeOpts); err != nil {
+			return err
+		}
+		if err := e.Type(ctx, value, &typeOpts); err != nil {
+			return err
+		}
+
+		var err error
+		got, err = e.Value(ctx)
+		if err != nil {
+			return err
+		}
+		if maskedValueMatches(got, value) {
+			return nil
+		}
+		debugLog(ctx, "masked fill mismatch", "selector", e.selector, "want", value, "got", got, "attempt", attempt+1)
+	}
+	return fmt.Errorf("masked fill of '%s': field value %q does not match %q", e.selector, got, value)
+}
+
+// maskedValueMatches reports whether got holds want once characters other
+// than letters and digits, which input masks insert, are removed from both.
+// Masks may also add fixed text around the value, such as a "+1" country
+// code or a currency suffix, so want only has to appear unbroken in got.
+func maskedValueMatches(got, want string) bool {
+	strip := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return r
+			}
+			return -1
+		}, s)
+	}
+	got, want = strip(got), strip(want)
+	if want == "" {
+		return got == ""
+	}
+	return strings.Contains(got, want)
+}
+
 // Press presses a key on the element.
 // It waits for the element to be visible, stable, and able to receive events.
 func (e *Element) Press(ctx context.Context, key string, opts *ActionOptions) error {
-	timeout := DefaultTimeout
+	timeout := e.defaultTimeout()
 	if opts != nil && opts.Timeout > 0 {
 		timeout = opts.Timeout
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
 	params := map[string]interface{}{
@@ -249,18 +470,23 @@ func (e *Element) Press(ctx context.Context, key string, opts *ActionOptions) er
 		"timeout":  timeout.Milliseconds(),
 	}
 
-	_, err := e.client.Send(ctx, "vibium:element.press", params)
+	addActionChecks(params, opts)
+
+	_, err = e.client.Send(ctx, "vibium:element.press", params)
 	return err
 }
 
 // Clear clears the text content of an input field.
 func (e *Element) Clear(ctx context.Context, opts *ActionOptions) error {
-	timeout := DefaultTimeout
+	timeout := e.defaultTimeout()
 	if opts != nil && opts.Timeout > 0 {
 		timeout = opts.Timeout
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
 	params := map[string]interface{}{
@@ -269,19 +495,24 @@ func (e *Element) Clear(ctx context.Context, opts *ActionOptions) error {
 		"timeout":  timeout.Milliseconds(),
 	}
 
-	_, err := e.client.Send(ctx, "vibium:element.clear", params)
+	addActionChecks(params, opts)
+
+	_, err = e.client.Send(ctx, "vibium:element.clear", params)
 	return err
 }
 
 // Check checks a checkbox element.
 // It waits for the element to be visible, stable, and enabled.
 func (e *Element) Check(ctx context.Context, opts *ActionOptions) error {
-	timeout := DefaultTimeout
+	timeout := e.defaultTimeout()
 	if opts != nil && opts.Timeout > 0 {
 		timeout = opts.Timeout
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
 	params := map[string]interface{}{
@@ -290,19 +521,24 @@ func (e *Element) Check(ctx context.Context, opts *ActionOptions) error {
 		"timeout":  timeout.Milliseconds(),
 	}
 
-	_, err := e.client.Send(ctx, "vibium:element.check", params)
+	addActionChecks(params, opts)
+
+	_, err = e.client.Send(ctx, "vibium:element.check", params)
 	return err
 }
 
 // Uncheck unchecks a checkbox element.
 // It waits for the element to be visible, stable, and enabled.
 func (e *Element) Uncheck(ctx context.Context, opts *ActionOptions) error {
-	timeout := DefaultTimeout
+	timeout := e.defaultTimeout()
 	if opts != nil && opts.Timeout > 0 {
 		timeout = opts.Timeout
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
 	params := map[string]interface{}{
@@ -311,20 +547,52 @@ func (e *Element) Uncheck(ctx context.Context, opts *ActionOptions) error {
 		"timeout":  timeout.Milliseconds(),
 	}
 
-	_, err := e.client.Send(ctx, "vibium:element.uncheck", params)
+	addActionChecks(params, opts)
+
+	_, err = e.client.Send(ctx, "vibium:element.uncheck", params)
 	return err
 }
 
+// SetChecked checks or unchecks a checkbox element to match checked.
+// It reads the current state first and only toggles when needed, so no
+// click or change events fire if the element is already in the desired state.
+func (e *Element) SetChecked(ctx context.Context, checked bool, opts *ActionOptions) error {
+	current, err := e.IsChecked(ctx)
+	if err != nil {
+		return err
+	}
+	if current == checked {
+		return nil
+	}
+	if checked {
+		return e.Check(ctx, opts)
+	}
+	return e.Uncheck(ctx, opts)
+}
+
 // SelectOption selects an option in a <select> element by value, label, or index.
+// If none of the requested values, labels, or indexes match an option, it
+// returns an *OptionNotFoundError listing the available option labels, unless
+// values.AllowNoMatch is set.
 func (e *Element) SelectOption(ctx context.Context, values SelectOptionValues, opts *ActionOptions) error {
-	timeout := DefaultTimeout
+	timeout := e.defaultTimeout()
 	if opts != nil && opts.Timeout > 0 {
 		timeout = opts.Timeout
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
+	requested := len(values.Values) > 0 || len(values.Labels) > 0 || len(values.Indexes) > 0
+	if requested && !values.AllowNoMatch {
+		if err := e.checkOptionMatch(ctx, values); err != nil {
+			return err
+		}
+	}
+
 	params := map[string]interface{}{
 		"context":  e.context,
 		"selector": e.selector,
@@ -341,18 +609,61 @@ func (e *Element) SelectOption(ctx context.Context, values SelectOptionValues, o
 		params["indexes"] = values.Indexes
 	}
 
-	_, err := e.client.Send(ctx, "vibium:element.selectOption", params)
+	addActionChecks(params, opts)
+
+	_, err = e.client.Send(ctx, "vibium:element.selectOption", params)
 	return err
 }
 
+// selectOptionMatchScript reports whether any requested value, label, or
+// index matches an option, along with the labels of all options. It returns
+// null for non-select elements so the selectOption command reports the error.
+const selectOptionMatchScript = `(el, values, labels, indexes) => {
+	if (!el.options) return null;
+	values = values || [];
+	labels = labels || [];
+	indexes = indexes || [];
+	const options = Array.from(el.options);
+	const matched = options.some((o, i) =>
+		values.includes(o.value) || labels.includes(o.label) ||
+		labels.includes(o.text.trim()) || indexes.includes(i));
+	return {matched, available: options.map(o => o.label)};
+}`
+
+// optionMatch is the result of selectOptionMatchScript.
+type optionMatch struct {
+	Matched   bool     `json:"matched"`
+	Available []string `json:"available"`
+}
+
+// checkOptionMatch returns an *OptionNotFoundError if none of the requested
+// options exist in the select element.
+func (e *Element) checkOptionMatch(ctx context.Context, values SelectOptionValues) error {
+	result, err := ElementEvalAs[*optionMatch](ctx, e, selectOptionMatchScript, values.Values, values.Labels, values.Indexes)
+	if err != nil {
+		return err
+	}
+	if result == nil || result.Matched {
+		return nil
+	}
+	return &OptionNotFoundError{
+		Selector:  e.selector,
+		Requested: values,
+		Available: result.Available,
+	}
+}
+
 // Focus focuses the element.
 func (e *Element) Focus(ctx context.Context, opts *ActionOptions) error {
-	timeout := DefaultTimeout
+	timeout := e.defaultTimeout()
 	if opts != nil && opts.Timeout > 0 {
 		timeout = opts.Timeout
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
 	params := map[string]interface{}{
@@ -361,18 +672,23 @@ func (e *Element) Focus(ctx context.Context, opts *ActionOptions) error {
 		"timeout":  timeout.Milliseconds(),
 	}
 
-	_, err := e.client.Send(ctx, "vibium:element.focus", params)
+	addActionChecks(params, opts)
+
+	_, err = e.client.Send(ctx, "vibium:element.focus", params)
 	return err
 }
 
 // Hover moves the mouse over the element.
 func (e *Element) Hover(ctx context.Context, opts *ActionOptions) error {
-	timeout := DefaultTimeout
+	timeout := e.defaultTimeout()
 	if opts != nil && opts.Timeout > 0 {
 		timeout = opts.Timeout
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
 	params := map[string]interface{}{
@@ -381,18 +697,24 @@ func (e *Element) Hover(ctx context.Context, opts *ActionOptions) error {
 		"timeout":  timeout.Milliseconds(),
 	}
 
-	_, err := e.client.Send(ctx, "vibium:element.hover", params)
+	addActionPosition(params, opts)
+	addActionChecks(params, opts)
+
+	_, err = e.client.Send(ctx, "vibium:element.hover", params)
 	return err
 }
 
 // ScrollIntoView scrolls the element into the visible area of the viewport.
 func (e *Element) ScrollIntoView(ctx context.Context, opts *ActionOptions) error {
-	timeout := DefaultTimeout
+	timeout := e.defaultTimeout()
 	if opts != nil && opts.Timeout > 0 {
 		timeout = opts.Timeout
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
 	params := map[string]interface{}{
@@ -401,18 +723,29 @@ func (e *Element) ScrollIntoView(ctx context.Context, opts *ActionOptions) error
 		"timeout":  timeout.Milliseconds(),
 	}
 
-	_, err := e.client.Send(ctx, "vibium:element.scrollIntoView", params)
+	_, err = e.client.Send(ctx, "vibium:element.scrollIntoView", params)
+	return err
+}
+
+// ScrollBy scrolls the element's own scroll container by a delta in CSS
+// pixels, e.g. to reach the bottom of a lazily loaded list. It has no effect
+// if the element is not scrollable; use Pilot.ScrollBy for the page itself.
+func (e *Element) ScrollBy(ctx context.Context, dx, dy float64) error {
+	_, err := e.Eval(ctx, "(el, dx, dy) => el.scrollBy({left: dx, top: dy, behavior: 'instant'})", dx, dy)
 	return err
 }
 
 // DblClick double-clicks on the element.
 func (e *Element) DblClick(ctx context.Context, opts *ActionOptions) error {
-	timeout := DefaultTimeout
+	timeout := e.defaultTimeout()
 	if opts != nil && opts.Timeout > 0 {
 		timeout = opts.Timeout
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
 	params := map[string]interface{}{
@@ -421,7 +754,10 @@ func (e *Element) DblClick(ctx context.Context, opts *ActionOptions) error {
 		"timeout":  timeout.Milliseconds(),
 	}
 
-	_, err := e.client.Send(ctx, "vibium:element.dblclick", params)
+	addActionPosition(params, opts)
+	addActionChecks(params, opts)
+
+	_, err = e.client.Send(ctx, "vibium:element.dblclick", params)
 	return err
 }
 
@@ -667,14 +1003,52 @@ func (e *Element) Label(ctx context.Context) (string, error) {
 	return resp.Label, nil
 }
 
+// ComputedStyle returns the resolved value of a CSS property for the element,
+// as reported by getComputedStyle (e.g. "rgb(255, 0, 0)" for color).
+func (e *Element) ComputedStyle(ctx context.Context, property string) (string, error) {
+	styles, err := e.ComputedStyles(ctx, []string{property})
+	if err != nil {
+		return "", err
+	}
+	return styles[property], nil
+}
+
+// ComputedStyles returns the resolved values of several CSS properties in a
+// single round trip. Unknown properties map to an empty string.
+func (e *Element) ComputedStyles(ctx context.Context, properties []string) (map[string]string, error) {
+	result, err := e.Eval(ctx, `(el, props) => {
+	const style = window.getComputedStyle(el);
+	const out = {};
+	for (const prop of props) out[prop] = style.getPropertyValue(prop);
+	return out;
+}`, properties)
+	if err != nil {
+		return nil, err
+	}
+
+	values, _ := result.(map[string]interface{})
+	styles := make(map[string]string, len(properties))
+	for _, prop := range properties {
+		if v, ok := values[prop].(string); ok {
+			styles[prop] = v
+		} else {
+			styles[prop] = ""
+		}
+	}
+	return styles, nil
+}
+
 // WaitUntil waits for the element to reach the specified state.
 // State can be: "attached", "detached", "visible", "hidden".
 func (e *Element) WaitUntil(ctx context.Context, state string, timeout time.Duration) error {
 	if timeout == 0 {
-		timeout = DefaultTimeout
+		timeout = e.defaultTimeout()
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
 	params := map[string]interface{}{
@@ -684,18 +1058,21 @@ func (e *Element) WaitUntil(ctx context.Context, state string, timeout time.Dura
 		"timeout":  timeout.Milliseconds(),
 	}
 
-	_, err := e.client.Send(ctx, "vibium:element.waitFor", params)
+	_, err = e.client.Send(ctx, "vibium:element.waitFor", params)
 	return err
 }
 
 // DragTo drags this element to the target element.
 func (e *Element) DragTo(ctx context.Context, target *Element, opts *ActionOptions) error {
-	timeout := DefaultTimeout
+	timeout := e.defaultTimeout()
 	if opts != nil && opts.Timeout > 0 {
 		timeout = opts.Timeout
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
 	params := map[string]interface{}{
@@ -705,18 +1082,23 @@ func (e *Element) DragTo(ctx context.Context, target *Element, opts *ActionOptio
 		"timeout":        timeout.Milliseconds(),
 	}
 
-	_, err := e.client.Send(ctx, "vibium:element.dragTo", params)
+	addActionChecks(params, opts)
+
+	_, err = e.client.Send(ctx, "vibium:element.dragTo", params)
 	return err
 }
 
 // Tap performs a touch tap on the element.
 func (e *Element) Tap(ctx context.Context, opts *ActionOptions) error {
-	timeout := DefaultTimeout
+	timeout := e.defaultTimeout()
 	if opts != nil && opts.Timeout > 0 {
 		timeout = opts.Timeout
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
 	params := map[string]interface{}{
@@ -725,10 +1107,64 @@ func (e *Element) Tap(ctx context.Context, opts *ActionOptions) error {
 		"timeout":  timeout.Milliseconds(),
 	}
 
-	_, err := e.client.Send(ctx, "vibium:element.tap", params)
+	addActionChecks(params, opts)
+
+	_, err = e.client.Send(ctx, "vibium:element.tap", params)
 	return err
 }
 
+// DoubleTap waits for the element to be visible, stable, and enabled, as Tap
+// does, then scrolls it into view and double-taps its center,
+// e.g. to test double-tap-to-zoom under mobile emulation.
+func (e *Element) DoubleTap(ctx context.Context, opts *ActionOptions) error {
+	// A Tap trial runs the touch actionability checks; a trial stops there
+	if err := e.Tap(ctx, trialOptions(opts)); err != nil || (opts != nil && opts.Trial) {
+		return err
+	}
+	x, y, err := e.touchPoint(ctx, opts)
+	if err != nil {
+		return err
+	}
+	return NewTouch(e.client, e.context).DoubleTap(ctx, x, y)
+}
+
+// LongPress waits for the element to be visible, stable, and enabled, as Tap
+// does, then scrolls it into view and presses its center for duration,
+// e.g. to open a long-press context menu. Zero uses DefaultLongPressDuration.
+func (e *Element) LongPress(ctx context.Context, duration time.Duration, opts *ActionOptions) error {
+	// A Tap trial runs the touch actionability checks; a trial stops there
+	if err := e.Tap(ctx, trialOptions(opts)); err != nil || (opts != nil && opts.Trial) {
+		return err
+	}
+	x, y, err := e.touchPoint(ctx, opts)
+	if err != nil {
+		return err
+	}
+	return NewTouch(e.client, e.context).LongPress(ctx, x, y, duration)
+}
+
+// trialOptions returns a copy of opts that only runs the actionability checks.
+func trialOptions(opts *ActionOptions) *ActionOptions {
+	var trial ActionOptions
+	if opts != nil {
+		trial = *opts
+	}
+	trial.Trial = true
+	return &trial
+}
+
+// touchPoint scrolls the element into view and returns its current center.
+func (e *Element) touchPoint(ctx context.Context, opts *ActionOptions) (x, y float64, err error) {
+	if err := e.ScrollIntoView(ctx, opts); err != nil {
+		return 0, 0, err
+	}
+	box, err := e.BoundingBox(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	return box.X + box.Width/2, box.Y + box.Height/2, nil
+}
+
 // DispatchEvent dispatches a DOM event on the element.
 func (e *Element) DispatchEvent(ctx context.Context, eventType string, eventInit map[string]interface{}) error {
 	params := map[string]interface{}{
@@ -747,12 +1183,15 @@ func (e *Element) DispatchEvent(ctx context.Context, eventType string, eventInit
 
 // SetFiles sets the files for a file input element.
 func (e *Element) SetFiles(ctx context.Context, paths []string, opts *ActionOptions) error {
-	timeout := DefaultTimeout
+	timeout := e.defaultTimeout()
 	if opts != nil && opts.Timeout > 0 {
 		timeout = opts.Timeout
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
 	params := map[string]interface{}{
@@ -762,7 +1201,9 @@ func (e *Element) SetFiles(ctx context.Context, paths []string, opts *ActionOpti
 		"timeout":  timeout.Milliseconds(),
 	}
 
-	_, err := e.client.Send(ctx, "vibium:element.setFiles", params)
+	addActionChecks(params, opts)
+
+	_, err = e.client.Send(ctx, "vibium:element.setFiles", params)
 	return err
 }
 
@@ -790,7 +1231,11 @@ func (e *Element) Screenshot(ctx context.Context) ([]byte, error) {
 }
 
 // Eval evaluates a JavaScript function with this element as the argument.
-// The function should accept the element as its first parameter.
+// The function should accept the element as its first parameter; args are
+// passed after it as BiDi local values, so strings and other values need no
+// escaping:
+//
+//	active, err := el.Eval(ctx, "(e, cls) => e.classList.contains(cls)", "active")
 func (e *Element) Eval(ctx context.Context, fn string, args ...interface{}) (interface{}, error) {
 	params := map[string]interface{}{
 		"context":  e.context,
@@ -799,7 +1244,15 @@ func (e *Element) Eval(ctx context.Context, fn string, args ...interface{}) (int
 	}
 
 	if len(args) > 0 {
-		params["args"] = args
+		arguments := make([]interface{}, 0, len(args))
+		for i, arg := range args {
+			value, err := serializeBiDiValue(arg)
+			if err != nil {
+				return nil, fmt.Errorf("argument %d: %w", i, err)
+			}
+			arguments = append(arguments, value)
+		}
+		params["args"] = arguments
 	}
 
 	result, err := e.client.Send(ctx, "vibium:element.eval", params)
@@ -817,14 +1270,28 @@ func (e *Element) Eval(ctx context.Context, fn string, args ...interface{}) (int
 	return resp.Value, nil
 }
 
+// ElementEvalAs evaluates fn against the element like Element.Eval and
+// decodes the result into T via JSON.
+func ElementEvalAs[T any](ctx context.Context, e *Element, fn string, args ...interface{}) (T, error) {
+	result, err := e.Eval(ctx, fn, args...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return decodeAs[T](result)
+}
+
 // Find finds a child element within this element by CSS selector or semantic options.
 func (e *Element) Find(ctx context.Context, selector string, opts *FindOptions) (*Element, error) {
-	timeout := DefaultTimeout
+	timeout := e.defaultTimeout()
 	if opts != nil && opts.Timeout > 0 {
 		timeout = opts.Timeout
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return nil, err
+	}
 	defer cancel()
 
 	params := map[string]interface{}{
@@ -835,34 +1302,8 @@ func (e *Element) Find(ctx context.Context, selector string, opts *FindOptions)
 	}
 
 	// Add semantic selector options if present
-	if opts != nil {
-		if opts.Role != "" {
-			params["role"] = opts.Role
-		}
-		if opts.Text != "" {
-			params["text"] = opts.Text
-		}
-		if opts.Label != "" {
-			params["label"] = opts.Label
-		}
-		if opts.Placeholder != "" {
-			params["placeholder"] = opts.Placeholder
-		}
-		if opts.TestID != "" {
-			params["testid"] = opts.TestID
-		}
-		if opts.Alt != "" {
-			params["alt"] = opts.Alt
-		}
-		if opts.Title != "" {
-			params["title"] = opts.Title
-		}
-		if opts.XPath != "" {
-			params["xpath"] = opts.XPath
-		}
-		if opts.Near != "" {
-			params["near"] = opts.Near
-		}
+	if err := addFindOptions(params, opts); err != nil {
+		return nil, err
 	}
 
 	result, err := e.client.Send(ctx, "vibium:element.find", params)
@@ -875,12 +1316,22 @@ func (e *Element) Find(ctx context.Context, selector string, opts *FindOptions)
 		return nil, err
 	}
 
-	return NewElement(e.client, e.context, selector, info), nil
+	if opts != nil && opts.Text != "" {
+		matches, err := e.FindAll(ctx, selector, opts)
+		if err != nil {
+			return nil, err
+		}
+		if err := ambiguousTextMatch(selector, opts.Text, matches); err != nil {
+			return nil, err
+		}
+	}
+
+	return e.newElement(selector, info), nil
 }
 
 // FindAll finds all child elements within this element by CSS selector or semantic options.
 func (e *Element) FindAll(ctx context.Context, selector string, opts *FindOptions) ([]*Element, error) {
-	timeout := DefaultTimeout
+	timeout := e.defaultTimeout()
 	if opts != nil && opts.Timeout > 0 {
 		timeout = opts.Timeout
 	}
@@ -893,34 +1344,8 @@ func (e *Element) FindAll(ctx context.Context, selector string, opts *FindOption
 	}
 
 	// Add semantic selector options if present
-	if opts != nil {
-		if opts.Role != "" {
-			params["role"] = opts.Role
-		}
-		if opts.Text != "" {
-			params["text"] = opts.Text
-		}
-		if opts.Label != "" {
-			params["label"] = opts.Label
-		}
-		if opts.Placeholder != "" {
-			params["placeholder"] = opts.Placeholder
-		}
-		if opts.TestID != "" {
-			params["testid"] = opts.TestID
-		}
-		if opts.Alt != "" {
-			params["alt"] = opts.Alt
-		}
-		if opts.Title != "" {
-			params["title"] = opts.Title
-		}
-		if opts.XPath != "" {
-			params["xpath"] = opts.XPath
-		}
-		if opts.Near != "" {
-			params["near"] = opts.Near
-		}
+	if err := addFindOptions(params, opts); err != nil {
+		return nil, err
 	}
 
 	result, err := e.client.Send(ctx, "vibium:element.findAll", params)
@@ -951,31 +1376,87 @@ func (e *Element) FindAll(ctx context.Context, selector string, opts *FindOption
 			Text: item.Text,
 			Box:  item.Box,
 		}
-		elements[i] = NewElement(e.client, e.context, elemSelector, info)
+		elements[i] = e.newElement(elemSelector, info)
 	}
 
 	return elements, nil
 }
 
+// highlightScript draws a fixed-position outline over the element. The overlay
+// ignores pointer events so it never intercepts clicks aimed at the page.
+const highlightScript = `(el, color, label, duration) => {
+	const store = window.__w3pilotHighlights || (window.__w3pilotHighlights = new WeakMap());
+	const prev = store.get(el);
+	if (prev) prev.remove();
+
+	const rect = el.getBoundingClientRect();
+	const overlay = document.createElement('div');
+	overlay.setAttribute('data-w3pilot-highlight', '');
+	overlay.style.cssText = 'position:fixed;pointer-events:none;z-index:2147483647;box-sizing:border-box;' +
+		'left:' + rect.left + 'px;top:' + rect.top + 'px;width:' + rect.width + 'px;height:' + rect.height + 'px;' +
+		'outline:3px solid ' + color + ';outline-offset:-1px;background:transparent;';
+
+	if (label) {
+		const tag = document.createElement('div');
+		tag.textContent = label;
+		tag.style.cssText = 'position:absolute;left:0;bottom:100%;padding:1px 4px;font:12px/1.4 sans-serif;' +
+			'color:#fff;background:' + color + ';white-space:nowrap;';
+		overlay.appendChild(tag);
+	}
+
+	document.documentElement.appendChild(overlay);
+	store.set(el, overlay);
+
+	if (duration > 0) {
+		setTimeout(() => {
+			if (store.get(el) === overlay) store.delete(el);
+			overlay.remove();
+		}, duration);
+	}
+	return true;
+}`
+
+// unhighlightScript removes an overlay previously drawn by highlightScript.
+const unhighlightScript = `(el) => {
+	const store = window.__w3pilotHighlights;
+	const overlay = store && store.get(el);
+	if (!overlay) return false;
+	store.delete(el);
+	overlay.remove();
+	return true;
+}`
+
 // Highlight draws a visual overlay on the element for debugging.
-// The highlight is displayed for the specified duration (default 2 seconds).
-// This is useful for visual debugging to see which element is being targeted.
+// The highlight is displayed for the specified duration (default 2 seconds),
+// or until Unhighlight is called when opts.Persistent is set.
+// The overlay does not intercept pointer events, so it is safe to leave in
+// place while interacting with the page or capturing screenshots.
 func (e *Element) Highlight(ctx context.Context, opts *HighlightOptions) error {
-	params := map[string]interface{}{
-		"context":  e.context,
-		"selector": e.selector,
-	}
+	color := "red"
+	label := ""
+	duration := 2000
 
 	if opts != nil {
 		if opts.Color != "" {
-			params["color"] = opts.Color
+			color = opts.Color
 		}
+		label = opts.Label
 		if opts.Duration > 0 {
-			params["duration"] = opts.Duration
+			duration = opts.Duration
+		}
+		if opts.Persistent {
+			duration = 0
 		}
 	}
 
-	_, err := e.client.Send(ctx, "vibium:element.highlight", params)
+	_, err := e.Eval(ctx, highlightScript, color, label, duration)
+	return err
+}
+
+// Unhighlight removes an overlay previously drawn by Highlight.
+// It is a no-op if the element is not highlighted.
+func (e *Element) Unhighlight(ctx context.Context) error {
+	_, err := e.Eval(ctx, unhighlightScript)
 	return err
 }
 
@@ -0,0 +1,100 @@
+package w3pilot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/plexusone/w3pilot/cdp"
+)
+
+// cdpCommand is a command received by the test CDP server.
+type cdpCommand struct {
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params"`
+}
+
+// newCDPTestClient connects a CDP client to a server that answers every
+// command with an empty result and records it.
+func newCDPTestClient(t *testing.T) (*cdp.Client, func() []cdpCommand) {
+	t.Helper()
+	var (
+		mu       sync.Mutex
+		commands []cdpCommand
+	)
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var msg struct {
+				ID int64 `json:"id"`
+				cdpCommand
+			}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			mu.Lock()
+			commands = append(commands, msg.cdpCommand)
+			mu.Unlock()
+			if err := conn.WriteJSON(map[string]interface{}{"id": msg.ID, "result": map[string]interface{}{}}); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client := cdp.NewClient()
+	if err := client.Connect(context.Background(), "ws"+strings.TrimPrefix(srv.URL, "http")); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client, func() []cdpCommand {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]cdpCommand(nil), commands...)
+	}
+}
+
+// TestPilot_SetLocale_AcceptLanguage verifies SetLocale overrides the
+// Accept-Language header with the current user agent, and clears it again.
+func TestPilot_SetLocale_AcceptLanguage(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"type":"success","result":{"type":"string","value":"Mozilla/5.0 Chrome/130.0.0.0"}}`))
+	cdpClient, commands := newCDPTestClient(t)
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123", cdpClient: cdpClient}
+	ctx := context.Background()
+
+	if err := pilot.SetLocale(ctx, "de-DE"); err != nil {
+		t.Fatalf("SetLocale failed: %v", err)
+	}
+	if err := pilot.SetLocale(ctx, ""); err != nil {
+		t.Fatalf("SetLocale failed: %v", err)
+	}
+
+	var overrides []map[string]interface{}
+	for _, cmd := range commands() {
+		if cmd.Method == cdp.NetworkSetUserAgentOverride {
+			overrides = append(overrides, cmd.Params)
+		}
+	}
+	if len(overrides) != 2 {
+		t.Fatalf("Expected 2 user agent overrides, got %v", commands())
+	}
+	if overrides[0]["acceptLanguage"] != "de-DE" || overrides[0]["userAgent"] != "Mozilla/5.0 Chrome/130.0.0.0" {
+		t.Errorf("Expected Accept-Language de-DE with the current user agent, got %v", overrides[0])
+	}
+	if _, ok := overrides[1]["acceptLanguage"]; ok || overrides[1]["userAgent"] != "" {
+		t.Errorf("Expected the override to be cleared, got %v", overrides[1])
+	}
+}
@@ -1,6 +1,7 @@
 package w3pilot
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -28,8 +29,46 @@ var (
 
 	// ErrConnectionClosed is returned when the WebSocket connection is closed.
 	ErrConnectionClosed = errors.New("connection closed")
+
+	// ErrNoElementAtPoint is returned by ElementFromPoint when no element is
+	// at the given coordinates, e.g. because they are outside the viewport.
+	ErrNoElementAtPoint = errors.New("no element at point")
+
+	// ErrStaleRef is returned by FindRef when no element has the snapshot
+	// ref, because the element was removed or the page navigated.
+	ErrStaleRef = errors.New("stale snapshot ref")
+
+	// ErrContrastIndeterminate is returned by ContrastRatio when the
+	// background cannot be determined from computed styles, e.g. because
+	// it is a gradient or an image.
+	ErrContrastIndeterminate = errors.New("contrast indeterminate")
 )
 
+// RetryableError is implemented by errors that know whether retrying the
+// failed operation could succeed.
+type RetryableError interface {
+	error
+	Retryable() bool
+}
+
+// IsRetryable reports whether the operation that returned err is worth
+// retrying. Errors implementing RetryableError decide for themselves;
+// cancellation and a closed connection are never retryable; any other
+// error is assumed to be transient.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrConnectionClosed) {
+		return false
+	}
+	var r RetryableError
+	if errors.As(err, &r) {
+		return r.Retryable()
+	}
+	return true
+}
+
 // PageContext provides context about the page state when an error occurred.
 // This helps AI agents understand the situation and recover from errors.
 type PageContext struct {
@@ -71,6 +110,18 @@ func (e *TimeoutError) Error() string {
 	return fmt.Sprintf("timeout after %dms waiting for '%s'", e.Timeout, e.Selector)
 }
 
+// timeoutReasonNotFound is the TimeoutError reason for an element that
+// never appeared.
+const timeoutReasonNotFound = "element did not appear"
+
+// Retryable classifies the timeout by its reason, as BiDiError does for
+// protocol timeouts. An element that never appeared usually means a wrong
+// selector; a slow navigation or network response, or an element that did
+// not settle, may well succeed on another attempt.
+func (e *TimeoutError) Retryable() bool {
+	return e.Reason != timeoutReasonNotFound
+}
+
 // ElementNotFoundError represents an element that could not be found.
 type ElementNotFoundError struct {
 	Selector    string       `json:"selector"`
@@ -82,6 +133,59 @@ func (e *ElementNotFoundError) Error() string {
 	return fmt.Sprintf("element not found: %s", e.Selector)
 }
 
+// Retryable returns false: a selector that found nothing is usually wrong.
+func (e *ElementNotFoundError) Retryable() bool { return false }
+
+// AmbiguousMatchError is returned by Find when a text selector matches more
+// than one element. Narrow the match with TextMatch "exact", a CSS selector,
+// or another semantic option.
+type AmbiguousMatchError struct {
+	Selector   string
+	Text       string
+	Candidates []ElementInfo
+}
+
+func (e *AmbiguousMatchError) Error() string {
+	texts := make([]string, len(e.Candidates))
+	for i, c := range e.Candidates {
+		texts[i] = fmt.Sprintf("<%s> %q", c.Tag, c.Text)
+	}
+	target := fmt.Sprintf("text %q", e.Text)
+	if e.Selector != "" {
+		target = fmt.Sprintf("'%s' with text %q", e.Selector, e.Text)
+	}
+	return fmt.Sprintf("%s matches %d elements: %s", target, len(e.Candidates), strings.Join(texts, ", "))
+}
+
+// Retryable returns false: the selector needs narrowing, not another attempt.
+func (e *AmbiguousMatchError) Retryable() bool { return false }
+
+// OptionNotFoundError is returned by SelectOption when none of the requested
+// values, labels, or indexes match an <option> in the select element.
+type OptionNotFoundError struct {
+	Selector  string
+	Requested SelectOptionValues
+	Available []string
+}
+
+func (e *OptionNotFoundError) Error() string {
+	var parts []string
+	if len(e.Requested.Values) > 0 {
+		parts = append(parts, fmt.Sprintf("values %q", e.Requested.Values))
+	}
+	if len(e.Requested.Labels) > 0 {
+		parts = append(parts, fmt.Sprintf("labels %q", e.Requested.Labels))
+	}
+	if len(e.Requested.Indexes) > 0 {
+		parts = append(parts, fmt.Sprintf("indexes %v", e.Requested.Indexes))
+	}
+	return fmt.Sprintf("no option in '%s' matches %s; available options: %q",
+		e.Selector, strings.Join(parts, ", "), e.Available)
+}
+
+// Retryable returns false: the requested options do not exist.
+func (e *OptionNotFoundError) Retryable() bool { return false }
+
 // BrowserCrashedError represents an unexpected browser exit.
 type BrowserCrashedError struct {
 	ExitCode int
@@ -95,6 +199,26 @@ func (e *BrowserCrashedError) Error() string {
 	return fmt.Sprintf("browser crashed with exit code %d", e.ExitCode)
 }
 
+// KeyboardTrapError is returned by TabOrder when pressing Tab stops moving
+// focus forward through the page (WCAG 2.1.2 No Keyboard Trap).
+type KeyboardTrapError struct {
+	// Selector identifies the element where focus got stuck, or the element
+	// focus cycled back to.
+	Selector string
+	// Stop is the zero-based index of the tab stop at which the trap was detected.
+	Stop int
+	// Cycle is true when focus loops through a subset of elements rather
+	// than staying on a single one.
+	Cycle bool
+}
+
+func (e *KeyboardTrapError) Error() string {
+	if e.Cycle {
+		return fmt.Sprintf("keyboard trap at tab stop %d: focus cycles back to %s without reaching the rest of the page", e.Stop, e.Selector)
+	}
+	return fmt.Sprintf("keyboard trap at tab stop %d: focus does not leave %s", e.Stop, e.Selector)
+}
+
 // BiDiError represents an error from the BiDi protocol.
 type BiDiError struct {
 	ErrorType string
@@ -108,6 +232,27 @@ func (e *BiDiError) Error() string {
 	return e.ErrorType
 }
 
+// Retryable classifies the error by its protocol error code. Transient
+// conditions, such as a stale element or a click landing on another element
+// mid-animation, are retryable. Invalid input, script errors, missing
+// elements, and unsupported commands are not. Unknown codes are retryable.
+func (e *BiDiError) Retryable() bool {
+	switch e.ErrorType {
+	case "stale element reference", "no such node", "element click intercepted",
+		"element not interactable", "move target out of bounds":
+		return true
+	case "invalid argument", "invalid selector", "javascript error", "no such element",
+		"no such frame", "no such script", "invalid session id",
+		"unknown command", "unknown method", "unsupported operation":
+		return false
+	case "timeout":
+		// An element that never appeared is a selector problem; one that
+		// never became actionable may still settle.
+		return !strings.Contains(strings.ToLower(e.Message), "not found")
+	}
+	return !IsUnsupportedCommand(e)
+}
+
 // IsUnsupportedCommand returns true if the error indicates the command is not
 // supported by the backend (e.g., clicker doesn't implement a vibium: command).
 // This is used internally to trigger fallback to CDP.
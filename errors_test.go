@@ -0,0 +1,41 @@
+package w3pilot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestIsRetryable verifies errors are classified by whether retrying could help.
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errors.New("transient"), true},
+		{"stale element", &BiDiError{ErrorType: "stale element reference"}, true},
+		{"click intercepted", fmt.Errorf("click failed: %w", &BiDiError{ErrorType: "element click intercepted"}), true},
+		{"invalid selector", &BiDiError{ErrorType: "invalid selector", Message: "'div[' is not valid"}, false},
+		{"script error", &BiDiError{ErrorType: "javascript error"}, false},
+		{"timeout not found", &BiDiError{ErrorType: "timeout", Message: "element not found: #btn"}, false},
+		{"timeout not visible", &BiDiError{ErrorType: "timeout", Message: "element not visible: #btn"}, true},
+		{"unknown command", &BiDiError{ErrorType: "error", Message: "unknown command vibium:foo"}, false},
+		{"element not found", &ElementNotFoundError{Selector: "#typo"}, false},
+		{"timeout element did not appear", &TimeoutError{Selector: "#btn", Timeout: 1000, Reason: "element did not appear"}, false},
+		{"timeout navigation", &TimeoutError{Selector: "navigation", Timeout: 1000, Reason: "navigation did not complete"}, true},
+		{"timeout response", &TimeoutError{Selector: "**/api", Timeout: 1000, Reason: "no matching response"}, true},
+		{"canceled", fmt.Errorf("step: %w", context.Canceled), false},
+		{"connection closed", ErrConnectionClosed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
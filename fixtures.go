@@ -0,0 +1,84 @@
+package w3pilot
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// RouteFromFile fulfills requests matching pattern with the contents of
+// filePath, e.g. a JSON fixture checked into the repo. The content type is
+// inferred from the file extension, or sniffed from the content if the
+// extension is unknown. opts may set Status (default 200), Headers, and
+// ContentType; its Body and Path are ignored. The file is read once and
+// served byte for byte, so binary fixtures such as images work too.
+func (p *Pilot) RouteFromFile(ctx context.Context, pattern, filePath string, opts *FulfillOptions) error {
+	body, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read fixture: %w", err)
+	}
+	if opts == nil {
+		opts = &FulfillOptions{}
+	}
+
+	contentType := opts.ContentType
+	if contentType == "" {
+		contentType = fixtureContentType(filePath, body)
+	}
+
+	fulfill := FulfillOptions{
+		Status:      opts.Status,
+		Headers:     opts.Headers,
+		ContentType: contentType,
+		Body:        body,
+	}
+	return p.Route(ctx, pattern, func(ctx context.Context, route *Route) error {
+		return route.Fulfill(ctx, fulfill)
+	})
+}
+
+// RouteFromDir serves every file under dir as a fixture for the URL formed
+// by joining prefix with the file's path relative to dir, without its
+// extension. With prefix "**/api", dir/users.json serves **/api/users and
+// dir/users/42.json serves **/api/users/42. Each file is also routed with a
+// "?**" suffix, so **/api/users?page=2 is served by dir/users.json too;
+// Unroute both patterns to remove a file's route. opts applies to every
+// file as in RouteFromFile.
+func (p *Pilot) RouteFromDir(ctx context.Context, prefix, dir string, opts *FulfillOptions) error {
+	return filepath.WalkDir(dir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		pattern := strings.TrimSuffix(prefix, "/") + "/" + strings.TrimSuffix(rel, path.Ext(rel))
+
+		for _, pattern := range []string{pattern, pattern + "?**"} {
+			if err := p.RouteFromFile(ctx, pattern, filePath, opts); err != nil {
+				return fmt.Errorf("failed to route %s: %w", rel, err)
+			}
+		}
+		return nil
+	})
+}
+
+// fixtureContentType returns the content type for a fixture file.
+func fixtureContentType(filePath string, body []byte) string {
+	if contentType := mime.TypeByExtension(filepath.Ext(filePath)); contentType != "" {
+		return contentType
+	}
+	return http.DetectContentType(body)
+}
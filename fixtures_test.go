@@ -0,0 +1,93 @@
+package w3pilot
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestPilotRouteFromFile verifies that RouteFromFile fulfills matching
+// requests with the file's bytes, unchanged, and a content type inferred
+// from its extension.
+func TestPilotRouteFromFile(t *testing.T) {
+	mock := newMockTransport()
+	mock.setMethodResponse("network.addIntercept", json.RawMessage(`{"intercept":"intercept-1"}`))
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	// PNG signature followed by bytes that are not valid UTF-8
+	png := []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n', 0xff, 0xfe, 0x00}
+	path := filepath.Join(t.TempDir(), "logo.png")
+	if err := os.WriteFile(path, png, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := pilot.RouteFromFile(context.Background(), "**/logo.png", path, &FulfillOptions{
+		Headers: map[string]string{"X-Fixture": "logo"},
+	})
+	if err != nil {
+		t.Fatalf("RouteFromFile failed: %v", err)
+	}
+
+	emitNetworkEvent(t, mock, "network.beforeRequestSent",
+		`{"context":"ctx-123","isBlocked":true,"intercepts":["intercept-1"],"request":{"request":"req-1","url":"https://example.com/logo.png","method":"GET"}}`)
+
+	params := waitForCall(t, mock, "network.provideResponse")
+	if params["request"] != "req-1" || params["statusCode"] != 200 {
+		t.Errorf("Unexpected provideResponse params: %v", params)
+	}
+	body := params["body"].(map[string]interface{})
+	if got, err := base64.StdEncoding.DecodeString(body["value"].(string)); err != nil || !bytes.Equal(got, png) {
+		t.Errorf("Expected the fixture bytes unchanged, got %v (%v)", got, err)
+	}
+	want := toNetworkHeaders(map[string]string{"Content-Type": "image/png", "X-Fixture": "logo"})
+	if !reflect.DeepEqual(params["headers"], want) {
+		t.Errorf("Expected headers %v, got %v", want, params["headers"])
+	}
+
+	if err := pilot.RouteFromFile(context.Background(), "**/missing", filepath.Join(t.TempDir(), "missing.json"), nil); err == nil {
+		t.Error("Expected an error for a missing fixture")
+	}
+}
+
+// TestPilotRouteFromDir verifies that RouteFromDir maps each file to the
+// prefix joined with its relative path, without the extension, with and
+// without a query string.
+func TestPilotRouteFromDir(t *testing.T) {
+	mock := newMockTransport()
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	dir := t.TempDir()
+	for _, name := range []string{"users.json", filepath.Join("users", "42.json")} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(`{}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := pilot.RouteFromDir(context.Background(), "**/api/", dir, nil); err != nil {
+		t.Fatalf("RouteFromDir failed: %v", err)
+	}
+
+	var patterns []string
+	for _, entry := range pilot.client.routes.routes {
+		patterns = append(patterns, entry.pattern)
+	}
+	sort.Strings(patterns)
+	want := "**/api/users,**/api/users/42,**/api/users/42?**,**/api/users?**"
+	if strings.Join(patterns, ",") != want {
+		t.Errorf("Unexpected patterns: %v", patterns)
+	}
+	if !matchURLPattern("https://example.com/api/users?page=2", "**/api/users?**") {
+		t.Error("Expected the query pattern to match a URL with a query string")
+	}
+}
@@ -0,0 +1,181 @@
+package w3pilot
+
+import (
+	"context"
+	"time"
+)
+
+// cssPathFunction defines cssPath(el), which builds a unique CSS path to an
+// element within its document or shadow root: the nearest ancestor with a
+// unique id, then tag names with :nth-of-type where siblings share a tag.
+const cssPathFunction = `function cssPath(el) {
+	const path = [];
+	const root = el.getRootNode();
+	let current = el;
+	while (current && current.nodeType === Node.ELEMENT_NODE) {
+		if (current.id && root.querySelectorAll('#' + CSS.escape(current.id)).length === 1) {
+			path.unshift('#' + CSS.escape(current.id));
+			break;
+		}
+		let selector = current.tagName.toLowerCase();
+		const parent = current.parentElement;
+		if (parent) {
+			const siblings = Array.from(parent.children).filter(c => c.tagName === current.tagName);
+			if (siblings.length > 1) {
+				selector += ':nth-of-type(' + (siblings.indexOf(current) + 1) + ')';
+			}
+		}
+		path.unshift(selector);
+		current = parent;
+	}
+	return path.join(' > ');
+}`
+
+// focusedSelectorScript builds a unique CSS path to document.activeElement,
+// or returns null when focus is on the body or nowhere.
+const focusedSelectorScript = `() => {
+	` + cssPathFunction + `
+	const el = document.activeElement;
+	if (!el || el === document.body || el === document.documentElement) return null;
+	return cssPath(el);
+}`
+
+// focusStopScript describes the focused element for TabOrder: selector is
+// the CSS path to document.activeElement, and key extends it into
+// same-origin frames and open shadow roots, where focus moves without
+// changing document.activeElement. opaque is set when focus may be moving
+// somewhere key cannot follow: a cross-origin frame, a plugin, or a custom
+// element that may have a closed shadow root. It returns null when focus is
+// on the body or nowhere.
+const focusStopScript = `() => {
+	` + cssPathFunction + `
+	let el = document.activeElement;
+	if (!el || el === document.body || el === document.documentElement) return null;
+	const selector = cssPath(el);
+	const path = [selector];
+	for (;;) {
+		let inner = null;
+		if (el.shadowRoot) {
+			inner = el.shadowRoot.activeElement;
+		} else if (el.contentDocument) {
+			const doc = el.contentDocument;
+			if (doc.activeElement !== doc.body && doc.activeElement !== doc.documentElement) {
+				inner = doc.activeElement;
+			}
+		}
+		if (!inner) break;
+		el = inner;
+		path.push(cssPath(el));
+	}
+	const tag = el.tagName.toLowerCase();
+	const opaque = ['iframe', 'frame', 'object', 'embed'].includes(tag) || (tag.includes('-') && !el.shadowRoot);
+	return {selector, key: path.join(' >>> '), opaque};
+}`
+
+// focusStop is the result of focusStopScript.
+type focusStop struct {
+	Selector string `json:"selector"`
+	Key      string `json:"key"`
+	Opaque   bool   `json:"opaque"`
+}
+
+// Focused returns the element that currently has keyboard focus
+// (document.activeElement). It returns nil with no error when focus is on
+// the document body, e.g. before anything has been tabbed to.
+// Call it after pressing Tab to verify tab order and focus traps.
+func (p *Pilot) Focused(ctx context.Context) (*Element, error) {
+	result, err := p.EvaluateWithArgs(ctx, focusedSelectorScript)
+	if err != nil {
+		return nil, err
+	}
+	selector, ok := result.(string)
+	if !ok || selector == "" {
+		return nil, nil
+	}
+	return p.Find(ctx, selector, &FindOptions{Timeout: time.Second})
+}
+
+// DefaultTabOrderMaxStops is the number of Tab presses TabOrder makes when
+// maxStops is not positive.
+const DefaultTabOrderMaxStops = 100
+
+// TabOrder presses Tab repeatedly from the current focus and records each
+// focused element, with its role and label, in order. It stops when focus
+// returns to the first stop, leaves the document, or maxStops Tab presses
+// have been made.
+//
+// If focus stops advancing, or loops through a subset of elements without
+// returning to the first stop, TabOrder returns the stops recorded so far
+// together with a *KeyboardTrapError.
+//
+// Focus is followed into same-origin frames and open shadow roots, but the
+// frame or shadow host is what is recorded for those stops. Focus inside a
+// cross-origin frame or closed shadow root cannot be followed, so repeated
+// stops on such an element are recorded once and not reported as a trap.
+//
+// Focus is left wherever the traversal ended.
+func (p *Pilot) TabOrder(ctx context.Context, maxStops int) ([]ElementInfo, error) {
+	if p.closed.Load() {
+		return nil, ErrConnectionClosed
+	}
+	if maxStops <= 0 {
+		maxStops = DefaultTabOrderMaxStops
+	}
+
+	keyboard, err := p.Keyboard(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var stops []ElementInfo
+	seen := make(map[string]int)
+	prev := ""
+
+	for presses := 0; presses < maxStops; presses++ {
+		if err := keyboard.Press(ctx, "Tab"); err != nil {
+			return stops, err
+		}
+
+		result, err := p.EvaluateWithArgs(ctx, focusStopScript)
+		if err != nil {
+			return stops, err
+		}
+		stop, err := decodeAs[*focusStop](result)
+		if err != nil {
+			return stops, err
+		}
+		if stop == nil {
+			// Focus moved past the last element to the browser UI
+			break
+		}
+
+		i, visited := seen[stop.Key]
+		if visited && i == 0 {
+			// Back at the first stop
+			break
+		}
+		if stop.Key == prev {
+			if stop.Opaque {
+				continue
+			}
+			return stops, &KeyboardTrapError{Selector: stop.Selector, Stop: len(stops)}
+		}
+		if visited {
+			return stops, &KeyboardTrapError{Selector: stop.Selector, Stop: len(stops), Cycle: true}
+		}
+
+		el, err := p.Find(ctx, stop.Selector, &FindOptions{Timeout: time.Second})
+		if err != nil {
+			return stops, err
+		}
+		info := el.Info()
+		info.Role, _ = el.Role(ctx)
+		info.Label, _ = el.Label(ctx)
+
+		seen[stop.Key] = len(stops)
+		stops = append(stops, info)
+		prev = stop.Key
+	}
+
+	return stops, nil
+}
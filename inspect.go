@@ -122,7 +122,7 @@ type InspectSummary struct {
 // Inspect examines the current page and returns information about interactive elements.
 // This is designed to help AI agents understand the page structure.
 func (p *Pilot) Inspect(ctx context.Context, opts *InspectOptions) (*InspectResult, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return nil, ErrConnectionClosed
 	}
 
@@ -4,6 +4,7 @@ package integration
 
 import (
 	"testing"
+	"time"
 
 	"github.com/plexusone/w3pilot"
 )
@@ -333,3 +334,24 @@ document.addEventListener('mouseup', () => { dragging = false; });
 	}
 	t.Logf("Final left: %v", finalLeft)
 }
+
+// TestWaitForSelectorHiddenFixed tests that waiting for a visible
+// position: fixed element to hide does not return early.
+func TestWaitForSelectorHiddenFixed(t *testing.T) {
+	bt := newBrowserTest(t)
+	defer bt.cleanup()
+
+	bt.go_(`data:text/html,<!DOCTYPE html>
+<html><body>
+<div id="modal" style="position: fixed; top: 10px; left: 10px;">Modal</div>
+</body></html>`)
+
+	if _, err := bt.pilot.WaitForSelector(bt.ctx, "#modal", "hidden", 500*time.Millisecond); err == nil {
+		t.Error("Expected timeout while the fixed modal stays visible")
+	}
+
+	bt.evaluate(`document.getElementById('modal').style.display = 'none'`)
+	if _, err := bt.pilot.WaitForSelector(bt.ctx, "#modal", "hidden", 5*time.Second); err != nil {
+		t.Errorf("WaitForSelector hidden failed after hiding: %v", err)
+	}
+}
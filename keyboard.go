@@ -2,6 +2,7 @@ package w3pilot
 
 import (
 	"context"
+	"time"
 )
 
 // Keyboard provides keyboard input control.
@@ -55,13 +56,26 @@ func (k *Keyboard) Up(ctx context.Context, key string) error {
 // Type types text character by character.
 // This sends individual keypress events for each character.
 func (k *Keyboard) Type(ctx context.Context, text string) error {
-	params := map[string]interface{}{
-		"context": k.context,
-		"text":    text,
+	return k.TypeWithOptions(ctx, text, nil)
+}
+
+// TypeWithOptions types text like Type. If opts.Delay is set, each character
+// is sent separately with that pause in between.
+func (k *Keyboard) TypeWithOptions(ctx context.Context, text string, opts *TypeOptions) error {
+	send := func(ctx context.Context, chunk string) error {
+		params := map[string]interface{}{
+			"context": k.context,
+			"text":    chunk,
+		}
+
+		_, err := k.client.Send(ctx, "vibium:keyboard.type", params)
+		return err
 	}
 
-	_, err := k.client.Send(ctx, "vibium:keyboard.type", params)
-	return err
+	if opts != nil && opts.Delay > 0 {
+		return typeWithDelay(ctx, text, opts.Delay, send)
+	}
+	return send(ctx, text)
 }
 
 // InsertText inserts text directly without keypress events.
@@ -75,3 +89,20 @@ func (k *Keyboard) InsertText(ctx context.Context, text string) error {
 	_, err := k.client.Send(ctx, "vibium:keyboard.insertText", params)
 	return err
 }
+
+// typeWithDelay calls send once per character of text, pausing delay between calls.
+func typeWithDelay(ctx context.Context, text string, delay time.Duration, send func(context.Context, string) error) error {
+	for i, r := range text {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+		if err := send(ctx, string(r)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
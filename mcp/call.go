@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ToolResult is the outcome of a tool call made with CallTool.
+type ToolResult struct {
+	// Result is the tool's structured output.
+	Result any `json:"result,omitempty"`
+
+	// IsError reports that the tool failed; Error holds the message.
+	IsError bool   `json:"isError,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// CallTool invokes a tool by name with JSON-encoded arguments. The call goes
+// through an in-process MCP client, so it runs the same handlers and
+// middleware as a call from an MCP client over stdio. This lets other
+// transports, such as the HTTP server, reuse the tool set.
+//
+// A tool that fails returns a ToolResult with IsError set; the error return
+// is reserved for protocol failures such as an unknown tool.
+func (s *Server) CallTool(ctx context.Context, name string, args json.RawMessage) (*ToolResult, error) {
+	cs, err := s.clientSession()
+	if err != nil {
+		return nil, err
+	}
+
+	var arguments any = map[string]any{}
+	if len(args) > 0 {
+		arguments = args
+	}
+
+	res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: arguments})
+	if err != nil {
+		return nil, err
+	}
+
+	out := &ToolResult{Result: res.StructuredContent, IsError: res.IsError}
+	if res.IsError {
+		var msgs []string
+		for _, c := range res.Content {
+			if text, ok := c.(*mcp.TextContent); ok {
+				msgs = append(msgs, text.Text)
+			}
+		}
+		out.Error = strings.Join(msgs, "\n")
+	}
+	return out, nil
+}
+
+// RegisteredTools lists the tools the server registers, as an MCP client
+// sees them. Unlike ListTools, which documents tools from a static table,
+// it is always complete. Categories come from that table where it lists
+// the tool, and are "other" otherwise.
+func (s *Server) RegisteredTools(ctx context.Context) (*ToolList, error) {
+	cs, err := s.clientSession()
+	if err != nil {
+		return nil, err
+	}
+
+	documented := make(map[string]string)
+	for _, cat := range toolDefinitions {
+		for _, tool := range cat.tools {
+			documented[tool.Name] = cat.category
+		}
+	}
+
+	list := &ToolList{Categories: make(map[string]int)}
+	for tool, err := range cs.Tools(ctx, nil) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+		category, ok := documented[tool.Name]
+		if !ok {
+			category = "other"
+		}
+		list.Tools = append(list.Tools, ToolInfo{
+			Name:        tool.Name,
+			Description: tool.Description,
+			Category:    category,
+		})
+		list.Categories[category]++
+	}
+
+	sort.Slice(list.Tools, func(i, j int) bool {
+		return list.Tools[i].Name < list.Tools[j].Name
+	})
+	list.Total = len(list.Tools)
+	return list, nil
+}
+
+// clientSession lazily connects an in-memory MCP client to the server.
+func (s *Server) clientSession() (*mcp.ClientSession, error) {
+	s.clientMu.Lock()
+	defer s.clientMu.Unlock()
+
+	if s.client != nil {
+		return s.client, nil
+	}
+
+	// The sessions outlive any single call, so they are not bound to a
+	// request context.
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := s.mcpServer.Connect(context.Background(), serverTransport, nil); err != nil {
+		return nil, fmt.Errorf("failed to connect in-process server: %w", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "w3pilot-inprocess", Version: "0.2.0"}, nil)
+	cs, err := client.Connect(context.Background(), clientTransport, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect in-process client: %w", err)
+	}
+	s.client = cs
+	return cs, nil
+}
@@ -17,6 +17,11 @@ type Config struct {
 	// InitScripts are JavaScript files to inject before any page scripts.
 	// Each string is the content of a script (not a file path).
 	InitScripts []string
+
+	// ScreenshotPolicy controls when tool calls attach a screenshot to their
+	// step result: "on-failure" (default), "always", or "never". Individual
+	// calls can override it with a screenshot_policy argument.
+	ScreenshotPolicy ScreenshotPolicy
 }
 
 // DefaultConfig returns a Config with sensible defaults.
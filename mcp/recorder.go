@@ -187,6 +187,15 @@ func (r *Recorder) RecordUncheck(selector string) {
 	})
 }
 
+// RecordSetChecked records a setChecked action.
+func (r *Recorder) RecordSetChecked(selector string, checked bool) {
+	r.AddStep(script.Step{
+		Action:   script.ActionSetChecked,
+		Selector: selector,
+		Checked:  checked,
+	})
+}
+
 // RecordSelect records a select action.
 func (r *Recorder) RecordSelect(selector, value string) {
 	r.AddStep(script.Step{
@@ -262,6 +271,22 @@ func (r *Recorder) RecordWaitForURL(pattern string) {
 	})
 }
 
+// RecordWaitForRequest records a waitForRequest action.
+func (r *Recorder) RecordWaitForRequest(pattern string) {
+	r.AddStep(script.Step{
+		Action:  script.ActionWaitForRequest,
+		Pattern: pattern,
+	})
+}
+
+// RecordWaitForResponse records a waitForResponse action.
+func (r *Recorder) RecordWaitForResponse(pattern string) {
+	r.AddStep(script.Step{
+		Action:  script.ActionWaitForResponse,
+		Pattern: pattern,
+	})
+}
+
 // RecordWaitForLoad records a waitForLoad action.
 func (r *Recorder) RecordWaitForLoad(state string) {
 	r.AddStep(script.Step{
@@ -270,6 +295,22 @@ func (r *Recorder) RecordWaitForLoad(state string) {
 	})
 }
 
+// RecordSaveStorageState records a saveStorageState action.
+func (r *Recorder) RecordSaveStorageState(file string) {
+	r.AddStep(script.Step{
+		Action: script.ActionSaveStorageState,
+		File:   file,
+	})
+}
+
+// RecordLoadStorageState records a loadStorageState action.
+func (r *Recorder) RecordLoadStorageState(file string) {
+	r.AddStep(script.Step{
+		Action: script.ActionLoadStorageState,
+		File:   file,
+	})
+}
+
 // RecordSetViewport records a setViewport action.
 func (r *Recorder) RecordSetViewport(width, height int) {
 	r.AddStep(script.Step{
@@ -301,6 +301,19 @@ func TestRecordActions(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:       "RecordSetChecked",
+			recordFunc: func(r *Recorder) { r.RecordSetChecked("#checkbox", true) },
+			wantAction: script.ActionSetChecked,
+			validate: func(t *testing.T, step script.Step) {
+				if step.Selector != "#checkbox" {
+					t.Errorf("Selector = %q, want %q", step.Selector, "#checkbox")
+				}
+				if !step.Checked {
+					t.Error("Checked = false, want true")
+				}
+			},
+		},
 		{
 			name:       "RecordSelect",
 			recordFunc: func(r *Recorder) { r.RecordSelect("#select", "option1") },
@@ -400,6 +413,26 @@ func TestRecordActions(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:       "RecordWaitForResponse",
+			recordFunc: func(r *Recorder) { r.RecordWaitForResponse("**/api/save") },
+			wantAction: script.ActionWaitForResponse,
+			validate: func(t *testing.T, step script.Step) {
+				if step.Pattern != "**/api/save" {
+					t.Errorf("Pattern = %q, want %q", step.Pattern, "**/api/save")
+				}
+			},
+		},
+		{
+			name:       "RecordLoadStorageState",
+			recordFunc: func(r *Recorder) { r.RecordLoadStorageState("auth.json") },
+			wantAction: script.ActionLoadStorageState,
+			validate: func(t *testing.T, step script.Step) {
+				if step.File != "auth.json" {
+					t.Errorf("File = %q, want %q", step.File, "auth.json")
+				}
+			},
+		},
 		{
 			name:       "RecordWaitForLoad",
 			recordFunc: func(r *Recorder) { r.RecordWaitForLoad("networkidle") },
@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ScreenshotPolicy controls when tool calls attach a screenshot to their
+// step result in the test report.
+type ScreenshotPolicy string
+
+const (
+	// ScreenshotOnFailure captures a screenshot only when a step fails (default).
+	ScreenshotOnFailure ScreenshotPolicy = "on-failure"
+
+	// ScreenshotAlways captures a screenshot after every browser step.
+	ScreenshotAlways ScreenshotPolicy = "always"
+
+	// ScreenshotNever disables screenshot capture for step results.
+	ScreenshotNever ScreenshotPolicy = "never"
+)
+
+// screenshotPolicyArg is the optional tool argument that overrides the
+// server's screenshot policy for a single call.
+const screenshotPolicyArg = "screenshot_policy"
+
+// ParseScreenshotPolicy validates a screenshot policy name.
+// An empty string selects ScreenshotOnFailure.
+func ParseScreenshotPolicy(s string) (ScreenshotPolicy, error) {
+	switch p := ScreenshotPolicy(s); p {
+	case "":
+		return ScreenshotOnFailure, nil
+	case ScreenshotOnFailure, ScreenshotAlways, ScreenshotNever:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid screenshot policy %q: must be on-failure, always, or never", s)
+	}
+}
+
+type screenshotPolicyKey struct{}
+
+// screenshotPolicyFrom returns the policy set for the current tool call, if any.
+func screenshotPolicyFrom(ctx context.Context) (ScreenshotPolicy, bool) {
+	p, ok := ctx.Value(screenshotPolicyKey{}).(ScreenshotPolicy)
+	return p, ok
+}
+
+// extractScreenshotPolicy removes the screenshot_policy argument from raw
+// tool arguments so typed handlers never see it. It returns an empty policy
+// and the original arguments when the argument is absent.
+func extractScreenshotPolicy(args json.RawMessage) (ScreenshotPolicy, json.RawMessage, error) {
+	if len(args) == 0 {
+		return "", args, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(args, &fields); err != nil {
+		// Not an object; leave validation to the tool handler.
+		return "", args, nil
+	}
+
+	raw, ok := fields[screenshotPolicyArg]
+	if !ok {
+		return "", args, nil
+	}
+
+	var name string
+	if err := json.Unmarshal(raw, &name); err != nil {
+		return "", nil, fmt.Errorf("%s must be a string", screenshotPolicyArg)
+	}
+	policy, err := ParseScreenshotPolicy(name)
+	if err != nil {
+		return "", nil, err
+	}
+
+	delete(fields, screenshotPolicyArg)
+	stripped, err := json.Marshal(fields)
+	if err != nil {
+		return "", nil, err
+	}
+	return policy, stripped, nil
+}
+
+// addTool registers a tool as mcp.AddTool does, with the optional
+// screenshot_policy argument added to its input schema so clients can
+// discover it. screenshotPolicyMiddleware removes the argument again
+// before the handler sees it.
+func addTool[In, Out any](s *Server, t *mcp.Tool, h mcp.ToolHandlerFor[In, Out]) {
+	schema, err := jsonschema.For[In](nil)
+	if err != nil {
+		panic(fmt.Sprintf("addTool: tool %q: %v", t.Name, err))
+	}
+	if schema.Properties == nil {
+		schema.Properties = make(map[string]*jsonschema.Schema)
+	}
+	schema.Properties[screenshotPolicyArg] = &jsonschema.Schema{
+		Type:        "string",
+		Enum:        []any{string(ScreenshotOnFailure), string(ScreenshotAlways), string(ScreenshotNever)},
+		Description: "Override the server's screenshot policy for this call",
+	}
+	t.InputSchema = schema
+	mcp.AddTool(s.mcpServer, t, h)
+}
+
+// screenshotPolicyMiddleware resolves the screenshot policy for each tool
+// call and, under ScreenshotAlways, attaches a screenshot to the steps the
+// call recorded.
+func (s *Server) screenshotPolicyMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		call, ok := req.(*mcp.CallToolRequest)
+		if !ok || call.Params == nil {
+			return next(ctx, method, req)
+		}
+
+		policy := s.session.config.ScreenshotPolicy
+		override, args, err := extractScreenshotPolicy(call.Params.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		if override != "" {
+			policy = override
+			call.Params.Arguments = args
+		}
+		ctx = context.WithValue(ctx, screenshotPolicyKey{}, policy)
+
+		from := s.session.StepCount()
+		result, err := next(ctx, method, req)
+		if policy == ScreenshotAlways {
+			s.session.attachScreenshot(ctx, from)
+		}
+		return result, err
+	}
+}
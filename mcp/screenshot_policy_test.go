@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestExtractScreenshotPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       string
+		wantPolicy ScreenshotPolicy
+		wantArgs   string
+		wantErr    bool
+	}{
+		{
+			name:     "absent",
+			args:     `{"selector":"#a"}`,
+			wantArgs: `{"selector":"#a"}`,
+		},
+		{
+			name:       "override stripped",
+			args:       `{"selector":"#a","screenshot_policy":"always"}`,
+			wantPolicy: ScreenshotAlways,
+			wantArgs:   `{"selector":"#a"}`,
+		},
+		{
+			name:    "invalid name",
+			args:    `{"screenshot_policy":"sometimes"}`,
+			wantErr: true,
+		},
+		{
+			name:    "not a string",
+			args:    `{"screenshot_policy":true}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, args, err := extractScreenshotPolicy(json.RawMessage(tt.args))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("extractScreenshotPolicy() error = %v", err)
+			}
+			if policy != tt.wantPolicy {
+				t.Errorf("policy = %q, want %q", policy, tt.wantPolicy)
+			}
+			if string(args) != tt.wantArgs {
+				t.Errorf("args = %s, want %s", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestParseScreenshotPolicy_Default(t *testing.T) {
+	policy, err := ParseScreenshotPolicy("")
+	if err != nil {
+		t.Fatalf("ParseScreenshotPolicy() error = %v", err)
+	}
+	if policy != ScreenshotOnFailure {
+		t.Errorf("policy = %q, want %q", policy, ScreenshotOnFailure)
+	}
+}
+
+func TestToolsListScreenshotPolicy(t *testing.T) {
+	cs, err := NewServer(DefaultConfig()).clientSession()
+	if err != nil {
+		t.Fatalf("clientSession() error = %v", err)
+	}
+
+	var count int
+	for tool, err := range cs.Tools(context.Background(), nil) {
+		if err != nil {
+			t.Fatalf("tools/list error = %v", err)
+		}
+		count++
+
+		data, err := json.Marshal(tool.InputSchema)
+		if err != nil {
+			t.Fatalf("%s: marshal input schema: %v", tool.Name, err)
+		}
+		var schema struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+			Required   []string                   `json:"required"`
+		}
+		if err := json.Unmarshal(data, &schema); err != nil {
+			t.Fatalf("%s: unmarshal input schema: %v", tool.Name, err)
+		}
+
+		raw, ok := schema.Properties[screenshotPolicyArg]
+		if !ok {
+			t.Errorf("%s: input schema has no %s property", tool.Name, screenshotPolicyArg)
+			continue
+		}
+		var prop struct {
+			Type string   `json:"type"`
+			Enum []string `json:"enum"`
+		}
+		if err := json.Unmarshal(raw, &prop); err != nil {
+			t.Fatalf("%s: unmarshal %s: %v", tool.Name, screenshotPolicyArg, err)
+		}
+		if prop.Type != "string" || len(prop.Enum) != 3 {
+			t.Errorf("%s: %s = %+v, want a string enum of 3 policies", tool.Name, screenshotPolicyArg, prop)
+		}
+		for _, name := range schema.Required {
+			if name == screenshotPolicyArg {
+				t.Errorf("%s: %s is required, want optional", tool.Name, screenshotPolicyArg)
+			}
+		}
+	}
+	if count == 0 {
+		t.Error("tools/list returned no tools")
+	}
+}
@@ -3,6 +3,7 @@ package mcp
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -19,7 +20,18 @@ type Session struct {
 	config        SessionConfig
 	results       []report.StepResult
 	stepNum       int
+	lastStepStart time.Time // Start time of the most recently recorded step
 	recorder      *Recorder
+	refs          *snapshotRefs // Refs from the latest page_snapshot, or nil
+}
+
+// snapshotRefs holds the ref map of the latest page snapshot and the page
+// and document it was taken in, so refs can be rejected once the page
+// navigates or another tab is selected.
+type snapshotRefs struct {
+	nodes    map[string]w3pilot.SnapshotNode
+	context  string // browsing context of the snapshot
+	document string // document token at snapshot time
 }
 
 // SessionConfig holds session configuration.
@@ -29,6 +41,10 @@ type SessionConfig struct {
 	Project        string
 	Target         string
 	InitScripts    []string
+
+	// ScreenshotPolicy controls when step results get a screenshot.
+	// Defaults to ScreenshotOnFailure.
+	ScreenshotPolicy ScreenshotPolicy
 }
 
 // NewSession creates a new Session.
@@ -39,6 +55,9 @@ func NewSession(config SessionConfig) *Session {
 	if config.Project == "" {
 		config.Project = "w3pilot-tests"
 	}
+	if config.ScreenshotPolicy == "" {
+		config.ScreenshotPolicy = ScreenshotOnFailure
+	}
 	return &Session{
 		config:   config,
 		results:  make([]report.StepResult, 0),
@@ -70,6 +89,25 @@ func (s *Session) LaunchIfNeeded(ctx context.Context) error {
 		return err
 	}
 
+	s.pilot.SetDefaultTimeout(s.config.DefaultTimeout)
+
+	// Buffer network events so wait_for_request/wait_for_response can match
+	// activity triggered by an earlier tool call. Best-effort.
+	_ = s.pilot.TrackNetwork(ctx)
+
+	// Start each page load with empty console/error buffers, however the
+	// navigation was triggered, so console_get_messages reports only what
+	// the current page produced. Best-effort.
+	resetOnNavigation := func(page *w3pilot.Pilot) {
+		bg := context.WithoutCancel(ctx)
+		_ = page.OnNavigation(bg, func(string) {
+			_ = page.ClearConsoleMessages(bg)
+			_ = page.ClearErrors(bg)
+		})
+	}
+	resetOnNavigation(s.pilot)
+	_ = s.pilot.OnPage(context.WithoutCancel(ctx), resetOnNavigation)
+
 	// Apply init scripts
 	for _, script := range s.config.InitScripts {
 		if err := s.pilot.AddInitScript(ctx, script); err != nil {
@@ -116,6 +154,53 @@ func (s *Session) RecordStep(result report.StepResult) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.results = append(s.results, result)
+	s.lastStepStart = time.Now().Add(-time.Duration(result.DurationMS) * time.Millisecond)
+}
+
+// LastStepStart returns when the most recently recorded step began,
+// or the zero time if no step has been recorded.
+func (s *Session) LastStepStart() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastStepStart
+}
+
+// StepCount returns the number of recorded step results.
+func (s *Session) StepCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.results)
+}
+
+// attachScreenshot captures one screenshot and attaches it to every step
+// recorded at or after index from that does not already have one.
+func (s *Session) attachScreenshot(ctx context.Context, from int) {
+	s.mu.Lock()
+	pending := false
+	for i := from; i < len(s.results); i++ {
+		if s.results[i].Screenshot == nil {
+			pending = true
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if !pending {
+		return
+	}
+
+	shot := s.CaptureScreenshot(ctx)
+	if shot == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := from; i < len(s.results); i++ {
+		if s.results[i].Screenshot == nil {
+			s.results[i].Screenshot = shot
+		}
+	}
 }
 
 // NextStepID returns the next step ID.
@@ -175,11 +260,70 @@ func (s *Session) Close(ctx context.Context) error {
 	if s.pilot != nil {
 		err := s.pilot.Quit(ctx)
 		s.pilot = nil
+		s.refs = nil
 		return err
 	}
 	return nil
 }
 
+// SessionStatus describes the health of the browser session.
+type SessionStatus struct {
+	Launched  bool
+	Alive     bool
+	URL       string
+	PageCount int
+	Error     string
+}
+
+// Status probes the browser and reports whether it still responds.
+// A launched browser that fails to answer within a few seconds is reported
+// as not alive; use Reconnect to recover.
+func (s *Session) Status(ctx context.Context) SessionStatus {
+	s.mu.Lock()
+	pilot := s.pilot
+	s.mu.Unlock()
+
+	if pilot == nil || pilot.IsClosed() {
+		return SessionStatus{}
+	}
+
+	status := SessionStatus{Launched: true}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	url, err := pilot.URL(ctx)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.Alive = true
+	status.URL = url
+
+	if pages, err := pilot.Pages(ctx); err == nil {
+		status.PageCount = len(pages)
+	}
+
+	return status
+}
+
+// Reconnect tears down the current browser, even if it has crashed, and
+// launches a new one with the session's configured settings. Recorded
+// steps and test results are preserved.
+func (s *Session) Reconnect(ctx context.Context) error {
+	s.mu.Lock()
+	if s.pilot != nil {
+		// The old browser may be unresponsive; ignore teardown errors.
+		_ = s.pilot.Quit(context.WithoutCancel(ctx))
+		s.pilot = nil
+	}
+	s.activeContext = ""
+	s.refs = nil
+	s.mu.Unlock()
+
+	return s.LaunchIfNeeded(ctx)
+}
+
 // SetTarget sets the test target description.
 func (s *Session) SetTarget(target string) {
 	s.mu.Lock()
@@ -208,15 +352,25 @@ func (s *Session) SetPilot(p *w3pilot.Pilot) {
 	defer s.mu.Unlock()
 	s.pilot = p
 	s.activeContext = "" // Clear active context since we're using a specific pilot
+	s.refs = nil
 }
 
 // CaptureScreenshot captures a screenshot and returns a ScreenshotRef.
+// It returns nil when the screenshot policy for the call is ScreenshotNever.
 func (s *Session) CaptureScreenshot(ctx context.Context) *report.ScreenshotRef {
+	policy, ok := screenshotPolicyFrom(ctx)
+	if !ok {
+		policy = s.config.ScreenshotPolicy
+	}
+	if policy == ScreenshotNever {
+		return nil
+	}
+
 	s.mu.Lock()
 	pilot := s.pilot
 	s.mu.Unlock()
 
-	if pilot == nil {
+	if pilot == nil || pilot.IsClosed() {
 		return nil
 	}
 
@@ -230,6 +384,24 @@ func (s *Session) CaptureScreenshot(ctx context.Context) *report.ScreenshotRef {
 	}
 }
 
+// CaptureElementScreenshot captures a screenshot with the given element
+// highlighted, so failure reports show which element was targeted.
+// Highlighting is best-effort; the screenshot is taken even if it fails.
+func (s *Session) CaptureElementScreenshot(ctx context.Context, elem *w3pilot.Element) *report.ScreenshotRef {
+	if policy, ok := screenshotPolicyFrom(ctx); ok && policy == ScreenshotNever {
+		return nil
+	}
+	if elem == nil {
+		return s.CaptureScreenshot(ctx)
+	}
+
+	if err := elem.Highlight(ctx, &w3pilot.HighlightOptions{Persistent: true}); err == nil {
+		defer func() { _ = elem.Unhighlight(ctx) }()
+	}
+
+	return s.CaptureScreenshot(ctx)
+}
+
 // CaptureContext captures the current page context.
 func (s *Session) CaptureContext(ctx context.Context) *report.StepContext {
 	s.mu.Lock()
@@ -266,7 +438,8 @@ func (s *Session) CaptureContext(ctx context.Context) *report.StepContext {
 	return stepContext
 }
 
-// FindSimilarSelectors attempts to find similar selectors to the given one.
+// FindSimilarSelectors returns selectors on the current page that resemble
+// the given one, for error suggestions. See w3pilot.Pilot.SuggestSelectors.
 func (s *Session) FindSimilarSelectors(ctx context.Context, selector string) []string {
 	s.mu.Lock()
 	pilot := s.pilot
@@ -276,61 +449,99 @@ func (s *Session) FindSimilarSelectors(ctx context.Context, selector string) []s
 		return nil
 	}
 
-	// Extract the base selector name for variations
-	baseName := selector
-	if len(baseName) > 0 && (baseName[0] == '#' || baseName[0] == '.') {
-		baseName = baseName[1:]
-	}
-
-	script := fmt.Sprintf(`
-		(function() {
-			const suggestions = [];
-			const base = %q;
-
-			// Try ID variations
-			['#' + base, '#' + base + '-btn', '#' + base + '-button', '#' + base + 'Btn'].forEach(sel => {
-				try { if (document.querySelector(sel)) suggestions.push(sel); } catch {}
-			});
-
-			// Try class variations
-			['.' + base, '.' + base + '-btn', '.' + base + '-button'].forEach(sel => {
-				try { if (document.querySelector(sel)) suggestions.push(sel); } catch {}
-			});
-
-			// Try data-testid
-			try {
-				const testId = document.querySelector('[data-testid="' + base + '"]');
-				if (testId) suggestions.push('[data-testid="' + base + '"]');
-			} catch {}
-
-			// Find buttons/inputs with similar text
-			document.querySelectorAll('button, input[type="submit"], a').forEach(el => {
-				const text = (el.textContent || el.value || '').toLowerCase();
-				if (text.includes(base.toLowerCase())) {
-					const id = el.id ? '#' + el.id : '';
-					const cls = el.className ? '.' + el.className.split(' ')[0] : '';
-					if (id) suggestions.push(id);
-					else if (cls) suggestions.push(cls);
-				}
-			});
+	suggestions, err := pilot.SuggestSelectors(ctx, selector, 0)
+	if err != nil {
+		return nil
+	}
+	return suggestions
+}
 
-			return [...new Set(suggestions)].slice(0, 5);
-		})()
-	`, baseName)
+// documentTokenScript returns a random token stored on the window, creating
+// it on first use. Every navigation gets a new window, so a changed token
+// means the page has navigated.
+const documentTokenScript = `() => {
+	if (!window.__w3pilotDocumentToken) {
+		window.__w3pilotDocumentToken = Math.random().toString(36).slice(2) + Date.now().toString(36);
+	}
+	return window.__w3pilotDocumentToken;
+}`
 
-	result, err := pilot.Evaluate(ctx, script)
+// documentToken returns the current document's token.
+func documentToken(ctx context.Context, pilot *w3pilot.Pilot) (string, error) {
+	result, err := pilot.EvaluateWithArgs(ctx, documentTokenScript)
 	if err != nil {
-		return nil
+		return "", err
 	}
+	token, _ := result.(string)
+	return token, nil
+}
 
-	if suggestions, ok := result.([]any); ok {
-		var strs []string
-		for _, s := range suggestions {
-			if str, ok := s.(string); ok {
-				strs = append(strs, str)
-			}
+// TakeSnapshot snapshots the current page and keeps its refs for
+// ResolveRef, replacing those of any earlier snapshot.
+func (s *Session) TakeSnapshot(ctx context.Context, opts w3pilot.SnapshotOptions) (*w3pilot.PageSnapshot, error) {
+	pilot, err := s.Pilot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snap, err := pilot.Snapshot(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	token, err := documentToken(ctx, pilot)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refs = &snapshotRefs{
+		nodes:    snap.Refs(),
+		context:  pilot.BrowsingContext(),
+		document: token,
+	}
+	return snap, nil
+}
+
+// ResolveRef returns the element for a ref from the latest snapshot, with
+// its snapshot node. It fails with an error wrapping w3pilot.ErrStaleRef if
+// the page has navigated, another tab is active, or the element is gone.
+func (s *Session) ResolveRef(ctx context.Context, ref string) (*w3pilot.Element, w3pilot.SnapshotNode, error) {
+	s.mu.Lock()
+	refs := s.refs
+	s.mu.Unlock()
+
+	if refs == nil {
+		return nil, w3pilot.SnapshotNode{}, fmt.Errorf("no snapshot taken: call page_snapshot first")
+	}
+	node, ok := refs.nodes[ref]
+	if !ok {
+		return nil, w3pilot.SnapshotNode{}, fmt.Errorf("unknown ref %q: not in the latest snapshot", ref)
+	}
+
+	pilot, err := s.Pilot(ctx)
+	if err != nil {
+		return nil, node, err
+	}
+	if pilot.BrowsingContext() != refs.context {
+		return nil, node, fmt.Errorf("%w: ref %s belongs to another tab; call page_snapshot again", w3pilot.ErrStaleRef, ref)
+	}
+	token, err := documentToken(ctx, pilot)
+	if err != nil {
+		return nil, node, err
+	}
+	if token != refs.document {
+		s.mu.Lock()
+		if s.refs == refs {
+			s.refs = nil
 		}
-		return strs
+		s.mu.Unlock()
+		return nil, node, fmt.Errorf("%w: ref %s is from before the page navigated; call page_snapshot again", w3pilot.ErrStaleRef, ref)
 	}
-	return nil
+
+	elem, err := pilot.FindRef(ctx, ref)
+	if errors.Is(err, w3pilot.ErrStaleRef) {
+		return nil, node, fmt.Errorf("%w: element for ref %s (%s %q) is no longer on the page; call page_snapshot again", w3pilot.ErrStaleRef, ref, node.Role, node.Name)
+	}
+	return elem, node, err
 }
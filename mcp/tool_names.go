@@ -43,8 +43,10 @@ package mcp
 // This serves as the single source of truth for all tool names.
 var ToolNames = struct {
 	// Browser
-	BrowserLaunch string
-	BrowserQuit   string
+	BrowserLaunch    string
+	BrowserQuit      string
+	BrowserStatus    string
+	BrowserReconnect string
 
 	// Page - Navigation
 	PageNavigate  string
@@ -79,6 +81,7 @@ var ToolNames = struct {
 	ElementPress          string
 	ElementCheck          string
 	ElementUncheck        string
+	ElementSetChecked     string
 	ElementSelect         string
 	ElementSetFiles       string
 	ElementHover          string
@@ -86,10 +89,15 @@ var ToolNames = struct {
 	ElementScrollIntoView string
 	ElementDragTo         string
 	ElementTap            string
+	ElementDoubleTap      string
+	ElementLongPress      string
 	ElementDispatchEvent  string
 	ElementScreenshot     string
 	ElementEvaluate       string
 
+	// Element - Navigation
+	ElementClickAndNavigate string
+
 	// Element - State
 	ElementGetText        string
 	ElementGetValue       string
@@ -103,8 +111,10 @@ var ToolNames = struct {
 	ElementIsEnabled      string
 	ElementIsChecked      string
 	ElementIsEditable     string
+	ElementCanClick       string
 	ElementGetRole        string
 	ElementGetLabel       string
+	ElementFindAll        string
 
 	// Input
 	InputKeyboardPress string
@@ -119,18 +129,21 @@ var ToolNames = struct {
 	InputMouseDrag     string
 	InputTouchTap      string
 	InputTouchSwipe    string
+	InputTouchPinch    string
 
 	// JavaScript
-	JSEvaluate   string
-	JSAddScript  string
-	JSAddStyle   string
-	JSInitScript string
+	JSEvaluate         string
+	JSEvaluateWithArgs string
+	JSAddScript        string
+	JSAddStyle         string
+	JSInitScript       string
 
 	// HTTP
 	HTTPRequest string
 
 	// Batch
-	BatchExecute string
+	BatchExecute  string
+	BatchRunSteps string
 
 	// Wait
 	WaitForState    string
@@ -139,6 +152,8 @@ var ToolNames = struct {
 	WaitForFunction string
 	WaitForSelector string
 	WaitForText     string
+	WaitForRequest  string
+	WaitForResponse string
 
 	// Tab
 	TabList   string
@@ -276,6 +291,12 @@ var ToolNames = struct {
 	// Page - Inspection
 	PageInspect string
 
+	// Page - Snapshot refs
+	PageSnapshot    string
+	ElementClickRef string
+	ElementFillRef  string
+	ElementHoverRef string
+
 	// Test - Validation
 	TestValidateSelectors string
 
@@ -290,8 +311,10 @@ var ToolNames = struct {
 	StateDelete string
 }{
 	// Browser
-	BrowserLaunch: "browser_launch",
-	BrowserQuit:   "browser_quit",
+	BrowserLaunch:    "browser_launch",
+	BrowserQuit:      "browser_quit",
+	BrowserStatus:    "browser_status",
+	BrowserReconnect: "browser_reconnect",
 
 	// Page - Navigation
 	PageNavigate:  "page_navigate",
@@ -326,6 +349,7 @@ var ToolNames = struct {
 	ElementPress:          "element_press",
 	ElementCheck:          "element_check",
 	ElementUncheck:        "element_uncheck",
+	ElementSetChecked:     "element_set_checked",
 	ElementSelect:         "element_select",
 	ElementSetFiles:       "element_set_files",
 	ElementHover:          "element_hover",
@@ -333,10 +357,15 @@ var ToolNames = struct {
 	ElementScrollIntoView: "element_scroll_into_view",
 	ElementDragTo:         "element_drag_to",
 	ElementTap:            "element_tap",
+	ElementDoubleTap:      "element_double_tap",
+	ElementLongPress:      "element_long_press",
 	ElementDispatchEvent:  "element_dispatch_event",
 	ElementScreenshot:     "element_screenshot",
 	ElementEvaluate:       "element_evaluate",
 
+	// Element - Navigation
+	ElementClickAndNavigate: "element_click_and_navigate",
+
 	// Element - State
 	ElementGetText:        "element_get_text",
 	ElementGetValue:       "element_get_value",
@@ -350,8 +379,10 @@ var ToolNames = struct {
 	ElementIsEnabled:      "element_is_enabled",
 	ElementIsChecked:      "element_is_checked",
 	ElementIsEditable:     "element_is_editable",
+	ElementCanClick:       "element_can_click",
 	ElementGetRole:        "element_get_role",
 	ElementGetLabel:       "element_get_label",
+	ElementFindAll:        "element_find_all",
 
 	// Input
 	InputKeyboardPress: "input_keyboard_press",
@@ -366,18 +397,21 @@ var ToolNames = struct {
 	InputMouseDrag:     "input_mouse_drag",
 	InputTouchTap:      "input_touch_tap",
 	InputTouchSwipe:    "input_touch_swipe",
+	InputTouchPinch:    "input_touch_pinch",
 
 	// JavaScript
-	JSEvaluate:   "js_evaluate",
-	JSAddScript:  "js_add_script",
-	JSAddStyle:   "js_add_style",
-	JSInitScript: "js_init_script",
+	JSEvaluate:         "js_evaluate",
+	JSEvaluateWithArgs: "js_evaluate_with_args",
+	JSAddScript:        "js_add_script",
+	JSAddStyle:         "js_add_style",
+	JSInitScript:       "js_init_script",
 
 	// HTTP
 	HTTPRequest: "http_request",
 
 	// Batch
-	BatchExecute: "batch_execute",
+	BatchExecute:  "batch_execute",
+	BatchRunSteps: "batch_run_steps",
 
 	// Wait
 	WaitForState:    "wait_for_state",
@@ -386,6 +420,8 @@ var ToolNames = struct {
 	WaitForFunction: "wait_for_function",
 	WaitForSelector: "wait_for_selector",
 	WaitForText:     "wait_for_text",
+	WaitForRequest:  "wait_for_request",
+	WaitForResponse: "wait_for_response",
 
 	// Tab
 	TabList:   "tab_list",
@@ -523,6 +559,12 @@ var ToolNames = struct {
 	// Page - Inspection
 	PageInspect: "page_inspect",
 
+	// Page - Snapshot refs
+	PageSnapshot:    "page_snapshot",
+	ElementClickRef: "element_click_ref",
+	ElementFillRef:  "element_fill_ref",
+	ElementHoverRef: "element_hover_ref",
+
 	// Test - Validation
 	TestValidateSelectors: "test_validate_selectors",
 
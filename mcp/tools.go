@@ -1,10 +1,16 @@
 package mcp
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -18,15 +24,22 @@ import (
 // SemanticSelector contains optional semantic selector fields for finding elements
 // by accessibility attributes instead of just CSS selectors.
 type SemanticSelector struct {
-	Role        string `json:"role,omitempty" jsonschema:"ARIA role (e.g. button, textbox, link)"`
-	Text        string `json:"text,omitempty" jsonschema:"Element text content"`
-	Label       string `json:"label,omitempty" jsonschema:"Associated label text"`
-	Placeholder string `json:"placeholder,omitempty" jsonschema:"Input placeholder text"`
-	TestID      string `json:"testid,omitempty" jsonschema:"data-testid attribute value"`
-	Alt         string `json:"alt,omitempty" jsonschema:"Image alt text"`
-	Title       string `json:"title,omitempty" jsonschema:"Element title attribute"`
-	XPath       string `json:"xpath,omitempty" jsonschema:"XPath expression"`
-	Near        string `json:"near,omitempty" jsonschema:"CSS selector of nearby element"`
+	Role        string  `json:"role,omitempty" jsonschema:"ARIA role (e.g. button, textbox, link)"`
+	Text        string  `json:"text,omitempty" jsonschema:"Element text content"`
+	Label       string  `json:"label,omitempty" jsonschema:"Associated label text"`
+	Placeholder string  `json:"placeholder,omitempty" jsonschema:"Input placeholder text"`
+	TestID      string  `json:"testid,omitempty" jsonschema:"data-testid attribute value"`
+	Alt         string  `json:"alt,omitempty" jsonschema:"Image alt text"`
+	Title       string  `json:"title,omitempty" jsonschema:"Element title attribute"`
+	XPath       string  `json:"xpath,omitempty" jsonschema:"XPath expression"`
+	Near        string  `json:"near,omitempty" jsonschema:"CSS selector of nearby element"`
+	Below       string  `json:"below,omitempty" jsonschema:"CSS selector of element the target is below"`
+	Above       string  `json:"above,omitempty" jsonschema:"CSS selector of element the target is above"`
+	LeftOf      string  `json:"left_of,omitempty" jsonschema:"CSS selector of element the target is left of"`
+	RightOf     string  `json:"right_of,omitempty" jsonschema:"CSS selector of element the target is right of"`
+	MaxDistance float64 `json:"max_distance,omitempty" jsonschema:"Maximum distance in pixels from the near/spatial anchor element"`
+	Visible     bool    `json:"visible,omitempty" jsonschema:"Only match visible elements"`
+	Hidden      bool    `json:"hidden,omitempty" jsonschema:"Only match hidden elements"`
 }
 
 // toFindOptions converts semantic selector fields to vibium.FindOptions.
@@ -42,6 +55,13 @@ func (s *SemanticSelector) toFindOptions(timeout time.Duration) *vibium.FindOpti
 		Title:       s.Title,
 		XPath:       s.XPath,
 		Near:        s.Near,
+		Below:       s.Below,
+		Above:       s.Above,
+		LeftOf:      s.LeftOf,
+		RightOf:     s.RightOf,
+		MaxDistance: s.MaxDistance,
+		Visible:     s.Visible,
+		Hidden:      s.Hidden,
 	}
 }
 
@@ -129,6 +149,74 @@ func (s *Server) handleBrowserQuit(
 	return nil, BrowserQuitOutput{Message: "Browser closed successfully"}, nil
 }
 
+type BrowserStatusInput struct{}
+
+type BrowserStatusOutput struct {
+	Launched  bool   `json:"launched"`
+	Alive     bool   `json:"alive"`
+	URL       string `json:"url,omitempty"`
+	PageCount int    `json:"page_count"`
+	Headless  bool   `json:"headless"`
+	Error     string `json:"error,omitempty"`
+}
+
+func (s *Server) handleBrowserStatus(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input BrowserStatusInput,
+) (*mcp.CallToolResult, BrowserStatusOutput, error) {
+	status := s.session.Status(ctx)
+
+	return nil, BrowserStatusOutput{
+		Launched:  status.Launched,
+		Alive:     status.Alive,
+		URL:       status.URL,
+		PageCount: status.PageCount,
+		Headless:  s.session.config.Headless,
+		Error:     status.Error,
+	}, nil
+}
+
+type BrowserReconnectInput struct{}
+
+type BrowserReconnectOutput struct {
+	Message string `json:"message"`
+}
+
+func (s *Server) handleBrowserReconnect(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input BrowserReconnectInput,
+) (*mcp.CallToolResult, BrowserReconnectOutput, error) {
+	start := time.Now()
+	err := s.session.Reconnect(ctx)
+	duration := time.Since(start)
+
+	result := report.StepResult{
+		ID:         s.session.NextStepID("browser_reconnect"),
+		Action:     "browser_reconnect",
+		Args:       map[string]any{"headless": s.session.config.Headless},
+		DurationMS: duration.Milliseconds(),
+	}
+
+	if err != nil {
+		result.Status = report.StatusNoGo
+		result.Severity = report.SeverityCritical
+		result.Error = &report.StepError{
+			Type:    "LaunchError",
+			Message: err.Error(),
+		}
+		s.session.RecordStep(result)
+		return nil, BrowserReconnectOutput{}, fmt.Errorf("failed to reconnect browser: %w", err)
+	}
+
+	result.Status = report.StatusGo
+	result.Severity = report.SeverityInfo
+	s.session.RecordStep(result)
+
+	return nil, BrowserReconnectOutput{Message: "Browser relaunched successfully"}, nil
+}
+
 type NavigateInput struct {
 	URL string `json:"url" jsonschema:"The URL to navigate to,required"`
 }
@@ -254,7 +342,7 @@ func (s *Server) handleClick(
 			Message:  err.Error(),
 			Selector: input.Selector,
 		}
-		result.Screenshot = s.session.CaptureScreenshot(ctx)
+		result.Screenshot = s.session.CaptureElementScreenshot(ctx, elem)
 		s.session.RecordStep(result)
 		return nil, ClickOutput{}, fmt.Errorf("click failed: %w", err)
 	}
@@ -269,10 +357,98 @@ func (s *Server) handleClick(
 	return nil, ClickOutput{Message: fmt.Sprintf("Clicked %s", input.Selector)}, nil
 }
 
+type ClickAndNavigateInput struct {
+	Selector  string `json:"selector" jsonschema:"CSS selector for the element to click (can be empty if using semantic selectors)"`
+	TimeoutMS int    `json:"timeout_ms" jsonschema:"Timeout in milliseconds for the click and navigation together (default: 30000)"`
+	SemanticSelector
+}
+
+type ClickAndNavigateOutput struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+}
+
+func (s *Server) handleClickAndNavigate(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ClickAndNavigateInput,
+) (*mcp.CallToolResult, ClickAndNavigateOutput, error) {
+	pilot, err := s.session.Pilot(ctx)
+	if err != nil {
+		return nil, ClickAndNavigateOutput{}, fmt.Errorf("browser not available: %w", err)
+	}
+
+	if input.TimeoutMS == 0 {
+		input.TimeoutMS = 30000
+	}
+	timeout := time.Duration(input.TimeoutMS) * time.Millisecond
+
+	start := time.Now()
+	findOpts := input.SemanticSelector.toFindOptions(timeout)
+	elem, err := pilot.Find(ctx, input.Selector, findOpts)
+
+	result := report.StepResult{
+		ID:     s.session.NextStepID("click_and_navigate"),
+		Action: "click_and_navigate",
+		Args:   map[string]any{"selector": input.Selector},
+	}
+
+	if err != nil {
+		result.DurationMS = time.Since(start).Milliseconds()
+		result.Status = report.StatusNoGo
+		result.Severity = report.SeverityCritical
+		result.Error = &report.StepError{
+			Type:        "ElementNotFoundError",
+			Message:     err.Error(),
+			Selector:    input.Selector,
+			TimeoutMS:   int64(input.TimeoutMS),
+			Suggestions: s.session.FindSimilarSelectors(ctx, input.Selector),
+		}
+		result.Context = s.session.CaptureContext(ctx)
+		result.Screenshot = s.session.CaptureScreenshot(ctx)
+		s.session.RecordStep(result)
+		return nil, ClickAndNavigateOutput{}, fmt.Errorf("element not found: %s", input.Selector)
+	}
+
+	err = elem.ClickAndWaitForNavigation(ctx, &vibium.ActionOptions{Timeout: timeout})
+	result.DurationMS = time.Since(start).Milliseconds()
+
+	if err != nil {
+		result.Status = report.StatusNoGo
+		result.Severity = report.SeverityCritical
+		result.Error = &report.StepError{
+			Type:     "NavigationError",
+			Message:  err.Error(),
+			Selector: input.Selector,
+		}
+		result.Screenshot = s.session.CaptureScreenshot(ctx)
+		s.session.RecordStep(result)
+		return nil, ClickAndNavigateOutput{}, fmt.Errorf("click and navigate failed: %w", err)
+	}
+
+	currentURL, _ := pilot.URL(ctx)
+	currentTitle, _ := pilot.Title(ctx)
+
+	result.Status = report.StatusGo
+	result.Severity = report.SeverityInfo
+	result.Result = map[string]any{
+		"url":   currentURL,
+		"title": currentTitle,
+	}
+	s.session.RecordStep(result)
+
+	// Record for script export
+	s.session.Recorder().RecordClick(input.Selector)
+	s.session.Recorder().RecordWaitForLoad("load")
+
+	return nil, ClickAndNavigateOutput{URL: currentURL, Title: currentTitle}, nil
+}
+
 type TypeInput struct {
 	Selector  string `json:"selector" jsonschema:"CSS selector for the input element (can be empty if using semantic selectors)"`
 	Text      string `json:"text" jsonschema:"Text to type,required"`
 	TimeoutMS int    `json:"timeout_ms" jsonschema:"Timeout in milliseconds (default: 5000)"`
+	DelayMS   int    `json:"delay_ms,omitempty" jsonschema:"Delay between characters in milliseconds, for autocomplete or masked inputs (default: 0)"`
 	SemanticSelector
 }
 
@@ -322,7 +498,10 @@ func (s *Server) handleType(
 		return nil, TypeOutput{}, fmt.Errorf("element not found: %s", input.Selector)
 	}
 
-	err = elem.Type(ctx, input.Text, &vibium.ActionOptions{Timeout: timeout})
+	err = elem.Type(ctx, input.Text, &vibium.ActionOptions{
+		Timeout: timeout,
+		Delay:   time.Duration(input.DelayMS) * time.Millisecond,
+	})
 	result.DurationMS = time.Since(start).Milliseconds()
 
 	if err != nil {
@@ -333,7 +512,7 @@ func (s *Server) handleType(
 			Message:  err.Error(),
 			Selector: input.Selector,
 		}
-		result.Screenshot = s.session.CaptureScreenshot(ctx)
+		result.Screenshot = s.session.CaptureElementScreenshot(ctx, elem)
 		s.session.RecordStep(result)
 		return nil, TypeOutput{}, fmt.Errorf("type failed: %w", err)
 	}
@@ -420,8 +599,9 @@ func (s *Server) handleGetText(
 }
 
 type ScreenshotInput struct {
-	Format string `json:"format" jsonschema:"Output format: base64 (default) or file,enum=base64,enum=file"`
-	Path   string `json:"path" jsonschema:"File path (required if format is file)"`
+	Format  string `json:"format" jsonschema:"Output format: base64 (PNG, default), jpeg (base64 JPEG), or file,enum=base64,enum=jpeg,enum=file"`
+	Path    string `json:"path" jsonschema:"File path (required if format is file). A .jpg or .jpeg extension writes JPEG"`
+	Quality int    `json:"quality" jsonschema:"JPEG quality 1-100 (default: 80)"`
 }
 
 type ScreenshotOutput struct {
@@ -443,6 +623,15 @@ func (s *Server) handleScreenshot(
 	if input.Format == "" {
 		input.Format = "base64"
 	}
+	switch input.Format {
+	case "base64", "jpeg":
+	case "file":
+		if input.Path == "" {
+			return nil, ScreenshotOutput{}, fmt.Errorf("path is required when format is file")
+		}
+	default:
+		return nil, ScreenshotOutput{}, fmt.Errorf("unsupported format: %s", input.Format)
+	}
 
 	start := time.Now()
 	data, err := pilot.Screenshot(ctx)
@@ -466,22 +655,80 @@ func (s *Server) handleScreenshot(
 		return nil, ScreenshotOutput{}, fmt.Errorf("screenshot failed: %w", err)
 	}
 
+	output := ScreenshotOutput{Format: input.Format}
+	switch input.Format {
+	case "base64":
+		output.Data = base64.StdEncoding.EncodeToString(data)
+	case "jpeg":
+		jpegData, err := pngToJPEG(data, input.Quality)
+		if err != nil {
+			return nil, ScreenshotOutput{}, fmt.Errorf("jpeg encoding failed: %w", err)
+		}
+		output.Data = base64.StdEncoding.EncodeToString(jpegData)
+	case "file":
+		ext := strings.ToLower(filepath.Ext(input.Path))
+		if ext == ".jpg" || ext == ".jpeg" {
+			if data, err = pngToJPEG(data, input.Quality); err != nil {
+				return nil, ScreenshotOutput{}, fmt.Errorf("jpeg encoding failed: %w", err)
+			}
+		}
+		if err := writeOutputFile(input.Path, data); err != nil {
+			return nil, ScreenshotOutput{}, err
+		}
+		output.Path = input.Path
+		result.Args["path"] = input.Path
+	}
+
 	result.Status = report.StatusGo
 	result.Severity = report.SeverityInfo
 	s.session.RecordStep(result)
 
 	// Record for script export
-	s.session.Recorder().RecordScreenshot("screenshot.png", false)
-
-	output := ScreenshotOutput{Format: input.Format}
-	if input.Format == "base64" {
-		output.Data = base64.StdEncoding.EncodeToString(data)
+	if input.Format == "file" {
+		s.session.Recorder().RecordScreenshot(input.Path, false)
+	} else {
+		s.session.Recorder().RecordScreenshot("screenshot.png", false)
 	}
-	// TODO: Handle file format
 
 	return nil, output, nil
 }
 
+// pngToJPEG re-encodes PNG image data as JPEG. Quality <= 0 defaults to 80.
+func pngToJPEG(data []byte, quality int) ([]byte, error) {
+	if quality <= 0 {
+		quality = 80
+	}
+	if quality > 100 {
+		quality = 100
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeOutputFile writes data to path, creating parent directories as needed.
+func writeOutputFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
 type GetTitleInput struct{}
 
 type GetTitleOutput struct {
@@ -586,6 +833,65 @@ func (s *Server) handleEvaluate(
 	return nil, output, nil
 }
 
+type EvaluateWithArgsInput struct {
+	Function      string `json:"function" jsonschema:"JavaScript function declaration, e.g. (id) => document.getElementById(id)?.textContent,required"`
+	Args          []any  `json:"args" jsonschema:"Arguments passed to the function in order. Values are serialized, not interpolated, so no escaping is needed."`
+	MaxResultSize int    `json:"max_result_size" jsonschema:"Maximum result size in characters (0=unlimited). If exceeded the result is truncated."`
+}
+
+func (s *Server) handleEvaluateWithArgs(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input EvaluateWithArgsInput,
+) (*mcp.CallToolResult, EvaluateOutput, error) {
+	pilot, err := s.session.Pilot(ctx)
+	if err != nil {
+		return nil, EvaluateOutput{}, fmt.Errorf("browser not available: %w", err)
+	}
+
+	start := time.Now()
+	result, err := pilot.EvaluateWithArgs(ctx, input.Function, input.Args...)
+	duration := time.Since(start)
+
+	stepResult := report.StepResult{
+		ID:         s.session.NextStepID("evaluate_with_args"),
+		Action:     "evaluate_with_args",
+		Args:       map[string]any{"function": truncateString(input.Function, 100), "args": len(input.Args)},
+		DurationMS: duration.Milliseconds(),
+	}
+
+	if err != nil {
+		stepResult.Status = report.StatusNoGo
+		stepResult.Severity = report.SeverityMedium
+		stepResult.Error = &report.StepError{
+			Type:    "EvaluateError",
+			Message: err.Error(),
+		}
+		s.session.RecordStep(stepResult)
+		return nil, EvaluateOutput{}, fmt.Errorf("evaluate failed: %w", err)
+	}
+
+	stepResult.Status = report.StatusGo
+	stepResult.Severity = report.SeverityInfo
+	s.session.RecordStep(stepResult)
+
+	// Record for script export. JSON is a valid JavaScript literal, so the
+	// call replays as an IIFE with the arguments inlined.
+	if argsJSON, err := json.Marshal(input.Args); err == nil {
+		if input.Args == nil {
+			argsJSON = []byte("[]")
+		}
+		s.session.Recorder().RecordEval(fmt.Sprintf("(%s)(...%s)", input.Function, argsJSON))
+	}
+
+	output := EvaluateOutput{Result: result}
+	if input.MaxResultSize > 0 {
+		output = truncateEvaluateResult(result, input.MaxResultSize)
+	}
+
+	return nil, output, nil
+}
+
 type AssertTextInput struct {
 	Text     string `json:"text" jsonschema:"Text to search for,required"`
 	Selector string `json:"selector" jsonschema:"Optional: limit search to element matching selector"`
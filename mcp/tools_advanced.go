@@ -18,6 +18,7 @@ type DragToInput struct {
 	SourceSelector string `json:"source_selector" jsonschema:"CSS selector for the element to drag,required"`
 	TargetSelector string `json:"target_selector" jsonschema:"CSS selector for the drop target,required"`
 	TimeoutMS      int    `json:"timeout_ms" jsonschema:"Timeout in milliseconds (default: 5000)"`
+	Mode           string `json:"mode,omitempty" jsonschema:"Drag model: pointer (mouse events), html5 (drag events with a DataTransfer), or auto (html5 if the source is draggable=true; default)"`
 }
 
 type DragToOutput struct {
@@ -50,13 +51,29 @@ func (s *Server) handleDragTo(
 		return nil, DragToOutput{}, fmt.Errorf("target element not found: %s", input.TargetSelector)
 	}
 
+	mode := input.Mode
+	switch mode {
+	case "", "auto":
+		mode = "pointer"
+		if draggable, err := source.IsHTML5Draggable(ctx); err == nil && draggable {
+			mode = "html5"
+		}
+	case "pointer", "html5":
+	default:
+		return nil, DragToOutput{}, fmt.Errorf("invalid mode %q: use pointer, html5, or auto", input.Mode)
+	}
+
 	result := report.StepResult{
 		ID:     s.session.NextStepID("drag_to"),
 		Action: "drag_to",
-		Args:   map[string]any{"source": input.SourceSelector, "target": input.TargetSelector},
+		Args:   map[string]any{"source": input.SourceSelector, "target": input.TargetSelector, "mode": mode},
 	}
 
-	err = source.DragTo(ctx, target, &vibium.ActionOptions{Timeout: timeout})
+	if mode == "html5" {
+		err = source.DragToHTML5(ctx, target, &vibium.HTML5DragOptions{Timeout: timeout})
+	} else {
+		err = source.DragTo(ctx, target, &vibium.ActionOptions{Timeout: timeout})
+	}
 	result.DurationMS = time.Since(start).Milliseconds()
 
 	if err != nil {
@@ -74,7 +91,7 @@ func (s *Server) handleDragTo(
 	result.Severity = report.SeverityInfo
 	s.session.RecordStep(result)
 
-	return nil, DragToOutput{Message: fmt.Sprintf("Dragged %s to %s", input.SourceSelector, input.TargetSelector)}, nil
+	return nil, DragToOutput{Message: fmt.Sprintf("Dragged %s to %s (%s)", input.SourceSelector, input.TargetSelector, mode)}, nil
 }
 
 // Tap tool
@@ -116,6 +133,86 @@ func (s *Server) handleTap(
 	return nil, TapOutput{Message: fmt.Sprintf("Tapped %s", input.Selector)}, nil
 }
 
+// DoubleTap tool
+
+type DoubleTapInput struct {
+	Selector  string `json:"selector" jsonschema:"CSS selector for the element,required"`
+	TimeoutMS int    `json:"timeout_ms" jsonschema:"Timeout in milliseconds (default: 5000)"`
+}
+
+type DoubleTapOutput struct {
+	Message string `json:"message"`
+}
+
+func (s *Server) handleDoubleTap(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input DoubleTapInput,
+) (*mcp.CallToolResult, DoubleTapOutput, error) {
+	pilot, err := s.session.Pilot(ctx)
+	if err != nil {
+		return nil, DoubleTapOutput{}, fmt.Errorf("browser not available: %w", err)
+	}
+
+	if input.TimeoutMS == 0 {
+		input.TimeoutMS = 5000
+	}
+	timeout := time.Duration(input.TimeoutMS) * time.Millisecond
+
+	elem, err := pilot.Find(ctx, input.Selector, &vibium.FindOptions{Timeout: timeout})
+	if err != nil {
+		return nil, DoubleTapOutput{}, fmt.Errorf("element not found: %s", input.Selector)
+	}
+
+	err = elem.DoubleTap(ctx, &vibium.ActionOptions{Timeout: timeout})
+	if err != nil {
+		return nil, DoubleTapOutput{}, fmt.Errorf("double tap failed: %w", err)
+	}
+
+	return nil, DoubleTapOutput{Message: fmt.Sprintf("Double-tapped %s", input.Selector)}, nil
+}
+
+// LongPress tool
+
+type LongPressInput struct {
+	Selector   string `json:"selector" jsonschema:"CSS selector for the element,required"`
+	DurationMS int    `json:"duration_ms" jsonschema:"How long to hold in milliseconds (default: 800)"`
+	TimeoutMS  int    `json:"timeout_ms" jsonschema:"Timeout in milliseconds (default: 5000)"`
+}
+
+type LongPressOutput struct {
+	Message string `json:"message"`
+}
+
+func (s *Server) handleLongPress(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input LongPressInput,
+) (*mcp.CallToolResult, LongPressOutput, error) {
+	pilot, err := s.session.Pilot(ctx)
+	if err != nil {
+		return nil, LongPressOutput{}, fmt.Errorf("browser not available: %w", err)
+	}
+
+	if input.TimeoutMS == 0 {
+		input.TimeoutMS = 5000
+	}
+	timeout := time.Duration(input.TimeoutMS) * time.Millisecond
+
+	elem, err := pilot.Find(ctx, input.Selector, &vibium.FindOptions{Timeout: timeout})
+	if err != nil {
+		return nil, LongPressOutput{}, fmt.Errorf("element not found: %s", input.Selector)
+	}
+
+	duration := time.Duration(input.DurationMS) * time.Millisecond
+	err = elem.LongPress(ctx, duration, &vibium.ActionOptions{Timeout: timeout})
+	if err != nil {
+		return nil, LongPressOutput{}, fmt.Errorf("long press failed: %w", err)
+	}
+
+	return nil, LongPressOutput{Message: fmt.Sprintf("Long-pressed %s", input.Selector)}, nil
+}
+
 // DispatchEvent tool
 
 type DispatchEventInput struct {
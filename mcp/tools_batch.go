@@ -1,14 +1,18 @@
 package mcp
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	w3pilot "github.com/plexusone/w3pilot"
+	"github.com/plexusone/w3pilot/mcp/report"
+	"github.com/plexusone/w3pilot/script"
 )
 
 // BatchStep represents a single step in a batch execution.
@@ -167,6 +171,21 @@ func (s *Server) executeStep(ctx context.Context, pilot *w3pilot.Pilot, step Bat
 		}
 		return map[string]any{"selector": selector, "clicked": true}, nil
 
+	case "element_click_and_navigate":
+		selector, _ := args["selector"].(string)
+		if selector == "" {
+			return nil, fmt.Errorf("selector is required")
+		}
+		elem, err := pilot.Find(ctx, selector, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := elem.ClickAndWaitForNavigation(ctx, nil); err != nil {
+			return nil, err
+		}
+		url, _ := pilot.URL(ctx)
+		return map[string]any{"selector": selector, "url": url}, nil
+
 	case "element_fill":
 		selector, _ := args["selector"].(string)
 		value, _ := args["value"].(string)
@@ -248,7 +267,7 @@ func (s *Server) executeStep(ctx context.Context, pilot *w3pilot.Pilot, step Bat
 		if t, ok := args["timeout_ms"].(float64); ok && t > 0 {
 			timeout = time.Duration(t) * time.Millisecond
 		}
-		err := pilot.WaitForNavigation(ctx, timeout)
+		err := pilot.WaitForLoad(ctx, "load", timeout)
 		if err != nil {
 			return nil, err
 		}
@@ -346,3 +365,144 @@ func (s *Server) executeHTTPRequest(ctx context.Context, pilot *w3pilot.Pilot, a
 
 	return result, nil
 }
+
+// BatchRunStepsInput defines the input for batch_run_steps tool.
+type BatchRunStepsInput struct {
+	Steps           []map[string]any `json:"steps" jsonschema:"Array of script steps in the same format as w3pilot run scripts (e.g. {\"action\": \"click\", \"selector\": \"#submit\"}),required"`
+	ContinueOnError bool             `json:"continue_on_error" jsonschema:"Continue execution even if a step fails"`
+}
+
+// RunStepResult represents the result of a single script step.
+type RunStepResult struct {
+	Index      int    `json:"index"`
+	Action     string `json:"action"`
+	Name       string `json:"name"`
+	Success    bool   `json:"success"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// BatchRunStepsOutput defines the output for batch_run_steps tool.
+type BatchRunStepsOutput struct {
+	Results       []RunStepResult `json:"results"`
+	TotalSteps    int             `json:"total_steps"`
+	SuccessCount  int             `json:"success_count"`
+	FailureCount  int             `json:"failure_count"`
+	StoppedEarly  bool            `json:"stopped_early,omitempty"`
+	TotalDuration int64           `json:"total_duration_ms"`
+}
+
+// parseBatchSteps decodes and validates batch_run_steps steps as strictly
+// as script.Parse and w3pilot validate check script files: unknown fields,
+// unknown actions, missing required fields, and bad durations are errors.
+// Steps are numbered from 1, as in validation errors.
+func parseBatchSteps(raw []map[string]any) ([]script.Step, error) {
+	steps := make([]script.Step, len(raw))
+	for i, fields := range raw {
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&steps[i]); err != nil {
+			return nil, fmt.Errorf("step %d: invalid step: %w", i+1, err)
+		}
+	}
+
+	if errs := script.Validate(&script.Script{Steps: steps}); len(errs) > 0 {
+		msgs := make([]string, len(errs))
+		for i, e := range errs {
+			msgs[i] = e.Error()
+		}
+		return nil, fmt.Errorf("invalid steps: %s", strings.Join(msgs, "; "))
+	}
+	return steps, nil
+}
+
+func (s *Server) handleBatchRunSteps(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input BatchRunStepsInput,
+) (*mcp.CallToolResult, BatchRunStepsOutput, error) {
+	// Check every step before running any, so a malformed step does not
+	// leave the page half changed
+	steps, err := parseBatchSteps(input.Steps)
+	if err != nil {
+		return nil, BatchRunStepsOutput{}, err
+	}
+
+	pilot, err := s.session.Pilot(ctx)
+	if err != nil {
+		return nil, BatchRunStepsOutput{}, fmt.Errorf("browser not available: %w", err)
+	}
+
+	output := BatchRunStepsOutput{
+		Results:    make([]RunStepResult, 0, len(steps)),
+		TotalSteps: len(steps),
+	}
+
+	startTime := time.Now()
+	prevStepStart := s.session.LastStepStart()
+
+	for i, step := range steps {
+		name := step.Name
+		if name == "" {
+			name = script.DescribeStep(step)
+		}
+
+		stepStart := time.Now()
+		err := script.ExecuteStep(ctx, pilot, step, prevStepStart)
+		duration := time.Since(stepStart)
+		prevStepStart = stepStart
+
+		stepResult := RunStepResult{
+			Index:      i,
+			Action:     string(step.Action),
+			Name:       name,
+			DurationMS: duration.Milliseconds(),
+		}
+
+		result := report.StepResult{
+			ID:         s.session.NextStepID(string(step.Action)),
+			Action:     string(step.Action),
+			Args:       map[string]any{"step": name},
+			DurationMS: duration.Milliseconds(),
+		}
+
+		if err != nil {
+			stepResult.Error = err.Error()
+			output.FailureCount++
+
+			result.Status = report.StatusNoGo
+			result.Severity = report.SeverityCritical
+			result.Error = &report.StepError{
+				Type:     "StepError",
+				Message:  err.Error(),
+				Selector: step.Selector,
+			}
+			result.Screenshot = s.session.CaptureScreenshot(ctx)
+		} else {
+			stepResult.Success = true
+			output.SuccessCount++
+
+			result.Status = report.StatusGo
+			result.Severity = report.SeverityInfo
+
+			// Record for script export
+			s.session.Recorder().AddStep(step)
+		}
+
+		s.session.RecordStep(result)
+		output.Results = append(output.Results, stepResult)
+
+		if err != nil && !input.ContinueOnError && !step.ContinueOnError {
+			output.StoppedEarly = true
+			break
+		}
+	}
+
+	output.TotalDuration = time.Since(startTime).Milliseconds()
+
+	return nil, output, nil
+}
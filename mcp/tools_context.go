@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -249,10 +250,13 @@ func (s *Server) handleDeleteCookie(
 
 // GetStorageState tool
 
-type GetStorageStateInput struct{}
+type GetStorageStateInput struct {
+	Path string `json:"path,omitempty" jsonschema:"File path to save the state to instead of returning it inline"`
+}
 
 type GetStorageStateOutput struct {
-	State string `json:"state"`
+	State string `json:"state,omitempty"`
+	Path  string `json:"path,omitempty"`
 }
 
 func (s *Server) handleGetStorageState(
@@ -271,6 +275,21 @@ func (s *Server) handleGetStorageState(
 		return nil, GetStorageStateOutput{}, fmt.Errorf("get storage state failed: %w", err)
 	}
 
+	if input.Path != "" {
+		jsonBytes, err := json.MarshalIndent(state, "", "  ")
+		if err != nil {
+			return nil, GetStorageStateOutput{}, fmt.Errorf("json marshal failed: %w", err)
+		}
+		if err := writeOutputFile(input.Path, jsonBytes); err != nil {
+			return nil, GetStorageStateOutput{}, err
+		}
+
+		// Record for script export
+		s.session.Recorder().RecordSaveStorageState(input.Path)
+
+		return nil, GetStorageStateOutput{Path: input.Path}, nil
+	}
+
 	jsonBytes, err := json.Marshal(state)
 	if err != nil {
 		return nil, GetStorageStateOutput{}, fmt.Errorf("json marshal failed: %w", err)
@@ -282,7 +301,8 @@ func (s *Server) handleGetStorageState(
 // SetStorageState tool
 
 type SetStorageStateInput struct {
-	State string `json:"state" jsonschema:"JSON from get_storage_state containing cookies, localStorage, and sessionStorage,required"`
+	State string `json:"state,omitempty" jsonschema:"JSON from get_storage_state containing cookies, localStorage, and sessionStorage"`
+	Path  string `json:"path,omitempty" jsonschema:"File path of a state saved with get_storage_state (alternative to state)"`
 }
 
 type SetStorageStateOutput struct {
@@ -294,14 +314,26 @@ func (s *Server) handleSetStorageState(
 	req *mcp.CallToolRequest,
 	input SetStorageStateInput,
 ) (*mcp.CallToolResult, SetStorageStateOutput, error) {
+	if (input.State == "") == (input.Path == "") {
+		return nil, SetStorageStateOutput{}, fmt.Errorf("exactly one of state or path is required")
+	}
+
 	pilot, err := s.session.Pilot(ctx)
 	if err != nil {
 		return nil, SetStorageStateOutput{}, fmt.Errorf("browser not available: %w", err)
 	}
 
+	data := []byte(input.State)
+	if input.Path != "" {
+		data, err = os.ReadFile(input.Path)
+		if err != nil {
+			return nil, SetStorageStateOutput{}, fmt.Errorf("failed to read state file: %w", err)
+		}
+	}
+
 	// Parse the storage state JSON
 	var state vibium.StorageState
-	if err := json.Unmarshal([]byte(input.State), &state); err != nil {
+	if err := json.Unmarshal(data, &state); err != nil {
 		return nil, SetStorageStateOutput{}, fmt.Errorf("invalid storage state JSON: %w", err)
 	}
 
@@ -310,6 +342,12 @@ func (s *Server) handleSetStorageState(
 		return nil, SetStorageStateOutput{}, fmt.Errorf("set storage state failed: %w", err)
 	}
 
+	// Record for script export. Inline state is not recorded to keep
+	// credentials out of exported scripts.
+	if input.Path != "" {
+		s.session.Recorder().RecordLoadStorageState(input.Path)
+	}
+
 	// Count what was restored
 	cookieCount := len(state.Cookies)
 	originCount := len(state.Origins)
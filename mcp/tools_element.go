@@ -312,6 +312,45 @@ func (s *Server) handleIsEditable(
 	return nil, IsEditableOutput{Editable: result.(bool)}, nil
 }
 
+// CanClick tool
+
+type CanClickInput struct {
+	Selector  string `json:"selector" jsonschema:"CSS selector for the element,required"`
+	TimeoutMS int    `json:"timeout_ms" jsonschema:"How long to wait for the element to become clickable in milliseconds (default: 5000)"`
+}
+
+type CanClickOutput struct {
+	Clickable bool   `json:"clickable"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+func (s *Server) handleCanClick(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input CanClickInput,
+) (*mcp.CallToolResult, CanClickOutput, error) {
+	pilot, err := s.session.Pilot(ctx)
+	if err != nil {
+		return nil, CanClickOutput{}, fmt.Errorf("browser not available: %w", err)
+	}
+
+	if input.TimeoutMS == 0 {
+		input.TimeoutMS = 5000
+	}
+	timeout := time.Duration(input.TimeoutMS) * time.Millisecond
+
+	elem, err := pilot.Find(ctx, input.Selector, &vibium.FindOptions{Timeout: timeout})
+	if err != nil {
+		return nil, CanClickOutput{Reason: err.Error()}, nil
+	}
+
+	if err := elem.Click(ctx, &vibium.ActionOptions{Timeout: timeout, Trial: true}); err != nil {
+		return nil, CanClickOutput{Reason: err.Error()}, nil
+	}
+
+	return nil, CanClickOutput{Clickable: true}, nil
+}
+
 // GetRole tool
 
 type GetRoleInput struct {
@@ -440,3 +479,97 @@ func (s *Server) handleGetBoundingBox(
 		Height: box.Height,
 	}, nil
 }
+
+// FindAll tool
+
+type FindAllInput struct {
+	Selector  string `json:"selector" jsonschema:"CSS selector to match (can be empty if using semantic selectors)"`
+	Limit     int    `json:"limit" jsonschema:"Maximum number of elements to return (default: 50)"`
+	TimeoutMS int    `json:"timeout_ms" jsonschema:"Timeout in milliseconds (default: 5000)"`
+	SemanticSelector
+}
+
+type FindAllOutput struct {
+	Elements []ElementSummary `json:"elements"`
+	Count    int              `json:"count"`
+	Total    int              `json:"total"`
+}
+
+// ElementSummary describes a matched element.
+type ElementSummary struct {
+	Selector string               `json:"selector"`
+	Tag      string               `json:"tag"`
+	Text     string               `json:"text,omitempty"`
+	Role     string               `json:"role,omitempty"`
+	Box      GetBoundingBoxOutput `json:"box"`
+}
+
+func (s *Server) handleFindAll(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input FindAllInput,
+) (*mcp.CallToolResult, FindAllOutput, error) {
+	pilot, err := s.session.Pilot(ctx)
+	if err != nil {
+		return nil, FindAllOutput{}, fmt.Errorf("browser not available: %w", err)
+	}
+
+	if input.TimeoutMS == 0 {
+		input.TimeoutMS = 5000
+	}
+	if input.Limit <= 0 {
+		input.Limit = 50
+	}
+	timeout := time.Duration(input.TimeoutMS) * time.Millisecond
+
+	elements, err := pilot.FindAll(ctx, input.Selector, input.SemanticSelector.toFindOptions(timeout))
+	if err != nil {
+		return nil, FindAllOutput{}, fmt.Errorf("find all failed: %w", err)
+	}
+
+	output, err := findAllOutput(elements, input.Limit, func(matched []*vibium.Element) ([]string, error) {
+		return pilot.ElementRoles(ctx, matched)
+	})
+	if err != nil {
+		return nil, FindAllOutput{}, fmt.Errorf("find all failed: %w", err)
+	}
+
+	return nil, output, nil
+}
+
+// findAllOutput summarizes the first limit elements, looking up their roles
+// with a single call to roles.
+func findAllOutput(elements []*vibium.Element, limit int, roles func([]*vibium.Element) ([]string, error)) (FindAllOutput, error) {
+	matched := elements
+	if len(matched) > limit {
+		matched = matched[:limit]
+	}
+
+	matchedRoles, err := roles(matched)
+	if err != nil {
+		return FindAllOutput{}, err
+	}
+
+	summaries := make([]ElementSummary, len(matched))
+	for i, elem := range matched {
+		info := elem.Info()
+		summaries[i] = ElementSummary{
+			Selector: elem.Selector(),
+			Tag:      info.Tag,
+			Text:     truncateString(info.Text, 100),
+			Role:     matchedRoles[i],
+			Box: GetBoundingBoxOutput{
+				X:      info.Box.X,
+				Y:      info.Box.Y,
+				Width:  info.Box.Width,
+				Height: info.Box.Height,
+			},
+		}
+	}
+
+	return FindAllOutput{
+		Elements: summaries,
+		Count:    len(summaries),
+		Total:    len(elements),
+	}, nil
+}
@@ -77,7 +77,7 @@ func (s *Server) handleFill(
 			Message:  err.Error(),
 			Selector: input.Selector,
 		}
-		result.Screenshot = s.session.CaptureScreenshot(ctx)
+		result.Screenshot = s.session.CaptureElementScreenshot(ctx, elem)
 		s.session.RecordStep(result)
 		return nil, FillOutput{}, fmt.Errorf("fill failed: %w", err)
 	}
@@ -387,6 +387,84 @@ func (s *Server) handleUncheck(
 	return nil, UncheckOutput{Message: fmt.Sprintf("Unchecked %s", input.Selector)}, nil
 }
 
+// SetChecked tool
+
+type SetCheckedInput struct {
+	Selector  string `json:"selector" jsonschema:"CSS selector for the checkbox,required"`
+	Checked   bool   `json:"checked" jsonschema:"Desired checked state,required"`
+	TimeoutMS int    `json:"timeout_ms" jsonschema:"Timeout in milliseconds (default: 5000)"`
+}
+
+type SetCheckedOutput struct {
+	Message string `json:"message"`
+}
+
+func (s *Server) handleSetChecked(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input SetCheckedInput,
+) (*mcp.CallToolResult, SetCheckedOutput, error) {
+	pilot, err := s.session.Pilot(ctx)
+	if err != nil {
+		return nil, SetCheckedOutput{}, fmt.Errorf("browser not available: %w", err)
+	}
+
+	if input.TimeoutMS == 0 {
+		input.TimeoutMS = 5000
+	}
+	timeout := time.Duration(input.TimeoutMS) * time.Millisecond
+
+	start := time.Now()
+	elem, err := pilot.Find(ctx, input.Selector, &vibium.FindOptions{Timeout: timeout})
+
+	result := report.StepResult{
+		ID:     s.session.NextStepID("set_checked"),
+		Action: "set_checked",
+		Args:   map[string]any{"selector": input.Selector, "checked": input.Checked},
+	}
+
+	if err != nil {
+		result.DurationMS = time.Since(start).Milliseconds()
+		result.Status = report.StatusNoGo
+		result.Severity = report.SeverityCritical
+		result.Error = &report.StepError{
+			Type:     "ElementNotFoundError",
+			Message:  err.Error(),
+			Selector: input.Selector,
+		}
+		s.session.RecordStep(result)
+		return nil, SetCheckedOutput{}, fmt.Errorf("element not found: %s", input.Selector)
+	}
+
+	err = elem.SetChecked(ctx, input.Checked, &vibium.ActionOptions{Timeout: timeout})
+	result.DurationMS = time.Since(start).Milliseconds()
+
+	if err != nil {
+		result.Status = report.StatusNoGo
+		result.Severity = report.SeverityCritical
+		result.Error = &report.StepError{
+			Type:     "SetCheckedError",
+			Message:  err.Error(),
+			Selector: input.Selector,
+		}
+		s.session.RecordStep(result)
+		return nil, SetCheckedOutput{}, fmt.Errorf("set checked failed: %w", err)
+	}
+
+	result.Status = report.StatusGo
+	result.Severity = report.SeverityInfo
+	s.session.RecordStep(result)
+
+	// Record for script export
+	s.session.Recorder().RecordSetChecked(input.Selector, input.Checked)
+
+	state := "Unchecked"
+	if input.Checked {
+		state = "Checked"
+	}
+	return nil, SetCheckedOutput{Message: fmt.Sprintf("%s %s", state, input.Selector)}, nil
+}
+
 // SelectOption tool
 
 type SelectOptionInput struct {
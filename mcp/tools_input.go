@@ -3,6 +3,7 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
@@ -111,7 +112,8 @@ func (s *Server) handleKeyboardUp(
 // KeyboardType tool
 
 type KeyboardTypeInput struct {
-	Text string `json:"text" jsonschema:"Text to type,required"`
+	Text    string `json:"text" jsonschema:"Text to type,required"`
+	DelayMS int    `json:"delay_ms,omitempty" jsonschema:"Delay between characters in milliseconds (default: 0)"`
 }
 
 type KeyboardTypeOutput struct {
@@ -133,7 +135,9 @@ func (s *Server) handleKeyboardType(
 		return nil, KeyboardTypeOutput{}, fmt.Errorf("keyboard not available: %w", err)
 	}
 
-	err = keyboard.Type(ctx, input.Text)
+	err = keyboard.TypeWithOptions(ctx, input.Text, &vibium.TypeOptions{
+		Delay: time.Duration(input.DelayMS) * time.Millisecond,
+	})
 	if err != nil {
 		return nil, KeyboardTypeOutput{}, fmt.Errorf("keyboard type failed: %w", err)
 	}
@@ -391,6 +395,41 @@ func (s *Server) handleTouchSwipe(
 	return nil, TouchSwipeOutput{Message: fmt.Sprintf("Swiped from (%f, %f) to (%f, %f)", input.StartX, input.StartY, input.EndX, input.EndY)}, nil
 }
 
+// TouchPinch tool
+
+type TouchPinchInput struct {
+	X     float64 `json:"x" jsonschema:"X coordinate of the pinch center,required"`
+	Y     float64 `json:"y" jsonschema:"Y coordinate of the pinch center,required"`
+	Scale float64 `json:"scale" jsonschema:"Zoom factor: greater than 1 zooms in and less than 1 zooms out,required"`
+}
+
+type TouchPinchOutput struct {
+	Message string `json:"message"`
+}
+
+func (s *Server) handleTouchPinch(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input TouchPinchInput,
+) (*mcp.CallToolResult, TouchPinchOutput, error) {
+	pilot, err := s.session.Pilot(ctx)
+	if err != nil {
+		return nil, TouchPinchOutput{}, fmt.Errorf("browser not available: %w", err)
+	}
+
+	touch, err := pilot.Touch(ctx)
+	if err != nil {
+		return nil, TouchPinchOutput{}, fmt.Errorf("touch not available: %w", err)
+	}
+
+	err = touch.Pinch(ctx, input.X, input.Y, input.Scale)
+	if err != nil {
+		return nil, TouchPinchOutput{}, fmt.Errorf("touch pinch failed: %w", err)
+	}
+
+	return nil, TouchPinchOutput{Message: fmt.Sprintf("Pinched at (%f, %f) with scale %f", input.X, input.Y, input.Scale)}, nil
+}
+
 // MouseDrag tool
 
 type MouseDragInput struct {
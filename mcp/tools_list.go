@@ -37,6 +37,8 @@ var toolDefinitions = []struct {
 		tools: []ToolInfo{
 			{Name: "browser_launch", Description: "Launch a browser instance. Call this before any other browser operations."},
 			{Name: "browser_quit", Description: "Close the browser and cleanup resources."},
+			{Name: "browser_status", Description: "Report whether the browser is alive, with current URL and page count."},
+			{Name: "browser_reconnect", Description: "Relaunch a crashed browser with the configured settings."},
 		},
 	},
 	{
@@ -62,12 +64,17 @@ var toolDefinitions = []struct {
 			{Name: "page_emulate_media", Description: "Emulate CSS media features (colorScheme, reducedMotion, forcedColors, contrast)."},
 			{Name: "page_set_geolocation", Description: "Set the browser's geolocation."},
 			{Name: "page_inspect", Description: "Inspect page elements to discover buttons, links, inputs, and other interactive elements. Designed for AI agents."},
+			{Name: "page_snapshot", Description: "Snapshot the page as an outline of roles and names, with a ref (e.g. e12) on each interactable element. Use the refs with element_click_ref, element_fill_ref, and element_hover_ref; take a new snapshot after the page navigates."},
 		},
 	},
 	{
 		category: "element",
 		tools: []ToolInfo{
 			{Name: "element_click", Description: "Click an element by CSS selector."},
+			{Name: "element_click_and_navigate", Description: "Click an element and wait for the navigation it triggers to finish loading. Fails if no navigation occurs within the timeout."},
+			{Name: "element_click_ref", Description: "Click an element by its ref from the latest page_snapshot."},
+			{Name: "element_fill_ref", Description: "Clear an input and fill it with text, by its ref from the latest page_snapshot."},
+			{Name: "element_hover_ref", Description: "Hover over an element by its ref from the latest page_snapshot."},
 			{Name: "element_double_click", Description: "Double-click an element by CSS selector."},
 			{Name: "element_type", Description: "Type text into an input element (appends to existing content)."},
 			{Name: "element_fill", Description: "Clear an input and fill it with text (replaces existing content)."},
@@ -76,13 +83,16 @@ var toolDefinitions = []struct {
 			{Name: "element_press", Description: "Press a key on an element (e.g., Enter, Tab, ArrowDown)."},
 			{Name: "element_check", Description: "Check a checkbox element."},
 			{Name: "element_uncheck", Description: "Uncheck a checkbox element."},
+			{Name: "element_set_checked", Description: "Set a checkbox to the given checked state."},
 			{Name: "element_select", Description: "Select option(s) in a <select> element."},
 			{Name: "element_set_files", Description: "Set files on a file input element."},
 			{Name: "element_hover", Description: "Hover over an element."},
 			{Name: "element_focus", Description: "Focus an element."},
 			{Name: "element_scroll_into_view", Description: "Scroll an element into view."},
-			{Name: "element_drag_to", Description: "Drag an element to another element."},
+			{Name: "element_drag_to", Description: "Drag an element to another element with mouse events, or with HTML5 drag events for draggable=true sources (mode)."},
 			{Name: "element_tap", Description: "Tap an element (touch gesture)."},
+			{Name: "element_double_tap", Description: "Double-tap an element (touch gesture)."},
+			{Name: "element_long_press", Description: "Long-press an element (touch gesture)."},
 			{Name: "element_dispatch_event", Description: "Dispatch a DOM event on an element."},
 			{Name: "element_get_text", Description: "Get the text content of an element."},
 			{Name: "element_get_value", Description: "Get the value of an input element."},
@@ -96,8 +106,10 @@ var toolDefinitions = []struct {
 			{Name: "element_is_enabled", Description: "Check if an element is enabled."},
 			{Name: "element_is_checked", Description: "Check if a checkbox/radio is checked."},
 			{Name: "element_is_editable", Description: "Check if an element is editable."},
+			{Name: "element_can_click", Description: "Check whether an element could be clicked, without clicking it."},
 			{Name: "element_get_role", Description: "Get the ARIA role of an element."},
 			{Name: "element_get_label", Description: "Get the accessible label of an element."},
+			{Name: "element_find_all", Description: "List all elements matching a selector with tag, text, role, and box."},
 			{Name: "element_screenshot", Description: "Capture an element screenshot."},
 			{Name: "element_evaluate", Description: "Evaluate JavaScript with an element context."},
 		},
@@ -117,12 +129,14 @@ var toolDefinitions = []struct {
 			{Name: "input_mouse_drag", Description: "Drag from one point to another."},
 			{Name: "input_touch_tap", Description: "Tap at coordinates."},
 			{Name: "input_touch_swipe", Description: "Swipe gesture."},
+			{Name: "input_touch_pinch", Description: "Pinch-zoom gesture."},
 		},
 	},
 	{
 		category: "js",
 		tools: []ToolInfo{
 			{Name: "js_evaluate", Description: "Execute JavaScript code."},
+			{Name: "js_evaluate_with_args", Description: "Call a JavaScript function with arguments passed by value."},
 			{Name: "js_add_script", Description: "Inject JavaScript into the page."},
 			{Name: "js_add_style", Description: "Inject CSS styles into the page."},
 			{Name: "js_init_script", Description: "Add JavaScript that runs before page scripts on every navigation."},
@@ -138,6 +152,7 @@ var toolDefinitions = []struct {
 		category: "batch",
 		tools: []ToolInfo{
 			{Name: "batch_execute", Description: "Execute multiple tools sequentially in a single call."},
+			{Name: "batch_run_steps", Description: "Run an array of script steps and return per-step results."},
 		},
 	},
 	{
@@ -149,6 +164,8 @@ var toolDefinitions = []struct {
 			{Name: "wait_for_function", Description: "Wait for a JavaScript function to return truthy."},
 			{Name: "wait_for_selector", Description: "Wait for element to appear/disappear with state option."},
 			{Name: "wait_for_text", Description: "Wait for text to appear on the page."},
+			{Name: "wait_for_request", Description: "Wait for a network request matching a URL pattern."},
+			{Name: "wait_for_response", Description: "Wait for a network response matching a URL pattern."},
 		},
 	},
 	{
@@ -3,6 +3,7 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"time"
 
 	vibium "github.com/plexusone/w3pilot"
 
@@ -13,14 +14,18 @@ import (
 
 // GetConsoleMessagesInput for retrieving console messages.
 type GetConsoleMessagesInput struct {
-	Level string `json:"level,omitempty" jsonschema:"Filter by message level (log/info/warn/error/debug). Empty for all levels.,enum=log,enum=info,enum=warn,enum=error,enum=debug"`
-	Clear bool   `json:"clear,omitempty" jsonschema:"Clear messages after retrieving them"`
+	Level             string `json:"level,omitempty" jsonschema:"Filter by message level (log/info/warn/error/debug). Empty for all levels.,enum=log,enum=info,enum=warn,enum=error,enum=debug"`
+	MinLevel          string `json:"min_level,omitempty" jsonschema:"Only include messages at or above this severity (debug < log/info < warn < error),enum=debug,enum=log,enum=info,enum=warn,enum=error"`
+	Limit             int    `json:"limit,omitempty" jsonschema:"Return only the last N messages (0 = all)"`
+	IncludePageErrors bool   `json:"include_page_errors,omitempty" jsonschema:"Also return uncaught JavaScript exceptions"`
+	Clear             bool   `json:"clear,omitempty" jsonschema:"Clear messages after retrieving them"`
 }
 
 // GetConsoleMessagesOutput contains console messages.
 type GetConsoleMessagesOutput struct {
-	Messages []ConsoleMessageInfo `json:"messages"`
-	Count    int                  `json:"count"`
+	Messages   []ConsoleMessageInfo `json:"messages"`
+	Count      int                  `json:"count"`
+	PageErrors []PageErrorInfo      `json:"page_errors,omitempty"`
 }
 
 // ConsoleMessageInfo represents a console message.
@@ -32,6 +37,29 @@ type ConsoleMessageInfo struct {
 	Line int      `json:"line,omitempty"`
 }
 
+// PageErrorInfo represents an uncaught JavaScript exception.
+type PageErrorInfo struct {
+	Message string `json:"message"`
+	Stack   string `json:"stack,omitempty"`
+	URL     string `json:"url,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+// consoleLevelRank orders console message types by severity.
+func consoleLevelRank(level string) int {
+	switch level {
+	case "debug", "verbose":
+		return 0
+	case "warn", "warning":
+		return 2
+	case "error":
+		return 3
+	default: // log, info
+		return 1
+	}
+}
+
 func (s *Server) handleGetConsoleMessages(
 	ctx context.Context,
 	req *mcp.CallToolRequest,
@@ -48,26 +76,56 @@ func (s *Server) handleGetConsoleMessages(
 	}
 
 	// Convert to output format
-	msgInfos := make([]ConsoleMessageInfo, len(messages))
-	for i, msg := range messages {
-		msgInfos[i] = ConsoleMessageInfo{
+	msgInfos := make([]ConsoleMessageInfo, 0, len(messages))
+	for _, msg := range messages {
+		if input.MinLevel != "" && consoleLevelRank(msg.Type) < consoleLevelRank(input.MinLevel) {
+			continue
+		}
+		msgInfos = append(msgInfos, ConsoleMessageInfo{
 			Type: msg.Type,
 			Text: msg.Text,
 			Args: msg.Args,
 			URL:  msg.URL,
 			Line: msg.Line,
+		})
+	}
+	if input.Limit > 0 && len(msgInfos) > input.Limit {
+		msgInfos = msgInfos[len(msgInfos)-input.Limit:]
+	}
+
+	output := GetConsoleMessagesOutput{
+		Messages: msgInfos,
+		Count:    len(msgInfos),
+	}
+
+	if input.IncludePageErrors {
+		pageErrors, err := pilot.Errors(ctx)
+		if err != nil {
+			return nil, GetConsoleMessagesOutput{}, fmt.Errorf("failed to get page errors: %w", err)
+		}
+		if input.Limit > 0 && len(pageErrors) > input.Limit {
+			pageErrors = pageErrors[len(pageErrors)-input.Limit:]
+		}
+		for _, pe := range pageErrors {
+			output.PageErrors = append(output.PageErrors, PageErrorInfo{
+				Message: pe.Message,
+				Stack:   pe.Stack,
+				URL:     pe.URL,
+				Line:    pe.Line,
+				Column:  pe.Column,
+			})
 		}
 	}
 
 	// Clear messages if requested
 	if input.Clear {
 		_ = pilot.ClearConsoleMessages(ctx)
+		if input.IncludePageErrors {
+			_ = pilot.ClearErrors(ctx)
+		}
 	}
 
-	return nil, GetConsoleMessagesOutput{
-		Messages: msgInfos,
-		Count:    len(msgInfos),
-	}, nil
+	return nil, output, nil
 }
 
 // ClearConsoleMessagesInput for clearing console messages.
@@ -195,3 +253,103 @@ func (s *Server) handleClearNetworkRequests(
 
 	return nil, ClearNetworkRequestsOutput{Message: "Network requests cleared"}, nil
 }
+
+// Network Wait Tools
+
+// WaitForRequestInput for waiting on a network request.
+type WaitForRequestInput struct {
+	Pattern   string `json:"pattern" jsonschema:"URL pattern: exact, glob (* and **), or regex wrapped in slashes,required"`
+	TimeoutMS int    `json:"timeout_ms" jsonschema:"Timeout in milliseconds (default: 30000)"`
+}
+
+// WaitForRequestOutput contains the matched request.
+type WaitForRequestOutput struct {
+	URL    string `json:"url"`
+	Method string `json:"method"`
+}
+
+func (s *Server) handleWaitForRequest(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input WaitForRequestInput,
+) (*mcp.CallToolResult, WaitForRequestOutput, error) {
+	pilot, err := s.session.Pilot(ctx)
+	if err != nil {
+		return nil, WaitForRequestOutput{}, fmt.Errorf("browser not available: %w", err)
+	}
+
+	request, err := pilot.WaitForRequest(ctx, input.Pattern, s.networkWaitOptions(input.TimeoutMS))
+	if err != nil {
+		return nil, WaitForRequestOutput{}, fmt.Errorf("wait for request failed: %w", err)
+	}
+
+	s.session.Recorder().RecordWaitForRequest(input.Pattern)
+
+	return nil, WaitForRequestOutput{
+		URL:    request.URL,
+		Method: request.Method,
+	}, nil
+}
+
+// WaitForResponseInput for waiting on a network response.
+type WaitForResponseInput struct {
+	Pattern     string `json:"pattern" jsonschema:"URL pattern: exact, glob (* and **), or regex wrapped in slashes,required"`
+	TimeoutMS   int    `json:"timeout_ms" jsonschema:"Timeout in milliseconds (default: 30000)"`
+	IncludeBody bool   `json:"include_body,omitempty" jsonschema:"Include the response body (requires CDP)"`
+}
+
+// WaitForResponseOutput contains the matched response.
+type WaitForResponseOutput struct {
+	URL           string `json:"url"`
+	Status        int    `json:"status"`
+	StatusText    string `json:"status_text,omitempty"`
+	Body          string `json:"body,omitempty"`
+	Base64Encoded bool   `json:"base64_encoded,omitempty"`
+}
+
+func (s *Server) handleWaitForResponse(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input WaitForResponseInput,
+) (*mcp.CallToolResult, WaitForResponseOutput, error) {
+	pilot, err := s.session.Pilot(ctx)
+	if err != nil {
+		return nil, WaitForResponseOutput{}, fmt.Errorf("browser not available: %w", err)
+	}
+
+	resp, err := pilot.WaitForResponse(ctx, input.Pattern, s.networkWaitOptions(input.TimeoutMS))
+	if err != nil {
+		return nil, WaitForResponseOutput{}, fmt.Errorf("wait for response failed: %w", err)
+	}
+
+	s.session.Recorder().RecordWaitForResponse(input.Pattern)
+
+	output := WaitForResponseOutput{
+		URL:        resp.URL,
+		Status:     resp.Status,
+		StatusText: resp.StatusText,
+	}
+
+	if input.IncludeBody {
+		body, err := pilot.GetNetworkResponseBody(ctx, resp.RequestID, "")
+		if err != nil {
+			return nil, WaitForResponseOutput{}, fmt.Errorf("failed to get response body: %w", err)
+		}
+		output.Body = body.Body
+		output.Base64Encoded = body.Base64Encoded
+	}
+
+	return nil, output, nil
+}
+
+// networkWaitOptions builds wait options that also match network activity
+// triggered by the most recent step, since tool calls arrive one at a time.
+func (s *Server) networkWaitOptions(timeoutMS int) *vibium.WaitForNetworkOptions {
+	if timeoutMS == 0 {
+		timeoutMS = 30000
+	}
+	return &vibium.WaitForNetworkOptions{
+		Timeout: time.Duration(timeoutMS) * time.Millisecond,
+		Since:   s.session.LastStepStart(),
+	}
+}
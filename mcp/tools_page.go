@@ -128,10 +128,12 @@ type PDFInput struct {
 	PrintBackground bool    `json:"print_background" jsonschema:"Print background graphics"`
 	Landscape       bool    `json:"landscape" jsonschema:"Landscape orientation"`
 	Format          string  `json:"format" jsonschema:"Paper format (Letter Legal A4 etc)"`
+	Path            string  `json:"path" jsonschema:"File path to write the PDF to instead of returning base64 data"`
 }
 
 type PDFOutput struct {
-	Data string `json:"data"`
+	Data string `json:"data,omitempty"`
+	Path string `json:"path,omitempty"`
 }
 
 func (s *Server) handlePDF(
@@ -156,6 +158,13 @@ func (s *Server) handlePDF(
 		return nil, PDFOutput{}, fmt.Errorf("pdf generation failed: %w", err)
 	}
 
+	if input.Path != "" {
+		if err := writeOutputFile(input.Path, data); err != nil {
+			return nil, PDFOutput{}, err
+		}
+		return nil, PDFOutput{Path: input.Path}, nil
+	}
+
 	return nil, PDFOutput{Data: base64.StdEncoding.EncodeToString(data)}, nil
 }
 
@@ -306,8 +315,10 @@ func (s *Server) handleSelectMainFrame(
 		return nil, SelectMainFrameOutput{}, fmt.Errorf("browser not available: %w", err)
 	}
 
-	// MainFrame returns the main frame (self in our case)
-	mainFrame := pilot.MainFrame()
+	mainFrame, err := pilot.MainFrame(ctx)
+	if err != nil {
+		return nil, SelectMainFrameOutput{}, fmt.Errorf("failed to get main frame: %w", err)
+	}
 	s.session.SetPilot(mainFrame)
 
 	return nil, SelectMainFrameOutput{
@@ -572,6 +583,7 @@ func (s *Server) handleWaitForFunction(
 type WaitForTextInput struct {
 	Text      string `json:"text" jsonschema:"Text to wait for,required"`
 	Selector  string `json:"selector,omitempty" jsonschema:"Optional selector to scope the search"`
+	Match     string `json:"match,omitempty" jsonschema:"How to match text: contains (default), exact, or regex"`
 	TimeoutMS int    `json:"timeout,omitempty" jsonschema:"Timeout in milliseconds (default 30000)"`
 }
 
@@ -592,22 +604,12 @@ func (s *Server) handleWaitForText(
 	if input.TimeoutMS == 0 {
 		input.TimeoutMS = 30000
 	}
-	timeout := time.Duration(input.TimeoutMS) * time.Millisecond
 
-	// Build JavaScript function to check for text
-	var checkScript string
-	if input.Selector != "" {
-		// Search within a specific element
-		checkScript = fmt.Sprintf(`() => {
-			const el = document.querySelector(%q);
-			return el && el.textContent.includes(%q);
-		}`, input.Selector, input.Text)
-	} else {
-		// Search entire document body
-		checkScript = fmt.Sprintf(`() => document.body.textContent.includes(%q)`, input.Text)
-	}
-
-	err = pilot.WaitForFunction(ctx, checkScript, timeout)
+	err = pilot.WaitForTextWithOptions(ctx, input.Text, &vibium.WaitForTextOptions{
+		Timeout:   time.Duration(input.TimeoutMS) * time.Millisecond,
+		Selector:  input.Selector,
+		TextMatch: input.Match,
+	})
 	if err != nil {
 		return nil, WaitForTextOutput{}, fmt.Errorf("wait for text failed: %w", err)
 	}
@@ -620,10 +622,15 @@ func (s *Server) handleWaitForText(
 type AccessibilitySnapshotInput struct {
 	InterestingOnly *bool  `json:"interesting_only,omitempty" jsonschema:"Only include interesting nodes with semantic meaning (default true)"`
 	Root            string `json:"root,omitempty" jsonschema:"CSS selector for root element to snapshot"`
+	MaxDepth        int    `json:"max_depth,omitempty" jsonschema:"Maximum tree depth to return (0 = unlimited)"`
+	MaxNodes        int    `json:"max_nodes,omitempty" jsonschema:"Maximum number of nodes to return (default: 500)"`
 }
 
 type AccessibilitySnapshotOutput struct {
-	Snapshot interface{} `json:"snapshot"`
+	Snapshot  interface{} `json:"snapshot"`
+	Truncated bool        `json:"truncated,omitempty"`
+	Omitted   int         `json:"omitted,omitempty"`
+	Message   string      `json:"message,omitempty"`
 }
 
 func (s *Server) handleAccessibilitySnapshot(
@@ -646,7 +653,85 @@ func (s *Server) handleAccessibilitySnapshot(
 		return nil, AccessibilitySnapshotOutput{}, fmt.Errorf("accessibility snapshot failed: %w", err)
 	}
 
-	return nil, AccessibilitySnapshotOutput{Snapshot: tree}, nil
+	if input.MaxNodes == 0 {
+		input.MaxNodes = 500
+	}
+
+	truncated, omitted := truncateA11yTree(tree, input.MaxDepth, input.MaxNodes)
+	output := AccessibilitySnapshotOutput{Snapshot: truncated}
+	if omitted > 0 {
+		output.Truncated = true
+		output.Omitted = omitted
+		output.Message = fmt.Sprintf("%d nodes omitted; nodes marked with children_omitted were cut. Call again with a narrower root selector or a larger max_depth/max_nodes.", omitted)
+	}
+
+	return nil, output, nil
+}
+
+// truncateA11yTree limits an accessibility tree to maxDepth levels and maxNodes
+// nodes (0 means unlimited). Nodes whose children were cut get a
+// "children_omitted" count. It returns the truncated tree and the total number
+// of omitted nodes.
+func truncateA11yTree(tree interface{}, maxDepth, maxNodes int) (interface{}, int) {
+	budget := maxNodes
+	omitted := 0
+
+	var count func(node interface{}) int
+	count = func(node interface{}) int {
+		n := 1
+		if m, ok := node.(map[string]interface{}); ok {
+			if children, ok := m["children"].([]interface{}); ok {
+				for _, child := range children {
+					n += count(child)
+				}
+			}
+		}
+		return n
+	}
+
+	var walk func(node interface{}, depth int) interface{}
+	walk = func(node interface{}, depth int) interface{} {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return node
+		}
+		if maxNodes > 0 {
+			budget--
+		}
+
+		children, ok := m["children"].([]interface{})
+		if !ok || len(children) == 0 {
+			return m
+		}
+
+		out := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			if k != "children" {
+				out[k] = v
+			}
+		}
+
+		kept := make([]interface{}, 0, len(children))
+		cut := 0
+		for _, child := range children {
+			if (maxDepth > 0 && depth+1 >= maxDepth) || (maxNodes > 0 && budget <= 0) {
+				cut += count(child)
+				continue
+			}
+			kept = append(kept, walk(child, depth+1))
+		}
+
+		if len(kept) > 0 {
+			out["children"] = kept
+		}
+		if cut > 0 {
+			out["children_omitted"] = cut
+			omitted += cut
+		}
+		return out
+	}
+
+	return walk(tree, 0), omitted
 }
 
 // Back tool
@@ -0,0 +1,180 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	w3pilot "github.com/plexusone/w3pilot"
+	"github.com/plexusone/w3pilot/mcp/report"
+)
+
+// PageSnapshot tool - ref-stamped outline of the page for AI agents
+
+type PageSnapshotInput struct {
+	Root          string `json:"root,omitempty" jsonschema:"CSS selector of the subtree to snapshot (default: document body)"`
+	IncludeHidden bool   `json:"include_hidden,omitempty" jsonschema:"Include elements that are not visible"`
+	MaxNodes      int    `json:"max_nodes,omitempty" jsonschema:"Maximum number of nodes (default 500)"`
+}
+
+type PageSnapshotOutput struct {
+	URL       string `json:"url"`
+	Title     string `json:"title"`
+	Snapshot  string `json:"snapshot"`
+	RefCount  int    `json:"ref_count"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+func (s *Server) handlePageSnapshot(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input PageSnapshotInput,
+) (*mcp.CallToolResult, PageSnapshotOutput, error) {
+	snap, err := s.session.TakeSnapshot(ctx, w3pilot.SnapshotOptions{
+		Root:          input.Root,
+		IncludeHidden: input.IncludeHidden,
+		MaxNodes:      input.MaxNodes,
+	})
+	if err != nil {
+		return nil, PageSnapshotOutput{}, fmt.Errorf("snapshot failed: %w", err)
+	}
+
+	return nil, PageSnapshotOutput{
+		URL:       snap.URL,
+		Title:     snap.Title,
+		Snapshot:  snap.String(),
+		RefCount:  len(snap.Refs()),
+		Truncated: snap.Truncated,
+	}, nil
+}
+
+// Ref-based element tools
+
+type ClickRefInput struct {
+	Ref       string `json:"ref" jsonschema:"Element ref from page_snapshot (e.g. e12),required"`
+	TimeoutMS int    `json:"timeout_ms" jsonschema:"Timeout in milliseconds (default: 5000)"`
+}
+
+type FillRefInput struct {
+	Ref       string `json:"ref" jsonschema:"Element ref from page_snapshot (e.g. e12),required"`
+	Value     string `json:"value" jsonschema:"Value to fill,required"`
+	TimeoutMS int    `json:"timeout_ms" jsonschema:"Timeout in milliseconds (default: 5000)"`
+}
+
+type HoverRefInput struct {
+	Ref       string `json:"ref" jsonschema:"Element ref from page_snapshot (e.g. e12),required"`
+	TimeoutMS int    `json:"timeout_ms" jsonschema:"Timeout in milliseconds (default: 5000)"`
+}
+
+type RefActionOutput struct {
+	Message string `json:"message"`
+}
+
+func (s *Server) handleClickRef(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ClickRefInput,
+) (*mcp.CallToolResult, RefActionOutput, error) {
+	timeout := refTimeout(input.TimeoutMS)
+	return s.runRefAction(ctx, "click", input.Ref, nil, "Clicked", func(elem *w3pilot.Element) error {
+		return elem.Click(ctx, &w3pilot.ActionOptions{Timeout: timeout})
+	})
+}
+
+func (s *Server) handleFillRef(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input FillRefInput,
+) (*mcp.CallToolResult, RefActionOutput, error) {
+	timeout := refTimeout(input.TimeoutMS)
+	args := map[string]any{"value": truncateString(input.Value, 50)}
+	return s.runRefAction(ctx, "fill", input.Ref, args, "Filled", func(elem *w3pilot.Element) error {
+		return elem.Fill(ctx, input.Value, &w3pilot.ActionOptions{Timeout: timeout})
+	})
+}
+
+func (s *Server) handleHoverRef(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input HoverRefInput,
+) (*mcp.CallToolResult, RefActionOutput, error) {
+	timeout := refTimeout(input.TimeoutMS)
+	return s.runRefAction(ctx, "hover", input.Ref, nil, "Hovered over", func(elem *w3pilot.Element) error {
+		return elem.Hover(ctx, &w3pilot.ActionOptions{Timeout: timeout})
+	})
+}
+
+// refTimeout converts a tool's timeout_ms to a duration, defaulting to 5s.
+func refTimeout(ms int) time.Duration {
+	if ms == 0 {
+		ms = 5000
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// runRefAction resolves ref against the session's latest snapshot, runs
+// action on the element, and records the step. Ref actions are not recorded
+// for script export: ref attributes exist only in the live page.
+func (s *Server) runRefAction(
+	ctx context.Context,
+	action, ref string,
+	args map[string]any,
+	verb string,
+	run func(elem *w3pilot.Element) error,
+) (*mcp.CallToolResult, RefActionOutput, error) {
+	if args == nil {
+		args = map[string]any{}
+	}
+	args["ref"] = ref
+
+	start := time.Now()
+	elem, node, err := s.session.ResolveRef(ctx, ref)
+
+	result := report.StepResult{
+		ID:     s.session.NextStepID(action),
+		Action: action,
+		Args:   args,
+	}
+
+	if err != nil {
+		errType := "ElementNotFoundError"
+		if errors.Is(err, w3pilot.ErrStaleRef) {
+			errType = "StaleRefError"
+		}
+		result.DurationMS = time.Since(start).Milliseconds()
+		result.Status = report.StatusNoGo
+		result.Severity = report.SeverityCritical
+		result.Error = &report.StepError{
+			Type:     errType,
+			Message:  err.Error(),
+			Selector: w3pilot.RefSelector(ref),
+		}
+		s.session.RecordStep(result)
+		return nil, RefActionOutput{}, err
+	}
+
+	err = run(elem)
+	result.DurationMS = time.Since(start).Milliseconds()
+
+	if err != nil {
+		result.Status = report.StatusNoGo
+		result.Severity = report.SeverityCritical
+		result.Error = &report.StepError{
+			Type:     "RefActionError",
+			Message:  err.Error(),
+			Selector: w3pilot.RefSelector(ref),
+		}
+		result.Screenshot = s.session.CaptureElementScreenshot(ctx, elem)
+		s.session.RecordStep(result)
+		return nil, RefActionOutput{}, fmt.Errorf("%s %s failed: %w", action, ref, err)
+	}
+
+	result.Status = report.StatusGo
+	result.Severity = report.SeverityInfo
+	s.session.RecordStep(result)
+
+	return nil, RefActionOutput{Message: fmt.Sprintf("%s %s (%s %q)", verb, ref, node.Role, node.Name)}, nil
+}
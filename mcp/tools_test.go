@@ -0,0 +1,209 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	vibium "github.com/plexusone/w3pilot"
+)
+
+func TestPNGToJPEG(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	img.Set(1, 1, color.RGBA{R: 255, A: 255})
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+
+	data, err := pngToJPEG(buf.Bytes(), 0)
+	if err != nil {
+		t.Fatalf("pngToJPEG() error = %v", err)
+	}
+
+	decoded, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("jpeg.Decode() error = %v", err)
+	}
+	if decoded.Bounds() != img.Bounds() {
+		t.Errorf("bounds = %v, want %v", decoded.Bounds(), img.Bounds())
+	}
+
+	if _, err := pngToJPEG([]byte("not a png"), 80); err == nil {
+		t.Error("pngToJPEG() should fail on invalid PNG data")
+	}
+}
+
+func TestWriteOutputFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "out.png")
+
+	if err := writeOutputFile(path, []byte("data")); err != nil {
+		t.Fatalf("writeOutputFile() error = %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(got) != "data" {
+		t.Errorf("file content = %q, want %q", got, "data")
+	}
+}
+
+func TestTruncateA11yTree(t *testing.T) {
+	leaf := func(name string) map[string]interface{} {
+		return map[string]interface{}{"role": "link", "name": name}
+	}
+	tree := map[string]interface{}{
+		"role": "WebArea",
+		"children": []interface{}{
+			map[string]interface{}{
+				"role":     "navigation",
+				"children": []interface{}{leaf("Home"), leaf("About")},
+			},
+			leaf("Footer"),
+		},
+	}
+
+	t.Run("unlimited", func(t *testing.T) {
+		_, omitted := truncateA11yTree(tree, 0, 0)
+		if omitted != 0 {
+			t.Errorf("omitted = %d, want 0", omitted)
+		}
+	})
+
+	t.Run("max depth", func(t *testing.T) {
+		got, omitted := truncateA11yTree(tree, 2, 0)
+		if omitted != 2 {
+			t.Errorf("omitted = %d, want 2", omitted)
+		}
+		nav := got.(map[string]interface{})["children"].([]interface{})[0].(map[string]interface{})
+		if nav["children_omitted"] != 2 {
+			t.Errorf("children_omitted = %v, want 2", nav["children_omitted"])
+		}
+		if _, ok := nav["children"]; ok {
+			t.Error("navigation children should be removed")
+		}
+	})
+
+	t.Run("max nodes", func(t *testing.T) {
+		_, omitted := truncateA11yTree(tree, 0, 3)
+		if omitted != 2 {
+			t.Errorf("omitted = %d, want 2", omitted)
+		}
+	})
+}
+
+func TestConsoleLevelRank(t *testing.T) {
+	if consoleLevelRank("debug") >= consoleLevelRank("log") {
+		t.Error("debug should rank below log")
+	}
+	if consoleLevelRank("info") != consoleLevelRank("log") {
+		t.Error("info and log should rank equally")
+	}
+	if consoleLevelRank("warning") != consoleLevelRank("warn") {
+		t.Error("warning and warn should rank equally")
+	}
+	if consoleLevelRank("error") <= consoleLevelRank("warn") {
+		t.Error("error should rank above warn")
+	}
+}
+
+func TestFindAllOutput(t *testing.T) {
+	elements := make([]*vibium.Element, 3)
+	for i := range elements {
+		elements[i] = vibium.NewElement(nil, "ctx-123", fmt.Sprintf("li:nth-of-type(%d)", i+1), vibium.ElementInfo{Tag: "li"})
+	}
+
+	calls := 0
+	roles := func(matched []*vibium.Element) ([]string, error) {
+		calls++
+		if len(matched) != 2 {
+			t.Errorf("roles called with %d elements, want 2", len(matched))
+		}
+		return []string{"listitem", "listitem"}, nil
+	}
+
+	output, err := findAllOutput(elements, 2, roles)
+	if err != nil {
+		t.Fatalf("findAllOutput() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("roles called %d times, want 1", calls)
+	}
+	if output.Count != 2 || output.Total != 3 || len(output.Elements) != 2 {
+		t.Errorf("count = %d, total = %d, elements = %d, want 2, 3, 2", output.Count, output.Total, len(output.Elements))
+	}
+	if got := output.Elements[1]; got.Selector != "li:nth-of-type(2)" || got.Role != "listitem" {
+		t.Errorf("elements[1] = %+v", got)
+	}
+
+	if _, err := findAllOutput(elements, 50, func([]*vibium.Element) ([]string, error) {
+		return nil, errors.New("boom")
+	}); err == nil {
+		t.Error("findAllOutput() should return the roles error")
+	}
+}
+
+func TestRegisteredTools(t *testing.T) {
+	list, err := NewServer(DefaultConfig()).RegisteredTools(context.Background())
+	if err != nil {
+		t.Fatalf("RegisteredTools() error = %v", err)
+	}
+	if list.Total != len(list.Tools) {
+		t.Errorf("total = %d, tools = %d", list.Total, len(list.Tools))
+	}
+
+	names := make(map[string]bool, len(list.Tools))
+	for _, tool := range list.Tools {
+		names[tool.Name] = true
+		if tool.Category == "" {
+			t.Errorf("%s has no category", tool.Name)
+		}
+	}
+	// Registered tools missing from the static ListTools table are included
+	for _, name := range []string{"page_navigate", "take_heap_snapshot", "start_coverage"} {
+		if !names[name] {
+			t.Errorf("%s is not listed", name)
+		}
+	}
+}
+
+func TestHandleBatchRunSteps_ValidatesBeforeRunning(t *testing.T) {
+	tests := []struct {
+		name string
+		step map[string]any
+	}{
+		{"unknown action", map[string]any{"action": "clickk", "selector": "#submit"}},
+		{"misspelled field", map[string]any{"action": "click", "selectr": "#submit"}},
+		{"missing field", map[string]any{"action": "click"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(DefaultConfig())
+			input := BatchRunStepsInput{Steps: []map[string]any{
+				{"action": "navigate", "url": "https://example.com"},
+				tt.step,
+			}}
+
+			_, _, err := s.handleBatchRunSteps(context.Background(), nil, input)
+			if err == nil || !strings.Contains(err.Error(), "step 2") {
+				t.Errorf("handleBatchRunSteps() error = %v, want a step 2 error", err)
+			}
+			// Step 1 never ran: no browser was launched and nothing was recorded
+			if s.session.IsLaunched() || s.session.StepCount() != 0 {
+				t.Errorf("step 1 ran: launched = %v, steps = %d", s.session.IsLaunched(), s.session.StepCount())
+			}
+		})
+	}
+}
@@ -287,32 +287,8 @@ func (s *Server) handleWaitForSelector(
 		input.State = "visible"
 	}
 
-	// Find the element first (for attached/visible states) or wait for condition
-	switch input.State {
-	case "attached", "visible":
-		elem, err := pilot.Find(ctx, input.Selector, &w3pilot.FindOptions{Timeout: timeout})
-		if err != nil {
-			return nil, WaitForSelectorOutput{}, fmt.Errorf("wait for selector failed: %w", err)
-		}
-		if input.State == "visible" {
-			err = elem.WaitUntil(ctx, "visible", timeout)
-			if err != nil {
-				return nil, WaitForSelectorOutput{}, fmt.Errorf("wait for visible failed: %w", err)
-			}
-		}
-	case "detached", "hidden":
-		err = pilot.WaitForFunction(ctx, fmt.Sprintf(`() => {
-			const el = document.querySelector(%q);
-			if (%q === "detached") return el === null;
-			if (el === null) return true;
-			const style = window.getComputedStyle(el);
-			return style.display === 'none' || style.visibility === 'hidden' || el.offsetParent === null;
-		}`, input.Selector, input.State), timeout)
-		if err != nil {
-			return nil, WaitForSelectorOutput{}, fmt.Errorf("wait for %s failed: %w", input.State, err)
-		}
-	default:
-		return nil, WaitForSelectorOutput{}, fmt.Errorf("invalid state: %s", input.State)
+	if _, err := pilot.WaitForSelector(ctx, input.Selector, input.State, timeout); err != nil {
+		return nil, WaitForSelectorOutput{}, fmt.Errorf("wait for %s failed: %w", input.State, err)
 	}
 
 	return nil, WaitForSelectorOutput{
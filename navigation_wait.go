@@ -0,0 +1,221 @@
+package w3pilot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// navigationEvents are the BiDi events that start or finish a navigation.
+var navigationEvents = []string{
+	"browsingContext.navigationStarted",
+	"browsingContext.load",
+	"browsingContext.fragmentNavigated",
+	"browsingContext.navigationFailed",
+}
+
+// navigationWaiter is a pending WaitForNavigation call.
+type navigationWaiter struct {
+	context string
+	ch      chan error
+}
+
+// navigationWatcher tracks navigation events so WaitForNavigation resolves
+// when a navigation finishes rather than on a poll. One watcher is shared by
+// all pages using the same BiDiClient.
+type navigationWatcher struct {
+	mu        sync.Mutex
+	inFlight  map[string]bool      // contexts with a navigation in progress
+	completed map[string]time.Time // when each context last finished a navigation
+	waiters   []*navigationWaiter
+}
+
+// navigationWatcher returns the client's navigation watcher, subscribing to
+// BiDi navigation events on first use.
+func (c *BiDiClient) navigationWatcher(ctx context.Context) (*navigationWatcher, error) {
+	c.navigationMu.Lock()
+	defer c.navigationMu.Unlock()
+
+	if c.navigation != nil {
+		return c.navigation, nil
+	}
+
+	_, err := c.Send(ctx, "session.subscribe", map[string]interface{}{
+		"events": navigationEvents,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	w := &navigationWatcher{
+		inFlight:  make(map[string]bool),
+		completed: make(map[string]time.Time),
+	}
+	for _, event := range navigationEvents {
+		c.OnEvent(event, w.onEvent)
+	}
+
+	c.navigation = w
+	return w, nil
+}
+
+func (w *navigationWatcher) onEvent(event *BiDiEvent) {
+	var params struct {
+		Context string `json:"context"`
+		URL     string `json:"url"`
+	}
+	if err := json.Unmarshal(event.Params, &params); err != nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var result error
+	switch event.Method {
+	case "browsingContext.navigationStarted":
+		w.inFlight[params.Context] = true
+		return
+	case "browsingContext.navigationFailed":
+		delete(w.inFlight, params.Context)
+		result = fmt.Errorf("navigation to %s failed", params.URL)
+	default:
+		delete(w.inFlight, params.Context)
+		w.completed[params.Context] = time.Now()
+	}
+
+	remaining := w.waiters[:0]
+	for _, waiter := range w.waiters {
+		if waiter.context == params.Context {
+			waiter.ch <- result
+			continue
+		}
+		remaining = append(remaining, waiter)
+	}
+	w.waiters = remaining
+}
+
+// wait blocks until a navigation in browsingCtx finishes. If no navigation
+// is in progress and one finished at or after since, it returns immediately.
+func (w *navigationWatcher) wait(ctx context.Context, browsingCtx string, since time.Time) error {
+	w.mu.Lock()
+	if !w.inFlight[browsingCtx] && !since.IsZero() {
+		if done, ok := w.completed[browsingCtx]; ok && !done.Before(since) {
+			w.mu.Unlock()
+			return nil
+		}
+	}
+	waiter := &navigationWaiter{context: browsingCtx, ch: make(chan error, 1)}
+	w.waiters = append(w.waiters, waiter)
+	w.mu.Unlock()
+
+	select {
+	case err := <-waiter.ch:
+		return err
+	case <-ctx.Done():
+		w.mu.Lock()
+		for i, other := range w.waiters {
+			if other == waiter {
+				w.waiters = append(w.waiters[:i], w.waiters[i+1:]...)
+				break
+			}
+		}
+		w.mu.Unlock()
+
+		// The navigation may have finished while we were removing the waiter.
+		select {
+		case err := <-waiter.ch:
+			return err
+		default:
+			return ctx.Err()
+		}
+	}
+}
+
+// WaitForNavigation waits for the page's next navigation to finish loading.
+// A navigation already in progress when it is called counts as the next one.
+// Fragment (#hash) navigations also count. It returns as soon as the load
+// event fires, or immediately if ctx is canceled.
+func (p *Pilot) WaitForNavigation(ctx context.Context, timeout time.Duration) error {
+	return p.waitForNavigationSince(ctx, timeout, time.Time{})
+}
+
+// waitForNavigationSince is WaitForNavigation that also accepts a navigation
+// which finished at or after since, for callers that trigger the navigation
+// before they start waiting.
+func (p *Pilot) waitForNavigationSince(ctx context.Context, timeout time.Duration, since time.Time) error {
+	if p.closed.Load() {
+		return ErrConnectionClosed
+	}
+
+	if timeout == 0 {
+		timeout = p.DefaultTimeout()
+	}
+
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	browsingCtx, err := p.getContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	w, err := p.client.navigationWatcher(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = w.wait(ctx, browsingCtx, since)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &TimeoutError{
+			Selector: "navigation",
+			Timeout:  timeout.Milliseconds(),
+			Reason:   "navigation did not complete",
+		}
+	}
+	return err
+}
+
+// ClickAndWaitForNavigation clicks the element and waits for the navigation
+// it triggers to finish loading. The navigation listener is armed before the
+// click, so a navigation that completes before the wait starts is not missed.
+// opts.Timeout bounds the click and the navigation together; if no
+// navigation finishes within it, a *TimeoutError is returned.
+func (e *Element) ClickAndWaitForNavigation(ctx context.Context, opts *ActionOptions) error {
+	timeout := e.defaultTimeout()
+	if opts != nil && opts.Timeout > 0 {
+		timeout = opts.Timeout
+	}
+
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	w, err := e.client.navigationWatcher(ctx)
+	if err != nil {
+		return err
+	}
+
+	since := time.Now()
+	if err := e.Click(ctx, opts); err != nil {
+		return err
+	}
+
+	err = w.wait(ctx, e.context, since)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &TimeoutError{
+			Selector: e.selector,
+			Timeout:  timeout.Milliseconds(),
+			Reason:   "click did not trigger a navigation",
+		}
+	}
+	return err
+}
@@ -0,0 +1,170 @@
+package w3pilot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitForNavigationWaiter blocks until a WaitForNavigation call has registered.
+func waitForNavigationWaiter(t *testing.T, pilot *Pilot) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		pilot.client.navigationMu.Lock()
+		w := pilot.client.navigation
+		pilot.client.navigationMu.Unlock()
+		if w != nil {
+			w.mu.Lock()
+			n := len(w.waiters)
+			w.mu.Unlock()
+			if n > 0 {
+				return
+			}
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("WaitForNavigation did not register a waiter")
+}
+
+// TestWaitForNavigation_ResolvesOnLoad verifies the wait ends on the load event for its context.
+func TestWaitForNavigation_ResolvesOnLoad(t *testing.T) {
+	mock := newMockTransport()
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- pilot.WaitForNavigation(context.Background(), time.Second)
+	}()
+	waitForNavigationWaiter(t, pilot)
+
+	emitNetworkEvent(t, mock, "browsingContext.navigationStarted", `{"context":"ctx-123","url":"https://example.com/next"}`)
+	emitNetworkEvent(t, mock, "browsingContext.load", `{"context":"other","url":"https://example.com/"}`)
+	select {
+	case err := <-done:
+		t.Fatalf("Wait resolved on another context's load: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	emitNetworkEvent(t, mock, "browsingContext.load", `{"context":"ctx-123","url":"https://example.com/next"}`)
+	if err := <-done; err != nil {
+		t.Fatalf("WaitForNavigation failed: %v", err)
+	}
+
+	for _, call := range mock.getCalls() {
+		if call.Method == "script.callFunction" {
+			t.Error("WaitForNavigation should not poll with script evaluation")
+		}
+	}
+}
+
+// TestWaitForNavigation_Failed verifies a failed navigation ends the wait with an error.
+func TestWaitForNavigation_Failed(t *testing.T) {
+	mock := newMockTransport()
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- pilot.WaitForNavigation(context.Background(), time.Second)
+	}()
+	waitForNavigationWaiter(t, pilot)
+
+	emitNetworkEvent(t, mock, "browsingContext.navigationFailed", `{"context":"ctx-123","url":"https://bad.invalid/"}`)
+	if err := <-done; err == nil {
+		t.Fatal("Expected error for failed navigation")
+	}
+}
+
+// TestWaitForNavigation_Cancel verifies cancellation and timeouts end the wait.
+func TestWaitForNavigation_Cancel(t *testing.T) {
+	mock := newMockTransport()
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- pilot.WaitForNavigation(ctx, time.Minute)
+	}()
+	waitForNavigationWaiter(t, pilot)
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("WaitForNavigation did not return after cancel")
+	}
+
+	err := pilot.WaitForNavigation(context.Background(), 10*time.Millisecond)
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Errorf("Expected *TimeoutError, got %v", err)
+	}
+}
+
+// TestWaitForNavigation_Since verifies a navigation finished after since counts.
+func TestWaitForNavigation_Since(t *testing.T) {
+	mock := newMockTransport()
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	since := time.Now()
+	if _, err := pilot.client.navigationWatcher(context.Background()); err != nil {
+		t.Fatalf("navigationWatcher failed: %v", err)
+	}
+	emitNetworkEvent(t, mock, "browsingContext.navigationStarted", `{"context":"ctx-123"}`)
+	emitNetworkEvent(t, mock, "browsingContext.load", `{"context":"ctx-123"}`)
+
+	if err := pilot.waitForNavigationSince(context.Background(), 50*time.Millisecond, since); err != nil {
+		t.Errorf("Expected completed navigation to satisfy the wait, got %v", err)
+	}
+}
+
+// TestClickAndWaitForNavigation verifies that the click is sent and the
+// wait ends when the triggered navigation loads.
+func TestClickAndWaitForNavigation(t *testing.T) {
+	mock := newMockTransport()
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+	elem := NewElement(pilot.client, "ctx-123", "a.next", ElementInfo{Tag: "a"})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- elem.ClickAndWaitForNavigation(context.Background(), &ActionOptions{Timeout: time.Second})
+	}()
+	waitForNavigationWaiter(t, pilot)
+
+	clicked := false
+	for _, call := range mock.getCalls() {
+		if call.Method == "vibium:element.click" {
+			clicked = true
+		}
+	}
+	if !clicked {
+		t.Error("Expected the element to be clicked before waiting")
+	}
+
+	emitNetworkEvent(t, mock, "browsingContext.navigationStarted", `{"context":"ctx-123","url":"https://example.com/next"}`)
+	emitNetworkEvent(t, mock, "browsingContext.load", `{"context":"ctx-123","url":"https://example.com/next"}`)
+	if err := <-done; err != nil {
+		t.Fatalf("ClickAndWaitForNavigation failed: %v", err)
+	}
+}
+
+// TestClickAndWaitForNavigation_NoNavigation verifies a timeout error when
+// the click does not navigate.
+func TestClickAndWaitForNavigation_NoNavigation(t *testing.T) {
+	mock := newMockTransport()
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+	elem := NewElement(pilot.client, "ctx-123", "button.noop", ElementInfo{Tag: "button"})
+
+	err := elem.ClickAndWaitForNavigation(context.Background(), &ActionOptions{Timeout: 50 * time.Millisecond})
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("Expected TimeoutError, got %v", err)
+	}
+	if timeoutErr.Selector != "button.noop" {
+		t.Errorf("Expected selector in timeout error, got %q", timeoutErr.Selector)
+	}
+}
@@ -0,0 +1,367 @@
+package w3pilot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// networkEventLimit caps how many completed requests and responses are kept
+// for WaitForNetworkOptions.Since lookups.
+const networkEventLimit = 100
+
+// networkHeader is a BiDi network.Header.
+type networkHeader struct {
+	Name  string `json:"name"`
+	Value struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	} `json:"value"`
+}
+
+// networkEventParams is the subset of BiDi network event params used by waiters.
+type networkEventParams struct {
+	Context string `json:"context"`
+	Request struct {
+		Request string           `json:"request"`
+		URL     string           `json:"url"`
+		Method  string           `json:"method"`
+		Headers []networkHeader  `json:"headers"`
+		Timings *fetchTimingInfo `json:"timings"`
+	} `json:"request"`
+	Response struct {
+		URL           string          `json:"url"`
+		Status        int             `json:"status"`
+		StatusText    string          `json:"statusText"`
+		Headers       []networkHeader `json:"headers"`
+		FromCache     bool            `json:"fromCache"`
+		BytesReceived int64           `json:"bytesReceived"`
+		BodySize      *int64          `json:"bodySize"`
+	} `json:"response"`
+	Navigation *string `json:"navigation"`
+}
+
+// fetchTimingInfo is a BiDi network.FetchTimingInfo. timeOrigin is
+// milliseconds since the Unix epoch; the other fields are milliseconds
+// relative to it, or zero when the phase did not occur.
+type fetchTimingInfo struct {
+	TimeOrigin    float64 `json:"timeOrigin"`
+	RequestTime   float64 `json:"requestTime"`
+	DNSStart      float64 `json:"dnsStart"`
+	DNSEnd        float64 `json:"dnsEnd"`
+	ConnectStart  float64 `json:"connectStart"`
+	ConnectEnd    float64 `json:"connectEnd"`
+	TLSStart      float64 `json:"tlsStart"`
+	RequestStart  float64 `json:"requestStart"`
+	ResponseStart float64 `json:"responseStart"`
+	ResponseEnd   float64 `json:"responseEnd"`
+}
+
+// responseTiming converts BiDi timings to offsets from the request time.
+func (t *fetchTimingInfo) responseTiming() *ResponseTiming {
+	if t == nil || t.TimeOrigin == 0 {
+		return nil
+	}
+	ms := func(v float64) time.Duration {
+		return time.Duration(v * float64(time.Millisecond))
+	}
+	offset := func(v float64) time.Duration {
+		if v <= 0 || v < t.RequestTime {
+			return 0
+		}
+		return ms(v - t.RequestTime)
+	}
+	return &ResponseTiming{
+		StartTime:     time.UnixMilli(0).Add(ms(t.TimeOrigin + t.RequestTime)),
+		DNSStart:      offset(t.DNSStart),
+		DNSEnd:        offset(t.DNSEnd),
+		ConnectStart:  offset(t.ConnectStart),
+		ConnectEnd:    offset(t.ConnectEnd),
+		TLSStart:      offset(t.TLSStart),
+		RequestStart:  offset(t.RequestStart),
+		ResponseStart: offset(t.ResponseStart),
+		ResponseEnd:   offset(t.ResponseEnd),
+	}
+}
+
+// observedNetworkEvent is a request or response seen by the networkWatcher.
+type observedNetworkEvent struct {
+	at       time.Time
+	context  string
+	request  *Request
+	response *Response
+}
+
+func (e *observedNetworkEvent) url() string {
+	if e.response != nil {
+		return e.response.URL
+	}
+	return e.request.URL
+}
+
+// networkWaiter is a pending WaitForRequest/WaitForResponse call.
+type networkWaiter struct {
+	response bool
+	context  string
+	pattern  string
+	ch       chan *observedNetworkEvent
+}
+
+func (w *networkWaiter) matches(e *observedNetworkEvent) bool {
+	if w.response != (e.response != nil) {
+		return false
+	}
+	if e.context != "" && e.context != w.context {
+		return false
+	}
+	return matchURLPattern(e.url(), w.pattern)
+}
+
+// networkWatcher buffers BiDi network events and dispatches them to waiters.
+// One watcher is shared by all pages using the same BiDiClient.
+type networkWatcher struct {
+	mu      sync.Mutex
+	events  []*observedNetworkEvent
+	waiters []*networkWaiter
+}
+
+// networkWatcher returns the client's network watcher, subscribing to BiDi
+// network events on first use.
+func (c *BiDiClient) networkWatcher(ctx context.Context) (*networkWatcher, error) {
+	c.networkMu.Lock()
+	defer c.networkMu.Unlock()
+
+	if c.network != nil {
+		return c.network, nil
+	}
+
+	_, err := c.Send(ctx, "session.subscribe", map[string]interface{}{
+		"events": []string{
+			"network.beforeRequestSent",
+			"network.responseCompleted",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	w := &networkWatcher{}
+	c.OnEvent("network.beforeRequestSent", w.onEvent)
+	c.OnEvent("network.responseCompleted", w.onEvent)
+
+	c.network = w
+	return w, nil
+}
+
+func (w *networkWatcher) onEvent(event *BiDiEvent) {
+	var params networkEventParams
+	if err := json.Unmarshal(event.Params, &params); err != nil {
+		return
+	}
+
+	observed := &observedNetworkEvent{
+		at:      time.Now(),
+		context: params.Context,
+	}
+	if event.Method == "network.responseCompleted" {
+		url := params.Response.URL
+		if url == "" {
+			url = params.Request.URL
+		}
+		observed.response = &Response{
+			URL:        url,
+			Status:     params.Response.Status,
+			StatusText: params.Response.StatusText,
+			Headers:    flattenNetworkHeaders(params.Response.Headers),
+			RequestID:  params.Request.Request,
+
+			Timing:       params.Request.Timings.responseTiming(),
+			TransferSize: params.Response.BytesReceived,
+			FromCache:    params.Response.FromCache,
+		}
+		if params.Response.BodySize != nil {
+			observed.response.EncodedBodySize = *params.Response.BodySize
+		}
+	} else {
+		observed.request = &Request{
+			URL:                 params.Request.URL,
+			Method:              params.Request.Method,
+			Headers:             flattenNetworkHeaders(params.Request.Headers),
+			IsNavigationRequest: params.Navigation != nil,
+			RequestID:           params.Request.Request,
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.events = append(w.events, observed)
+	if len(w.events) > networkEventLimit {
+		w.events = w.events[len(w.events)-networkEventLimit:]
+	}
+
+	remaining := w.waiters[:0]
+	for _, waiter := range w.waiters {
+		if waiter.matches(observed) {
+			waiter.ch <- observed
+			continue
+		}
+		remaining = append(remaining, waiter)
+	}
+	w.waiters = remaining
+}
+
+// wait returns the first buffered event at or after since matching waiter,
+// or blocks until a new matching event arrives or ctx is done.
+func (w *networkWatcher) wait(ctx context.Context, waiter *networkWaiter, since time.Time) (*observedNetworkEvent, error) {
+	w.mu.Lock()
+	if !since.IsZero() {
+		for _, e := range w.events {
+			if !e.at.Before(since) && waiter.matches(e) {
+				w.mu.Unlock()
+				return e, nil
+			}
+		}
+	}
+	waiter.ch = make(chan *observedNetworkEvent, 1)
+	w.waiters = append(w.waiters, waiter)
+	w.mu.Unlock()
+
+	select {
+	case e := <-waiter.ch:
+		return e, nil
+	case <-ctx.Done():
+		w.mu.Lock()
+		for i, other := range w.waiters {
+			if other == waiter {
+				w.waiters = append(w.waiters[:i], w.waiters[i+1:]...)
+				break
+			}
+		}
+		w.mu.Unlock()
+
+		// The event may have been delivered while we were removing the waiter.
+		select {
+		case e := <-waiter.ch:
+			return e, nil
+		default:
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func flattenNetworkHeaders(headers []networkHeader) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(headers))
+	for _, h := range headers {
+		result[h.Name] = h.Value.Value
+	}
+	return result
+}
+
+// TrackNetwork starts buffering network events so that WaitForRequest and
+// WaitForResponse with WaitForNetworkOptions.Since can match requests that
+// completed before the wait began. It is called automatically by the first
+// wait, so it is only needed when Since should cover earlier activity.
+func (p *Pilot) TrackNetwork(ctx context.Context) error {
+	if p.closed.Load() {
+		return ErrConnectionClosed
+	}
+
+	_, err := p.client.networkWatcher(ctx)
+	return err
+}
+
+// WaitForRequest waits for a request whose URL matches pattern.
+// Pattern supports exact match, glob (* and **), and regex wrapped in slashes.
+//
+// Start the wait before triggering the request, or set opts.Since to match
+// requests already sent (see TrackNetwork).
+func (p *Pilot) WaitForRequest(ctx context.Context, pattern string, opts *WaitForNetworkOptions) (*Request, error) {
+	e, err := p.waitForNetwork(ctx, pattern, false, opts)
+	if err != nil {
+		return nil, err
+	}
+	return e.request, nil
+}
+
+// WaitForResponse waits for a completed response whose URL matches pattern.
+// Pattern supports exact match, glob (* and **), and regex wrapped in slashes.
+//
+// Start the wait before triggering the request, or set opts.Since to match
+// responses already received (see TrackNetwork).
+func (p *Pilot) WaitForResponse(ctx context.Context, pattern string, opts *WaitForNetworkOptions) (*Response, error) {
+	e, err := p.waitForNetwork(ctx, pattern, true, opts)
+	if err != nil {
+		return nil, err
+	}
+	return e.response, nil
+}
+
+// WaitForRequestFinished waits for a request whose URL matches pattern to
+// finish, i.e. for its network.responseCompleted event, and returns its
+// response so the status can be asserted in the same step. It is a more
+// targeted way to settle before assertions than a networkidle wait on a
+// busy page. A zero timeout uses the default timeout.
+//
+// Start the wait before triggering the request; to match a request that
+// may already have finished, use WaitForResponse with Since.
+func (p *Pilot) WaitForRequestFinished(ctx context.Context, pattern string, timeout time.Duration) (*Response, error) {
+	return p.WaitForResponse(ctx, pattern, &WaitForNetworkOptions{Timeout: timeout})
+}
+
+func (p *Pilot) waitForNetwork(ctx context.Context, pattern string, response bool, opts *WaitForNetworkOptions) (*observedNetworkEvent, error) {
+	if p.closed.Load() {
+		return nil, ErrConnectionClosed
+	}
+
+	timeout := p.DefaultTimeout()
+	var since time.Time
+	if opts != nil {
+		if opts.Timeout > 0 {
+			timeout = opts.Timeout
+		}
+		since = opts.Since
+	}
+
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	browsingCtx, err := p.getContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := p.client.networkWatcher(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := w.wait(ctx, &networkWaiter{
+		response: response,
+		context:  browsingCtx,
+		pattern:  pattern,
+	}, since)
+	if errors.Is(err, context.Canceled) {
+		return nil, err
+	}
+	if err != nil {
+		kind := "request"
+		if response {
+			kind = "response"
+		}
+		return nil, &TimeoutError{
+			Selector: pattern,
+			Timeout:  timeout.Milliseconds(),
+			Reason:   "no matching " + kind,
+		}
+	}
+	return e, nil
+}
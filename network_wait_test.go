@@ -0,0 +1,189 @@
+package w3pilot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func emitNetworkEvent(t *testing.T, mock *mockTransport, method, params string) {
+	t.Helper()
+	mock.mu.Lock()
+	handlers := mock.handlers[method]
+	mock.mu.Unlock()
+	if len(handlers) == 0 {
+		t.Fatalf("No handler registered for %s", method)
+	}
+	for _, h := range handlers {
+		h(&BiDiEvent{Method: method, Params: json.RawMessage(params)})
+	}
+}
+
+// TestWaitForResponse_MatchesPattern verifies a pending wait resolves on a matching response.
+func TestWaitForResponse_MatchesPattern(t *testing.T) {
+	mock := newMockTransport()
+	pilot := &Pilot{
+		client:          NewBiDiClient(mock),
+		browsingContext: "ctx-123",
+	}
+
+	if err := pilot.TrackNetwork(context.Background()); err != nil {
+		t.Fatalf("TrackNetwork failed: %v", err)
+	}
+
+	done := make(chan *Response, 1)
+	go func() {
+		resp, err := pilot.WaitForResponse(context.Background(), "**/api/save", &WaitForNetworkOptions{Timeout: time.Second})
+		if err != nil {
+			t.Errorf("WaitForResponse failed: %v", err)
+		}
+		done <- resp
+	}()
+
+	// Give the waiter time to register before emitting events.
+	time.Sleep(20 * time.Millisecond)
+
+	emitNetworkEvent(t, mock, "network.responseCompleted",
+		`{"context":"ctx-123","request":{"request":"r0","url":"https://example.com/api/other"},"response":{"url":"https://example.com/api/other","status":200}}`)
+	emitNetworkEvent(t, mock, "network.responseCompleted",
+		`{"context":"ctx-123","request":{"request":"r1","url":"https://example.com/api/save"},"response":{"url":"https://example.com/api/save","status":201,"headers":[{"name":"content-type","value":{"type":"string","value":"application/json"}}]}}`)
+
+	resp := <-done
+	if resp == nil {
+		t.Fatal("Expected a response")
+	}
+	if resp.Status != 201 || resp.RequestID != "r1" {
+		t.Errorf("Expected status 201 for r1, got %d for %s", resp.Status, resp.RequestID)
+	}
+	if resp.Headers["content-type"] != "application/json" {
+		t.Errorf("Expected content-type header, got %v", resp.Headers)
+	}
+}
+
+// TestWaitForRequest_Since verifies buffered requests match when Since is set.
+func TestWaitForRequest_Since(t *testing.T) {
+	mock := newMockTransport()
+	pilot := &Pilot{
+		client:          NewBiDiClient(mock),
+		browsingContext: "ctx-123",
+	}
+
+	since := time.Now()
+	if err := pilot.TrackNetwork(context.Background()); err != nil {
+		t.Fatalf("TrackNetwork failed: %v", err)
+	}
+
+	emitNetworkEvent(t, mock, "network.beforeRequestSent",
+		`{"context":"ctx-123","request":{"request":"r1","url":"https://example.com/api/save","method":"POST"}}`)
+
+	req, err := pilot.WaitForRequest(context.Background(), "/api\\/save$/", &WaitForNetworkOptions{
+		Timeout: 100 * time.Millisecond,
+		Since:   since,
+	})
+	if err != nil {
+		t.Fatalf("WaitForRequest failed: %v", err)
+	}
+	if req.Method != "POST" {
+		t.Errorf("Expected POST, got %s", req.Method)
+	}
+
+	// Without Since, the buffered request is ignored and the wait times out.
+	_, err = pilot.WaitForRequest(context.Background(), "**/api/save", &WaitForNetworkOptions{Timeout: 20 * time.Millisecond})
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Errorf("Expected TimeoutError, got %v", err)
+	}
+}
+
+// TestWaitForRequestFinished verifies the wait resolves on responseCompleted,
+// not on the request being sent, and times out without one.
+func TestWaitForRequestFinished(t *testing.T) {
+	mock := newMockTransport()
+	pilot := &Pilot{
+		client:          NewBiDiClient(mock),
+		browsingContext: "ctx-123",
+	}
+
+	if err := pilot.TrackNetwork(context.Background()); err != nil {
+		t.Fatalf("TrackNetwork failed: %v", err)
+	}
+
+	done := make(chan *Response, 1)
+	go func() {
+		resp, err := pilot.WaitForRequestFinished(context.Background(), "**/beacon", time.Second)
+		if err != nil {
+			t.Errorf("WaitForRequestFinished failed: %v", err)
+		}
+		done <- resp
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+
+	emitNetworkEvent(t, mock, "network.beforeRequestSent",
+		`{"context":"ctx-123","request":{"request":"r1","url":"https://example.com/beacon","method":"POST"}}`)
+	select {
+	case <-done:
+		t.Fatal("Expected the wait to continue until the response completed")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	emitNetworkEvent(t, mock, "network.responseCompleted",
+		`{"context":"ctx-123","request":{"request":"r1","url":"https://example.com/beacon"},"response":{"url":"https://example.com/beacon","status":204}}`)
+
+	resp := <-done
+	if resp == nil || resp.Status != 204 {
+		t.Fatalf("Expected a 204 response, got %+v", resp)
+	}
+
+	_, err := pilot.WaitForRequestFinished(context.Background(), "**/beacon", 20*time.Millisecond)
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Errorf("Expected TimeoutError, got %v", err)
+	}
+}
+
+// TestWaitForResponse_Timing verifies BiDi timings and sizes are surfaced on Response.
+func TestWaitForResponse_Timing(t *testing.T) {
+	mock := newMockTransport()
+	pilot := &Pilot{
+		client:          NewBiDiClient(mock),
+		browsingContext: "ctx-123",
+	}
+
+	since := time.Now()
+	if err := pilot.TrackNetwork(context.Background()); err != nil {
+		t.Fatalf("TrackNetwork failed: %v", err)
+	}
+
+	emitNetworkEvent(t, mock, "network.responseCompleted", `{
+		"context":"ctx-123",
+		"request":{"request":"r1","url":"https://example.com/","timings":{
+			"timeOrigin":1700000000000,"requestTime":100,"dnsStart":0,"dnsEnd":0,
+			"connectStart":0,"connectEnd":0,"tlsStart":0,
+			"requestStart":110,"responseStart":350,"responseEnd":520}},
+		"response":{"url":"https://example.com/","status":200,"bytesReceived":15320,"bodySize":14800,"fromCache":false}}`)
+
+	resp, err := pilot.WaitForResponse(context.Background(), "https://example.com/", &WaitForNetworkOptions{Since: since, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("WaitForResponse failed: %v", err)
+	}
+
+	if resp.TransferSize != 15320 || resp.EncodedBodySize != 14800 {
+		t.Errorf("Unexpected sizes: transfer %d, body %d", resp.TransferSize, resp.EncodedBodySize)
+	}
+	timing := resp.Timing
+	if timing == nil {
+		t.Fatal("Expected timing")
+	}
+	if !timing.StartTime.Equal(time.UnixMilli(1700000000100)) {
+		t.Errorf("Unexpected start time %v", timing.StartTime)
+	}
+	if timing.Duration() != 420*time.Millisecond || timing.TimeToFirstByte() != 250*time.Millisecond {
+		t.Errorf("Unexpected durations: total %v, TTFB %v", timing.Duration(), timing.TimeToFirstByte())
+	}
+	if timing.DNSStart != 0 || timing.RequestStart != 10*time.Millisecond {
+		t.Errorf("Unexpected phases: %+v", timing)
+	}
+}
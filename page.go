@@ -0,0 +1,36 @@
+package w3pilot
+
+import (
+	"context"
+	"time"
+)
+
+// Page is the page-level surface of *Pilot that page objects and helpers
+// typically depend on. Accepting a Page instead of a *Pilot lets that code
+// be unit tested without a browser, using w3pilottest.FakePage, and lets
+// callers inject their own implementations.
+type Page interface {
+	// Navigation
+	Go(ctx context.Context, url string) error
+	Back(ctx context.Context) error
+	Forward(ctx context.Context) error
+	Reload(ctx context.Context) error
+
+	// Page information
+	URL(ctx context.Context) (string, error)
+	Title(ctx context.Context) (string, error)
+	Content(ctx context.Context) (string, error)
+	Screenshot(ctx context.Context) ([]byte, error)
+	Evaluate(ctx context.Context, script string) (interface{}, error)
+
+	// Elements
+	Find(ctx context.Context, selector string, opts *FindOptions) (*Element, error)
+	FindAll(ctx context.Context, selector string, opts *FindOptions) ([]*Element, error)
+
+	// Waiting
+	WaitForSelector(ctx context.Context, selector, state string, timeout time.Duration) (*Element, error)
+	WaitForURL(ctx context.Context, pattern string, timeout time.Duration) error
+	WaitForLoad(ctx context.Context, state string, timeout time.Duration) error
+}
+
+var _ Page = (*Pilot)(nil)
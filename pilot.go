@@ -1,23 +1,48 @@
 package w3pilot
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/plexusone/w3pilot/cdp"
 )
 
 // Pilot is the main browser control interface.
+//
+// A Pilot is safe for concurrent use by multiple goroutines: its cached
+// browsing context, lazily created controllers, and closed state are
+// synchronized, and commands are multiplexed over one connection. The
+// browser itself still processes commands against a single page, so
+// concurrent actions on the same page (e.g. two Fill calls) may interleave;
+// observation calls such as Screenshot, URL, or Evaluate can safely run
+// alongside an action. Use NewPage for independent parallel work.
+// Quit may be called more than once and from any goroutine; calls after
+// the first return nil.
 type Pilot struct {
-	client          *BiDiClient
-	pipeTransport   *pipeTransport  // Used in pipe mode (default)
-	clicker         *ClickerProcess // Used in WebSocket mode
+	client        *BiDiClient
+	pipeTransport *pipeTransport  // Used in pipe mode (default)
+	clicker       *ClickerProcess // Used in WebSocket mode
+	closed        atomic.Bool
+
+	// isFrame is set for pages returned by Frame, whose screenshots are
+	// clipped from the top-level page.
+	isFrame bool
+
+	// mu guards browsingContext and the lazy-initialized fields below.
+	mu              sync.Mutex
 	browsingContext string
-	closed          bool
 
 	// CDP client for direct Chrome DevTools Protocol access
 	cdpClient *cdp.Client
@@ -37,6 +62,26 @@ type Pilot struct {
 
 	// CDP console debugger (lazy-initialized)
 	consoleDebugger *cdp.ConsoleDebugger
+
+	// Permissions granted via GrantPermissions, reset by ClearPermissions
+	grantedPermissions []grantedPermission
+
+	// Pages and browser contexts created by NewPage and NewContext, closed
+	// by CloseAll and Quit
+	spawnedPages    []*Pilot
+	spawnedContexts []*BrowserContext
+
+	// pageClosed is set once Close has closed this page
+	pageClosed atomic.Bool
+
+	// defaultTimeout overrides DefaultTimeout when positive (nanoseconds)
+	defaultTimeout atomic.Int64
+}
+
+// grantedPermission records a permission granted for an origin.
+type grantedPermission struct {
+	name   string
+	origin string
 }
 
 // Browser provides browser launching capabilities.
@@ -56,13 +101,46 @@ func (b *browserLauncher) Launch(ctx context.Context, opts *LaunchOptions) (*Pil
 		debugLog(ctx, "launching browser", "headless", opts.Headless, "websocket", opts.UseWebSocket)
 	}
 
+	var pilot *Pilot
+	var err error
 	if opts.UseWebSocket {
 		// WebSocket mode (clicker serve) - for multiple clients or debugging
-		return b.launchWebSocket(ctx, opts)
+		pilot, err = b.launchWebSocket(ctx, opts)
+	} else {
+		// Pipe mode (clicker pipe) - default, full vibium:* command support
+		pilot, err = b.launchPipe(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.SlowMo > 0 {
+		pilot.client.SetSlowMo(opts.SlowMo)
 	}
 
-	// Pipe mode (clicker pipe) - default, full vibium:* command support
-	return b.launchPipe(ctx, opts)
+	if opts.OnCommand != nil {
+		pilot.client.SetCommandHook(opts.OnCommand)
+	}
+
+	if opts.Tracer != nil {
+		pilot.client.SetTracer(opts.Tracer)
+	}
+
+	if opts.LogRequests {
+		if err := pilot.enableRequestLogging(ctx, opts.LogRequestsLevel); err != nil {
+			_ = pilot.Quit(ctx)
+			return nil, fmt.Errorf("failed to enable request logging: %w", err)
+		}
+	}
+
+	if opts.Stealth {
+		if err := pilot.applyStealth(ctx); err != nil {
+			_ = pilot.Quit(ctx)
+			return nil, fmt.Errorf("failed to apply stealth: %w", err)
+		}
+	}
+
+	return pilot, nil
 }
 
 // launchPipe starts the browser using pipe (stdin/stdout) transport.
@@ -107,7 +185,7 @@ func (b *browserLauncher) launchWebSocket(ctx context.Context, opts *LaunchOptio
 	debugLog(ctx, "clicker started", "url", clicker.WebSocketURL())
 
 	// Connect WebSocket transport for BiDi
-	wsTransport := newWSTransport()
+	wsTransport := newWSTransport(pingInterval(opts.PingInterval))
 	if err := wsTransport.Connect(ctx, clicker.WebSocketURL()); err != nil {
 		_ = clicker.Stop()
 		return nil, err
@@ -132,6 +210,17 @@ func (b *browserLauncher) launchWebSocket(ctx context.Context, opts *LaunchOptio
 	return pilot, nil
 }
 
+// pingInterval resolves LaunchOptions.PingInterval: 0 means the default, negative disables.
+func pingInterval(d time.Duration) time.Duration {
+	switch {
+	case d == 0:
+		return DefaultPingInterval
+	case d < 0:
+		return 0
+	}
+	return d
+}
+
 // Connect connects to an existing clicker instance via WebSocket URL.
 // This is used to reconnect to a browser session that was previously launched.
 func (b *browserLauncher) Connect(ctx context.Context, wsURL string) (*Pilot, error) {
@@ -141,7 +230,7 @@ func (b *browserLauncher) Connect(ctx context.Context, wsURL string) (*Pilot, er
 	}
 
 	// Connect WebSocket transport for BiDi
-	wsTransport := newWSTransport()
+	wsTransport := newWSTransport(DefaultPingInterval)
 	if err := wsTransport.Connect(ctx, wsURL); err != nil {
 		return nil, fmt.Errorf("failed to connect to WebSocket: %w", err)
 	}
@@ -210,10 +299,98 @@ func LaunchHeadless(ctx context.Context) (*Pilot, error) {
 	return Browser.Launch(ctx, &LaunchOptions{Headless: true})
 }
 
+// WithPilot launches a browser, runs fn, and always quits the browser afterwards,
+// even if fn panics. It returns the error from fn if any, otherwise the error
+// from Quit. A panic in fn is re-raised after the browser has been closed.
+//
+// Example:
+//
+//	err := w3pilot.WithPilot(ctx, &w3pilot.LaunchOptions{Headless: true}, func(pilot *w3pilot.Pilot) error {
+//		return pilot.Go(ctx, "https://example.com")
+//	})
+func WithPilot(ctx context.Context, opts *LaunchOptions, fn func(*Pilot) error) (err error) {
+	pilot, err := Browser.Launch(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		// Quit with a non-cancelable context so cleanup runs even if ctx is done.
+		quitErr := pilot.Quit(context.WithoutCancel(ctx))
+		if err == nil {
+			err = quitErr
+		}
+	}()
+
+	return fn(pilot)
+}
+
+// WithPage opens a new page on pilot, runs fn, and always closes the page
+// afterwards, even if fn panics. It returns the error from fn if any,
+// otherwise the error from closing the page.
+func WithPage(ctx context.Context, pilot *Pilot, fn func(*Pilot) error) (err error) {
+	page, err := pilot.NewPage(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		closeErr := page.Close(context.WithoutCancel(ctx))
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	return fn(page)
+}
+
+// SetDefaultTimeout sets the timeout used by this page, and by elements,
+// frames, and pages obtained from it afterwards, when a method's options
+// or arguments omit one. A zero or negative d restores DefaultTimeout.
+func (p *Pilot) SetDefaultTimeout(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	p.defaultTimeout.Store(int64(d))
+}
+
+// DefaultTimeout returns the timeout used when a method's options or
+// arguments omit one: the value set with SetDefaultTimeout, or the package
+// DefaultTimeout.
+func (p *Pilot) DefaultTimeout() time.Duration {
+	if d := time.Duration(p.defaultTimeout.Load()); d > 0 {
+		return d
+	}
+	return DefaultTimeout
+}
+
+// derive returns a Pilot for another browsing context on the same
+// connection that inherits this page's default timeout.
+func (p *Pilot) derive(browsingContext string) *Pilot {
+	child := &Pilot{
+		client:          p.client,
+		clicker:         p.clicker,
+		browsingContext: browsingContext,
+	}
+	child.defaultTimeout.Store(p.defaultTimeout.Load())
+	return child
+}
+
+// newElement returns an element in browsingCtx that uses this page's
+// default timeout.
+func (p *Pilot) newElement(browsingCtx, selector string, info ElementInfo) *Element {
+	elem := NewElement(p.client, browsingCtx, selector, info)
+	elem.timeout = time.Duration(p.defaultTimeout.Load())
+	return elem
+}
+
 // getContext returns the browsing context ID, fetching it if necessary.
 func (p *Pilot) getContext(ctx context.Context) (string, error) {
-	if p.browsingContext != "" {
-		return p.browsingContext, nil
+	p.mu.Lock()
+	browsingCtx := p.browsingContext
+	p.mu.Unlock()
+	if browsingCtx != "" {
+		return browsingCtx, nil
 	}
 
 	result, err := p.client.Send(ctx, "browsingContext.getTree", map[string]interface{}{})
@@ -234,13 +411,18 @@ func (p *Pilot) getContext(ctx context.Context) (string, error) {
 		return "", fmt.Errorf("no browsing context available")
 	}
 
-	p.browsingContext = tree.Contexts[0].Context
+	// Another goroutine may have resolved the context concurrently; keep the first
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.browsingContext == "" {
+		p.browsingContext = tree.Contexts[0].Context
+	}
 	return p.browsingContext, nil
 }
 
 // Go navigates to the specified URL.
 func (p *Pilot) Go(ctx context.Context, url string) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 	debugLog(ctx, "navigating", "url", url)
@@ -263,12 +445,31 @@ func (p *Pilot) Go(ctx context.Context, url string) error {
 	return err
 }
 
-// Reload reloads the current page.
+// Reload reloads the current page and waits for it to finish loading.
 func (p *Pilot) Reload(ctx context.Context) error {
-	if p.closed {
+	return p.ReloadWithOptions(ctx, nil)
+}
+
+// ReloadWithOptions reloads the current page, optionally bypassing the cache
+// and waiting for a specific readiness state.
+func (p *Pilot) ReloadWithOptions(ctx context.Context, opts *ReloadOptions) error {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
-	debugLog(ctx, "reloading page")
+
+	wait := "complete"
+	ignoreCache := false
+	if opts != nil {
+		switch opts.WaitUntil {
+		case "":
+		case "none", "interactive", "complete":
+			wait = opts.WaitUntil
+		default:
+			return fmt.Errorf("invalid ReloadOptions.WaitUntil %q: must be none, interactive, or complete", opts.WaitUntil)
+		}
+		ignoreCache = opts.IgnoreCache
+	}
+	debugLog(ctx, "reloading page", "ignoreCache", ignoreCache, "wait", wait)
 
 	browsingCtx, err := p.getContext(ctx)
 	if err != nil {
@@ -277,7 +478,10 @@ func (p *Pilot) Reload(ctx context.Context) error {
 
 	params := map[string]interface{}{
 		"context": browsingCtx,
-		"wait":    "complete",
+		"wait":    wait,
+	}
+	if ignoreCache {
+		params["ignoreCache"] = true
 	}
 
 	_, err = p.client.Send(ctx, "browsingContext.reload", params)
@@ -286,10 +490,24 @@ func (p *Pilot) Reload(ctx context.Context) error {
 
 // Back navigates back in history.
 func (p *Pilot) Back(ctx context.Context) error {
-	if p.closed {
+	return p.Traverse(ctx, -1)
+}
+
+// Forward navigates forward in history.
+func (p *Pilot) Forward(ctx context.Context) error {
+	return p.Traverse(ctx, 1)
+}
+
+// Traverse moves delta entries through the session history. Negative values
+// go back and positive values go forward; delta must not be zero.
+func (p *Pilot) Traverse(ctx context.Context, delta int) error {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
-	debugLog(ctx, "navigating back")
+	if delta == 0 {
+		return fmt.Errorf("history delta must not be zero")
+	}
+	debugLog(ctx, "traversing history", "delta", delta)
 
 	browsingCtx, err := p.getContext(ctx)
 	if err != nil {
@@ -298,48 +516,75 @@ func (p *Pilot) Back(ctx context.Context) error {
 
 	params := map[string]interface{}{
 		"context": browsingCtx,
-		"delta":   -1,
+		"delta":   delta,
 	}
 
 	_, err = p.client.Send(ctx, "browsingContext.traverseHistory", params)
 	return err
 }
 
-// Forward navigates forward in history.
-func (p *Pilot) Forward(ctx context.Context) error {
-	if p.closed {
-		return ErrConnectionClosed
-	}
-	debugLog(ctx, "navigating forward")
-
-	browsingCtx, err := p.getContext(ctx)
+// HistoryLength returns the number of entries in the session history,
+// including the current page (window.history.length).
+func (p *Pilot) HistoryLength(ctx context.Context) (int, error) {
+	result, err := p.Evaluate(ctx, "return window.history.length")
 	if err != nil {
-		return err
+		return 0, err
 	}
-
-	params := map[string]interface{}{
-		"context": browsingCtx,
-		"delta":   1,
+	n, ok := result.(float64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected history length result: %v", result)
 	}
-
-	_, err = p.client.Send(ctx, "browsingContext.traverseHistory", params)
-	return err
+	return int(n), nil
 }
 
 // Screenshot captures a screenshot of the current page and returns PNG data.
 func (p *Pilot) Screenshot(ctx context.Context) ([]byte, error) {
-	if p.closed {
+	return p.ScreenshotWithOptions(ctx, nil)
+}
+
+// ScreenshotWithOptions captures a screenshot of the current page and returns
+// PNG data. With opts.FullPage it captures the whole scrollable document
+// rather than the viewport.
+func (p *Pilot) ScreenshotWithOptions(ctx context.Context, opts *ScreenshotOptions) ([]byte, error) {
+	if p.closed.Load() {
 		return nil, ErrConnectionClosed
 	}
 
+	fullPage := opts != nil && opts.FullPage
+	if fullPage && p.isFrame {
+		return nil, fmt.Errorf("full-page screenshots are not supported for frames")
+	}
+
 	browsingCtx, err := p.getContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := p.client.Send(ctx, "browsingContext.captureScreenshot", map[string]interface{}{
+	params := map[string]interface{}{
 		"context": browsingCtx,
-	})
+	}
+	if fullPage {
+		params["origin"] = "document"
+	}
+
+	// BiDi only captures top-level contexts, so a frame is captured by
+	// clipping its top-level page to the frame's content area.
+	if p.isFrame {
+		topCtx, clip, err := p.frameClip(ctx, browsingCtx)
+		if err != nil {
+			return nil, err
+		}
+		params["context"] = topCtx
+		params["clip"] = map[string]interface{}{
+			"type":   "box",
+			"x":      clip.X,
+			"y":      clip.Y,
+			"width":  clip.Width,
+			"height": clip.Height,
+		}
+	}
+
+	result, err := p.client.Send(ctx, "browsingContext.captureScreenshot", params)
 	if err != nil {
 		return nil, err
 	}
@@ -362,7 +607,7 @@ func (p *Pilot) Screenshot(ctx context.Context) ([]byte, error) {
 
 // Find finds an element by CSS selector.
 func (p *Pilot) Find(ctx context.Context, selector string, opts *FindOptions) (*Element, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return nil, ErrConnectionClosed
 	}
 	debugLog(ctx, "finding element", "selector", selector)
@@ -372,12 +617,15 @@ func (p *Pilot) Find(ctx context.Context, selector string, opts *FindOptions) (*
 		return nil, err
 	}
 
-	timeout := DefaultTimeout
+	timeout := p.DefaultTimeout()
 	if opts != nil && opts.Timeout > 0 {
 		timeout = opts.Timeout
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return nil, err
+	}
 	defer cancel()
 
 	params := map[string]interface{}{
@@ -387,34 +635,8 @@ func (p *Pilot) Find(ctx context.Context, selector string, opts *FindOptions) (*
 	}
 
 	// Add semantic selector options if present
-	if opts != nil {
-		if opts.Role != "" {
-			params["role"] = opts.Role
-		}
-		if opts.Text != "" {
-			params["text"] = opts.Text
-		}
-		if opts.Label != "" {
-			params["label"] = opts.Label
-		}
-		if opts.Placeholder != "" {
-			params["placeholder"] = opts.Placeholder
-		}
-		if opts.TestID != "" {
-			params["testid"] = opts.TestID
-		}
-		if opts.Alt != "" {
-			params["alt"] = opts.Alt
-		}
-		if opts.Title != "" {
-			params["title"] = opts.Title
-		}
-		if opts.XPath != "" {
-			params["xpath"] = opts.XPath
-		}
-		if opts.Near != "" {
-			params["near"] = opts.Near
-		}
+	if err := addFindOptions(params, opts); err != nil {
+		return nil, err
 	}
 
 	result, err := p.client.Send(ctx, "vibium:page.find", params)
@@ -427,14 +649,26 @@ func (p *Pilot) Find(ctx context.Context, selector string, opts *FindOptions) (*
 		return nil, fmt.Errorf("failed to parse element info: %w", err)
 	}
 
+	// Text selectors are easily ambiguous ("Log in" vs "Log in with Google"),
+	// so refuse to silently pick the first of several matches.
+	if opts != nil && opts.Text != "" {
+		matches, err := p.FindAll(ctx, selector, opts)
+		if err != nil {
+			return nil, err
+		}
+		if err := ambiguousTextMatch(selector, opts.Text, matches); err != nil {
+			return nil, err
+		}
+	}
+
 	debugLog(ctx, "element found", "selector", selector, "tag", info.Tag)
-	return NewElement(p.client, browsingCtx, selector, info), nil
+	return p.newElement(browsingCtx, selector, info), nil
 }
 
 // FindAll finds all elements matching the selector and optional semantic options.
 // If selector is empty but semantic options are provided, elements are found by those options.
 func (p *Pilot) FindAll(ctx context.Context, selector string, opts *FindOptions) ([]*Element, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return nil, ErrConnectionClosed
 	}
 	debugLog(ctx, "finding all elements", "selector", selector)
@@ -444,7 +678,7 @@ func (p *Pilot) FindAll(ctx context.Context, selector string, opts *FindOptions)
 		return nil, err
 	}
 
-	timeout := DefaultTimeout
+	timeout := p.DefaultTimeout()
 	if opts != nil && opts.Timeout > 0 {
 		timeout = opts.Timeout
 	}
@@ -456,34 +690,8 @@ func (p *Pilot) FindAll(ctx context.Context, selector string, opts *FindOptions)
 	}
 
 	// Add semantic selector options if present
-	if opts != nil {
-		if opts.Role != "" {
-			params["role"] = opts.Role
-		}
-		if opts.Text != "" {
-			params["text"] = opts.Text
-		}
-		if opts.Label != "" {
-			params["label"] = opts.Label
-		}
-		if opts.Placeholder != "" {
-			params["placeholder"] = opts.Placeholder
-		}
-		if opts.TestID != "" {
-			params["testid"] = opts.TestID
-		}
-		if opts.Alt != "" {
-			params["alt"] = opts.Alt
-		}
-		if opts.Title != "" {
-			params["title"] = opts.Title
-		}
-		if opts.XPath != "" {
-			params["xpath"] = opts.XPath
-		}
-		if opts.Near != "" {
-			params["near"] = opts.Near
-		}
+	if err := addFindOptions(params, opts); err != nil {
+		return nil, err
 	}
 
 	result, err := p.client.Send(ctx, "vibium:page.findAll", params)
@@ -520,13 +728,66 @@ func (p *Pilot) FindAll(ctx context.Context, selector string, opts *FindOptions)
 			Text: item.Text,
 			Box:  item.Box,
 		}
-		elements[i] = NewElement(p.client, browsingCtx, elemSelector, info)
+		elements[i] = p.newElement(browsingCtx, elemSelector, info)
 	}
 
 	debugLog(ctx, "elements found", "selector", selector, "count", len(elements))
 	return elements, nil
 }
 
+// FindByText finds an element by its visible text content.
+// Set opts.ExactText to match the whole text instead of a substring.
+func (p *Pilot) FindByText(ctx context.Context, text string, opts *FindOptions) (*Element, error) {
+	o := findOptionsOrDefault(opts)
+	o.Text = text
+	return p.Find(ctx, "", o)
+}
+
+// FindByRole finds an element by its ARIA role (e.g. "button", "textbox").
+// Combine with opts.Text or opts.Label to pick among several elements with the same role.
+func (p *Pilot) FindByRole(ctx context.Context, role string, opts *FindOptions) (*Element, error) {
+	o := findOptionsOrDefault(opts)
+	o.Role = role
+	return p.Find(ctx, "", o)
+}
+
+// FindByLabel finds a form control by its associated label text.
+// Set opts.ExactText to match the whole label instead of a substring.
+func (p *Pilot) FindByLabel(ctx context.Context, label string, opts *FindOptions) (*Element, error) {
+	o := findOptionsOrDefault(opts)
+	o.Label = label
+	return p.Find(ctx, "", o)
+}
+
+// FindByTestID finds an element by its data-testid attribute.
+func (p *Pilot) FindByTestID(ctx context.Context, testID string, opts *FindOptions) (*Element, error) {
+	o := findOptionsOrDefault(opts)
+	o.TestID = testID
+	return p.Find(ctx, "", o)
+}
+
+// ambiguousTextMatch returns an AmbiguousMatchError if more than one element matched text.
+func ambiguousTextMatch(selector, text string, matches []*Element) error {
+	if len(matches) <= 1 {
+		return nil
+	}
+	candidates := make([]ElementInfo, len(matches))
+	for i, m := range matches {
+		candidates[i] = m.Info()
+	}
+	return &AmbiguousMatchError{Selector: selector, Text: text, Candidates: candidates}
+}
+
+// findOptionsOrDefault returns a copy of opts, or empty options if opts is nil,
+// so FindBy* helpers don't modify the caller's options.
+func findOptionsOrDefault(opts *FindOptions) *FindOptions {
+	var o FindOptions
+	if opts != nil {
+		o = *opts
+	}
+	return &o
+}
+
 // MustFind finds an element by CSS selector and panics if not found.
 func (p *Pilot) MustFind(ctx context.Context, selector string) *Element {
 	elem, err := p.Find(ctx, selector, nil)
@@ -536,6 +797,70 @@ func (p *Pilot) MustFind(ctx context.Context, selector string) *Element {
 	return elem
 }
 
+// serializeBiDiValue converts a Go value to a BiDi local value.
+// It is the inverse of deserializeBiDiValue: maps become [[key, value], ...]
+// object pairs and slices become arrays of typed values. Values of other
+// types are round-tripped through JSON first.
+func serializeBiDiValue(v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case nil:
+		return map[string]interface{}{"type": "null"}, nil
+	case bool:
+		return map[string]interface{}{"type": "boolean", "value": val}, nil
+	case string:
+		return map[string]interface{}{"type": "string", "value": val}, nil
+	case float64:
+		switch {
+		case math.IsNaN(val):
+			return map[string]interface{}{"type": "number", "value": "NaN"}, nil
+		case math.IsInf(val, 1):
+			return map[string]interface{}{"type": "number", "value": "Infinity"}, nil
+		case math.IsInf(val, -1):
+			return map[string]interface{}{"type": "number", "value": "-Infinity"}, nil
+		}
+		return map[string]interface{}{"type": "number", "value": val}, nil
+	case int:
+		return map[string]interface{}{"type": "number", "value": val}, nil
+	case int64:
+		return map[string]interface{}{"type": "number", "value": val}, nil
+	case []interface{}:
+		items := make([]interface{}, 0, len(val))
+		for _, item := range val {
+			s, err := serializeBiDiValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, s)
+		}
+		return map[string]interface{}{"type": "array", "value": items}, nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		pairs := make([]interface{}, 0, len(val))
+		for _, k := range keys {
+			s, err := serializeBiDiValue(val[k])
+			if err != nil {
+				return nil, err
+			}
+			pairs = append(pairs, []interface{}{k, s})
+		}
+		return map[string]interface{}{"type": "object", "value": pairs}, nil
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return nil, err
+		}
+		var generic interface{}
+		if err := json.Unmarshal(data, &generic); err != nil {
+			return nil, err
+		}
+		return serializeBiDiValue(generic)
+	}
+}
+
 // deserializeBiDiValue converts a BiDi remote value to a Go value.
 // BiDi returns objects as [[key, {type, value}], ...] and arrays as [{type, value}, ...]
 // This function recursively converts these to Go maps and slices.
@@ -654,7 +979,15 @@ func deserializeBiDiValue(typ string, value interface{}) interface{} {
 
 // Evaluate executes JavaScript in the page context and returns the result.
 func (p *Pilot) Evaluate(ctx context.Context, script string) (interface{}, error) {
-	if p.closed {
+	return p.EvaluateWithOptions(ctx, script, nil)
+}
+
+// EvaluateWithOptions executes JavaScript like Evaluate. If opts.Sandbox is
+// set, the script runs in an isolated realm of that name: it shares the DOM
+// with the page but not its globals, so page code can neither observe nor
+// tamper with it. Scripts using the same sandbox name share a realm.
+func (p *Pilot) EvaluateWithOptions(ctx context.Context, script string, opts *EvaluateOptions) (interface{}, error) {
+	if p.closed.Load() {
 		return nil, ErrConnectionClosed
 	}
 
@@ -702,9 +1035,14 @@ func (p *Pilot) Evaluate(ctx context.Context, script string) (interface{}, error
 		wrappedScript = fmt.Sprintf("() => (%s)", script)
 	}
 
+	target := map[string]interface{}{"context": browsingCtx}
+	if opts != nil && opts.Sandbox != "" {
+		target["sandbox"] = opts.Sandbox
+	}
+
 	params := map[string]interface{}{
 		"functionDeclaration": wrappedScript,
-		"target":              map[string]interface{}{"context": browsingCtx},
+		"target":              target,
 		"arguments":           []interface{}{},
 		"awaitPromise":        true,
 		"resultOwnership":     "root",
@@ -729,6 +1067,93 @@ func (p *Pilot) Evaluate(ctx context.Context, script string) (interface{}, error
 	return deserializeBiDiValue(resp.Result.Type, resp.Result.Value), nil
 }
 
+// EvaluateWithArgs calls a JavaScript function in the page context with the
+// given arguments and returns the result. Arguments are passed through BiDi
+// value serialization rather than interpolated into the script, so strings
+// containing quotes or untrusted input need no escaping.
+//
+// fn must be a function declaration, e.g. "(id) => document.getElementById(id)?.textContent".
+// Arguments may be nil, booleans, numbers, strings, slices, maps, or any
+// value that marshals to JSON.
+func (p *Pilot) EvaluateWithArgs(ctx context.Context, fn string, args ...interface{}) (interface{}, error) {
+	if p.closed.Load() {
+		return nil, ErrConnectionClosed
+	}
+
+	browsingCtx, err := p.getContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	arguments := make([]interface{}, 0, len(args))
+	for i, arg := range args {
+		value, err := serializeBiDiValue(arg)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i, err)
+		}
+		arguments = append(arguments, value)
+	}
+
+	params := map[string]interface{}{
+		"functionDeclaration": fn,
+		"target":              map[string]interface{}{"context": browsingCtx},
+		"arguments":           arguments,
+		"awaitPromise":        true,
+		"resultOwnership":     "root",
+	}
+
+	result, err := p.client.Send(ctx, "script.callFunction", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Result struct {
+			Type  string      `json:"type"`
+			Value interface{} `json:"value"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return nil, err
+	}
+
+	return deserializeBiDiValue(resp.Result.Type, resp.Result.Value), nil
+}
+
+// EvaluateAs evaluates a script like Pilot.Evaluate and decodes the result
+// into T via JSON, so callers avoid type assertions:
+//
+//	hrefs, err := w3pilot.EvaluateAs[[]string](ctx, pilot,
+//		"Array.from(document.links, a => a.href)")
+//
+// A null or undefined result yields the zero value of T.
+func EvaluateAs[T any](ctx context.Context, p *Pilot, script string) (T, error) {
+	result, err := p.Evaluate(ctx, script)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return decodeAs[T](result)
+}
+
+// decodeAs converts a deserialized evaluation result into T via JSON.
+// A nil result yields the zero value of T.
+func decodeAs[T any](result interface{}) (T, error) {
+	var out T
+	if result == nil {
+		return out, nil
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return out, fmt.Errorf("failed to encode result: %w", err)
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return out, fmt.Errorf("failed to decode result as %T: %w", out, err)
+	}
+	return out, nil
+}
+
 // Title returns the page title.
 func (p *Pilot) Title(ctx context.Context) (string, error) {
 	result, err := p.Evaluate(ctx, "return document.title")
@@ -753,45 +1178,18 @@ func (p *Pilot) URL(ctx context.Context) (string, error) {
 	return "", nil
 }
 
-// WaitForNavigation waits for a navigation to complete.
-func (p *Pilot) WaitForNavigation(ctx context.Context, timeout time.Duration) error {
-	if timeout == 0 {
-		timeout = DefaultTimeout
-	}
-
-	ctx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
-
-	// Simple implementation: wait for document ready state
-	ticker := time.NewTicker(100 * time.Millisecond)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return &TimeoutError{
-				Selector: "navigation",
-				Timeout:  timeout.Milliseconds(),
-				Reason:   "navigation did not complete",
-			}
-		case <-ticker.C:
-			result, err := p.Evaluate(ctx, "return document.readyState")
-			if err != nil {
-				continue
-			}
-			if result == "complete" {
-				return nil
-			}
-		}
-	}
-}
-
-// Quit closes the browser and cleans up resources.
+// Quit closes the browser and cleans up resources. Pages and contexts
+// created with NewPage and NewContext are closed first.
 func (p *Pilot) Quit(ctx context.Context) error {
-	if p.closed {
+	if !p.closed.CompareAndSwap(false, true) {
 		return nil
 	}
-	p.closed = true
+
+	// Close spawned pages and contexts while the connection is still up.
+	// The browser may be unresponsive, so don't let this block shutdown.
+	closeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	_ = p.closeSpawned(closeCtx)
+	cancel()
 
 	// Close the CDP client connection
 	if p.cdpClient != nil {
@@ -818,7 +1216,7 @@ func (p *Pilot) Quit(ctx context.Context) error {
 
 // IsClosed returns whether the browser has been closed.
 func (p *Pilot) IsClosed() bool {
-	return p.closed
+	return p.closed.Load()
 }
 
 // Clicker returns the clicker process, or nil if using pipe mode.
@@ -828,6 +1226,8 @@ func (p *Pilot) Clicker() *ClickerProcess {
 
 // BrowsingContext returns the browsing context ID for this page.
 func (p *Pilot) BrowsingContext() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	return p.browsingContext
 }
 
@@ -904,6 +1304,41 @@ func (p *Pilot) ClearCPUEmulation(ctx context.Context) error {
 	return p.cdpClient.ClearCPUThrottling(ctx)
 }
 
+// SetTimezone overrides the page timezone (IANA ID, e.g. "America/New_York").
+// This affects Date and Intl.DateTimeFormat. An empty timezone restores the host timezone.
+// Requires CDP connection. Returns error if CDP is not available.
+func (p *Pilot) SetTimezone(ctx context.Context, timezone string) error {
+	if !p.HasCDP() {
+		return fmt.Errorf("CDP not available")
+	}
+	return p.cdpClient.SetTimezoneOverride(ctx, timezone)
+}
+
+// SetLocale overrides the page locale (e.g. "de-DE").
+// This affects Intl formatting, navigator.language, and the Accept-Language
+// request header. An empty locale restores the host locale.
+// Requires CDP connection. Returns error if CDP is not available.
+func (p *Pilot) SetLocale(ctx context.Context, locale string) error {
+	if !p.HasCDP() {
+		return fmt.Errorf("CDP not available")
+	}
+	if err := p.cdpClient.SetLocaleOverride(ctx, locale); err != nil {
+		return err
+	}
+
+	// Accept-Language can only be overridden together with the user agent,
+	// so keep the current one. An empty user agent clears both.
+	var userAgent string
+	if locale != "" {
+		result, err := p.Evaluate(ctx, "navigator.userAgent")
+		if err != nil {
+			return fmt.Errorf("failed to read user agent: %w", err)
+		}
+		userAgent, _ = result.(string)
+	}
+	return p.cdpClient.SetUserAgentOverride(ctx, userAgent, locale)
+}
+
 // ScreencastFrameHandler is called for each captured screencast frame.
 type ScreencastFrameHandler func(frame *cdp.ScreencastFrame)
 
@@ -914,10 +1349,7 @@ func (p *Pilot) StartScreencast(ctx context.Context, opts *cdp.ScreencastOptions
 	if !p.HasCDP() {
 		return fmt.Errorf("CDP not available")
 	}
-	if p.screencast == nil {
-		p.screencast = cdp.NewScreencast(p.cdpClient)
-	}
-	return p.screencast.Start(ctx, opts, func(frame *cdp.ScreencastFrame) {
+	return p.screencastManager(true).Start(ctx, opts, func(frame *cdp.ScreencastFrame) {
 		if handler != nil {
 			handler(frame)
 		}
@@ -930,18 +1362,20 @@ func (p *Pilot) StopScreencast(ctx context.Context) error {
 	if !p.HasCDP() {
 		return fmt.Errorf("CDP not available")
 	}
-	if p.screencast == nil {
+	screencast := p.screencastManager(false)
+	if screencast == nil {
 		return nil
 	}
-	return p.screencast.Stop(ctx)
+	return screencast.Stop(ctx)
 }
 
 // IsScreencasting returns whether screencast is active.
 func (p *Pilot) IsScreencasting() bool {
-	if p.screencast == nil {
+	screencast := p.screencastManager(false)
+	if screencast == nil {
 		return false
 	}
-	return p.screencast.IsRunning()
+	return screencast.IsRunning()
 }
 
 // ExtensionInfo contains information about a browser extension.
@@ -987,10 +1421,7 @@ func (p *Pilot) StartCoverage(ctx context.Context) error {
 	if !p.HasCDP() {
 		return fmt.Errorf("CDP not available")
 	}
-	if p.coverage == nil {
-		p.coverage = cdp.NewCoverage(p.cdpClient)
-	}
-	return p.coverage.Start(ctx)
+	return p.coverageManager(true).Start(ctx)
 }
 
 // StartJSCoverage begins collecting JavaScript coverage data.
@@ -1001,10 +1432,7 @@ func (p *Pilot) StartJSCoverage(ctx context.Context, callCount, detailed bool) e
 	if !p.HasCDP() {
 		return fmt.Errorf("CDP not available")
 	}
-	if p.coverage == nil {
-		p.coverage = cdp.NewCoverage(p.cdpClient)
-	}
-	return p.coverage.StartJS(ctx, callCount, detailed)
+	return p.coverageManager(true).StartJS(ctx, callCount, detailed)
 }
 
 // StartCSSCoverage begins collecting CSS coverage data.
@@ -1013,10 +1441,7 @@ func (p *Pilot) StartCSSCoverage(ctx context.Context) error {
 	if !p.HasCDP() {
 		return fmt.Errorf("CDP not available")
 	}
-	if p.coverage == nil {
-		p.coverage = cdp.NewCoverage(p.cdpClient)
-	}
-	return p.coverage.StartCSS(ctx)
+	return p.coverageManager(true).StartCSS(ctx)
 }
 
 // StopCoverage stops coverage collection and returns the results.
@@ -1025,18 +1450,20 @@ func (p *Pilot) StopCoverage(ctx context.Context) (*CoverageReport, error) {
 	if !p.HasCDP() {
 		return nil, fmt.Errorf("CDP not available")
 	}
-	if p.coverage == nil {
+	coverage := p.coverageManager(false)
+	if coverage == nil {
 		return nil, fmt.Errorf("coverage not started")
 	}
-	return p.coverage.Stop(ctx)
+	return coverage.Stop(ctx)
 }
 
 // IsCoverageRunning returns whether coverage collection is active.
 func (p *Pilot) IsCoverageRunning() bool {
-	if p.coverage == nil {
+	coverage := p.coverageManager(false)
+	if coverage == nil {
 		return false
 	}
-	return p.coverage.IsRunning()
+	return coverage.IsRunning()
 }
 
 // ConsoleEntry is an alias for cdp.ConsoleEntry.
@@ -1055,10 +1482,7 @@ func (p *Pilot) EnableConsoleDebugger(ctx context.Context) error {
 	if !p.HasCDP() {
 		return fmt.Errorf("CDP not available")
 	}
-	if p.consoleDebugger == nil {
-		p.consoleDebugger = cdp.NewConsoleDebugger(p.cdpClient)
-	}
-	return p.consoleDebugger.Enable(ctx)
+	return p.consoleDebuggerManager(true).Enable(ctx)
 }
 
 // DisableConsoleDebugger stops capturing console messages.
@@ -1067,117 +1491,152 @@ func (p *Pilot) DisableConsoleDebugger(ctx context.Context) error {
 	if !p.HasCDP() {
 		return fmt.Errorf("CDP not available")
 	}
-	if p.consoleDebugger == nil {
+	consoleDebugger := p.consoleDebuggerManager(false)
+	if consoleDebugger == nil {
 		return nil
 	}
-	return p.consoleDebugger.Disable(ctx)
+	return consoleDebugger.Disable(ctx)
 }
 
 // ConsoleEntries returns all captured console entries with stack traces.
 // Call EnableConsoleDebugger first to start capturing.
 func (p *Pilot) ConsoleEntries() []ConsoleEntry {
-	if p.consoleDebugger == nil {
+	consoleDebugger := p.consoleDebuggerManager(false)
+	if consoleDebugger == nil {
 		return nil
 	}
-	return p.consoleDebugger.Entries()
+	return consoleDebugger.Entries()
 }
 
 // ConsoleExceptions returns all captured JavaScript exceptions.
 // Call EnableConsoleDebugger first to start capturing.
 func (p *Pilot) ConsoleExceptions() []ExceptionDetails {
-	if p.consoleDebugger == nil {
+	consoleDebugger := p.consoleDebuggerManager(false)
+	if consoleDebugger == nil {
 		return nil
 	}
-	return p.consoleDebugger.Errors()
+	return consoleDebugger.Errors()
 }
 
 // BrowserLogs returns all captured browser log entries (deprecations, interventions).
 // Call EnableConsoleDebugger first to start capturing.
 func (p *Pilot) BrowserLogs() []LogEntry {
-	if p.consoleDebugger == nil {
+	consoleDebugger := p.consoleDebuggerManager(false)
+	if consoleDebugger == nil {
 		return nil
 	}
-	return p.consoleDebugger.Logs()
+	return consoleDebugger.Logs()
 }
 
 // ClearConsoleDebugger clears all captured console entries, exceptions, and logs.
 func (p *Pilot) ClearConsoleDebugger() {
-	if p.consoleDebugger != nil {
-		p.consoleDebugger.Clear()
+	if consoleDebugger := p.consoleDebuggerManager(false); consoleDebugger != nil {
+		consoleDebugger.Clear()
 	}
 }
 
 // IsConsoleDebuggerEnabled returns whether the console debugger is active.
 func (p *Pilot) IsConsoleDebuggerEnabled() bool {
-	if p.consoleDebugger == nil {
+	consoleDebugger := p.consoleDebuggerManager(false)
+	if consoleDebugger == nil {
 		return false
 	}
-	return p.consoleDebugger.IsEnabled()
+	return consoleDebugger.IsEnabled()
 }
 
-// Keyboard returns the keyboard controller for this page.
-func (p *Pilot) Keyboard(ctx context.Context) (*Keyboard, error) {
-	if p.keyboard != nil {
-		return p.keyboard, nil
+// screencastManager returns the lazily created screencast, creating it first if create is set.
+func (p *Pilot) screencastManager(create bool) *cdp.Screencast {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.screencast == nil && create {
+		p.screencast = cdp.NewScreencast(p.cdpClient)
+	}
+	return p.screencast
+}
+
+// coverageManager returns the lazily created coverage, creating it first if create is set.
+func (p *Pilot) coverageManager(create bool) *cdp.Coverage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.coverage == nil && create {
+		p.coverage = cdp.NewCoverage(p.cdpClient)
 	}
+	return p.coverage
+}
+
+// consoleDebuggerManager returns the lazily created consoleDebugger, creating it first if create is set.
+func (p *Pilot) consoleDebuggerManager(create bool) *cdp.ConsoleDebugger {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.consoleDebugger == nil && create {
+		p.consoleDebugger = cdp.NewConsoleDebugger(p.cdpClient)
+	}
+	return p.consoleDebugger
+}
 
+// Keyboard returns the keyboard controller for this page.
+func (p *Pilot) Keyboard(ctx context.Context) (*Keyboard, error) {
 	browsingCtx, err := p.getContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	p.keyboard = NewKeyboard(p.client, browsingCtx)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.keyboard == nil {
+		p.keyboard = NewKeyboard(p.client, browsingCtx)
+	}
 	return p.keyboard, nil
 }
 
 // Mouse returns the mouse controller for this page.
 func (p *Pilot) Mouse(ctx context.Context) (*Mouse, error) {
-	if p.mouse != nil {
-		return p.mouse, nil
-	}
-
 	browsingCtx, err := p.getContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	p.mouse = NewMouse(p.client, browsingCtx)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.mouse == nil {
+		p.mouse = NewMouse(p.client, browsingCtx)
+	}
 	return p.mouse, nil
 }
 
 // Touch returns the touch controller for this page.
 func (p *Pilot) Touch(ctx context.Context) (*Touch, error) {
-	if p.touch != nil {
-		return p.touch, nil
-	}
-
 	browsingCtx, err := p.getContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	p.touch = NewTouch(p.client, browsingCtx)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.touch == nil {
+		p.touch = NewTouch(p.client, browsingCtx)
+	}
 	return p.touch, nil
 }
 
 // Clock returns the clock controller for this page.
 func (p *Pilot) Clock(ctx context.Context) (*Clock, error) {
-	if p.clock != nil {
-		return p.clock, nil
-	}
-
 	browsingCtx, err := p.getContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	p.clock = NewClock(p.client, browsingCtx)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.clock == nil {
+		p.clock = NewClock(p.client, browsingCtx)
+	}
 	return p.clock, nil
 }
 
 // Content returns the full HTML content of the page.
 func (p *Pilot) Content(ctx context.Context) (string, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return "", ErrConnectionClosed
 	}
 
@@ -1207,7 +1666,7 @@ func (p *Pilot) Content(ctx context.Context) (string, error) {
 
 // SetContent sets the HTML content of the page.
 func (p *Pilot) SetContent(ctx context.Context, html string) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -1227,7 +1686,7 @@ func (p *Pilot) SetContent(ctx context.Context, html string) error {
 
 // GetViewport returns the current viewport dimensions.
 func (p *Pilot) GetViewport(ctx context.Context) (Viewport, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return Viewport{}, ErrConnectionClosed
 	}
 
@@ -1255,7 +1714,7 @@ func (p *Pilot) GetViewport(ctx context.Context) (Viewport, error) {
 
 // SetViewport sets the viewport dimensions.
 func (p *Pilot) SetViewport(ctx context.Context, viewport Viewport) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -1276,7 +1735,7 @@ func (p *Pilot) SetViewport(ctx context.Context, viewport Viewport) error {
 
 // GetWindow returns the browser window state.
 func (p *Pilot) GetWindow(ctx context.Context) (WindowState, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return WindowState{}, ErrConnectionClosed
 	}
 
@@ -1304,7 +1763,7 @@ func (p *Pilot) GetWindow(ctx context.Context) (WindowState, error) {
 
 // SetWindow sets the browser window state.
 func (p *Pilot) SetWindow(ctx context.Context, opts SetWindowOptions) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -1338,14 +1797,33 @@ func (p *Pilot) SetWindow(ctx context.Context, opts SetWindowOptions) error {
 }
 
 // PDF generates a PDF of the page and returns the bytes.
+// Use PDFTo to write large documents without buffering the decoded PDF.
 func (p *Pilot) PDF(ctx context.Context, opts *PDFOptions) ([]byte, error) {
-	if p.closed {
-		return nil, ErrConnectionClosed
+	var buf bytes.Buffer
+	if err := p.PDFTo(ctx, &buf, opts); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// PDFTo generates a PDF of the page and streams the decoded bytes to w.
+func (p *Pilot) PDFTo(ctx context.Context, w io.Writer, opts *PDFOptions) error {
+	if p.closed.Load() {
+		return ErrConnectionClosed
+	}
+
+	if opts != nil {
+		if opts.HeaderTemplate != "" && !opts.DisplayHeader {
+			return fmt.Errorf("PDFOptions.HeaderTemplate requires DisplayHeader")
+		}
+		if opts.FooterTemplate != "" && !opts.DisplayFooter {
+			return fmt.Errorf("PDFOptions.FooterTemplate requires DisplayFooter")
+		}
 	}
 
 	browsingCtx, err := p.getContext(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	params := map[string]interface{}{
@@ -1362,6 +1840,12 @@ func (p *Pilot) PDF(ctx context.Context, opts *PDFOptions) ([]byte, error) {
 		if opts.DisplayFooter {
 			params["displayFooter"] = opts.DisplayFooter
 		}
+		if opts.HeaderTemplate != "" {
+			params["headerTemplate"] = opts.HeaderTemplate
+		}
+		if opts.FooterTemplate != "" {
+			params["footerTemplate"] = opts.FooterTemplate
+		}
 		if opts.PrintBackground {
 			params["printBackground"] = opts.PrintBackground
 		}
@@ -1392,22 +1876,26 @@ func (p *Pilot) PDF(ctx context.Context, opts *PDFOptions) ([]byte, error) {
 
 	result, err := p.client.Send(ctx, "vibium:page.pdf", params)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	var resp struct {
 		Data string `json:"data"`
 	}
 	if err := json.Unmarshal(result, &resp); err != nil {
-		return nil, err
+		return err
 	}
 
-	return base64.StdEncoding.DecodeString(resp.Data)
+	dec := base64.NewDecoder(base64.StdEncoding, strings.NewReader(resp.Data))
+	if _, err := io.Copy(w, dec); err != nil {
+		return fmt.Errorf("failed to write PDF: %w", err)
+	}
+	return nil
 }
 
 // BringToFront activates the page (brings the browser tab to front).
 func (p *Pilot) BringToFront(ctx context.Context) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -1424,9 +1912,10 @@ func (p *Pilot) BringToFront(ctx context.Context) error {
 	return err
 }
 
-// Close closes the current page but not the browser.
+// Close closes the current page but not the browser. Pages and contexts
+// created from it stay open; use CloseAll to close them too.
 func (p *Pilot) Close(ctx context.Context) error {
-	if p.closed {
+	if p.closed.Load() || p.pageClosed.Load() {
 		return nil
 	}
 
@@ -1439,13 +1928,61 @@ func (p *Pilot) Close(ctx context.Context) error {
 		"context": browsingCtx,
 	}
 
-	_, err = p.client.Send(ctx, "browsingContext.close", params)
-	return err
+	if _, err := p.client.Send(ctx, "browsingContext.close", params); err != nil {
+		return err
+	}
+	p.pageClosed.Store(true)
+	return nil
+}
+
+// CloseAll closes every page and browser context created from this page
+// with NewPage and NewContext, including those created from them in turn,
+// and then the page itself. It keeps going after a failure and returns
+// all errors joined.
+func (p *Pilot) CloseAll(ctx context.Context) error {
+	if p.closed.Load() {
+		return nil
+	}
+	err := p.closeSpawned(ctx)
+	return errors.Join(err, p.Close(ctx))
+}
+
+// closedLeaf reports whether the page is closed and tracks no open pages or
+// contexts, so its parent can stop tracking it.
+func (p *Pilot) closedLeaf() bool {
+	if !p.pageClosed.Load() {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.spawnedPages) == 0 && len(p.spawnedContexts) == 0
+}
+
+// closeSpawned closes the pages and contexts created from this page and
+// stops tracking them.
+func (p *Pilot) closeSpawned(ctx context.Context) error {
+	p.mu.Lock()
+	pages, contexts := p.spawnedPages, p.spawnedContexts
+	p.spawnedPages, p.spawnedContexts = nil, nil
+	p.mu.Unlock()
+
+	var errs []error
+	for _, page := range pages {
+		if err := page.CloseAll(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close page %s: %w", page.BrowsingContext(), err))
+		}
+	}
+	for _, c := range contexts {
+		if err := c.Close(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close browser context %s: %w", c.userContext, err))
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // Frames returns all frames on the page.
 func (p *Pilot) Frames(ctx context.Context) ([]FrameInfo, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return nil, ErrConnectionClosed
 	}
 
@@ -1475,43 +2012,150 @@ func (p *Pilot) Frames(ctx context.Context) ([]FrameInfo, error) {
 
 // Frame finds a frame by name or URL pattern.
 func (p *Pilot) Frame(ctx context.Context, nameOrURL string) (*Pilot, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return nil, ErrConnectionClosed
 	}
 
-	browsingCtx, err := p.getContext(ctx)
+	browsingCtx, err := p.getContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{
+		"context":   browsingCtx,
+		"nameOrURL": nameOrURL,
+	}
+
+	result, err := p.client.Send(ctx, "vibium:page.frame", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Context string `json:"context"`
+	}
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return nil, err
+	}
+
+	frame := p.derive(resp.Context)
+	frame.isFrame = true
+	return frame, nil
+}
+
+// contextTreeNode is a node of the browsingContext.getTree result.
+type contextTreeNode struct {
+	Context  string            `json:"context"`
+	Children []contextTreeNode `json:"children"`
+}
+
+// contextTree returns the tree of all top-level browsing contexts and their frames.
+func (p *Pilot) contextTree(ctx context.Context) ([]contextTreeNode, error) {
+	result, err := p.client.Send(ctx, "browsingContext.getTree", map[string]interface{}{})
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to get browsing context tree: %w", err)
 	}
 
-	params := map[string]interface{}{
-		"context":   browsingCtx,
-		"nameOrURL": nameOrURL,
+	var tree struct {
+		Contexts []contextTreeNode `json:"contexts"`
+	}
+	if err := json.Unmarshal(result, &tree); err != nil {
+		return nil, fmt.Errorf("failed to parse browsing context tree: %w", err)
 	}
+	return tree.Contexts, nil
+}
 
-	result, err := p.client.Send(ctx, "vibium:page.frame", params)
+// contextTreePath returns the nodes from the top-level context down to target.
+func (p *Pilot) contextTreePath(ctx context.Context, target string) ([]contextTreeNode, error) {
+	tree, err := p.contextTree(ctx)
 	if err != nil {
 		return nil, err
 	}
+	path := contextPath(tree, target)
+	if path == nil {
+		return nil, fmt.Errorf("frame %s not found in browsing context tree", target)
+	}
+	return path, nil
+}
 
-	var resp struct {
-		Context string `json:"context"`
+// contextPath returns the nodes from a top-level context down to target, or nil if not found.
+func contextPath(nodes []contextTreeNode, target string) []contextTreeNode {
+	for _, node := range nodes {
+		if node.Context == target {
+			return []contextTreeNode{node}
+		}
+		if path := contextPath(node.Children, target); path != nil {
+			return append([]contextTreeNode{node}, path...)
+		}
 	}
-	if err := json.Unmarshal(result, &resp); err != nil {
-		return nil, err
+	return nil
+}
+
+// frameRectScript returns the content box of the parent's child frame at
+// index, relative to the parent's viewport. Frame windows are compared by
+// identity, which also works for cross-origin frames.
+const frameRectScript = `(index) => {
+	const win = window.frames[index];
+	const frame = Array.from(document.querySelectorAll('iframe, frame')).find(f => f.contentWindow === win);
+	if (!frame) return null;
+	const r = frame.getBoundingClientRect();
+	return {x: r.left + frame.clientLeft, y: r.top + frame.clientTop, width: frame.clientWidth, height: frame.clientHeight};
+}`
+
+// frameClip returns the top-level context containing frameCtx and the frame's
+// visible content area within that context's viewport.
+func (p *Pilot) frameClip(ctx context.Context, frameCtx string) (string, BoundingBox, error) {
+	path, err := p.contextTreePath(ctx, frameCtx)
+	if err != nil {
+		return "", BoundingBox{}, err
+	}
+
+	var clip BoundingBox
+	var originX, originY float64
+	for i := 1; i < len(path); i++ {
+		parent := path[i-1]
+		index := -1
+		for j, child := range parent.Children {
+			if child.Context == path[i].Context {
+				index = j
+				break
+			}
+		}
+
+		parentPage := &Pilot{client: p.client, browsingContext: parent.Context}
+		value, err := parentPage.EvaluateWithArgs(ctx, frameRectScript, index)
+		if err != nil {
+			return "", BoundingBox{}, fmt.Errorf("failed to locate frame: %w", err)
+		}
+		rect, err := decodeAs[*BoundingBox](value)
+		if err != nil {
+			return "", BoundingBox{}, fmt.Errorf("failed to locate frame: %w", err)
+		}
+		if rect == nil {
+			return "", BoundingBox{}, fmt.Errorf("frame element for %s not found", path[i].Context)
+		}
+
+		// Nested frames are offset by, and clipped to, their parent frame.
+		originX += rect.X
+		originY += rect.Y
+		box := BoundingBox{X: originX, Y: originY, Width: rect.Width, Height: rect.Height}
+		if i > 1 {
+			box = box.Intersection(clip)
+		}
+		clip = box
 	}
 
-	return &Pilot{
-		client:          p.client,
-		clicker:         p.clicker,
-		browsingContext: resp.Context,
-	}, nil
+	if clip.Area() == 0 {
+		return "", BoundingBox{}, fmt.Errorf("frame %s is not visible", frameCtx)
+	}
+
+	return path[0].Context, clip, nil
 }
 
 // A11yTree returns the accessibility tree for the page.
 // Options can filter the tree to only interesting nodes or specify a root element.
 func (p *Pilot) A11yTree(ctx context.Context, opts *A11yTreeOptions) (interface{}, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return nil, ErrConnectionClosed
 	}
 
@@ -1546,16 +2190,58 @@ func (p *Pilot) A11yTree(ctx context.Context, opts *A11yTreeOptions) (interface{
 	return resp, nil
 }
 
-// MainFrame returns the main frame of the page.
-// Since Pilot represents both page and frame in this SDK, it returns itself.
-// This method exists for API compatibility with other W3Pilot clients.
-func (p *Pilot) MainFrame() *Pilot {
-	return p
+// MainFrame returns a Pilot bound to the top-level page containing this one.
+// For a page that is not a frame it returns p itself.
+func (p *Pilot) MainFrame(ctx context.Context) (*Pilot, error) {
+	if p.closed.Load() {
+		return nil, ErrConnectionClosed
+	}
+	if !p.isFrame {
+		return p, nil
+	}
+
+	browsingCtx, err := p.getContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	path, err := p.contextTreePath(ctx, browsingCtx)
+	if err != nil {
+		return nil, err
+	}
+	return p.frameAt(path, 0), nil
+}
+
+// ParentFrame returns a Pilot bound to the frame or page that contains this
+// frame. It returns an error when called on a top-level page.
+func (p *Pilot) ParentFrame(ctx context.Context) (*Pilot, error) {
+	if p.closed.Load() {
+		return nil, ErrConnectionClosed
+	}
+
+	browsingCtx, err := p.getContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	path, err := p.contextTreePath(ctx, browsingCtx)
+	if err != nil {
+		return nil, err
+	}
+	if len(path) < 2 {
+		return nil, fmt.Errorf("page has no parent frame")
+	}
+	return p.frameAt(path, len(path)-2), nil
+}
+
+// frameAt returns a Pilot for path[i], marked as a frame unless it is the top-level context.
+func (p *Pilot) frameAt(path []contextTreeNode, i int) *Pilot {
+	frame := p.derive(path[i].Context)
+	frame.isFrame = i > 0
+	return frame
 }
 
 // EmulateMedia sets the media emulation options.
 func (p *Pilot) EmulateMedia(ctx context.Context, opts EmulateMediaOptions) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -1590,7 +2276,7 @@ func (p *Pilot) EmulateMedia(ctx context.Context, opts EmulateMediaOptions) erro
 
 // SetGeolocation overrides the browser's geolocation.
 func (p *Pilot) SetGeolocation(ctx context.Context, coords Geolocation) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -1613,9 +2299,62 @@ func (p *Pilot) SetGeolocation(ctx context.Context, coords Geolocation) error {
 	return err
 }
 
+// GrantPermissions grants the named permissions (e.g. "geolocation", "notifications",
+// "clipboard-read") for the given origin, so no permission prompt blocks the page.
+// Grants apply to the origin and survive navigations within it.
+func (p *Pilot) GrantPermissions(ctx context.Context, permissions []string, origin string) error {
+	if p.closed.Load() {
+		return ErrConnectionClosed
+	}
+	if origin == "" {
+		return fmt.Errorf("origin is required")
+	}
+
+	for _, name := range permissions {
+		if err := p.setPermission(ctx, name, origin, "granted"); err != nil {
+			return fmt.Errorf("failed to grant %s permission: %w", name, err)
+		}
+		p.mu.Lock()
+		p.grantedPermissions = append(p.grantedPermissions, grantedPermission{name: name, origin: origin})
+		p.mu.Unlock()
+	}
+	return nil
+}
+
+// ClearPermissions resets all permissions granted via GrantPermissions back to "prompt".
+func (p *Pilot) ClearPermissions(ctx context.Context) error {
+	if p.closed.Load() {
+		return ErrConnectionClosed
+	}
+
+	p.mu.Lock()
+	granted := p.grantedPermissions
+	p.grantedPermissions = nil
+	p.mu.Unlock()
+
+	for _, perm := range granted {
+		if err := p.setPermission(ctx, perm.name, perm.origin, "prompt"); err != nil {
+			return fmt.Errorf("failed to clear %s permission: %w", perm.name, err)
+		}
+	}
+	return nil
+}
+
+// setPermission sends permissions.setPermission for a single permission.
+func (p *Pilot) setPermission(ctx context.Context, name, origin, state string) error {
+	_, err := p.client.Send(ctx, "permissions.setPermission", map[string]interface{}{
+		"descriptor": map[string]interface{}{
+			"name": name,
+		},
+		"state":  state,
+		"origin": origin,
+	})
+	return err
+}
+
 // AddScript adds a script that will be evaluated in the page context.
 func (p *Pilot) AddScript(ctx context.Context, source string) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -1635,7 +2374,7 @@ func (p *Pilot) AddScript(ctx context.Context, source string) error {
 
 // AddStyle adds a stylesheet to the page.
 func (p *Pilot) AddStyle(ctx context.Context, source string) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -1656,7 +2395,7 @@ func (p *Pilot) AddStyle(ctx context.Context, source string) error {
 // Expose exposes a function that can be called from JavaScript in the page.
 // Note: The handler function must be registered separately.
 func (p *Pilot) Expose(ctx context.Context, name string) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -1676,15 +2415,18 @@ func (p *Pilot) Expose(ctx context.Context, name string) error {
 
 // WaitForURL waits for the page URL to match the specified pattern.
 func (p *Pilot) WaitForURL(ctx context.Context, pattern string, timeout time.Duration) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
 	if timeout == 0 {
-		timeout = DefaultTimeout
+		timeout = p.DefaultTimeout()
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
 	browsingCtx, err := p.getContext(ctx)
@@ -1705,15 +2447,18 @@ func (p *Pilot) WaitForURL(ctx context.Context, pattern string, timeout time.Dur
 // WaitForLoad waits for the page to reach the specified load state.
 // State can be: "load", "domcontentloaded", "networkidle".
 func (p *Pilot) WaitForLoad(ctx context.Context, state string, timeout time.Duration) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
 	if timeout == 0 {
-		timeout = DefaultTimeout
+		timeout = p.DefaultTimeout()
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
 	browsingCtx, err := p.getContext(ctx)
@@ -1733,15 +2478,18 @@ func (p *Pilot) WaitForLoad(ctx context.Context, state string, timeout time.Dura
 
 // WaitForFunction waits for a JavaScript function to return a truthy value.
 func (p *Pilot) WaitForFunction(ctx context.Context, fn string, timeout time.Duration) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
 	if timeout == 0 {
-		timeout = DefaultTimeout
+		timeout = p.DefaultTimeout()
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
 	browsingCtx, err := p.getContext(ctx)
@@ -1759,13 +2507,113 @@ func (p *Pilot) WaitForFunction(ctx context.Context, fn string, timeout time.Dur
 	return err
 }
 
+// WaitForSelector waits for the element matching selector to reach state:
+// "attached" (present in the DOM), "visible" (the default when state is
+// empty), "hidden" (absent or not rendered), or "detached" (absent).
+// For attached and visible it returns the element; for hidden and detached
+// it returns nil, since the element may no longer exist.
+func (p *Pilot) WaitForSelector(ctx context.Context, selector, state string, timeout time.Duration) (*Element, error) {
+	if p.closed.Load() {
+		return nil, ErrConnectionClosed
+	}
+
+	if state == "" {
+		state = "visible"
+	}
+	if timeout == 0 {
+		timeout = p.DefaultTimeout()
+	}
+
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	switch state {
+	case "attached", "visible":
+		start := time.Now()
+		elem, err := p.Find(ctx, selector, &FindOptions{Timeout: timeout})
+		if err != nil {
+			return nil, err
+		}
+		if state == "visible" {
+			// Share one deadline between finding and becoming visible
+			if err := elem.WaitUntil(ctx, "visible", timeout-time.Since(start)); err != nil {
+				return nil, err
+			}
+		}
+		return elem, nil
+
+	case "hidden", "detached":
+		// Let the server decide visibility, so hidden is the exact
+		// opposite of visible
+		browsingCtx, err := p.getContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return nil, p.newElement(browsingCtx, selector, ElementInfo{}).WaitUntil(ctx, state, timeout)
+
+	default:
+		return nil, fmt.Errorf("invalid state %q: must be attached, visible, hidden, or detached", state)
+	}
+}
+
+// WaitForText waits for text to appear anywhere in the page body.
+// It is more robust than guessing the selector of an ephemeral toast or
+// status message.
+func (p *Pilot) WaitForText(ctx context.Context, text string, timeout time.Duration) error {
+	return p.WaitForTextWithOptions(ctx, text, &WaitForTextOptions{Timeout: timeout})
+}
+
+// WaitForTextWithOptions waits for text to appear in the page body, or in
+// the element matching opts.Selector, using the configured match mode.
+// Only rendered text counts; text in hidden elements does not match.
+func (p *Pilot) WaitForTextWithOptions(ctx context.Context, text string, opts *WaitForTextOptions) error {
+	if opts == nil {
+		opts = &WaitForTextOptions{}
+	}
+	match := opts.TextMatch
+	if match == "" {
+		match = "contains"
+	}
+	if match != "contains" && match != "exact" && match != "regex" {
+		return fmt.Errorf("invalid WaitForTextOptions.TextMatch %q: must be contains, exact, or regex", opts.TextMatch)
+	}
+	debugLog(ctx, "waiting for text", "text", text, "selector", opts.Selector, "match", match)
+
+	fn := fmt.Sprintf(`() => {
+		const selector = %q, text = %q, match = %q;
+		const root = selector ? document.querySelector(selector) : document.body;
+		if (!root) return false;
+		const norm = s => (s || '').replace(/\s+/g, ' ').trim();
+		if (match === 'exact') {
+			return [root, ...root.querySelectorAll('*')].some(el =>
+				el.getClientRects().length > 0 && norm(el.innerText) === text);
+		}
+		const content = root.innerText || '';
+		if (match === 'regex') return new RegExp(text).test(content);
+		return content.includes(text);
+	}`, opts.Selector, text, match)
+
+	if err := p.WaitForFunction(ctx, fn, opts.Timeout); err != nil {
+		return fmt.Errorf("wait for text %q: %w", text, err)
+	}
+	return nil
+}
+
 // RouteHandler is called when a request matches a route pattern.
 type RouteHandler func(ctx context.Context, route *Route) error
 
 // Route registers a handler for requests matching the URL pattern.
 // The pattern can be a glob pattern (e.g., "**/*.png") or regex (e.g., "/api/.*").
+//
+// Matching requests are paused with BiDi network interception and passed to
+// handler, which settles them with Route.Fulfill, Route.Continue, or
+// Route.Abort. If several routes match, the most recently registered wins.
+// The handler's context expires after the page's default timeout.
 func (p *Pilot) Route(ctx context.Context, pattern string, handler RouteHandler) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -1774,18 +2622,22 @@ func (p *Pilot) Route(ctx context.Context, pattern string, handler RouteHandler)
 		return err
 	}
 
-	params := map[string]interface{}{
-		"context": browsingCtx,
-		"pattern": pattern,
+	r, err := p.client.router(ctx)
+	if err != nil {
+		return err
 	}
 
-	_, err = p.client.Send(ctx, "vibium:network.route", params)
-	return err
+	return r.add(ctx, &routeEntry{
+		context: browsingCtx,
+		pattern: pattern,
+		handler: handler,
+		timeout: p.DefaultTimeout(),
+	})
 }
 
-// Unroute removes a previously registered route handler.
+// Unroute removes a previously registered route handler or mock route.
 func (p *Pilot) Unroute(ctx context.Context, pattern string) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -1794,6 +2646,15 @@ func (p *Pilot) Unroute(ctx context.Context, pattern string) error {
 		return err
 	}
 
+	p.client.routerMu.Lock()
+	r := p.client.routes
+	p.client.routerMu.Unlock()
+	if r != nil {
+		if removed, err := r.remove(ctx, browsingCtx, pattern); removed || err != nil {
+			return err
+		}
+	}
+
 	params := map[string]interface{}{
 		"context": browsingCtx,
 		"pattern": pattern,
@@ -1814,7 +2675,7 @@ type MockRouteOptions struct {
 // MockRoute registers a route that returns a static mock response.
 // This is useful for MCP tools and testing without callbacks.
 func (p *Pilot) MockRoute(ctx context.Context, pattern string, opts MockRouteOptions) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -1861,7 +2722,7 @@ type RouteInfo struct {
 
 // ListRoutes returns all active route handlers.
 func (p *Pilot) ListRoutes(ctx context.Context) ([]RouteInfo, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return nil, ErrConnectionClosed
 	}
 
@@ -1893,7 +2754,7 @@ func (p *Pilot) ListRoutes(ctx context.Context) ([]RouteInfo, error) {
 // Tries BiDi first, falls back to CDP if BiDi doesn't support this command.
 // For fine-grained network control (latency, bandwidth), use EmulateNetwork() instead.
 func (p *Pilot) SetOffline(ctx context.Context, offline bool) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -1929,7 +2790,7 @@ func (p *Pilot) SetOffline(ctx context.Context, offline bool) error {
 
 // SetExtraHTTPHeaders sets extra HTTP headers that will be sent with every request.
 func (p *Pilot) SetExtraHTTPHeaders(ctx context.Context, headers map[string]string) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -1971,10 +2832,17 @@ type PageHandler func(*Pilot)
 // PopupHandler is called when a popup window opens.
 type PopupHandler func(*Pilot)
 
+// FrameHandler is called when a frame is attached, detached, or navigated.
+type FrameHandler func(*FrameEvent)
+
+// NavigationHandler is called with the target URL when a page starts a
+// navigation.
+type NavigationHandler func(url string)
+
 // OnRequest registers a handler for network requests.
 // Note: This is a convenience method; for full control use Route().
 func (p *Pilot) OnRequest(ctx context.Context, handler RequestHandler) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -2003,7 +2871,7 @@ func (p *Pilot) OnRequest(ctx context.Context, handler RequestHandler) error {
 
 // OnResponse registers a handler for network responses.
 func (p *Pilot) OnResponse(ctx context.Context, handler ResponseHandler) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -2032,7 +2900,7 @@ func (p *Pilot) OnResponse(ctx context.Context, handler ResponseHandler) error {
 
 // OnConsole registers a handler for console messages.
 func (p *Pilot) OnConsole(ctx context.Context, handler ConsoleHandler) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -2061,7 +2929,7 @@ func (p *Pilot) OnConsole(ctx context.Context, handler ConsoleHandler) error {
 
 // OnDialog registers a handler for dialogs (alert, confirm, prompt).
 func (p *Pilot) OnDialog(ctx context.Context, handler DialogHandler) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -2090,7 +2958,7 @@ func (p *Pilot) OnDialog(ctx context.Context, handler DialogHandler) error {
 
 // OnDownload registers a handler for downloads.
 func (p *Pilot) OnDownload(ctx context.Context, handler DownloadHandler) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -2119,7 +2987,7 @@ func (p *Pilot) OnDownload(ctx context.Context, handler DownloadHandler) error {
 
 // OnError registers a handler for JavaScript errors on the page.
 func (p *Pilot) OnError(ctx context.Context, handler PageErrorHandler) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -2149,7 +3017,7 @@ func (p *Pilot) OnError(ctx context.Context, handler PageErrorHandler) error {
 // CollectConsole enables buffered console message collection.
 // Messages can be retrieved with ConsoleMessages() and cleared with ClearConsoleMessages().
 func (p *Pilot) CollectConsole(ctx context.Context) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -2169,7 +3037,7 @@ func (p *Pilot) CollectConsole(ctx context.Context) error {
 // CollectErrors enables buffered page error collection.
 // Errors can be retrieved with Errors() and cleared with ClearErrors().
 func (p *Pilot) CollectErrors(ctx context.Context) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -2189,7 +3057,7 @@ func (p *Pilot) CollectErrors(ctx context.Context) error {
 // Errors retrieves buffered page errors.
 // Call CollectErrors() first to enable error collection.
 func (p *Pilot) Errors(ctx context.Context) ([]PageError, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return nil, ErrConnectionClosed
 	}
 
@@ -2219,7 +3087,7 @@ func (p *Pilot) Errors(ctx context.Context) ([]PageError, error) {
 
 // ClearErrors clears the buffered page errors.
 func (p *Pilot) ClearErrors(ctx context.Context) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -2239,7 +3107,7 @@ func (p *Pilot) ClearErrors(ctx context.Context) error {
 // OnPage registers a handler that is called when a new page is created in the browser.
 // This includes pages created via NewPage(), window.open(), or clicking links with target="_blank".
 func (p *Pilot) OnPage(ctx context.Context, handler PageHandler) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -2256,12 +3124,7 @@ func (p *Pilot) OnPage(ctx context.Context, handler PageHandler) error {
 		}
 
 		// Create a new Pilot instance for the new page
-		newPage := &Pilot{
-			client:          p.client,
-			clicker:         p.clicker,
-			browsingContext: params.Context,
-		}
-		handler(newPage)
+		handler(p.derive(params.Context))
 	})
 
 	// Subscribe to context created events
@@ -2274,7 +3137,7 @@ func (p *Pilot) OnPage(ctx context.Context, handler PageHandler) error {
 // OnPopup registers a handler that is called when a popup window is opened.
 // Popups are typically created via window.open() with specific features.
 func (p *Pilot) OnPopup(ctx context.Context, handler PopupHandler) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -2297,12 +3160,7 @@ func (p *Pilot) OnPopup(ctx context.Context, handler PopupHandler) error {
 		}
 
 		// Create a new Pilot instance for the popup
-		popup := &Pilot{
-			client:          p.client,
-			clicker:         p.clicker,
-			browsingContext: params.Context,
-		}
-		handler(popup)
+		handler(p.derive(params.Context))
 	})
 
 	// Subscribe to context created events
@@ -2312,6 +3170,147 @@ func (p *Pilot) OnPopup(ctx context.Context, handler PopupHandler) error {
 	return err
 }
 
+// OnFrame registers a handler for frames being attached to, detached from, or
+// navigated within this page. FrameNavigated fires once a navigation's load
+// event has fired, including for the top-level page, so it can be used to
+// wait for a specific frame to load.
+func (p *Pilot) OnFrame(ctx context.Context, handler FrameHandler) error {
+	if p.closed.Load() {
+		return ErrConnectionClosed
+	}
+
+	browsingCtx, err := p.getContext(ctx)
+	if err != nil {
+		return err
+	}
+	path, err := p.contextTreePath(ctx, browsingCtx)
+	if err != nil {
+		return err
+	}
+
+	// parents maps each context in this page to its parent, so events from
+	// other pages are ignored and navigations report the frame's parent.
+	var mu sync.Mutex
+	parents := map[string]string{}
+	var walk func(node contextTreeNode, parent string)
+	walk = func(node contextTreeNode, parent string) {
+		parents[node.Context] = parent
+		for _, child := range node.Children {
+			walk(child, node.Context)
+		}
+	}
+	walk(path[0], "")
+
+	emit := func(eventType, context, parent, url string) {
+		event := &FrameEvent{Type: eventType, Context: context, Parent: parent, URL: url}
+		if eventType != FrameDetached {
+			event.Frame = p.derive(context)
+			event.Frame.isFrame = parent != ""
+		}
+		handler(event)
+	}
+
+	p.client.OnEvent("browsingContext.contextCreated", func(event *BiDiEvent) {
+		var params struct {
+			Context string `json:"context"`
+			URL     string `json:"url"`
+			Parent  string `json:"parent,omitempty"`
+		}
+		if err := json.Unmarshal(event.Params, &params); err != nil {
+			debugLog(ctx, "failed to unmarshal frame attached event", "error", err)
+			return
+		}
+		mu.Lock()
+		_, known := parents[params.Parent]
+		attached := params.Parent != "" && known
+		if attached {
+			parents[params.Context] = params.Parent
+		}
+		mu.Unlock()
+		if attached {
+			emit(FrameAttached, params.Context, params.Parent, params.URL)
+		}
+	})
+
+	p.client.OnEvent("browsingContext.contextDestroyed", func(event *BiDiEvent) {
+		var params struct {
+			Context string `json:"context"`
+			URL     string `json:"url"`
+		}
+		if err := json.Unmarshal(event.Params, &params); err != nil {
+			debugLog(ctx, "failed to unmarshal frame detached event", "error", err)
+			return
+		}
+		mu.Lock()
+		parent, known := parents[params.Context]
+		delete(parents, params.Context)
+		mu.Unlock()
+		if known && parent != "" {
+			emit(FrameDetached, params.Context, parent, params.URL)
+		}
+	})
+
+	p.client.OnEvent("browsingContext.load", func(event *BiDiEvent) {
+		var params struct {
+			Context string `json:"context"`
+			URL     string `json:"url"`
+		}
+		if err := json.Unmarshal(event.Params, &params); err != nil {
+			debugLog(ctx, "failed to unmarshal frame navigated event", "error", err)
+			return
+		}
+		mu.Lock()
+		parent, known := parents[params.Context]
+		mu.Unlock()
+		if known {
+			emit(FrameNavigated, params.Context, parent, params.URL)
+		}
+	})
+
+	_, err = p.client.Send(ctx, "session.subscribe", map[string]interface{}{
+		"events": []string{
+			"browsingContext.contextCreated",
+			"browsingContext.contextDestroyed",
+			"browsingContext.load",
+		},
+	})
+	return err
+}
+
+// OnNavigation registers a handler that is called when this page starts a
+// navigation, however it was triggered: Go, a link click, a form submission,
+// a script, or a reload. Fragment (#hash) navigations and navigations
+// inside frames are not reported.
+func (p *Pilot) OnNavigation(ctx context.Context, handler NavigationHandler) error {
+	if p.closed.Load() {
+		return ErrConnectionClosed
+	}
+
+	browsingCtx, err := p.getContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	p.client.OnEvent("browsingContext.navigationStarted", func(event *BiDiEvent) {
+		var params struct {
+			Context string `json:"context"`
+			URL     string `json:"url"`
+		}
+		if err := json.Unmarshal(event.Params, &params); err != nil {
+			debugLog(ctx, "failed to unmarshal navigation started event", "error", err)
+			return
+		}
+		if params.Context == browsingCtx {
+			handler(params.URL)
+		}
+	})
+
+	_, err = p.client.Send(ctx, "session.subscribe", map[string]interface{}{
+		"events": []string{"browsingContext.navigationStarted"},
+	})
+	return err
+}
+
 // RemoveAllListeners removes all registered event listeners.
 // This is useful for cleanup when you no longer need to receive events.
 func (p *Pilot) RemoveAllListeners() {
@@ -2325,7 +3324,7 @@ func (p *Pilot) RemoveAllListeners() {
 
 // NewPage creates a new page in the default browser context.
 func (p *Pilot) NewPage(ctx context.Context) (*Pilot, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return nil, ErrConnectionClosed
 	}
 
@@ -2343,16 +3342,16 @@ func (p *Pilot) NewPage(ctx context.Context) (*Pilot, error) {
 		return nil, err
 	}
 
-	return &Pilot{
-		client:          p.client,
-		clicker:         p.clicker,
-		browsingContext: resp.Context,
-	}, nil
+	page := p.derive(resp.Context)
+	p.mu.Lock()
+	p.spawnedPages = append(slices.DeleteFunc(p.spawnedPages, (*Pilot).closedLeaf), page)
+	p.mu.Unlock()
+	return page, nil
 }
 
 // NewContext creates a new isolated browser context.
 func (p *Pilot) NewContext(ctx context.Context) (*BrowserContext, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return nil, ErrConnectionClosed
 	}
 
@@ -2368,16 +3367,22 @@ func (p *Pilot) NewContext(ctx context.Context) (*BrowserContext, error) {
 		return nil, err
 	}
 
-	return &BrowserContext{
+	bc := &BrowserContext{
 		client:      p.client,
 		clicker:     p.clicker,
 		userContext: resp.UserContext,
-	}, nil
+	}
+	p.mu.Lock()
+	p.spawnedContexts = append(slices.DeleteFunc(p.spawnedContexts, func(c *BrowserContext) bool {
+		return c.closed.Load()
+	}), bc)
+	p.mu.Unlock()
+	return bc, nil
 }
 
 // Pages returns all open pages.
 func (p *Pilot) Pages(ctx context.Context) ([]*Pilot, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return nil, ErrConnectionClosed
 	}
 
@@ -2397,11 +3402,7 @@ func (p *Pilot) Pages(ctx context.Context) ([]*Pilot, error) {
 
 	pages := make([]*Pilot, len(tree.Contexts))
 	for i, c := range tree.Contexts {
-		pages[i] = &Pilot{
-			client:          p.client,
-			clicker:         p.clicker,
-			browsingContext: c.Context,
-		}
+		pages[i] = p.derive(c.Context)
 	}
 
 	return pages, nil
@@ -2418,7 +3419,7 @@ func (p *Pilot) Context() *BrowserContext {
 // If accept is true, the dialog is accepted. If promptText is provided (for prompt dialogs),
 // it will be entered before accepting.
 func (p *Pilot) HandleDialog(ctx context.Context, accept bool, promptText string) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -2445,7 +3446,7 @@ func (p *Pilot) HandleDialog(ctx context.Context, accept bool, promptText string
 
 // GetDialog returns information about the current dialog, if any.
 func (p *Pilot) GetDialog(ctx context.Context) (DialogInfo, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return DialogInfo{}, ErrConnectionClosed
 	}
 
@@ -2489,7 +3490,7 @@ func (p *Pilot) GetDialog(ctx context.Context) (DialogInfo, error) {
 // The level parameter filters messages by type (log, info, warn, error, debug).
 // If level is empty, all messages are returned.
 func (p *Pilot) ConsoleMessages(ctx context.Context, level string) ([]ConsoleMessage, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return nil, ErrConnectionClosed
 	}
 
@@ -2524,7 +3525,7 @@ func (p *Pilot) ConsoleMessages(ctx context.Context, level string) ([]ConsoleMes
 			return nil, fmt.Errorf("ConsoleMessages: BiDi not supported and CDP not available")
 		}
 		// Ensure console debugger is enabled
-		if p.consoleDebugger == nil {
+		if p.consoleDebuggerManager(false) == nil {
 			if err := p.EnableConsoleDebugger(ctx); err != nil {
 				return nil, fmt.Errorf("ConsoleMessages: failed to enable console debugger: %w", err)
 			}
@@ -2551,7 +3552,7 @@ func (p *Pilot) ConsoleMessages(ctx context.Context, level string) ([]ConsoleMes
 // ClearConsoleMessages clears the buffered console messages.
 // Tries BiDi first, falls back to CDP if BiDi doesn't support this command.
 func (p *Pilot) ClearConsoleMessages(ctx context.Context) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -2572,8 +3573,8 @@ func (p *Pilot) ClearConsoleMessages(ctx context.Context) error {
 
 	// If BiDi doesn't support this command, fall back to CDP
 	if IsUnsupportedCommand(err) {
-		if p.consoleDebugger != nil {
-			p.consoleDebugger.Clear()
+		if consoleDebugger := p.consoleDebuggerManager(false); consoleDebugger != nil {
+			consoleDebugger.Clear()
 		}
 		return nil
 	}
@@ -2597,7 +3598,7 @@ type NetworkRequest struct {
 // NetworkRequests returns buffered network requests from the page.
 // Options can filter by URL pattern, method, or resource type.
 func (p *Pilot) NetworkRequests(ctx context.Context, opts *NetworkRequestsOptions) ([]NetworkRequest, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return nil, ErrConnectionClosed
 	}
 
@@ -2646,7 +3647,7 @@ type NetworkRequestsOptions struct {
 
 // ClearNetworkRequests clears the buffered network requests.
 func (p *Pilot) ClearNetworkRequests(ctx context.Context) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -2672,7 +3673,7 @@ type ScrollOptions struct {
 // direction can be "up", "down", "left", or "right".
 // amount is the number of pixels to scroll (use 0 for full page).
 func (p *Pilot) Scroll(ctx context.Context, direction string, amount int, opts *ScrollOptions) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -2695,9 +3696,25 @@ func (p *Pilot) Scroll(ctx context.Context, direction string, amount int, opts *
 	return err
 }
 
+// ScrollTo scrolls the page to an absolute position in CSS pixels.
+// Scrolling is instant regardless of the page's scroll-behavior style.
+func (p *Pilot) ScrollTo(ctx context.Context, x, y float64) error {
+	_, err := p.EvaluateWithArgs(ctx,
+		"(x, y) => window.scrollTo({left: x, top: y, behavior: 'instant'})", x, y)
+	return err
+}
+
+// ScrollBy scrolls the page by a delta in CSS pixels. Positive dy scrolls down.
+// Scrolling is instant regardless of the page's scroll-behavior style.
+func (p *Pilot) ScrollBy(ctx context.Context, dx, dy float64) error {
+	_, err := p.EvaluateWithArgs(ctx,
+		"(dx, dy) => window.scrollBy({left: dx, top: dy, behavior: 'instant'})", dx, dy)
+	return err
+}
+
 // BrowserVersion returns the browser version string.
 func (p *Pilot) BrowserVersion(ctx context.Context) (string, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return "", ErrConnectionClosed
 	}
 
@@ -2733,7 +3750,7 @@ func (p *Pilot) Tracing() *Tracing {
 // AddInitScript adds a script that will be evaluated in every page before any page scripts.
 // This is useful for mocking APIs, injecting test helpers, or setting up authentication.
 func (p *Pilot) AddInitScript(ctx context.Context, script string) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -2780,7 +3797,7 @@ func (p *Pilot) getDefaultUserContext(ctx context.Context) (string, error) {
 // and sessionStorage for the current page's origin. This can be saved and later restored
 // using SetStorageState to resume a session.
 func (p *Pilot) StorageState(ctx context.Context) (*StorageState, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return nil, ErrConnectionClosed
 	}
 
@@ -2802,80 +3819,34 @@ func (p *Pilot) StorageState(ctx context.Context) (*StorageState, error) {
 		return state, nil
 	}
 
-	// If origins is empty (fallback case), collect localStorage via JavaScript
-	if len(state.Origins) == 0 {
-		localStorageScript := `
-			(function() {
-				const items = {};
-				for (let i = 0; i < localStorage.length; i++) {
-					const key = localStorage.key(i);
-					items[key] = localStorage.getItem(key);
-				}
-				return JSON.stringify({
-					origin: window.location.origin,
-					items: items
-				});
-			})()
-		`
-
-		localResult, localErr := p.Evaluate(ctx, localStorageScript)
-		if localErr == nil {
-			if resultStr, ok := localResult.(string); ok {
-				var localData struct {
-					Origin string            `json:"origin"`
-					Items  map[string]string `json:"items"`
-				}
-				if json.Unmarshal([]byte(resultStr), &localData) == nil && len(localData.Items) > 0 {
-					state.Origins = append(state.Origins, StorageStateOrigin{
-						Origin:       localData.Origin,
-						LocalStorage: localData.Items,
-					})
-				}
-			}
-		}
-	}
-
-	// Get sessionStorage via JavaScript
-	sessionStorageScript := `
-		(function() {
-			const items = {};
-			for (let i = 0; i < sessionStorage.length; i++) {
-				const key = sessionStorage.key(i);
-				items[key] = sessionStorage.getItem(key);
-			}
-			return JSON.stringify({
-				origin: window.location.origin,
-				items: items
-			});
-		})()
-	`
-
-	result, err := p.Evaluate(ctx, sessionStorageScript)
+	originResult, err := p.Evaluate(ctx, "window.location.origin")
 	if err != nil {
-		// sessionStorage not available (e.g., file:// protocol), return without it
 		return state, nil
 	}
+	origin, _ := originResult.(string)
 
-	// Parse the sessionStorage result
-	resultStr, ok := result.(string)
-	if !ok {
-		return state, nil
+	// If origins is empty (fallback case), collect localStorage from the page
+	if len(state.Origins) == 0 {
+		if items, err := p.LocalStorage(ctx); err == nil && len(items) > 0 {
+			state.Origins = append(state.Origins, StorageStateOrigin{
+				Origin:       origin,
+				LocalStorage: items,
+			})
+		}
 	}
 
-	var sessionData struct {
-		Origin string            `json:"origin"`
-		Items  map[string]string `json:"items"`
-	}
-	if err := json.Unmarshal([]byte(resultStr), &sessionData); err != nil {
+	sessionItems, err := p.SessionStorage(ctx)
+	if err != nil {
+		// sessionStorage not available (e.g., file:// protocol), return without it
 		return state, nil
 	}
 
 	// Merge sessionStorage into the appropriate origin
-	if len(sessionData.Items) > 0 {
+	if len(sessionItems) > 0 {
 		found := false
 		for i := range state.Origins {
-			if state.Origins[i].Origin == sessionData.Origin {
-				state.Origins[i].SessionStorage = sessionData.Items
+			if state.Origins[i].Origin == origin {
+				state.Origins[i].SessionStorage = sessionItems
 				found = true
 				break
 			}
@@ -2883,9 +3854,9 @@ func (p *Pilot) StorageState(ctx context.Context) (*StorageState, error) {
 		if !found {
 			// Add new origin entry for sessionStorage
 			state.Origins = append(state.Origins, StorageStateOrigin{
-				Origin:         sessionData.Origin,
+				Origin:         origin,
 				LocalStorage:   map[string]string{},
-				SessionStorage: sessionData.Items,
+				SessionStorage: sessionItems,
 			})
 		}
 	}
@@ -2897,7 +3868,7 @@ func (p *Pilot) StorageState(ctx context.Context) (*StorageState, error) {
 // This includes cookies, localStorage, and sessionStorage. The browser should be on
 // a page (or will be navigated to the first origin) for storage to be set correctly.
 func (p *Pilot) SetStorageState(ctx context.Context, state *StorageState) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -2994,7 +3965,7 @@ func (p *Pilot) SetStorageState(ctx context.Context, state *StorageState) error
 
 // ClearStorage clears all cookies, localStorage, and sessionStorage.
 func (p *Pilot) ClearStorage(ctx context.Context) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -0,0 +1,35 @@
+package w3pilot
+
+import (
+	"context"
+	"time"
+)
+
+// elementFromPointScript builds a unique CSS path to the topmost element at
+// viewport coordinates (x, y), or returns null when there is none.
+const elementFromPointScript = `(x, y) => {
+	` + cssPathFunction + `
+	const el = document.elementFromPoint(x, y);
+	if (!el) return null;
+	return cssPath(el);
+}`
+
+// ElementFromPoint returns the topmost element at viewport coordinates
+// (x, y), as used by document.elementFromPoint. Use it to find out what
+// intercepted a click or what covers a focused input. It returns
+// ErrNoElementAtPoint if there is no element at the point.
+func (p *Pilot) ElementFromPoint(ctx context.Context, x, y float64) (*Element, error) {
+	if p.closed.Load() {
+		return nil, ErrConnectionClosed
+	}
+
+	result, err := p.EvaluateWithArgs(ctx, elementFromPointScript, x, y)
+	if err != nil {
+		return nil, err
+	}
+	selector, ok := result.(string)
+	if !ok || selector == "" {
+		return nil, ErrNoElementAtPoint
+	}
+	return p.Find(ctx, selector, &FindOptions{Timeout: time.Second})
+}
@@ -0,0 +1,44 @@
+package w3pilot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// TestElementFromPoint verifies that the element at the point is found by
+// the CSS path the page returns.
+func TestElementFromPoint(t *testing.T) {
+	mock := newMockTransport()
+	mock.methodResponses = map[string]json.RawMessage{
+		"script.callFunction": json.RawMessage(`{"result":{"type":"string","value":"#overlay > div"}}`),
+		"vibium:page.find":    json.RawMessage(`{"tag":"div","text":"Cookie banner"}`),
+	}
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	el, err := pilot.ElementFromPoint(context.Background(), 120, 40)
+	if err != nil {
+		t.Fatalf("ElementFromPoint failed: %v", err)
+	}
+	if el.Selector() != "#overlay > div" {
+		t.Errorf("Expected selector %q, got %q", "#overlay > div", el.Selector())
+	}
+
+	args := mock.calls[0].Params.(map[string]interface{})["arguments"].([]interface{})
+	if x := args[0].(map[string]interface{})["value"]; x != 120.0 {
+		t.Errorf("Expected x argument 120, got %v", x)
+	}
+}
+
+// TestElementFromPoint_None verifies the sentinel error for empty points.
+func TestElementFromPoint_None(t *testing.T) {
+	mock := newMockTransport()
+	mock.response = json.RawMessage(`{"result":{"type":"null"}}`)
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	_, err := pilot.ElementFromPoint(context.Background(), -10, -10)
+	if !errors.Is(err, ErrNoElementAtPoint) {
+		t.Errorf("Expected ErrNoElementAtPoint, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package w3pilot
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// requestLogEvent is the subset of a BiDi network event used for request logging.
+type requestLogEvent struct {
+	Context string `json:"context"`
+	Request struct {
+		Request string `json:"request"`
+		URL     string `json:"url"`
+		Method  string `json:"method"`
+	} `json:"request"`
+	Response struct {
+		Status int `json:"status"`
+	} `json:"response"`
+	ErrorText string `json:"errorText"`
+	Timestamp int64  `json:"timestamp"` // milliseconds since epoch
+}
+
+// requestLogger emits a structured log record for every network request/response pair.
+type requestLogger struct {
+	logger *slog.Logger
+	level  slog.Leveler
+
+	mu      sync.Mutex
+	started map[string]int64 // request ID -> start timestamp (ms)
+}
+
+// enableRequestLogging subscribes to BiDi network events and logs each completed
+// or failed request with its method, URL, status, and duration.
+//
+// The logger is taken from ctx (see ContextWithLogger), falling back to slog.Default().
+func (p *Pilot) enableRequestLogging(ctx context.Context, level slog.Leveler) error {
+	if level == nil {
+		level = slog.LevelDebug
+	}
+
+	logger := LoggerFromContext(ctx)
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	rl := &requestLogger{
+		logger:  logger,
+		level:   level,
+		started: make(map[string]int64),
+	}
+
+	p.client.OnEvent("network.beforeRequestSent", rl.onRequest)
+	p.client.OnEvent("network.responseCompleted", rl.onResponse)
+	p.client.OnEvent("network.fetchError", rl.onResponse)
+
+	_, err := p.client.Send(ctx, "session.subscribe", map[string]interface{}{
+		"events": []string{
+			"network.beforeRequestSent",
+			"network.responseCompleted",
+			"network.fetchError",
+		},
+	})
+	if err == nil {
+		debugLog(ctx, "request logging enabled", "level", level.Level().String())
+	}
+	return err
+}
+
+func (rl *requestLogger) onRequest(event *BiDiEvent) {
+	var params requestLogEvent
+	if err := json.Unmarshal(event.Params, &params); err != nil {
+		return
+	}
+
+	rl.mu.Lock()
+	rl.started[params.Request.Request] = params.Timestamp
+	rl.mu.Unlock()
+}
+
+func (rl *requestLogger) onResponse(event *BiDiEvent) {
+	var params requestLogEvent
+	if err := json.Unmarshal(event.Params, &params); err != nil {
+		return
+	}
+
+	rl.mu.Lock()
+	start, ok := rl.started[params.Request.Request]
+	delete(rl.started, params.Request.Request)
+	rl.mu.Unlock()
+
+	attrs := []any{
+		"method", params.Request.Method,
+		"url", params.Request.URL,
+		"status", params.Response.Status,
+	}
+	if ok && params.Timestamp >= start {
+		attrs = append(attrs, "duration", time.Duration(params.Timestamp-start)*time.Millisecond)
+	}
+	if params.ErrorText != "" {
+		attrs = append(attrs, "error", params.ErrorText)
+	}
+	if params.Context != "" {
+		attrs = append(attrs, "context", params.Context)
+	}
+
+	rl.logger.Log(context.Background(), rl.level.Level(), "network request", attrs...)
+}
@@ -0,0 +1,97 @@
+package w3pilot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestEnableRequestLogging_SubscribesToNetworkEvents verifies the BiDi subscription.
+func TestEnableRequestLogging_SubscribesToNetworkEvents(t *testing.T) {
+	mock := newMockTransport()
+	pilot := &Pilot{
+		client:          NewBiDiClient(mock),
+		browsingContext: "ctx-123",
+	}
+
+	if err := pilot.enableRequestLogging(context.Background(), nil); err != nil {
+		t.Fatalf("enableRequestLogging failed: %v", err)
+	}
+
+	calls := mock.getCalls()
+	if len(calls) != 1 || calls[0].Method != "session.subscribe" {
+		t.Fatalf("Expected a single session.subscribe call, got: %v", calls)
+	}
+
+	for _, method := range []string{"network.beforeRequestSent", "network.responseCompleted", "network.fetchError"} {
+		if len(mock.handlers[method]) != 1 {
+			t.Errorf("Expected handler for %s", method)
+		}
+	}
+}
+
+// TestRequestLogger_LogsRequestWithDuration verifies request/response correlation.
+func TestRequestLogger_LogsRequestWithDuration(t *testing.T) {
+	var buf bytes.Buffer
+	rl := &requestLogger{
+		logger:  slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		level:   slog.LevelDebug,
+		started: make(map[string]int64),
+	}
+
+	rl.onRequest(&BiDiEvent{
+		Method: "network.beforeRequestSent",
+		Params: json.RawMessage(`{"request":{"request":"r1","url":"https://example.com/","method":"GET"},"timestamp":1000}`),
+	})
+	rl.onResponse(&BiDiEvent{
+		Method: "network.responseCompleted",
+		Params: json.RawMessage(`{"request":{"request":"r1","url":"https://example.com/","method":"GET"},"response":{"status":200},"timestamp":1250}`),
+	})
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Expected a JSON log record, got %q: %v", buf.String(), err)
+	}
+
+	if record["level"] != "DEBUG" {
+		t.Errorf("Expected level DEBUG, got %v", record["level"])
+	}
+	if record["method"] != "GET" {
+		t.Errorf("Expected method GET, got %v", record["method"])
+	}
+	if record["url"] != "https://example.com/" {
+		t.Errorf("Expected url https://example.com/, got %v", record["url"])
+	}
+	if record["status"] != float64(200) {
+		t.Errorf("Expected status 200, got %v", record["status"])
+	}
+	// slog encodes time.Duration as nanoseconds in JSON
+	if record["duration"] != float64(250_000_000) {
+		t.Errorf("Expected duration 250ms, got %v", record["duration"])
+	}
+	if len(rl.started) != 0 {
+		t.Errorf("Expected completed request to be removed, got %d pending", len(rl.started))
+	}
+}
+
+// TestRequestLogger_LevelSilencesRecords verifies that the configured level is honored.
+func TestRequestLogger_LevelSilencesRecords(t *testing.T) {
+	var buf bytes.Buffer
+	rl := &requestLogger{
+		logger:  slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})),
+		level:   slog.LevelDebug,
+		started: make(map[string]int64),
+	}
+
+	rl.onResponse(&BiDiEvent{
+		Method: "network.fetchError",
+		Params: json.RawMessage(`{"request":{"request":"r2","url":"https://example.com/x","method":"POST"},"errorText":"net::ERR_FAILED"}`),
+	})
+
+	if strings.TrimSpace(buf.String()) != "" {
+		t.Errorf("Expected debug record to be dropped by info handler, got %q", buf.String())
+	}
+}
@@ -2,14 +2,26 @@ package w3pilot
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
-// Route represents an intercepted network request.
+// Route represents an intercepted network request. A handler settles it
+// with Fulfill, Continue, or Abort; if it does neither, the request is
+// continued unchanged when the handler returns.
 type Route struct {
-	client    *BiDiClient
-	context   string
-	intercept string
-	Request   *Request
+	client  *BiDiClient
+	handled atomic.Bool
+	Request *Request
 }
 
 // Request represents a network request.
@@ -20,6 +32,7 @@ type Request struct {
 	PostData            string            `json:"postData,omitempty"`
 	ResourceType        string            `json:"resourceType"`
 	IsNavigationRequest bool              `json:"isNavigationRequest"`
+	RequestID           string            `json:"requestId,omitempty"`
 }
 
 // Response represents a network response.
@@ -29,6 +42,45 @@ type Response struct {
 	StatusText string            `json:"statusText"`
 	Headers    map[string]string `json:"headers"`
 	Body       []byte            `json:"-"`
+	RequestID  string            `json:"requestId,omitempty"`
+
+	// Timing is the request's network timing, when reported by the browser.
+	Timing *ResponseTiming `json:"timing,omitempty"`
+	// TransferSize is the total bytes received over the network, including headers.
+	TransferSize int64 `json:"transferSize,omitempty"`
+	// EncodedBodySize is the size of the body as transferred (before decompression).
+	EncodedBodySize int64 `json:"encodedBodySize,omitempty"`
+	// FromCache is true if the response was served from the browser cache.
+	FromCache bool `json:"fromCache,omitempty"`
+}
+
+// ResponseTiming breaks down a request's network timing.
+// Phase fields are offsets from StartTime and are zero when a phase did not
+// occur (e.g. no DNS lookup on a reused connection).
+type ResponseTiming struct {
+	// StartTime is when the request was issued.
+	StartTime time.Time `json:"startTime"`
+
+	DNSStart      time.Duration `json:"dnsStart,omitempty"`
+	DNSEnd        time.Duration `json:"dnsEnd,omitempty"`
+	ConnectStart  time.Duration `json:"connectStart,omitempty"`
+	ConnectEnd    time.Duration `json:"connectEnd,omitempty"`
+	TLSStart      time.Duration `json:"tlsStart,omitempty"`
+	RequestStart  time.Duration `json:"requestStart,omitempty"`
+	ResponseStart time.Duration `json:"responseStart,omitempty"`
+	ResponseEnd   time.Duration `json:"responseEnd,omitempty"`
+}
+
+// Duration returns the time from issuing the request to receiving the last
+// byte of the response.
+func (t *ResponseTiming) Duration() time.Duration {
+	return t.ResponseEnd
+}
+
+// TimeToFirstByte returns the time from issuing the request to receiving the
+// first byte of the response.
+func (t *ResponseTiming) TimeToFirstByte() time.Duration {
+	return t.ResponseStart
 }
 
 // FulfillOptions configures how to fulfill a route.
@@ -42,44 +94,60 @@ type FulfillOptions struct {
 
 // ContinueOptions configures how to continue a route.
 type ContinueOptions struct {
-	URL      string
-	Method   string
-	Headers  map[string]string
+	URL    string
+	Method string
+
+	// Headers are merged into the request's headers, replacing any with
+	// the same name (compared case-insensitively), e.g. to add an
+	// Authorization header to API calls only.
+	Headers map[string]string
+
 	PostData string
 }
 
-// Fulfill fulfills the route with the given response.
+// Fulfill fulfills the route with the given response instead of sending
+// the request.
 func (r *Route) Fulfill(ctx context.Context, opts FulfillOptions) error {
-	params := map[string]interface{}{
-		"context":   r.context,
-		"intercept": r.intercept,
+	body := opts.Body
+	if opts.Path != "" {
+		content, err := os.ReadFile(opts.Path)
+		if err != nil {
+			return fmt.Errorf("failed to read fulfill body: %w", err)
+		}
+		body = content
 	}
 
-	if opts.Status != 0 {
-		params["status"] = opts.Status
+	status := opts.Status
+	if status == 0 {
+		status = 200
 	}
-	if opts.Headers != nil {
-		params["headers"] = opts.Headers
+	headers := make(map[string]string, len(opts.Headers)+1)
+	for name, value := range opts.Headers {
+		headers[name] = value
 	}
 	if opts.ContentType != "" {
-		params["contentType"] = opts.ContentType
-	}
-	if opts.Body != nil {
-		params["body"] = opts.Body
+		headers = mergeHeaders(headers, map[string]string{"Content-Type": opts.ContentType})
 	}
-	if opts.Path != "" {
-		params["path"] = opts.Path
+
+	params := map[string]interface{}{
+		"request":    r.requestID(),
+		"statusCode": status,
+		"headers":    toNetworkHeaders(headers),
+		"body": map[string]interface{}{
+			"type":  "base64",
+			"value": base64.StdEncoding.EncodeToString(body),
+		},
 	}
 
-	_, err := r.client.Send(ctx, "vibium:network.fulfill", params)
+	r.handled.Store(true)
+	_, err := r.client.Send(ctx, "network.provideResponse", params)
 	return err
 }
 
 // Continue continues the route with optional modifications.
 func (r *Route) Continue(ctx context.Context, opts *ContinueOptions) error {
 	params := map[string]interface{}{
-		"context":   r.context,
-		"intercept": r.intercept,
+		"request": r.requestID(),
 	}
 
 	if opts != nil {
@@ -90,28 +158,299 @@ func (r *Route) Continue(ctx context.Context, opts *ContinueOptions) error {
 			params["method"] = opts.Method
 		}
 		if opts.Headers != nil {
-			params["headers"] = opts.Headers
+			var original map[string]string
+			if r.Request != nil {
+				original = r.Request.Headers
+			}
+			params["headers"] = toNetworkHeaders(mergeHeaders(original, opts.Headers))
 		}
 		if opts.PostData != "" {
-			params["postData"] = opts.PostData
+			params["body"] = map[string]interface{}{
+				"type":  "string",
+				"value": opts.PostData,
+			}
 		}
 	}
 
-	_, err := r.client.Send(ctx, "vibium:network.continue", params)
+	r.handled.Store(true)
+	_, err := r.client.Send(ctx, "network.continueRequest", params)
 	return err
 }
 
+// mergeHeaders returns base with overrides applied. A header in overrides
+// replaces every header in base with the same name, ignoring case.
+func mergeHeaders(base, overrides map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(overrides))
+	for name, value := range base {
+		replaced := false
+		for override := range overrides {
+			if strings.EqualFold(name, override) {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged[name] = value
+		}
+	}
+	for name, value := range overrides {
+		merged[name] = value
+	}
+	return merged
+}
+
+// toNetworkHeaders converts headers to BiDi network.Header values, sorted
+// by name.
+func toNetworkHeaders(headers map[string]string) []map[string]interface{} {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		result = append(result, map[string]interface{}{
+			"name":  name,
+			"value": map[string]interface{}{"type": "string", "value": headers[name]},
+		})
+	}
+	return result
+}
+
 // Abort aborts the route.
 func (r *Route) Abort(ctx context.Context) error {
 	params := map[string]interface{}{
-		"context":   r.context,
-		"intercept": r.intercept,
+		"request": r.requestID(),
 	}
 
-	_, err := r.client.Send(ctx, "vibium:network.abort", params)
+	r.handled.Store(true)
+	_, err := r.client.Send(ctx, "network.failRequest", params)
 	return err
 }
 
+func (r *Route) requestID() string {
+	if r.Request == nil {
+		return ""
+	}
+	return r.Request.RequestID
+}
+
+// routeEntry is a handler registered with Pilot.Route.
+type routeEntry struct {
+	context   string
+	pattern   string
+	handler   RouteHandler
+	timeout   time.Duration
+	intercept string
+}
+
+// router dispatches requests blocked by BiDi network interception to Route
+// handlers. Each route has its own intercept, limited to the URLs its
+// pattern can match where BiDi can express that; requests that match no
+// route are continued unchanged. One router is shared by all pages using
+// the same BiDiClient.
+type router struct {
+	client *BiDiClient
+
+	// interceptMu serializes adding and removing intercepts. It is not held
+	// by onEvent, so commands can be sent while holding it.
+	interceptMu sync.Mutex
+
+	mu     sync.Mutex
+	routes []*routeEntry
+}
+
+// router returns the client's router, subscribing to
+// network.beforeRequestSent on first use.
+func (c *BiDiClient) router(ctx context.Context) (*router, error) {
+	c.routerMu.Lock()
+	defer c.routerMu.Unlock()
+
+	if c.routes != nil {
+		return c.routes, nil
+	}
+
+	_, err := c.Send(ctx, "session.subscribe", map[string]interface{}{
+		"events": []string{"network.beforeRequestSent"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r := &router{client: c}
+	c.OnEvent("network.beforeRequestSent", r.onEvent)
+
+	c.routes = r
+	return r, nil
+}
+
+// interceptPathPattern matches paths that need no escaping, so the
+// browser compares them as written.
+var interceptPathPattern = regexp.MustCompile(`^/[A-Za-z0-9/._~-]*$`)
+
+// interceptURLPattern returns a BiDi URL pattern matching every URL the
+// route pattern matches, and possibly more. Regular expressions and globs
+// with wildcards in the scheme or host cannot be expressed, and return false.
+func interceptURLPattern(pattern string) (map[string]interface{}, bool) {
+	scheme, rest, ok := strings.Cut(pattern, "://")
+	if !ok || (scheme != "http" && scheme != "https") {
+		return nil, false
+	}
+	host, path, _ := strings.Cut(rest, "/")
+	if host == "" || strings.ContainsAny(host, "*[]@") {
+		return nil, false
+	}
+
+	urlPattern := map[string]interface{}{
+		"type":     "pattern",
+		"protocol": scheme,
+	}
+	hostname, port, hasPort := strings.Cut(host, ":")
+	urlPattern["hostname"] = hostname
+	if hasPort {
+		urlPattern["port"] = port
+	}
+	// Any query is left to the route pattern
+	path, _, _ = strings.Cut("/"+path, "?")
+	if path != "/" && interceptPathPattern.MatchString(path) {
+		urlPattern["pathname"] = path
+	}
+	return urlPattern, true
+}
+
+// add registers a route with an intercept of its own.
+func (r *router) add(ctx context.Context, entry *routeEntry) error {
+	r.interceptMu.Lock()
+	defer r.interceptMu.Unlock()
+
+	params := map[string]interface{}{
+		"phases":   []string{"beforeRequestSent"},
+		"contexts": []string{entry.context},
+	}
+	// Requests the browser can rule out are never blocked; without URL
+	// patterns the intercept blocks every request in the context
+	if urlPattern, ok := interceptURLPattern(entry.pattern); ok {
+		params["urlPatterns"] = []interface{}{urlPattern}
+	}
+
+	result, err := r.client.Send(ctx, "network.addIntercept", params)
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Intercept string `json:"intercept"`
+	}
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return fmt.Errorf("failed to parse intercept: %w", err)
+	}
+	entry.intercept = resp.Intercept
+
+	r.mu.Lock()
+	r.routes = append(r.routes, entry)
+	r.mu.Unlock()
+	return nil
+}
+
+// remove unregisters the routes for pattern in browsingCtx and removes
+// their intercepts. It reports whether any route was removed.
+func (r *router) remove(ctx context.Context, browsingCtx, pattern string) (bool, error) {
+	r.interceptMu.Lock()
+	defer r.interceptMu.Unlock()
+
+	r.mu.Lock()
+	var removed []*routeEntry
+	r.routes = slices.DeleteFunc(r.routes, func(entry *routeEntry) bool {
+		if entry.context == browsingCtx && entry.pattern == pattern {
+			removed = append(removed, entry)
+			return true
+		}
+		return false
+	})
+	r.mu.Unlock()
+
+	for _, entry := range removed {
+		if _, err := r.client.Send(ctx, "network.removeIntercept", map[string]interface{}{
+			"intercept": entry.intercept,
+		}); err != nil {
+			return true, err
+		}
+	}
+	return len(removed) > 0, nil
+}
+
+func (r *router) onEvent(event *BiDiEvent) {
+	var params struct {
+		networkEventParams
+		IsBlocked  bool     `json:"isBlocked"`
+		Intercepts []string `json:"intercepts"`
+	}
+	if err := json.Unmarshal(event.Params, &params); err != nil || !params.IsBlocked {
+		return
+	}
+
+	r.mu.Lock()
+	var browsingCtx string
+	for _, entry := range r.routes {
+		if slices.Contains(params.Intercepts, entry.intercept) {
+			browsingCtx = entry.context
+			break
+		}
+	}
+	var entry *routeEntry
+	if browsingCtx != "" {
+		// The most recently registered matching route wins
+		for i := len(r.routes) - 1; i >= 0; i-- {
+			if r.routes[i].context == browsingCtx && matchURLPattern(params.Request.URL, r.routes[i].pattern) {
+				entry = r.routes[i]
+				break
+			}
+		}
+	}
+	r.mu.Unlock()
+
+	if browsingCtx == "" {
+		// Blocked by an intercept that is not ours
+		return
+	}
+
+	route := &Route{
+		client: r.client,
+		Request: &Request{
+			URL:                 params.Request.URL,
+			Method:              params.Request.Method,
+			Headers:             flattenNetworkHeaders(params.Request.Headers),
+			IsNavigationRequest: params.Navigation != nil,
+			RequestID:           params.Request.Request,
+		},
+	}
+
+	// Handlers send commands, so they must not run on the event goroutine.
+	go r.dispatch(route, entry)
+}
+
+// dispatch runs the route's handler, continuing the request unchanged if
+// there is no handler or the handler did not settle it.
+func (r *router) dispatch(route *Route, entry *routeEntry) {
+	timeout := DefaultTimeout
+	if entry != nil && entry.timeout > 0 {
+		timeout = entry.timeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if entry != nil && entry.handler != nil {
+		if err := entry.handler(ctx, route); err != nil {
+			debugLog(ctx, "route handler failed", "pattern", entry.pattern, "url", route.Request.URL, "error", err)
+		}
+	}
+	if !route.handled.Load() {
+		if err := route.Continue(ctx, nil); err != nil {
+			debugLog(ctx, "failed to continue request", "url", route.Request.URL, "error", err)
+		}
+	}
+}
+
 // ConsoleMessage represents a console message from the browser.
 type ConsoleMessage struct {
 	Type string   `json:"type"`
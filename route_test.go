@@ -0,0 +1,223 @@
+package w3pilot
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// waitForCall returns the params of the first call to method, failing the
+// test if none arrives within a second.
+func waitForCall(t *testing.T, mock *mockTransport, method string) map[string]interface{} {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		for _, call := range mock.getCalls() {
+			if call.Method == method {
+				return call.Params.(map[string]interface{})
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("Expected a %s call, got %v", method, mock.getCalls())
+	return nil
+}
+
+// TestRouteContinue_MergesHeaders verifies that Continue merges
+// ContinueOptions.Headers into the request's headers, overriding same-named
+// headers regardless of case.
+func TestRouteContinue_MergesHeaders(t *testing.T) {
+	mock := newMockTransport()
+	route := &Route{
+		client: NewBiDiClient(mock),
+		Request: &Request{
+			URL:       "https://example.com/api/users",
+			RequestID: "req-1",
+			Headers: map[string]string{
+				"accept":        "application/json",
+				"authorization": "Bearer old",
+			},
+		},
+	}
+
+	err := route.Continue(context.Background(), &ContinueOptions{
+		Headers: map[string]string{"Authorization": "Bearer test-token", "X-Test": "1"},
+	})
+	if err != nil {
+		t.Fatalf("Continue failed: %v", err)
+	}
+
+	calls := mock.getCalls()
+	if len(calls) != 1 || calls[0].Method != "network.continueRequest" {
+		t.Fatalf("Expected one network.continueRequest call, got %v", calls)
+	}
+	params := calls[0].Params.(map[string]interface{})
+	if params["request"] != "req-1" {
+		t.Errorf("Expected request req-1, got %v", params["request"])
+	}
+	want := toNetworkHeaders(map[string]string{
+		"accept":        "application/json",
+		"Authorization": "Bearer test-token",
+		"X-Test":        "1",
+	})
+	if !reflect.DeepEqual(params["headers"], want) {
+		t.Errorf("Expected headers %v, got %v", want, params["headers"])
+	}
+}
+
+// TestPilotRoute_DispatchesInterceptedRequests verifies that Route adds a
+// BiDi intercept, passes blocked requests matching its pattern to the
+// handler with their headers, and continues other requests unchanged.
+func TestPilotRoute_DispatchesInterceptedRequests(t *testing.T) {
+	mock := newMockTransport()
+	mock.setMethodResponse("network.addIntercept", json.RawMessage(`{"intercept":"intercept-1"}`))
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	routed := make(chan *Request, 1)
+	err := pilot.Route(context.Background(), "**/api/*", func(ctx context.Context, route *Route) error {
+		routed <- route.Request
+		return route.Continue(ctx, &ContinueOptions{
+			Headers: map[string]string{"Authorization": "Bearer test-token"},
+		})
+	})
+	if err != nil {
+		t.Fatalf("Route failed: %v", err)
+	}
+
+	params := waitForCall(t, mock, "network.addIntercept")
+	if !reflect.DeepEqual(params["contexts"], []string{"ctx-123"}) {
+		t.Errorf("Expected intercept for ctx-123, got %v", params["contexts"])
+	}
+	if _, ok := params["urlPatterns"]; ok {
+		t.Errorf("Expected a catch-all intercept for a glob starting with **, got %v", params["urlPatterns"])
+	}
+
+	emitNetworkEvent(t, mock, "network.beforeRequestSent",
+		`{"context":"ctx-123","isBlocked":true,"intercepts":["intercept-1"],"request":{"request":"req-1","url":"https://example.com/api/users","method":"GET","headers":[{"name":"Accept","value":{"type":"string","value":"application/json"}}]}}`)
+
+	select {
+	case req := <-routed:
+		if req.RequestID != "req-1" || req.Method != "GET" || req.Headers["Accept"] != "application/json" {
+			t.Errorf("Unexpected routed request: %+v", req)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the handler to be called")
+	}
+
+	params = waitForCall(t, mock, "network.continueRequest")
+	want := toNetworkHeaders(map[string]string{
+		"Accept":        "application/json",
+		"Authorization": "Bearer test-token",
+	})
+	if params["request"] != "req-1" || !reflect.DeepEqual(params["headers"], want) {
+		t.Errorf("Unexpected continueRequest params: %v", params)
+	}
+
+	// A request matching no route is continued unchanged
+	mock.mu.Lock()
+	mock.calls = nil
+	mock.mu.Unlock()
+	emitNetworkEvent(t, mock, "network.beforeRequestSent",
+		`{"context":"ctx-123","isBlocked":true,"intercepts":["intercept-1"],"request":{"request":"req-2","url":"https://example.com/logo.png","method":"GET"}}`)
+
+	params = waitForCall(t, mock, "network.continueRequest")
+	if params["request"] != "req-2" || params["headers"] != nil {
+		t.Errorf("Unexpected continueRequest params: %v", params)
+	}
+
+	if err := pilot.Unroute(context.Background(), "**/api/*"); err != nil {
+		t.Fatalf("Unroute failed: %v", err)
+	}
+	params = waitForCall(t, mock, "network.removeIntercept")
+	if params["intercept"] != "intercept-1" {
+		t.Errorf("Expected intercept-1 to be removed, got %v", params["intercept"])
+	}
+}
+
+// TestInterceptURLPattern verifies route patterns become BiDi URL patterns
+// that match at least the same URLs, or none where BiDi cannot express them.
+func TestInterceptURLPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    map[string]interface{}
+	}{
+		{"https://example.com/api/users", map[string]interface{}{"type": "pattern", "protocol": "https", "hostname": "example.com", "pathname": "/api/users"}},
+		{"https://example.com/api/users?**", map[string]interface{}{"type": "pattern", "protocol": "https", "hostname": "example.com", "pathname": "/api/users"}},
+		{"http://localhost:8080/**", map[string]interface{}{"type": "pattern", "protocol": "http", "hostname": "localhost", "port": "8080"}},
+		{"https://cdn.example.com/img/*.png", map[string]interface{}{"type": "pattern", "protocol": "https", "hostname": "cdn.example.com"}},
+		{"https://example.com/search%20results", map[string]interface{}{"type": "pattern", "protocol": "https", "hostname": "example.com"}},
+		{"**/api/*", nil},
+		{"https://*.example.com/**", nil},
+		{"/api\\/v[0-9]+/", nil},
+		{"ws://example.com/socket", nil},
+	}
+	for _, tt := range tests {
+		got, ok := interceptURLPattern(tt.pattern)
+		if ok != (tt.want != nil) || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("interceptURLPattern(%q) = %v, %v, want %v", tt.pattern, got, ok, tt.want)
+		}
+	}
+}
+
+// TestPilotRoute_InterceptPerRoute verifies each route has its own
+// intercept, limited to its URLs, and Unroute removes only that one.
+func TestPilotRoute_InterceptPerRoute(t *testing.T) {
+	mock := newMockTransport()
+	mock.setMethodResponse("network.addIntercept", json.RawMessage(`{"intercept":"intercept-1"}`))
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+	ctx := context.Background()
+
+	noop := func(ctx context.Context, route *Route) error { return nil }
+	if err := pilot.Route(ctx, "**/*.png", noop); err != nil {
+		t.Fatalf("Route failed: %v", err)
+	}
+	mock.setMethodResponse("network.addIntercept", json.RawMessage(`{"intercept":"intercept-2"}`))
+	routed := make(chan string, 1)
+	err := pilot.Route(ctx, "https://api.example.com/users/**", func(ctx context.Context, route *Route) error {
+		routed <- route.Request.URL
+		return route.Abort(ctx)
+	})
+	if err != nil {
+		t.Fatalf("Route failed: %v", err)
+	}
+
+	var intercepts []map[string]interface{}
+	for _, call := range mock.getCalls() {
+		if call.Method == "network.addIntercept" {
+			intercepts = append(intercepts, call.Params.(map[string]interface{}))
+		}
+	}
+	if len(intercepts) != 2 {
+		t.Fatalf("Expected an intercept per route, got %d", len(intercepts))
+	}
+	want := []interface{}{map[string]interface{}{"type": "pattern", "protocol": "https", "hostname": "api.example.com"}}
+	if !reflect.DeepEqual(intercepts[1]["urlPatterns"], want) {
+		t.Errorf("Expected urlPatterns %v, got %v", want, intercepts[1]["urlPatterns"])
+	}
+
+	emitNetworkEvent(t, mock, "network.beforeRequestSent",
+		`{"context":"ctx-123","isBlocked":true,"intercepts":["intercept-2"],"request":{"request":"req-1","url":"https://api.example.com/users/1","method":"GET"}}`)
+	select {
+	case url := <-routed:
+		if url != "https://api.example.com/users/1" {
+			t.Errorf("Unexpected routed URL: %s", url)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the handler to be called")
+	}
+
+	if err := pilot.Unroute(ctx, "https://api.example.com/users/**"); err != nil {
+		t.Fatalf("Unroute failed: %v", err)
+	}
+	var removed []interface{}
+	for _, call := range mock.getCalls() {
+		if call.Method == "network.removeIntercept" {
+			removed = append(removed, call.Params.(map[string]interface{})["intercept"])
+		}
+	}
+	if !reflect.DeepEqual(removed, []interface{}{"intercept-2"}) {
+		t.Errorf("Expected only intercept-2 to be removed, got %v", removed)
+	}
+}
@@ -33,6 +33,13 @@ type Environment struct {
 
 	// Headless indicates if the browser is running in headless mode.
 	Headless bool
+
+	// StepID is the ID of the step currently executing.
+	StepID string
+
+	// Pause blocks until an operator resumes the current step (see control.pause).
+	// It is nil when the executor does not support pausing.
+	Pause PauseFunc
 }
 
 // NewEnvironment creates a new Environment with initialized fields.
@@ -0,0 +1,188 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/plexusone/w3pilot"
+)
+
+// AssertTextActivity asserts that an element's text contains, or with
+// exact set equals, the expected text.
+type AssertTextActivity struct{}
+
+func (a *AssertTextActivity) Name() string { return "assert.text" }
+
+func (a *AssertTextActivity) Execute(ctx context.Context, params map[string]any, env *Environment) (any, error) {
+	expected, ok := params["expected"]
+	if !ok {
+		return nil, fmt.Errorf("expected parameter is required")
+	}
+	want := fmt.Sprint(expected)
+
+	el, err := findForAssert(ctx, params, env)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := el.Text(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get text failed: %w", err)
+	}
+
+	if GetBool(params, "exact") {
+		if text != want {
+			return nil, assertionError(params, "text assertion failed: expected %q, got %q", want, text)
+		}
+	} else if !strings.Contains(text, want) {
+		return nil, assertionError(params, "text assertion failed: expected text containing %q, got %q", want, text)
+	}
+
+	return text, nil
+}
+
+// AssertVisibleActivity asserts that an element is visible.
+type AssertVisibleActivity struct{}
+
+func (a *AssertVisibleActivity) Name() string { return "assert.visible" }
+
+func (a *AssertVisibleActivity) Execute(ctx context.Context, params map[string]any, env *Environment) (any, error) {
+	el, err := findForAssert(ctx, params, env)
+	if err != nil {
+		return nil, err
+	}
+
+	visible, err := el.IsVisible(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("visibility check failed: %w", err)
+	}
+	if !visible {
+		return nil, assertionError(params, "visibility assertion failed: expected %s to be visible, got hidden", GetString(params, "selector"))
+	}
+
+	return true, nil
+}
+
+// AssertURLActivity asserts that the current page URL contains the expected
+// string, or matches a regular expression given as pattern.
+type AssertURLActivity struct{}
+
+func (a *AssertURLActivity) Name() string { return "assert.url" }
+
+func (a *AssertURLActivity) Execute(ctx context.Context, params map[string]any, env *Environment) (any, error) {
+	expected := GetString(params, "expected")
+	pattern := GetString(params, "pattern")
+	if expected == "" && pattern == "" {
+		return nil, fmt.Errorf("expected or pattern parameter is required")
+	}
+
+	url, err := env.Pilot.URL(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get URL failed: %w", err)
+	}
+
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL pattern: %w", err)
+		}
+		if !re.MatchString(url) {
+			return nil, assertionError(params, "URL assertion failed: expected URL matching %q, got %q", pattern, url)
+		}
+	}
+	if expected != "" && !strings.Contains(url, expected) {
+		return nil, assertionError(params, "URL assertion failed: expected URL containing %q, got %q", expected, url)
+	}
+
+	return url, nil
+}
+
+// AssertValueActivity asserts that an input element's value equals the
+// expected value.
+type AssertValueActivity struct{}
+
+func (a *AssertValueActivity) Name() string { return "assert.value" }
+
+func (a *AssertValueActivity) Execute(ctx context.Context, params map[string]any, env *Environment) (any, error) {
+	expected, ok := params["expected"]
+	if !ok {
+		return nil, fmt.Errorf("expected parameter is required")
+	}
+	want := fmt.Sprint(expected)
+
+	el, err := findForAssert(ctx, params, env)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := el.Value(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get value failed: %w", err)
+	}
+	if value != want {
+		return nil, assertionError(params, "value assertion failed: expected %q, got %q", want, value)
+	}
+
+	return value, nil
+}
+
+// AssertAttributeActivity asserts that an element attribute equals the
+// expected value.
+type AssertAttributeActivity struct{}
+
+func (a *AssertAttributeActivity) Name() string { return "assert.attribute" }
+
+func (a *AssertAttributeActivity) Execute(ctx context.Context, params map[string]any, env *Environment) (any, error) {
+	name := GetString(params, "name")
+	if name == "" {
+		return nil, fmt.Errorf("name parameter is required")
+	}
+	expected, ok := params["expected"]
+	if !ok {
+		return nil, fmt.Errorf("expected parameter is required")
+	}
+	want := fmt.Sprint(expected)
+
+	el, err := findForAssert(ctx, params, env)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := el.GetAttribute(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("get attribute failed: %w", err)
+	}
+	if value != want {
+		return nil, assertionError(params, "attribute assertion failed: expected %s=%q, got %q", name, want, value)
+	}
+
+	return value, nil
+}
+
+// findForAssert locates the element named by the selector parameter.
+func findForAssert(ctx context.Context, params map[string]any, env *Environment) (*w3pilot.Element, error) {
+	selector := GetString(params, "selector")
+	if selector == "" {
+		return nil, fmt.Errorf("selector parameter is required")
+	}
+
+	timeout := time.Duration(GetIntDefault(params, "timeout", 30000)) * time.Millisecond
+	el, err := env.Pilot.Find(ctx, selector, &w3pilot.FindOptions{Timeout: timeout})
+	if err != nil {
+		return nil, fmt.Errorf("element not found: %w", err)
+	}
+	return el, nil
+}
+
+// assertionError formats an expected-vs-actual failure, prefixed with the
+// optional message parameter.
+func assertionError(params map[string]any, format string, args ...any) error {
+	err := fmt.Errorf(format, args...)
+	if message := GetString(params, "message"); message != "" {
+		return fmt.Errorf("%s: %w", message, err)
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAssertActivities_RequiredParams(t *testing.T) {
+	ctx := context.Background()
+	env := &Environment{}
+
+	tests := []struct {
+		activity Activity
+		params   map[string]any
+		wantErr  string
+	}{
+		{&AssertTextActivity{}, map[string]any{"selector": "h1"}, "expected parameter is required"},
+		{&AssertTextActivity{}, map[string]any{"expected": "x"}, "selector parameter is required"},
+		{&AssertVisibleActivity{}, map[string]any{}, "selector parameter is required"},
+		{&AssertURLActivity{}, map[string]any{}, "expected or pattern parameter is required"},
+		{&AssertValueActivity{}, map[string]any{"selector": "#q"}, "expected parameter is required"},
+		{&AssertAttributeActivity{}, map[string]any{"selector": "a", "expected": "x"}, "name parameter is required"},
+		{&AssertAttributeActivity{}, map[string]any{"selector": "a", "name": "href"}, "expected parameter is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.activity.Name(), func(t *testing.T) {
+			_, err := tt.activity.Execute(ctx, tt.params, env)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAssertionError(t *testing.T) {
+	err := assertionError(map[string]any{}, "value assertion failed: expected %q, got %q", "a", "b")
+	if err.Error() != `value assertion failed: expected "a", got "b"` {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	wrapped := assertionError(map[string]any{"message": "Login failed"}, "URL assertion failed")
+	if wrapped.Error() != "Login failed: URL assertion failed" {
+		t.Errorf("Unexpected error: %v", wrapped)
+	}
+	if errors.Unwrap(wrapped) == nil {
+		t.Error("Expected custom message to wrap the assertion error")
+	}
+}
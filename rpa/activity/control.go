@@ -0,0 +1,69 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrPauseRejected is returned by control.pause when the operator rejects
+// the paused step instead of approving it.
+var ErrPauseRejected = errors.New("rejected by operator")
+
+// PauseFunc blocks until the paused step is resumed or rejected, or ctx is done.
+type PauseFunc func(ctx context.Context, stepID, message string) error
+
+// Interactive is implemented by activities that wait on a person.
+// The executor does not apply its default step timeout to them; an
+// explicit step timeout still applies.
+type Interactive interface {
+	Interactive() bool
+}
+
+// PauseActivity pauses the workflow until an operator approves it.
+type PauseActivity struct{}
+
+func (a *PauseActivity) Name() string { return "control.pause" }
+
+// Interactive reports that control.pause waits on an operator.
+func (a *PauseActivity) Interactive() bool { return true }
+
+func (a *PauseActivity) Execute(ctx context.Context, params map[string]any, env *Environment) (any, error) {
+	if env.Pause == nil {
+		return nil, fmt.Errorf("pause is not supported by this executor")
+	}
+
+	message := GetStringDefault(params, "message", "Waiting for approval to continue")
+
+	// Support both "timeout" as a duration string ("10m") and integer milliseconds
+	var timeout time.Duration
+	if s := GetString(params, "timeout"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout: %w", err)
+		}
+		timeout = d
+	} else if ms := GetInt(params, "timeout"); ms > 0 {
+		timeout = time.Duration(ms) * time.Millisecond
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	if err := env.Pause(ctx, env.StepID, message); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("no approval within %s", time.Since(start).Round(time.Second))
+		}
+		return nil, err
+	}
+
+	return map[string]any{
+		"approved": true,
+		"waited":   time.Since(start).String(),
+	}, nil
+}
@@ -2,8 +2,12 @@ package activity
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/plexusone/w3pilot"
@@ -103,3 +107,130 @@ func (a *ScrapeTableActivity) Execute(ctx context.Context, params map[string]any
 		"count":   len(tableData.Rows),
 	}, nil
 }
+
+// ReadCSVActivity parses a CSV file into rows for forEach loops.
+// With a header row (the default), each row is a map keyed by column name,
+// so loop bodies can reference ${row.email}. Missing trailing cells are
+// empty strings and extra cells are ignored.
+type ReadCSVActivity struct{}
+
+func (a *ReadCSVActivity) Name() string { return "data.readCSV" }
+
+func (a *ReadCSVActivity) Execute(ctx context.Context, params map[string]any, env *Environment) (any, error) {
+	path := GetString(params, "path")
+	if path == "" {
+		return nil, fmt.Errorf("path parameter is required")
+	}
+
+	// Resolve relative paths
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(env.WorkDir, path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read failed: %w", err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.TrimLeadingSpace = true
+	// Spreadsheet exports often drop trailing empty cells, so allow rows
+	// shorter (or longer) than the header
+	r.FieldsPerRecord = -1
+	if delimiter := GetString(params, "delimiter"); delimiter != "" {
+		r.Comma = []rune(delimiter)[0]
+	}
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("CSV parse failed: %w", err)
+	}
+
+	// Default to a header row unless header: false is given
+	header := true
+	if v, ok := params["header"].(bool); ok {
+		header = v
+	}
+
+	rows := make([]any, 0, len(records))
+	if !header {
+		for _, record := range records {
+			row := make([]any, len(record))
+			for i, field := range record {
+				row[i] = field
+			}
+			rows = append(rows, row)
+		}
+		return rows, nil
+	}
+
+	if len(records) == 0 {
+		return rows, nil
+	}
+
+	headers := records[0]
+	for i, h := range headers {
+		headers[i] = strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))
+	}
+
+	for _, record := range records[1:] {
+		row := make(map[string]any, len(headers))
+		for i, h := range headers {
+			if i < len(record) {
+				row[h] = record[i]
+			} else {
+				row[h] = ""
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// ReadJSONActivity parses a JSON file for use as workflow data.
+// The optional field param selects a nested value, e.g. "data.items",
+// when the array to iterate is not at the top level.
+type ReadJSONActivity struct{}
+
+func (a *ReadJSONActivity) Name() string { return "data.readJSON" }
+
+func (a *ReadJSONActivity) Execute(ctx context.Context, params map[string]any, env *Environment) (any, error) {
+	path := GetString(params, "path")
+	if path == "" {
+		return nil, fmt.Errorf("path parameter is required")
+	}
+
+	// Resolve relative paths
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(env.WorkDir, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read failed: %w", err)
+	}
+
+	var result any
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("JSON parse failed: %w", err)
+	}
+
+	field := GetString(params, "field")
+	if field == "" {
+		return result, nil
+	}
+
+	for _, key := range strings.Split(field, ".") {
+		obj, ok := result.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("field %q not found: %q is not an object", field, key)
+		}
+		if result, ok = obj[key]; !ok {
+			return nil, fmt.Errorf("field %q not found", field)
+		}
+	}
+
+	return result, nil
+}
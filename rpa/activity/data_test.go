@@ -0,0 +1,86 @@
+package activity
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadCSVActivity(t *testing.T) {
+	dir := t.TempDir()
+	csvData := "name,email\nAda,ada@example.com\nGrace,\"grace@example.com\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "in.csv"), []byte(csvData), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	a := &ReadCSVActivity{}
+	out, err := a.Execute(context.Background(), map[string]any{"path": "in.csv"}, &Environment{WorkDir: dir})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	rows, ok := out.([]any)
+	if !ok || len(rows) != 2 {
+		t.Fatalf("Expected 2 rows as []any, got %#v", out)
+	}
+	row := rows[1].(map[string]any)
+	if row["name"] != "Grace" || row["email"] != "grace@example.com" {
+		t.Errorf("Unexpected row: %v", row)
+	}
+
+	// Without a header row, rows are positional
+	out, err = a.Execute(context.Background(), map[string]any{"path": "in.csv", "header": false}, &Environment{WorkDir: dir})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if rows := out.([]any); len(rows) != 3 {
+		t.Errorf("Expected 3 rows without header, got %d", len(rows))
+	}
+}
+
+func TestReadCSVActivity_RaggedRows(t *testing.T) {
+	dir := t.TempDir()
+	csvData := "name,email,phone\nAda,ada@example.com\nGrace,grace@example.com,555-0100,extra\n"
+	if err := os.WriteFile(filepath.Join(dir, "in.csv"), []byte(csvData), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	a := &ReadCSVActivity{}
+	out, err := a.Execute(context.Background(), map[string]any{"path": "in.csv"}, &Environment{WorkDir: dir})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	rows := out.([]any)
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(rows))
+	}
+	if row := rows[0].(map[string]any); row["email"] != "ada@example.com" || row["phone"] != "" {
+		t.Errorf("Expected missing phone to be empty, got %v", row)
+	}
+	if row := rows[1].(map[string]any); len(row) != 3 || row["phone"] != "555-0100" {
+		t.Errorf("Expected extra cells to be ignored, got %v", row)
+	}
+}
+
+func TestReadJSONActivity_Field(t *testing.T) {
+	dir := t.TempDir()
+	jsonData := `{"data": {"items": [{"id": 1}, {"id": 2}]}}`
+	if err := os.WriteFile(filepath.Join(dir, "in.json"), []byte(jsonData), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	a := &ReadJSONActivity{}
+	out, err := a.Execute(context.Background(), map[string]any{"path": "in.json", "field": "data.items"}, &Environment{WorkDir: dir})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if items, ok := out.([]any); !ok || len(items) != 2 {
+		t.Errorf("Expected 2 items as []any, got %#v", out)
+	}
+
+	if _, err := a.Execute(context.Background(), map[string]any{"path": "in.json", "field": "data.missing"}, &Environment{WorkDir: dir}); err == nil {
+		t.Error("Expected error for missing field")
+	}
+}
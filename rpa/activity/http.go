@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -156,6 +157,101 @@ func (a *HTTPPostActivity) Execute(ctx context.Context, params map[string]any, e
 	return result, nil
 }
 
+// HTTPRequestActivity performs an HTTP request with any method.
+// It covers PUT, PATCH, DELETE and other methods not served by http.get and http.post.
+type HTTPRequestActivity struct{}
+
+func (a *HTTPRequestActivity) Name() string { return "http.request" }
+
+func (a *HTTPRequestActivity) Execute(ctx context.Context, params map[string]any, env *Environment) (any, error) {
+	url := GetString(params, "url")
+	if url == "" {
+		return nil, fmt.Errorf("url parameter is required")
+	}
+
+	method := strings.ToUpper(GetStringDefault(params, "method", http.MethodGet))
+
+	timeout := time.Duration(GetIntDefault(params, "timeout", 30000)) * time.Millisecond
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Prepare body
+	var bodyReader io.Reader
+	contentType := GetString(params, "contentType")
+
+	if body := params["body"]; body != nil {
+		switch v := body.(type) {
+		case string:
+			bodyReader = bytes.NewBufferString(v)
+		case []byte:
+			bodyReader = bytes.NewBuffer(v)
+		default:
+			// Encode as JSON
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode body: %w", err)
+			}
+			bodyReader = bytes.NewBuffer(data)
+			contentType = "application/json"
+		}
+		if contentType == "" {
+			contentType = "application/json"
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	// Add headers
+	if headers := GetMap(params, "headers"); headers != nil {
+		for k, v := range headers {
+			if s, ok := v.(string); ok {
+				req.Header.Set(k, s)
+			}
+		}
+	}
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if GetBool(params, "failOnError") && resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("request failed: HTTP %d", resp.StatusCode)
+	}
+
+	result := map[string]any{
+		"status":     resp.StatusCode,
+		"statusText": resp.Status,
+		"headers":    headerToMap(resp.Header),
+	}
+
+	// Parse as JSON if requested or if Content-Type is JSON
+	if GetBool(params, "json") || isJSONContentType(resp.Header.Get("Content-Type")) {
+		var jsonBody any
+		if err := json.Unmarshal(respBody, &jsonBody); err == nil {
+			result["body"] = jsonBody
+			return result, nil
+		}
+	}
+
+	result["body"] = string(respBody)
+	return result, nil
+}
+
 // HTTPDownloadActivity downloads a file.
 type HTTPDownloadActivity struct{}
 
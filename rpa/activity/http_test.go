@@ -0,0 +1,74 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPRequestActivity(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"method":      r.Method,
+			"token":       r.Header.Get("X-Token"),
+			"contentType": r.Header.Get("Content-Type"),
+			"body":        string(body),
+		})
+	}))
+	defer srv.Close()
+
+	a := &HTTPRequestActivity{}
+	out, err := a.Execute(context.Background(), map[string]any{
+		"method":  "patch",
+		"url":     srv.URL + "/records/1",
+		"headers": map[string]any{"X-Token": "abc"},
+		"body":    map[string]any{"name": "test"},
+	}, &Environment{})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	result := out.(map[string]any)
+	if result["status"] != http.StatusOK {
+		t.Errorf("Expected status 200, got %v", result["status"])
+	}
+	body, ok := result["body"].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected JSON body, got %T", result["body"])
+	}
+	if body["method"] != "PATCH" || body["token"] != "abc" {
+		t.Errorf("Unexpected method/header echo: %v", body)
+	}
+	if body["contentType"] != "application/json" || body["body"] != `{"name":"test"}` {
+		t.Errorf("Unexpected body echo: %v", body)
+	}
+}
+
+func TestHTTPRequestActivity_FailOnError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	a := &HTTPRequestActivity{}
+	params := map[string]any{"method": "DELETE", "url": srv.URL}
+
+	out, err := a.Execute(context.Background(), params, &Environment{})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if status := out.(map[string]any)["status"]; status != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %v", status)
+	}
+
+	params["failOnError"] = true
+	if _, err := a.Execute(context.Background(), params, &Environment{}); err == nil {
+		t.Error("Expected error with failOnError on HTTP 404")
+	}
+}
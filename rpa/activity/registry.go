@@ -147,8 +147,17 @@ func init() {
 	Register(&WaitForActivity{})
 	Register(&IsVisibleActivity{})
 
+	// Assertion activities
+	Register(&AssertTextActivity{})
+	Register(&AssertVisibleActivity{})
+	Register(&AssertURLActivity{})
+	Register(&AssertValueActivity{})
+	Register(&AssertAttributeActivity{})
+
 	// Data activities
 	Register(&ScrapeTableActivity{})
+	Register(&ReadCSVActivity{})
+	Register(&ReadJSONActivity{})
 
 	// File activities
 	Register(&FileReadActivity{})
@@ -159,6 +168,7 @@ func init() {
 	// HTTP activities
 	Register(&HTTPGetActivity{})
 	Register(&HTTPPostActivity{})
+	Register(&HTTPRequestActivity{})
 	Register(&HTTPDownloadActivity{})
 
 	// Utility activities
@@ -166,4 +176,7 @@ func init() {
 	Register(&WaitActivity{})
 	Register(&AssertActivity{})
 	Register(&SetVariableActivity{})
+
+	// Control activities
+	Register(&PauseActivity{})
 }
@@ -101,9 +101,12 @@ func TestDefaultRegistry(t *testing.T) {
 		"browser.click",
 		"browser.fill",
 		"element.getText",
+		"assert.text",
+		"assert.url",
 		"file.read",
 		"file.write",
 		"http.get",
+		"http.request",
 		"util.log",
 	}
 
@@ -0,0 +1,107 @@
+package rpa
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestCronSchedule_Next(t *testing.T) {
+	base := time.Date(2026, 3, 14, 10, 17, 30, 0, time.UTC) // Saturday
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"0 * * * *", time.Date(2026, 3, 14, 11, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 3, 14, 11, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 14, 10, 30, 0, 0, time.UTC)},
+		{"30 9 * * 1-5", time.Date(2026, 3, 16, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)},
+		// Day-of-month OR day-of-week when both are restricted
+		{"0 0 20 * 1", time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", base.Add(90 * time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron(%q) error = %v", tt.expr, err)
+			}
+			if got := s.Next(base); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronSchedule_NextHalfHourZone(t *testing.T) {
+	ist := time.FixedZone("IST", 5*3600+30*60)
+
+	s, err := ParseCron("0 11 * * *")
+	if err != nil {
+		t.Fatalf("ParseCron error = %v", err)
+	}
+	got := s.Next(time.Date(2026, 3, 14, 10, 45, 0, 0, ist))
+	if want := time.Date(2026, 3, 14, 11, 0, 0, 0, ist); !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+
+	s, err = ParseCron("30 * * * *")
+	if err != nil {
+		t.Fatalf("ParseCron error = %v", err)
+	}
+	got = s.Next(time.Date(2026, 3, 14, 10, 45, 0, 0, ist))
+	if want := time.Date(2026, 3, 14, 11, 30, 0, 0, ist); !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+}
+
+func TestCronSchedule_NextAcrossDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation error = %v", err)
+	}
+
+	tests := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		// Clocks spring forward from 02:00 to 03:00 on 2026-03-08
+		{"0 9 * * *", time.Date(2026, 3, 7, 10, 0, 0, 0, ny), time.Date(2026, 3, 8, 9, 0, 0, 0, ny)},
+		{"0 3 * * *", time.Date(2026, 3, 8, 1, 30, 0, 0, ny), time.Date(2026, 3, 8, 3, 0, 0, 0, ny)},
+		{"*/30 * * * *", time.Date(2026, 3, 8, 1, 45, 0, 0, ny), time.Date(2026, 3, 8, 3, 0, 0, 0, ny)},
+		// Clocks fall back from 02:00 to 01:00 on 2026-11-01
+		{"0 9 * * *", time.Date(2026, 10, 31, 10, 0, 0, 0, ny), time.Date(2026, 11, 1, 9, 0, 0, 0, ny)},
+		{"0 2 * * *", time.Date(2026, 11, 1, 0, 30, 0, 0, ny), time.Date(2026, 11, 1, 2, 0, 0, 0, ny)},
+	}
+
+	for _, tt := range tests {
+		s, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q) error = %v", tt.expr, err)
+		}
+		if got := s.Next(tt.from); !got.Equal(tt.want) {
+			t.Errorf("%q Next(%v) = %v, want %v", tt.expr, tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "@every 10ms"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) expected error", expr)
+		}
+	}
+
+	s, err := ParseCron("0 0 31 2 *")
+	if err != nil {
+		t.Fatalf("ParseCron error = %v", err)
+	}
+	if next := s.Next(time.Now()); !next.IsZero() {
+		t.Errorf("Expected no next time for Feb 31, got %v", next)
+	}
+}
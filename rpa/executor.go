@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/plexusone/w3pilot"
@@ -32,11 +33,28 @@ type ExecutorConfig struct {
 	// Logger is the structured logger.
 	Logger *slog.Logger
 
+	// Pilot is an already-launched browser to run workflows in.
+	// When set, each run opens a fresh page on it and closes that page
+	// afterwards instead of launching and quitting a browser per run.
+	// Headless settings are ignored in that case.
+	Pilot *w3pilot.Pilot
+
+	// DefaultRetry is the retry configuration for steps without their own
+	// Retry. A step's Retry always takes precedence; set its maxAttempts
+	// to 1 to opt a step out.
+	DefaultRetry *RetryConfig
+
 	// OnStepStart is called when a step starts.
+	// It may be called concurrently by steps in parallel branches.
 	OnStepStart func(step *Step)
 
 	// OnStepComplete is called when a step completes.
+	// It may be called concurrently by steps in parallel branches.
 	OnStepComplete func(step *Step, result *StepResult)
+
+	// OnPause is called when a control.pause step starts waiting.
+	// Deliver the operator's decision with Executor.Resume.
+	OnPause func(req PauseRequest)
 }
 
 // Executor runs RPA workflows.
@@ -44,6 +62,9 @@ type Executor struct {
 	config   ExecutorConfig
 	registry *activity.Registry
 	logger   *slog.Logger
+
+	pauseMu sync.Mutex
+	pauses  map[string]chan bool
 }
 
 // NewExecutor creates a new workflow executor.
@@ -105,24 +126,41 @@ func (e *Executor) RunWorkflow(ctx context.Context, wf *Workflow) (*WorkflowResu
 	// Determine headless mode
 	headless := e.config.Headless || wf.Browser.Headless
 
-	// Launch browser
-	e.logger.Info("launching browser", "headless", headless)
-	launchOpts := &w3pilot.LaunchOptions{Headless: headless}
-	vibe, err := w3pilot.Browser.Launch(ctx, launchOpts)
-	if err != nil {
-		result.Complete(StatusFailure, fmt.Errorf("failed to launch browser: %w", err))
-		return result, nil
-	}
-	defer func() {
-		if err := vibe.Quit(ctx); err != nil {
-			e.logger.Warn("failed to quit browser", "error", err)
+	// Launch browser, or open a page on the shared one
+	var vibe *w3pilot.Pilot
+	if e.config.Pilot != nil {
+		page, err := e.config.Pilot.NewPage(ctx)
+		if err != nil {
+			result.Complete(StatusFailure, fmt.Errorf("failed to open page: %w", err))
+			return result, nil
+		}
+		defer func() {
+			if err := page.Close(context.WithoutCancel(ctx)); err != nil {
+				e.logger.Warn("failed to close page", "error", err)
+			}
+		}()
+		vibe = page
+	} else {
+		e.logger.Info("launching browser", "headless", headless)
+		launchOpts := &w3pilot.LaunchOptions{Headless: headless}
+		launched, err := w3pilot.Browser.Launch(ctx, launchOpts)
+		if err != nil {
+			result.Complete(StatusFailure, fmt.Errorf("failed to launch browser: %w", err))
+			return result, nil
 		}
-	}()
+		defer func() {
+			if err := launched.Quit(ctx); err != nil {
+				e.logger.Warn("failed to quit browser", "error", err)
+			}
+		}()
+		vibe = launched
+	}
 
 	// Create execution environment
 	env := activity.NewEnvironment(vibe, e.config.WorkDir, e.logger)
 	env.Variables = resolver.Variables()
 	env.Headless = headless
+	env.Pause = e.pause
 
 	// Execute steps
 	if err := e.runSteps(ctx, wf.Steps, env, resolver, result); err != nil {
@@ -167,6 +205,16 @@ func (e *Executor) runSteps(ctx context.Context, steps []Step, env *activity.Env
 			}
 		}
 
+		// Handle parallel branches
+		if step.HasParallel() {
+			if err := e.runParallel(ctx, step, env, resolver, result); err != nil {
+				if !step.ContinueOnError {
+					return err
+				}
+			}
+			continue
+		}
+
 		// Handle forEach
 		if step.HasForEach() {
 			if err := e.runForEach(ctx, step, env, resolver, result); err != nil {
@@ -235,10 +283,11 @@ func (e *Executor) executeStepWithRetry(ctx context.Context, step *Step, env *ac
 	maxAttempts := 1
 	delay := DefaultRetryDelay
 
-	if step.HasRetry() {
-		maxAttempts = step.Retry.MaxAttempts
-		if step.Retry.Delay > 0 {
-			delay = step.Retry.Delay.Duration()
+	retry := e.retryFor(step)
+	if retry != nil {
+		maxAttempts = retry.MaxAttempts
+		if retry.Delay > 0 {
+			delay = retry.Delay.Duration()
 		}
 	}
 
@@ -271,12 +320,18 @@ func (e *Executor) executeStepWithRetry(ctx context.Context, step *Step, env *ac
 			"maxAttempts", maxAttempts,
 			"error", err)
 
+		// Retrying a selector typo or a script error would only burn the timeout again
+		if attempt < maxAttempts && !w3pilot.IsRetryable(err) {
+			e.logger.Info("not retrying step: error is not retryable", "step", step.GetID())
+			break
+		}
+
 		if attempt < maxAttempts {
 			// Apply backoff
 			backoffDelay := delay
-			if step.Retry != nil && step.Retry.BackoffMultiplier > 0 {
+			if retry != nil && retry.BackoffMultiplier > 0 {
 				for i := 1; i < attempt; i++ {
-					backoffDelay = time.Duration(float64(backoffDelay) * step.Retry.BackoffMultiplier)
+					backoffDelay = time.Duration(float64(backoffDelay) * retry.BackoffMultiplier)
 				}
 			}
 
@@ -296,6 +351,26 @@ func (e *Executor) executeStepWithRetry(ctx context.Context, step *Step, env *ac
 	return stepResult, lastErr
 }
 
+// retryFor returns the retry configuration for a step: the step's own
+// Retry if set, otherwise ExecutorConfig.DefaultRetry. Interactive
+// activities such as control.pause never use the default.
+func (e *Executor) retryFor(step *Step) *RetryConfig {
+	if step.HasRetry() {
+		return step.Retry
+	}
+
+	def := e.config.DefaultRetry
+	if def == nil || def.MaxAttempts <= 0 {
+		return nil
+	}
+	if act, ok := e.registry.Get(step.Activity); ok {
+		if interactive, ok := act.(activity.Interactive); ok && interactive.Interactive() {
+			return nil
+		}
+	}
+	return def
+}
+
 // executeStep executes a single step.
 func (e *Executor) executeStep(ctx context.Context, step *Step, env *activity.Environment, resolver *Resolver) (any, error) {
 	// Get the activity
@@ -314,10 +389,16 @@ func (e *Executor) executeStep(ctx context.Context, step *Step, env *activity.En
 		params = resolved
 	}
 
-	// Apply timeout
-	timeout := step.GetTimeout(Duration(e.config.DefaultTimeout)).Duration()
-	ctx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
+	// Apply timeout. Interactive activities wait on a person, so only an
+	// explicit step timeout bounds them.
+	if interactive, ok := act.(activity.Interactive); !ok || !interactive.Interactive() || step.Timeout > 0 {
+		timeout := step.GetTimeout(Duration(e.config.DefaultTimeout)).Duration()
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	env.StepID = step.GetID()
 
 	e.logger.Info("executing step", "step", step.GetID(), "activity", step.Activity)
 
@@ -337,6 +418,7 @@ func (e *Executor) handleError(ctx context.Context, handler *ErrorHandler, env *
 		data, err := env.Pilot.Screenshot(ctx)
 		if err == nil {
 			result.AddScreenshot(Screenshot{
+				StepID:    failedStepID(result),
 				Timestamp: time.Now(),
 				Data:      base64.StdEncoding.EncodeToString(data),
 				Reason:    "error: " + originalErr.Error(),
@@ -352,6 +434,16 @@ func (e *Executor) handleError(ctx context.Context, handler *ErrorHandler, env *
 	}
 }
 
+// failedStepID returns the ID of the most recent failed step, if any.
+func failedStepID(result *WorkflowResult) string {
+	for i := len(result.Steps) - 1; i >= 0; i-- {
+		if result.Steps[i].Status == StatusFailure {
+			return result.Steps[i].StepID
+		}
+	}
+	return ""
+}
+
 // ValidationError represents a validation error.
 type ValidationError struct {
 	StepID  string
@@ -390,11 +482,13 @@ func (e *Executor) validateStep(step *Step) []ValidationError {
 	var errors []ValidationError
 
 	if step.Activity == "" {
-		errors = append(errors, ValidationError{
-			StepID:  step.GetID(),
-			Field:   "activity",
-			Message: "activity is required",
-		})
+		if step.Parallel == nil {
+			errors = append(errors, ValidationError{
+				StepID:  step.GetID(),
+				Field:   "activity",
+				Message: "activity is required",
+			})
+		}
 	} else if _, ok := e.registry.Get(step.Activity); !ok {
 		errors = append(errors, ValidationError{
 			StepID:  step.GetID(),
@@ -414,6 +508,14 @@ func (e *Executor) validateStep(step *Step) []ValidationError {
 		stepErrors := e.validateStep(&step.Steps[i])
 		errors = append(errors, stepErrors...)
 	}
+	if step.Parallel != nil {
+		for b := range step.Parallel.Branches {
+			for i := range step.Parallel.Branches[b].Steps {
+				stepErrors := e.validateStep(&step.Parallel.Branches[b].Steps[i])
+				errors = append(errors, stepErrors...)
+			}
+		}
+	}
 
 	return errors
 }
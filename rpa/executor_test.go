@@ -0,0 +1,122 @@
+package rpa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/plexusone/w3pilot"
+	"github.com/plexusone/w3pilot/rpa/activity"
+)
+
+// flakyActivity fails until it has been called failures+1 times.
+type flakyActivity struct {
+	failures int
+	calls    int
+	err      error
+}
+
+func (a *flakyActivity) Name() string { return "test.flaky" }
+
+func (a *flakyActivity) Execute(ctx context.Context, params map[string]any, env *activity.Environment) (any, error) {
+	a.calls++
+	if a.calls <= a.failures {
+		if a.err != nil {
+			return nil, a.err
+		}
+		return nil, errors.New("transient")
+	}
+	return "ok", nil
+}
+
+func TestExecuteStepWithRetry_DefaultRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		stepRetry *RetryConfig
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "default applies", wantCalls: 3},
+		{name: "step retry wins", stepRetry: &RetryConfig{MaxAttempts: 1}, wantCalls: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flaky := &flakyActivity{failures: 2}
+			registry := activity.NewRegistry()
+			registry.Register(flaky)
+			e := &Executor{
+				config: ExecutorConfig{
+					DefaultTimeout: time.Second,
+					DefaultRetry:   &RetryConfig{MaxAttempts: 3, Delay: Duration(time.Millisecond)},
+				},
+				registry: registry,
+				logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+			}
+
+			step := &Step{Activity: "test.flaky", Retry: tt.stepRetry}
+			env := activity.NewEnvironment(nil, "", e.logger)
+			result, err := e.executeStepWithRetry(context.Background(), step, env, NewResolver(nil))
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if flaky.calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", flaky.calls, tt.wantCalls)
+			}
+			if !tt.wantErr && result.Retries != 2 {
+				t.Errorf("Retries = %d, want 2", result.Retries)
+			}
+		})
+	}
+}
+
+func TestExecuteStepWithRetry_NonRetryableError(t *testing.T) {
+	flaky := &flakyActivity{
+		failures: 2,
+		err:      fmt.Errorf("element not found: %w", &w3pilot.ElementNotFoundError{Selector: "#typo"}),
+	}
+	registry := activity.NewRegistry()
+	registry.Register(flaky)
+	e := &Executor{
+		config:   ExecutorConfig{DefaultTimeout: time.Second},
+		registry: registry,
+		logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	step := &Step{Activity: "test.flaky", Retry: &RetryConfig{MaxAttempts: 3, Delay: Duration(time.Millisecond)}}
+	env := activity.NewEnvironment(nil, "", e.logger)
+	if _, err := e.executeStepWithRetry(context.Background(), step, env, NewResolver(nil)); err == nil {
+		t.Fatal("expected error")
+	}
+	if flaky.calls != 1 {
+		t.Errorf("calls = %d, want 1 (non-retryable errors should not be retried)", flaky.calls)
+	}
+}
+
+func TestExecuteStepWithRetry_NavigationTimeoutRetried(t *testing.T) {
+	flaky := &flakyActivity{
+		failures: 1,
+		err:      fmt.Errorf("navigate: %w", &w3pilot.TimeoutError{Selector: "navigation", Timeout: 30000, Reason: "navigation did not complete"}),
+	}
+	registry := activity.NewRegistry()
+	registry.Register(flaky)
+	e := &Executor{
+		config:   ExecutorConfig{DefaultTimeout: time.Second},
+		registry: registry,
+		logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	step := &Step{Activity: "test.flaky", Retry: &RetryConfig{MaxAttempts: 3, Delay: Duration(time.Millisecond)}}
+	env := activity.NewEnvironment(nil, "", e.logger)
+	if _, err := e.executeStepWithRetry(context.Background(), step, env, NewResolver(nil)); err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+	if flaky.calls != 2 {
+		t.Errorf("calls = %d, want 2", flaky.calls)
+	}
+}
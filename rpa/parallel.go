@@ -0,0 +1,150 @@
+package rpa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/plexusone/w3pilot"
+	"github.com/plexusone/w3pilot/rpa/activity"
+)
+
+// branchOutcome is the result of running one parallel branch.
+type branchOutcome struct {
+	name      string
+	result    *WorkflowResult
+	variables map[string]any
+	err       error
+}
+
+// runParallel executes a parallel step's branches concurrently and merges
+// their step results and stored variables back in branch order.
+func (e *Executor) runParallel(ctx context.Context, step *Step, env *activity.Environment, resolver *Resolver, result *WorkflowResult) error {
+	cfg := step.Parallel
+
+	limit := cfg.MaxConcurrency
+	if limit <= 0 {
+		limit = DefaultMaxConcurrency
+	}
+
+	// Branches start from a snapshot of the current variables
+	snapshot := make(map[string]any, len(resolver.Variables()))
+	for k, v := range resolver.Variables() {
+		snapshot[k] = v
+	}
+
+	branchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	e.logger.Info("running parallel branches", "step", step.GetID(), "branches", len(cfg.Branches), "maxConcurrency", limit)
+
+	outcomes := make([]branchOutcome, len(cfg.Branches))
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+
+	for i := range cfg.Branches {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+			case <-branchCtx.Done():
+				outcomes[i] = branchOutcome{name: branchName(&cfg.Branches[i], i), err: branchCtx.Err()}
+				return
+			}
+			defer func() { <-sem }()
+
+			outcomes[i] = e.runBranch(branchCtx, &cfg.Branches[i], i, env, snapshot)
+			if outcomes[i].err != nil && cfg.FailFast {
+				cancel()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, outcome := range outcomes {
+		if outcome.result != nil {
+			for _, sr := range outcome.result.Steps {
+				result.AddStep(sr)
+			}
+			for _, shot := range outcome.result.Screenshots {
+				result.AddScreenshot(shot)
+			}
+		}
+
+		// Merge variables the branch added or changed
+		for k, v := range outcome.variables {
+			if old, ok := snapshot[k]; ok && reflect.DeepEqual(old, v) {
+				continue
+			}
+			resolver.Set(k, v)
+			env.Variables[k] = v
+		}
+
+		if outcome.err != nil {
+			// Siblings cancelled by FailFast are not failures in their own right
+			if errors.Is(outcome.err, context.Canceled) && ctx.Err() == nil {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("branch %s: %w", outcome.name, outcome.err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// runBranch runs one branch sequentially in its own page with its own
+// copy of the workflow variables.
+func (e *Executor) runBranch(ctx context.Context, branch *ParallelBranch, index int, parent *activity.Environment, variables map[string]any) branchOutcome {
+	name := branchName(branch, index)
+	logger := e.logger.With("branch", name)
+
+	vars := make(map[string]any, len(variables))
+	for k, v := range variables {
+		vars[k] = v
+	}
+	resolver := NewResolver(vars)
+
+	outcome := branchOutcome{name: name, result: NewWorkflowResult(name)}
+
+	var page *w3pilot.Pilot
+	if parent.Pilot != nil {
+		p, err := parent.Pilot.NewPage(ctx)
+		if err != nil {
+			outcome.err = fmt.Errorf("failed to open page: %w", err)
+			return outcome
+		}
+		defer func() {
+			if err := p.Close(context.WithoutCancel(ctx)); err != nil {
+				logger.Warn("failed to close branch page", "error", err)
+			}
+		}()
+		page = p
+	}
+
+	env := activity.NewEnvironment(page, parent.WorkDir, logger)
+	env.Variables = resolver.Variables()
+	env.Headless = parent.Headless
+	if parent.Pause != nil {
+		// Prefix pause IDs with the branch so unnamed pause steps in
+		// sibling branches can be told apart
+		env.Pause = func(ctx context.Context, stepID, message string) error {
+			return parent.Pause(ctx, name+"/"+stepID, message)
+		}
+	}
+
+	outcome.err = e.runSteps(ctx, branch.Steps, env, resolver, outcome.result)
+	outcome.variables = resolver.Variables()
+	return outcome
+}
+
+func branchName(branch *ParallelBranch, index int) string {
+	if branch.Name != "" {
+		return branch.Name
+	}
+	return fmt.Sprintf("branch-%d", index)
+}
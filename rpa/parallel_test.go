@@ -0,0 +1,139 @@
+package rpa
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/plexusone/w3pilot/rpa/activity"
+)
+
+// echoActivity returns its "value" param, or fails when "fail" is set.
+type echoActivity struct {
+	running atomic.Int32
+	peak    atomic.Int32
+}
+
+func (a *echoActivity) Name() string { return "test.echo" }
+
+func (a *echoActivity) Execute(ctx context.Context, params map[string]any, env *activity.Environment) (any, error) {
+	n := a.running.Add(1)
+	defer a.running.Add(-1)
+	for {
+		peak := a.peak.Load()
+		if n <= peak || a.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if activity.GetBool(params, "fail") {
+		return nil, errors.New("boom")
+	}
+	return params["value"], nil
+}
+
+func newTestExecutor(act activity.Activity) *Executor {
+	registry := activity.NewRegistry()
+	registry.Register(act)
+	return &Executor{
+		config:   ExecutorConfig{DefaultTimeout: time.Second},
+		registry: registry,
+		logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+}
+
+func TestRunParallel_MergesStoredVariables(t *testing.T) {
+	echo := &echoActivity{}
+	e := newTestExecutor(echo)
+
+	branch := func(name, value string) ParallelBranch {
+		return ParallelBranch{Name: name, Steps: []Step{
+			{Name: name, Activity: "test.echo", Params: map[string]any{"value": "${prefix}-" + value}, Store: name},
+		}}
+	}
+	steps := []Step{{
+		Name: "scrape",
+		Parallel: &ParallelConfig{
+			MaxConcurrency: 2,
+			Branches:       []ParallelBranch{branch("a", "1"), branch("b", "2"), branch("c", "3")},
+		},
+	}}
+
+	resolver := NewResolver(map[string]any{"prefix": "p"})
+	env := activity.NewEnvironment(nil, t.TempDir(), e.logger)
+	env.Variables = resolver.Variables()
+	result := NewWorkflowResult("test")
+
+	if err := e.runSteps(context.Background(), steps, env, resolver, result); err != nil {
+		t.Fatalf("runSteps failed: %v", err)
+	}
+
+	for name, want := range map[string]string{"a": "p-1", "b": "p-2", "c": "p-3"} {
+		if got, _ := resolver.GetString(name); got != want {
+			t.Errorf("variable %s = %q, want %q", name, got, want)
+		}
+	}
+	if len(result.Steps) != 3 || result.Steps[0].StepName != "a" || result.Steps[2].StepName != "c" {
+		t.Errorf("Expected step results merged in branch order, got %+v", result.Steps)
+	}
+	if peak := echo.peak.Load(); peak > 2 {
+		t.Errorf("Expected at most 2 concurrent branches, got %d", peak)
+	}
+}
+
+func TestRunParallel_BranchFailure(t *testing.T) {
+	e := newTestExecutor(&echoActivity{})
+
+	steps := []Step{{
+		Name: "work",
+		Parallel: &ParallelConfig{
+			Branches: []ParallelBranch{
+				{Name: "bad", Steps: []Step{{Name: "bad", Activity: "test.echo", Params: map[string]any{"fail": true}}}},
+				{Name: "good", Steps: []Step{{Name: "good", Activity: "test.echo", Params: map[string]any{"value": "ok"}, Store: "good"}}},
+			},
+		},
+	}}
+
+	resolver := NewResolver(nil)
+	env := activity.NewEnvironment(nil, t.TempDir(), e.logger)
+	env.Variables = resolver.Variables()
+
+	err := e.runSteps(context.Background(), steps, env, resolver, NewWorkflowResult("test"))
+	if err == nil || !strings.Contains(err.Error(), "branch bad") {
+		t.Fatalf("Expected branch bad error, got %v", err)
+	}
+
+	// Without FailFast the other branch still completes
+	if got, _ := resolver.GetString("good"); got != "ok" {
+		t.Errorf("Expected good branch to complete, got %q", got)
+	}
+}
+
+func TestParseParallel_NoActivityRequired(t *testing.T) {
+	yaml := `
+name: Parallel
+steps:
+  - name: Fan out
+    parallel:
+      maxConcurrency: 2
+      branches:
+        - name: one
+          steps:
+            - activity: util.log
+              params:
+                message: one
+`
+	wf, err := ParseBytes([]byte(yaml))
+	if err != nil {
+		t.Fatalf("ParseBytes failed: %v", err)
+	}
+	if !wf.Steps[0].HasParallel() || len(wf.Steps[0].Parallel.Branches) != 1 {
+		t.Errorf("Expected parsed parallel step, got %+v", wf.Steps[0])
+	}
+}
@@ -163,7 +163,7 @@ func validateWorkflow(wf *Workflow) []ParserValidationError {
 func validateParserStep(step *Step, path string) []ParserValidationError {
 	var errors []ParserValidationError
 
-	if step.Activity == "" {
+	if step.Activity == "" && step.Parallel == nil {
 		errors = append(errors, ParserValidationError{
 			Path:    path,
 			Field:   "activity",
@@ -171,6 +171,29 @@ func validateParserStep(step *Step, path string) []ParserValidationError {
 		})
 	}
 
+	if step.Parallel != nil {
+		if len(step.Parallel.Branches) == 0 {
+			errors = append(errors, ParserValidationError{
+				Path:    path + ".parallel",
+				Field:   "branches",
+				Message: "at least one branch is required for parallel",
+			})
+		}
+		if step.Parallel.MaxConcurrency < 0 {
+			errors = append(errors, ParserValidationError{
+				Path:    path + ".parallel",
+				Field:   "maxConcurrency",
+				Message: "maxConcurrency must not be negative",
+			})
+		}
+		for b, branch := range step.Parallel.Branches {
+			for i, nested := range branch.Steps {
+				nestedErrors := validateParserStep(&nested, fmt.Sprintf("%s.parallel.branches[%d].steps[%d]", path, b, i))
+				errors = append(errors, nestedErrors...)
+			}
+		}
+	}
+
 	if step.ForEach != nil {
 		if step.ForEach.Items == "" {
 			errors = append(errors, ParserValidationError{
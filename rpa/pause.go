@@ -0,0 +1,98 @@
+package rpa
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/plexusone/w3pilot/rpa/activity"
+)
+
+// PauseRequest describes a step waiting for operator approval.
+type PauseRequest struct {
+	// StepID uniquely identifies the paused step; pass it to
+	// Executor.Resume. Steps in parallel branches are prefixed with the
+	// branch name (e.g. "branch-1/control.pause"), and a suffix such as
+	// "#2" is added if the ID is still taken by another paused step.
+	StepID string
+
+	// Message is the prompt to show the operator.
+	Message string
+
+	// Done is closed when the step stops waiting, whether it was resumed
+	// or its timeout or the workflow ended first.
+	Done <-chan struct{}
+}
+
+// Resume delivers an operator decision to a step paused by control.pause.
+// With approve false the step fails with activity.ErrPauseRejected.
+// It returns an error if no step with that ID is currently paused.
+func (e *Executor) Resume(stepID string, approve bool) error {
+	e.pauseMu.Lock()
+	ch, ok := e.pauses[stepID]
+	if ok {
+		delete(e.pauses, stepID)
+	}
+	e.pauseMu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("step %s is not paused", stepID)
+	}
+	ch <- approve
+	return nil
+}
+
+// Paused returns the IDs of steps currently waiting for approval.
+func (e *Executor) Paused() []string {
+	e.pauseMu.Lock()
+	defer e.pauseMu.Unlock()
+
+	ids := make([]string, 0, len(e.pauses))
+	for id := range e.pauses {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// pause implements activity.PauseFunc.
+func (e *Executor) pause(ctx context.Context, stepID, message string) error {
+	ch := make(chan bool, 1)
+
+	done := make(chan struct{})
+
+	e.pauseMu.Lock()
+	if e.pauses == nil {
+		e.pauses = make(map[string]chan bool)
+	}
+	id := stepID
+	for n := 2; e.pauses[id] != nil; n++ {
+		id = fmt.Sprintf("%s#%d", stepID, n)
+	}
+	stepID = id
+	e.pauses[stepID] = ch
+	e.pauseMu.Unlock()
+
+	defer func() {
+		e.pauseMu.Lock()
+		if e.pauses[stepID] == ch {
+			delete(e.pauses, stepID)
+		}
+		e.pauseMu.Unlock()
+		close(done)
+	}()
+
+	e.logger.Info("workflow paused", "step", stepID, "message", message)
+	if e.config.OnPause != nil {
+		e.config.OnPause(PauseRequest{StepID: stepID, Message: message, Done: done})
+	}
+
+	select {
+	case approved := <-ch:
+		if !approved {
+			return activity.ErrPauseRejected
+		}
+		e.logger.Info("workflow resumed", "step", stepID)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,122 @@
+package rpa
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/plexusone/w3pilot/rpa/activity"
+)
+
+func newPauseExecutor(onPause func(e *Executor, req PauseRequest)) *Executor {
+	registry := activity.NewRegistry()
+	registry.Register(&activity.PauseActivity{})
+	e := &Executor{
+		config:   ExecutorConfig{DefaultTimeout: 20 * time.Millisecond},
+		registry: registry,
+		logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	e.config.OnPause = func(req PauseRequest) { go onPause(e, req) }
+	return e
+}
+
+func runPauseStep(e *Executor, params map[string]any) error {
+	resolver := NewResolver(nil)
+	env := activity.NewEnvironment(nil, "", e.logger)
+	env.Variables = resolver.Variables()
+	env.Pause = e.pause
+
+	steps := []Step{{ID: "approve-payment", Activity: "control.pause", Params: params}}
+	return e.runSteps(context.Background(), steps, env, resolver, NewWorkflowResult("test"))
+}
+
+func TestPause_Resume(t *testing.T) {
+	e := newPauseExecutor(func(e *Executor, req PauseRequest) {
+		// Resume after the default step timeout to show it does not apply
+		time.Sleep(50 * time.Millisecond)
+		if req.StepID != "approve-payment" || req.Message != "Submit payment?" {
+			t.Errorf("Unexpected pause request: %+v", req)
+		}
+		if err := e.Resume(req.StepID, true); err != nil {
+			t.Errorf("Resume failed: %v", err)
+		}
+	})
+
+	if err := runPauseStep(e, map[string]any{"message": "Submit payment?"}); err != nil {
+		t.Fatalf("Expected approved pause to succeed, got %v", err)
+	}
+	if len(e.Paused()) != 0 {
+		t.Errorf("Expected no paused steps, got %v", e.Paused())
+	}
+}
+
+func TestPause_RejectAndTimeout(t *testing.T) {
+	e := newPauseExecutor(func(e *Executor, req PauseRequest) {
+		_ = e.Resume(req.StepID, false)
+	})
+	if err := runPauseStep(e, nil); !errors.Is(err, activity.ErrPauseRejected) {
+		t.Errorf("Expected ErrPauseRejected, got %v", err)
+	}
+
+	e = newPauseExecutor(func(e *Executor, req PauseRequest) {})
+	if err := runPauseStep(e, map[string]any{"timeout": "30ms"}); err == nil {
+		t.Error("Expected timeout error")
+	}
+	if err := e.Resume("approve-payment", true); err == nil {
+		t.Error("Expected error resuming a step that is no longer paused")
+	}
+}
+
+func TestPause_ParallelBranchesGetUniqueIDs(t *testing.T) {
+	var mu sync.Mutex
+	var ids []string
+	e := newPauseExecutor(func(e *Executor, req PauseRequest) {
+		mu.Lock()
+		ids = append(ids, req.StepID)
+		mu.Unlock()
+		if err := e.Resume(req.StepID, true); err != nil {
+			t.Errorf("Resume(%q) failed: %v", req.StepID, err)
+		}
+	})
+
+	pause := []Step{{Activity: "control.pause"}}
+	steps := []Step{{
+		Name: "review",
+		Parallel: &ParallelConfig{
+			Branches: []ParallelBranch{{Steps: pause}, {Steps: pause}},
+		},
+	}}
+
+	resolver := NewResolver(nil)
+	env := activity.NewEnvironment(nil, "", e.logger)
+	env.Variables = resolver.Variables()
+	env.Pause = e.pause
+	if err := e.runSteps(context.Background(), steps, env, resolver, NewWorkflowResult("test")); err != nil {
+		t.Fatalf("Expected both pauses to be approved, got %v", err)
+	}
+
+	sort.Strings(ids)
+	if want := []string{"branch-0/control.pause", "branch-1/control.pause"}; !slices.Equal(ids, want) {
+		t.Errorf("Expected pause IDs %v, got %v", want, ids)
+	}
+}
+
+func TestPause_DoneOnTimeout(t *testing.T) {
+	done := make(chan (<-chan struct{}), 1)
+	e := newPauseExecutor(func(e *Executor, req PauseRequest) { done <- req.Done })
+
+	if err := runPauseStep(e, map[string]any{"timeout": "30ms"}); err == nil {
+		t.Fatal("Expected timeout error")
+	}
+	select {
+	case <-<-done:
+	case <-time.After(time.Second):
+		t.Error("Expected Done to be closed after the pause timed out")
+	}
+}
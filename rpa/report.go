@@ -0,0 +1,153 @@
+package rpa
+
+import (
+	"bytes"
+	"html/template"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// WriteJSON writes the result as indented JSON to path, creating parent
+// directories as needed.
+func (r *WorkflowResult) WriteJSON(path string) error {
+	data, err := r.JSON()
+	if err != nil {
+		return err
+	}
+	return writeReportFile(path, data)
+}
+
+// WriteHTML writes a self-contained HTML report to path, creating parent
+// directories as needed.
+func (r *WorkflowResult) WriteHTML(path string) error {
+	data, err := r.HTML()
+	if err != nil {
+		return err
+	}
+	return writeReportFile(path, data)
+}
+
+// HTML renders the result as a self-contained HTML report: a summary
+// followed by a step timeline with status, duration, and retries.
+// Screenshots are embedded inline next to the step that captured them;
+// screenshots not tied to a step are listed at the end.
+func (r *WorkflowResult) HTML() ([]byte, error) {
+	type reportStep struct {
+		StepResult
+		Index       int
+		Offset      time.Duration
+		Screenshots []Screenshot
+	}
+
+	// Attach screenshots to the last step with a matching ID
+	byStep := make(map[string]int)
+	for i, s := range r.Steps {
+		byStep[s.StepID] = i
+	}
+
+	steps := make([]reportStep, len(r.Steps))
+	for i, s := range r.Steps {
+		steps[i] = reportStep{
+			StepResult: s,
+			Index:      i + 1,
+			Offset:     s.StartTime.Sub(r.StartTime),
+		}
+		if s.Screenshot != "" {
+			steps[i].Screenshots = append(steps[i].Screenshots, Screenshot{
+				StepID:    s.StepID,
+				Timestamp: s.EndTime,
+				Data:      s.Screenshot,
+			})
+		}
+	}
+
+	var unattached []Screenshot
+	for _, shot := range r.Screenshots {
+		if i, ok := byStep[shot.StepID]; ok && shot.StepID != "" {
+			steps[i].Screenshots = append(steps[i].Screenshots, shot)
+			continue
+		}
+		unattached = append(unattached, shot)
+	}
+
+	data := struct {
+		*WorkflowResult
+		Timeline    []reportStep
+		Screenshots []Screenshot
+	}{
+		WorkflowResult: r,
+		Timeline:       steps,
+		Screenshots:    unattached,
+	}
+
+	var buf bytes.Buffer
+	if err := reportTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeReportFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"round": func(d time.Duration) time.Duration { return d.Round(time.Millisecond) },
+	"img": func(data string) template.URL {
+		return template.URL("data:image/png;base64," + data) //nolint:gosec // base64 PNG data captured by the executor
+	},
+	"time": func(t time.Time) string { return t.Format(time.RFC3339) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Workflow Results: {{.WorkflowName}}</title>
+<style>
+body { font-family: sans-serif; margin: 20px; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #ddd; padding: 8px; text-align: left; vertical-align: top; }
+th { background-color: #f2f2f2; }
+.success { color: green; }
+.failure { color: red; }
+.skipped { color: gray; }
+.error { font-family: monospace; white-space: pre-wrap; }
+.shot img { max-width: 640px; border: 1px solid #ccc; margin: 4px 0; }
+.shot p { margin: 4px 0; color: #555; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>Workflow: {{.WorkflowName}}</h1>
+<p><strong>Status:</strong> <span class="{{.Status}}">{{.Status}}</span></p>
+<p><strong>Started:</strong> {{time .StartTime}}</p>
+<p><strong>Duration:</strong> {{round .Duration}}</p>
+<p><strong>Steps:</strong> {{.TotalSteps}} total, {{.SuccessCount}} success, {{.FailureCount}} failed, {{.SkippedCount}} skipped</p>
+{{if .Error}}<p><strong>Error:</strong> <span class="error">{{.Error}}</span></p>{{end}}
+<h2>Timeline</h2>
+<table>
+<tr><th>#</th><th>Step</th><th>Activity</th><th>Status</th><th>Start</th><th>Duration</th><th>Retries</th><th>Error</th></tr>
+{{range .Timeline}}<tr>
+<td>{{.Index}}</td>
+<td>{{if .StepName}}{{.StepName}}{{else}}{{.StepID}}{{end}}</td>
+<td>{{.Activity}}</td>
+<td class="{{.Status}}">{{.Status}}</td>
+<td>+{{round .Offset}}</td>
+<td>{{round .Duration}}</td>
+<td>{{.Retries}}</td>
+<td class="error">{{.Error}}</td>
+</tr>
+{{range .Screenshots}}<tr><td></td><td colspan="7" class="shot">
+{{if .Reason}}<p>{{.Reason}}</p>{{end}}<img src="{{img .Data}}" alt="screenshot">
+</td></tr>
+{{end}}{{end}}</table>
+{{if .Screenshots}}<h2>Screenshots</h2>
+{{range .Screenshots}}<div class="shot">
+<p>{{time .Timestamp}}{{if .Reason}} — {{.Reason}}{{end}}</p>
+<img src="{{img .Data}}" alt="screenshot">
+</div>
+{{end}}{{end}}</body>
+</html>
+`))
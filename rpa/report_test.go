@@ -0,0 +1,73 @@
+package rpa
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWorkflowResult_WriteHTML(t *testing.T) {
+	result := NewWorkflowResult("Checkout <test>")
+
+	ok := NewStepResult(&Step{Name: "Open cart", Activity: "browser.navigate"})
+	ok.Complete(StatusSuccess, nil, nil)
+	result.AddStep(*ok)
+
+	failed := NewStepResult(&Step{ID: "pay", Name: "Pay", Activity: "browser.click"})
+	failed.Retries = 2
+	failed.Complete(StatusFailure, nil, errors.New("element not found"))
+	result.AddStep(*failed)
+
+	result.AddScreenshot(Screenshot{StepID: "pay", Data: "c3RlcA==", Reason: "error: element not found"})
+	result.AddScreenshot(Screenshot{Data: "b3RoZXI=", Reason: "manual"})
+	result.Complete(StatusFailure, errors.New("step pay failed"))
+
+	path := filepath.Join(t.TempDir(), "out", "report.html")
+	if err := result.WriteHTML(path); err != nil {
+		t.Fatalf("WriteHTML failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+
+	if !strings.Contains(html, "Checkout &lt;test&gt;") {
+		t.Error("Expected escaped workflow name")
+	}
+
+	// The step screenshot appears after the failing step and before the
+	// trailing section with unattached screenshots.
+	payIdx := strings.Index(html, "element not found</td>")
+	stepShot := strings.Index(html, "data:image/png;base64,c3RlcA==")
+	sectionIdx := strings.Index(html, "<h2>Screenshots</h2>")
+	otherShot := strings.Index(html, "data:image/png;base64,b3RoZXI=")
+	if payIdx < 0 || stepShot < payIdx || sectionIdx < stepShot || otherShot < sectionIdx {
+		t.Errorf("Unexpected screenshot placement: step=%d shot=%d section=%d other=%d", payIdx, stepShot, sectionIdx, otherShot)
+	}
+}
+
+func TestWorkflowResult_WriteJSON(t *testing.T) {
+	result := NewWorkflowResult("Export")
+	result.Complete(StatusSuccess, nil)
+
+	path := filepath.Join(t.TempDir(), "result.json")
+	if err := result.WriteJSON(path); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded WorkflowResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if decoded.WorkflowName != "Export" || decoded.Status != StatusSuccess {
+		t.Errorf("Unexpected decoded result: %+v", decoded)
+	}
+}
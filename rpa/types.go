@@ -78,5 +78,8 @@ const DefaultTimeout = 30 * time.Second
 // DefaultRetryDelay is the default delay between retries.
 const DefaultRetryDelay = time.Second
 
+// DefaultMaxConcurrency is the default number of parallel branches run at once.
+const DefaultMaxConcurrency = 4
+
 // DefaultMaxRetries is the default maximum number of retry attempts.
 const DefaultMaxRetries = 3
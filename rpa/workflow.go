@@ -83,6 +83,9 @@ type Step struct {
 
 	// Steps contains nested steps (for control flow activities).
 	Steps []Step `yaml:"steps,omitempty" json:"steps,omitempty"`
+
+	// Parallel runs groups of steps concurrently. A parallel step needs no activity.
+	Parallel *ParallelConfig `yaml:"parallel,omitempty" json:"parallel,omitempty"`
 }
 
 // ForEachConfig configures iteration over a collection.
@@ -97,7 +100,40 @@ type ForEachConfig struct {
 	Steps []Step `yaml:"steps" json:"steps"`
 }
 
-// RetryConfig configures automatic retry behavior.
+// ParallelConfig configures concurrent execution of step groups.
+//
+// Each branch runs in its own browser page (tab) so branches never share
+// navigation state; the page is closed when the branch finishes. Branches
+// start with a copy of the workflow variables, and variables they store are
+// merged back in branch order once all branches finish, so a later branch
+// wins if two branches store the same name.
+//
+// By default every branch runs to completion and the parallel step fails if
+// any branch failed. With FailFast, the first failure cancels the remaining
+// branches.
+type ParallelConfig struct {
+	// MaxConcurrency limits how many branches run at once (default: DefaultMaxConcurrency).
+	MaxConcurrency int `yaml:"maxConcurrency,omitempty" json:"maxConcurrency,omitempty"`
+
+	// FailFast cancels the other branches as soon as one fails.
+	FailFast bool `yaml:"failFast,omitempty" json:"failFast,omitempty"`
+
+	// Branches are the step groups to run concurrently.
+	Branches []ParallelBranch `yaml:"branches" json:"branches"`
+}
+
+// ParallelBranch is one group of sequential steps within a parallel step.
+type ParallelBranch struct {
+	// Name identifies the branch in logs and errors.
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+
+	// Steps are executed sequentially within the branch.
+	Steps []Step `yaml:"steps" json:"steps"`
+}
+
+// RetryConfig configures automatic retry behavior. Errors that
+// w3pilot.IsRetryable reports as permanent, such as an element that was
+// never found, end the step without further attempts.
 type RetryConfig struct {
 	// MaxAttempts is the maximum number of retry attempts.
 	MaxAttempts int `yaml:"maxAttempts" json:"maxAttempts"`
@@ -126,6 +162,9 @@ func (s *Step) GetID() string {
 	if s.Name != "" {
 		return s.Name
 	}
+	if s.Activity == "" && s.Parallel != nil {
+		return "parallel"
+	}
 	return s.Activity
 }
 
@@ -147,6 +186,11 @@ func (s *Step) HasForEach() bool {
 	return s.ForEach != nil
 }
 
+// HasParallel returns true if the step runs parallel branches.
+func (s *Step) HasParallel() bool {
+	return s.Parallel != nil
+}
+
 // HasRetry returns true if the step has retry configuration.
 func (s *Step) HasRetry() bool {
 	return s.Retry != nil && s.Retry.MaxAttempts > 0
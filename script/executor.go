@@ -0,0 +1,510 @@
+package script
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	w3pilot "github.com/plexusone/w3pilot"
+)
+
+// ResolveStep applies script-level defaults to step before it runs: a step
+// without its own Timeout inherits the script Timeout, and relative navigate
+// URLs are joined to BaseURL. Wait steps keep an empty Timeout because it
+// doubles as the sleep duration.
+func (s *Script) ResolveStep(step Step) Step {
+	if step.Timeout == "" && step.Action != ActionWait {
+		step.Timeout = s.Timeout
+	}
+	if (step.Action == ActionNavigate || step.Action == ActionGo) && s.BaseURL != "" {
+		step.URL = joinBaseURL(s.BaseURL, step.URL)
+	}
+	return step
+}
+
+// joinBaseURL prefixes a relative URL (one without a scheme) with base.
+// Absolute URLs, including protocol-relative ones, are returned unchanged.
+func joinBaseURL(base, ref string) string {
+	if ref == "" || strings.HasPrefix(ref, "//") {
+		return ref
+	}
+	if u, err := url.Parse(ref); err == nil && u.Scheme != "" {
+		return ref
+	}
+	if strings.HasPrefix(ref, "?") || strings.HasPrefix(ref, "#") {
+		return base + ref
+	}
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(ref, "/")
+}
+
+// MatchesTags reports whether the step should run when the given tags are
+// selected: true if no tags are selected or the step has any of them.
+func (s Step) MatchesTags(tags []string) bool {
+	if len(tags) == 0 {
+		return true
+	}
+	for _, want := range tags {
+		for _, have := range s.Tags {
+			if have == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ExecuteStep runs a single script step against pilot. prevStepStart is when
+// the preceding step began; waitForRequest/waitForResponse steps also match
+// network activity since then. Pass the zero time to only match new activity.
+//
+// A step's Timeout bounds how long it waits for its element and for
+// actionability; use Script.ResolveStep to fall back to the script Timeout.
+func ExecuteStep(ctx context.Context, pilot *w3pilot.Pilot, step Step, prevStepStart time.Time) error {
+	var timeout time.Duration
+	if step.Timeout != "" && step.Action != ActionWait {
+		d, err := time.ParseDuration(step.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout: %w", err)
+		}
+		timeout = d
+	}
+	findOpts := &w3pilot.FindOptions{Timeout: timeout}
+	actionOpts := &w3pilot.ActionOptions{Timeout: timeout}
+
+	switch step.Action {
+	case ActionNavigate, ActionGo:
+		return pilot.Go(ctx, step.URL)
+
+	case ActionBack:
+		return pilot.Back(ctx)
+
+	case ActionForward:
+		return pilot.Forward(ctx)
+
+	case ActionReload:
+		return pilot.Reload(ctx)
+
+	case ActionClick:
+		el, err := pilot.Find(ctx, step.Selector, findOpts)
+		if err != nil {
+			return err
+		}
+		return el.Click(ctx, actionOpts)
+
+	case ActionDblClick:
+		el, err := pilot.Find(ctx, step.Selector, findOpts)
+		if err != nil {
+			return err
+		}
+		return el.DblClick(ctx, actionOpts)
+
+	case ActionType:
+		el, err := pilot.Find(ctx, step.Selector, findOpts)
+		if err != nil {
+			return err
+		}
+		text := step.Text
+		if text == "" {
+			text = step.Value
+		}
+		return el.Type(ctx, text, actionOpts)
+
+	case ActionFill:
+		el, err := pilot.Find(ctx, step.Selector, findOpts)
+		if err != nil {
+			return err
+		}
+		value := step.Value
+		if value == "" {
+			value = step.Text
+		}
+		return el.Fill(ctx, value, actionOpts)
+
+	case ActionClear:
+		el, err := pilot.Find(ctx, step.Selector, findOpts)
+		if err != nil {
+			return err
+		}
+		return el.Clear(ctx, actionOpts)
+
+	case ActionPress:
+		el, err := pilot.Find(ctx, step.Selector, findOpts)
+		if err != nil {
+			return err
+		}
+		return el.Press(ctx, step.Key, actionOpts)
+
+	case ActionCheck:
+		el, err := pilot.Find(ctx, step.Selector, findOpts)
+		if err != nil {
+			return err
+		}
+		return el.Check(ctx, actionOpts)
+
+	case ActionUncheck:
+		el, err := pilot.Find(ctx, step.Selector, findOpts)
+		if err != nil {
+			return err
+		}
+		return el.Uncheck(ctx, actionOpts)
+
+	case ActionSetChecked:
+		el, err := pilot.Find(ctx, step.Selector, findOpts)
+		if err != nil {
+			return err
+		}
+		return el.SetChecked(ctx, step.Checked, actionOpts)
+
+	case ActionSelect:
+		el, err := pilot.Find(ctx, step.Selector, findOpts)
+		if err != nil {
+			return err
+		}
+		selectValues := w3pilot.SelectOptionValues{Values: []string{step.Value}}
+		return el.SelectOption(ctx, selectValues, actionOpts)
+
+	case ActionHover:
+		el, err := pilot.Find(ctx, step.Selector, findOpts)
+		if err != nil {
+			return err
+		}
+		return el.Hover(ctx, actionOpts)
+
+	case ActionFocus:
+		el, err := pilot.Find(ctx, step.Selector, findOpts)
+		if err != nil {
+			return err
+		}
+		return el.Focus(ctx, actionOpts)
+
+	case ActionScrollIntoView:
+		el, err := pilot.Find(ctx, step.Selector, findOpts)
+		if err != nil {
+			return err
+		}
+		return el.ScrollIntoView(ctx, actionOpts)
+
+	case ActionTap:
+		el, err := pilot.Find(ctx, step.Selector, findOpts)
+		if err != nil {
+			return err
+		}
+		return el.Tap(ctx, actionOpts)
+
+	case ActionScreenshot:
+		data, err := pilot.ScreenshotWithOptions(ctx, &w3pilot.ScreenshotOptions{FullPage: step.FullPage})
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(step.File, data, 0600)
+
+	case ActionPDF:
+		data, err := pilot.PDF(ctx, nil)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(step.File, data, 0600)
+
+	case ActionEval:
+		_, err := pilot.Evaluate(ctx, step.Script)
+		return err
+
+	case ActionWait:
+		duration := step.Duration
+		if duration == "" {
+			duration = step.Timeout
+		}
+		d, err := time.ParseDuration(duration)
+		if err != nil {
+			return fmt.Errorf("invalid duration: %w", err)
+		}
+		time.Sleep(d)
+		return nil
+
+	case ActionWaitForSelector:
+		_, err := pilot.WaitForSelector(ctx, step.Selector, step.State, timeout)
+		return err
+
+	case ActionWaitForURL:
+		if timeout == 0 {
+			timeout = 30 * time.Second
+		}
+		return pilot.WaitForURL(ctx, step.Pattern, timeout)
+
+	case ActionWaitForRequest, ActionWaitForResponse:
+		opts := &w3pilot.WaitForNetworkOptions{
+			Timeout: 30 * time.Second,
+			Since:   prevStepStart,
+		}
+		if timeout > 0 {
+			opts.Timeout = timeout
+		}
+		if step.Action == ActionWaitForRequest {
+			_, err := pilot.WaitForRequest(ctx, step.Pattern, opts)
+			return err
+		}
+		_, err := pilot.WaitForResponse(ctx, step.Pattern, opts)
+		return err
+
+	case ActionWaitForLoad:
+		state := step.LoadState
+		if state == "" {
+			state = "load"
+		}
+		if timeout == 0 {
+			timeout = 30 * time.Second
+		}
+		return pilot.WaitForLoad(ctx, state, timeout)
+
+	case ActionSetViewport:
+		viewport := w3pilot.Viewport{Width: step.Width, Height: step.Height}
+		return pilot.SetViewport(ctx, viewport)
+
+	case ActionSaveStorageState:
+		state, err := pilot.StorageState(ctx)
+		if err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(state, "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(step.File, data, 0600)
+
+	case ActionLoadStorageState:
+		data, err := os.ReadFile(step.File)
+		if err != nil {
+			return err
+		}
+		var state w3pilot.StorageState
+		if err := json.Unmarshal(data, &state); err != nil {
+			return fmt.Errorf("invalid storage state file: %w", err)
+		}
+		return pilot.SetStorageState(ctx, &state)
+
+	case ActionKeyboardPress:
+		kb, err := pilot.Keyboard(ctx)
+		if err != nil {
+			return err
+		}
+		return kb.Press(ctx, step.Key)
+
+	case ActionKeyboardType:
+		kb, err := pilot.Keyboard(ctx)
+		if err != nil {
+			return err
+		}
+		text := step.Text
+		if text == "" {
+			text = step.Value
+		}
+		return kb.Type(ctx, text)
+
+	case ActionMouseClick:
+		mouse, err := pilot.Mouse(ctx)
+		if err != nil {
+			return err
+		}
+		return mouse.Click(ctx, step.X, step.Y, nil)
+
+	case ActionMouseMove:
+		mouse, err := pilot.Mouse(ctx)
+		if err != nil {
+			return err
+		}
+		return mouse.Move(ctx, step.X, step.Y)
+
+	// Assertions
+	case ActionAssertText:
+		el, err := pilot.Find(ctx, step.Selector, findOpts)
+		if err != nil {
+			return err
+		}
+		text, err := el.Text(ctx)
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(text, step.Expected) {
+			return fmt.Errorf("text assertion failed: expected %q, got %q", step.Expected, text)
+		}
+		return nil
+
+	case ActionAssertElement:
+		_, err := pilot.Find(ctx, step.Selector, findOpts)
+		return err
+
+	case ActionAssertValue:
+		el, err := pilot.Find(ctx, step.Selector, findOpts)
+		if err != nil {
+			return err
+		}
+		value, err := el.Value(ctx)
+		if err != nil {
+			return err
+		}
+		if value != step.Expected {
+			return fmt.Errorf("value assertion failed: expected %q, got %q", step.Expected, value)
+		}
+		return nil
+
+	case ActionAssertVisible:
+		el, err := pilot.Find(ctx, step.Selector, findOpts)
+		if err != nil {
+			return err
+		}
+		visible, err := el.IsVisible(ctx)
+		if err != nil {
+			return err
+		}
+		if !visible {
+			return fmt.Errorf("visibility assertion failed: element %s is not visible", step.Selector)
+		}
+		return nil
+
+	case ActionAssertHidden:
+		el, err := pilot.Find(ctx, step.Selector, findOpts)
+		if err != nil {
+			// Element not found is acceptable for assertHidden
+			return nil
+		}
+		hidden, err := el.IsHidden(ctx)
+		if err != nil {
+			return err
+		}
+		if !hidden {
+			return fmt.Errorf("hidden assertion failed: element %s is visible", step.Selector)
+		}
+		return nil
+
+	case ActionAssertURL:
+		pageURL, err := pilot.URL(ctx)
+		if err != nil {
+			return err
+		}
+		if step.Pattern != "" {
+			matched, err := regexp.MatchString(step.Pattern, pageURL)
+			if err != nil {
+				return fmt.Errorf("invalid URL pattern: %w", err)
+			}
+			if !matched {
+				return fmt.Errorf("URL assertion failed: %q does not match pattern %q", pageURL, step.Pattern)
+			}
+		} else if !strings.Contains(pageURL, step.Expected) {
+			return fmt.Errorf("URL assertion failed: expected %q in %q", step.Expected, pageURL)
+		}
+		return nil
+
+	case ActionAssertTitle:
+		title, err := pilot.Title(ctx)
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(title, step.Expected) {
+			return fmt.Errorf("title assertion failed: expected %q, got %q", step.Expected, title)
+		}
+		return nil
+
+	case ActionAssertAttribute:
+		el, err := pilot.Find(ctx, step.Selector, findOpts)
+		if err != nil {
+			return err
+		}
+		value, err := el.GetAttribute(ctx, step.Attribute)
+		if err != nil {
+			return err
+		}
+		if value != step.Expected {
+			return fmt.Errorf("attribute assertion failed: expected %s=%q, got %q", step.Attribute, step.Expected, value)
+		}
+		return nil
+
+	case ActionAssertAccessibility:
+		return fmt.Errorf("assertAccessibility has moved to agent-a11y; use github.com/agentplexus/agent-a11y for accessibility testing")
+
+	default:
+		return fmt.Errorf("unknown action: %s", step.Action)
+	}
+}
+
+// DescribeStep returns a short human-readable summary of a step,
+// used when the step has no explicit name.
+func DescribeStep(step Step) string {
+	switch step.Action {
+	case ActionNavigate, ActionGo:
+		return fmt.Sprintf("navigate %s", step.URL)
+	case ActionClick:
+		return fmt.Sprintf("click %s", step.Selector)
+	case ActionDblClick:
+		return fmt.Sprintf("dblclick %s", step.Selector)
+	case ActionType:
+		return fmt.Sprintf("type %s", step.Selector)
+	case ActionFill:
+		return fmt.Sprintf("fill %s", step.Selector)
+	case ActionClear:
+		return fmt.Sprintf("clear %s", step.Selector)
+	case ActionPress:
+		return fmt.Sprintf("press %s on %s", step.Key, step.Selector)
+	case ActionCheck:
+		return fmt.Sprintf("check %s", step.Selector)
+	case ActionUncheck:
+		return fmt.Sprintf("uncheck %s", step.Selector)
+	case ActionSetChecked:
+		return fmt.Sprintf("setChecked %s=%t", step.Selector, step.Checked)
+	case ActionSelect:
+		return fmt.Sprintf("select %s", step.Selector)
+	case ActionHover:
+		return fmt.Sprintf("hover %s", step.Selector)
+	case ActionFocus:
+		return fmt.Sprintf("focus %s", step.Selector)
+	case ActionScreenshot:
+		return fmt.Sprintf("screenshot %s", step.File)
+	case ActionPDF:
+		return fmt.Sprintf("pdf %s", step.File)
+	case ActionSaveStorageState:
+		return fmt.Sprintf("saveStorageState %s", step.File)
+	case ActionLoadStorageState:
+		return fmt.Sprintf("loadStorageState %s", step.File)
+	case ActionEval:
+		return "eval javascript"
+	case ActionWait:
+		return fmt.Sprintf("wait %s", step.Duration)
+	case ActionWaitForSelector:
+		if step.State != "" {
+			return fmt.Sprintf("waitForSelector %s (%s)", step.Selector, step.State)
+		}
+		return fmt.Sprintf("waitForSelector %s", step.Selector)
+	case ActionWaitForURL:
+		return fmt.Sprintf("waitForUrl %s", step.Pattern)
+	case ActionWaitForRequest:
+		return fmt.Sprintf("waitForRequest %s", step.Pattern)
+	case ActionWaitForResponse:
+		return fmt.Sprintf("waitForResponse %s", step.Pattern)
+	case ActionWaitForLoad:
+		return fmt.Sprintf("waitForLoad %s", step.LoadState)
+	case ActionAssertText:
+		return fmt.Sprintf("assertText %s", step.Selector)
+	case ActionAssertElement:
+		return fmt.Sprintf("assertElement %s", step.Selector)
+	case ActionAssertVisible:
+		return fmt.Sprintf("assertVisible %s", step.Selector)
+	case ActionAssertHidden:
+		return fmt.Sprintf("assertHidden %s", step.Selector)
+	case ActionAssertURL:
+		return fmt.Sprintf("assertUrl %s", step.Expected)
+	case ActionAssertTitle:
+		return fmt.Sprintf("assertTitle %s", step.Expected)
+	case ActionAssertAccessibility:
+		standard := "wcag22aa"
+		if step.A11y != nil && step.A11y.Standard != "" {
+			standard = step.A11y.Standard
+		}
+		return fmt.Sprintf("assertAccessibility (%s)", standard)
+	default:
+		return string(step.Action)
+	}
+}
@@ -0,0 +1,71 @@
+package script
+
+import "testing"
+
+// TestScript_ResolveStep verifies that steps inherit the script timeout
+// unless they set their own, and that wait steps are left alone.
+func TestScript_ResolveStep(t *testing.T) {
+	s := &Script{Timeout: "10s"}
+
+	tests := []struct {
+		step Step
+		want string
+	}{
+		{Step{Action: ActionClick, Selector: "#a"}, "10s"},
+		{Step{Action: ActionWaitForSelector, Selector: "#a", Timeout: "60s"}, "60s"},
+		{Step{Action: ActionWait, Duration: "1s"}, ""},
+	}
+	for _, tt := range tests {
+		got := s.ResolveStep(tt.step)
+		if got.Timeout != tt.want {
+			t.Errorf("%s: expected timeout %q, got %q", tt.step.Action, tt.want, got.Timeout)
+		}
+	}
+}
+
+// TestScript_ResolveStep_BaseURL verifies that relative navigate URLs are
+// joined to BaseURL and absolute ones are left alone.
+func TestScript_ResolveStep_BaseURL(t *testing.T) {
+	s := &Script{BaseURL: "https://staging.example.com/app/"}
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"/login", "https://staging.example.com/app/login"},
+		{"login?next=/home", "https://staging.example.com/app/login?next=/home"},
+		{"https://other.example.com/", "https://other.example.com/"},
+		{"//cdn.example.com/x", "//cdn.example.com/x"},
+		{"about:blank", "about:blank"},
+	}
+	for _, tt := range tests {
+		got := s.ResolveStep(Step{Action: ActionNavigate, URL: tt.url})
+		if got.URL != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.url, tt.want, got.URL)
+		}
+	}
+
+	click := s.ResolveStep(Step{Action: ActionClick, URL: "/ignored"})
+	if click.URL != "/ignored" {
+		t.Errorf("Expected non-navigate step URL unchanged, got %q", click.URL)
+	}
+}
+
+// TestStep_MatchesTags verifies tag filtering for steps.
+func TestStep_MatchesTags(t *testing.T) {
+	smoke := Step{Action: ActionClick, Tags: []string{"smoke", "login"}}
+	untagged := Step{Action: ActionClick}
+
+	if !smoke.MatchesTags(nil) || !untagged.MatchesTags(nil) {
+		t.Error("Expected every step to match when no tags are selected")
+	}
+	if !smoke.MatchesTags([]string{"full", "login"}) {
+		t.Error("Expected step to match any selected tag")
+	}
+	if smoke.MatchesTags([]string{"full"}) {
+		t.Error("Expected step without the selected tag to be skipped")
+	}
+	if untagged.MatchesTags([]string{"smoke"}) {
+		t.Error("Expected untagged step to be skipped when tags are selected")
+	}
+}
@@ -0,0 +1,76 @@
+package script
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FormatFromPath returns the script format implied by a file extension:
+// "json" for .json and "yaml" for everything else.
+func FormatFromPath(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return "json"
+	}
+	return "yaml"
+}
+
+// Parse decodes a script in the given format ("yaml", "yml", or "json").
+// Unknown fields are rejected so that misspelled keys fail loudly.
+func Parse(data []byte, format string) (*Script, error) {
+	var s Script
+
+	switch format {
+	case "yaml", "yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&s); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML script: %w", err)
+		}
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&s); err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				return nil, fmt.Errorf("failed to parse JSON script: line %d: %w", lineAt(data, syntaxErr.Offset), err)
+			}
+			return nil, fmt.Errorf("failed to parse JSON script: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+
+	return &s, nil
+}
+
+// Marshal encodes a script in the given format ("yaml", "yml", or "json").
+// Fields are written in declaration order and empty fields are omitted.
+func Marshal(s *Script, format string) ([]byte, error) {
+	switch format {
+	case "yaml", "yml":
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(s); err != nil {
+			return nil, fmt.Errorf("failed to encode YAML script: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode YAML script: %w", err)
+		}
+		return buf.Bytes(), nil
+	case "json":
+		data, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode JSON script: %w", err)
+		}
+		return append(data, '\n'), nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
@@ -0,0 +1,45 @@
+package script
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestMarshal_RoundTrip verifies that converting YAML to JSON and back
+// preserves the script and omits empty fields.
+func TestMarshal_RoundTrip(t *testing.T) {
+	data := []byte(`name: Search
+variables:
+  q: w3pilot
+steps:
+  - action: navigate
+    url: https://example.com
+  - action: fill
+    selector: '#q'
+    value: ${q}
+`)
+
+	s, err := Parse(data, "yaml")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	jsonData, err := Marshal(s, "json")
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if strings.Contains(string(jsonData), `"selector": ""`) {
+		t.Errorf("Expected empty fields to be omitted, got %s", jsonData)
+	}
+
+	back, err := Parse(jsonData, "json")
+	if err != nil {
+		t.Fatalf("Parse of converted JSON failed: %v", err)
+	}
+	yamlData, err := Marshal(back, "yaml")
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(yamlData) != string(data) {
+		t.Errorf("Round trip changed the script:\n%s", yamlData)
+	}
+}
@@ -25,8 +25,17 @@ type Script struct {
 	// Variables defines reusable values that can be referenced in steps.
 	Variables map[string]string `json:"variables,omitempty" yaml:"variables,omitempty" jsonschema:"description=Reusable values referenced in steps as ${varName}"`
 
+	// Setup lists steps that run before Steps. If a setup step fails,
+	// Steps are skipped but Teardown still runs.
+	Setup []Step `json:"setup,omitempty" yaml:"setup,omitempty" jsonschema:"description=Steps run before the main steps"`
+
 	// Steps is the ordered list of automation steps to execute.
 	Steps []Step `json:"steps" yaml:"steps" jsonschema:"description=Ordered list of automation steps,required"`
+
+	// Teardown lists steps that always run after Setup and Steps, even when
+	// they fail. Teardown failures are reported but do not replace the
+	// original error.
+	Teardown []Step `json:"teardown,omitempty" yaml:"teardown,omitempty" jsonschema:"description=Steps that always run last, even after a failure"`
 }
 
 // Step represents a single automation action in a script.
@@ -37,8 +46,12 @@ type Step struct {
 	// Name is an optional human-readable description of the step.
 	Name string `json:"name,omitempty" yaml:"name,omitempty" jsonschema:"description=Human-readable description of the step"`
 
+	// Tags label the step for filtering, e.g. "smoke". When a run selects
+	// tags, main steps without a matching tag are skipped.
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty" jsonschema:"description=Labels for selecting steps to run (e.g. smoke)"`
+
 	// Action is the type of action to perform.
-	Action Action `json:"action" yaml:"action" jsonschema:"description=Type of action to perform,required,enum=navigate,enum=go,enum=back,enum=forward,enum=reload,enum=click,enum=dblclick,enum=type,enum=fill,enum=clear,enum=press,enum=check,enum=uncheck,enum=select,enum=setFiles,enum=hover,enum=focus,enum=scrollIntoView,enum=dragTo,enum=tap,enum=screenshot,enum=pdf,enum=eval,enum=wait,enum=waitForSelector,enum=waitForUrl,enum=waitForLoad,enum=setViewport,enum=newPage,enum=closePage,enum=keyboardPress,enum=keyboardType,enum=mouseClick,enum=mouseMove,enum=assertText,enum=assertElement,enum=assertValue,enum=assertVisible,enum=assertHidden,enum=assertUrl,enum=assertTitle,enum=assertAttribute,enum=assertAccessibility,enum=getText,enum=getValue,enum=getAttribute,enum=getUrl,enum=getTitle"`
+	Action Action `json:"action" yaml:"action" jsonschema:"description=Type of action to perform,required,enum=navigate,enum=go,enum=back,enum=forward,enum=reload,enum=click,enum=dblclick,enum=type,enum=fill,enum=clear,enum=press,enum=check,enum=uncheck,enum=setChecked,enum=select,enum=setFiles,enum=hover,enum=focus,enum=scrollIntoView,enum=dragTo,enum=tap,enum=screenshot,enum=pdf,enum=eval,enum=wait,enum=waitForSelector,enum=waitForUrl,enum=waitForLoad,enum=waitForRequest,enum=waitForResponse,enum=setViewport,enum=newPage,enum=closePage,enum=saveStorageState,enum=loadStorageState,enum=keyboardPress,enum=keyboardType,enum=mouseClick,enum=mouseMove,enum=assertText,enum=assertElement,enum=assertValue,enum=assertVisible,enum=assertHidden,enum=assertUrl,enum=assertTitle,enum=assertAttribute,enum=assertAccessibility,enum=getText,enum=getValue,enum=getAttribute,enum=getUrl,enum=getTitle"`
 
 	// Selector is the CSS selector for element actions.
 	Selector string `json:"selector,omitempty" yaml:"selector,omitempty" jsonschema:"description=CSS selector for element actions"`
@@ -58,8 +71,8 @@ type Step struct {
 	// Script is the JavaScript code for eval actions.
 	Script string `json:"script,omitempty" yaml:"script,omitempty" jsonschema:"description=JavaScript code for eval actions"`
 
-	// File is the output file path for screenshot/pdf actions.
-	File string `json:"file,omitempty" yaml:"file,omitempty" jsonschema:"description=Output file path for screenshot/pdf actions"`
+	// File is the file path for screenshot/pdf/storage state actions.
+	File string `json:"file,omitempty" yaml:"file,omitempty" jsonschema:"description=File path for screenshot/pdf/saveStorageState/loadStorageState actions"`
 
 	// Files is a list of file paths for file input actions.
 	Files []string `json:"files,omitempty" yaml:"files,omitempty" jsonschema:"description=File paths for file input actions"`
@@ -73,6 +86,9 @@ type Step struct {
 	// FullPage captures the full page for screenshot actions.
 	FullPage bool `json:"fullPage,omitempty" yaml:"fullPage,omitempty" jsonschema:"description=Capture full page for screenshots"`
 
+	// Checked is the desired checkbox state for setChecked actions.
+	Checked bool `json:"checked,omitempty" yaml:"checked,omitempty" jsonschema:"description=Desired checkbox state for setChecked actions"`
+
 	// Target is the destination element for drag actions.
 	Target string `json:"target,omitempty" yaml:"target,omitempty" jsonschema:"description=Destination selector for drag actions"`
 
@@ -91,8 +107,8 @@ type Step struct {
 	// State is the expected state for wait actions (visible, hidden, attached, detached).
 	State string `json:"state,omitempty" yaml:"state,omitempty" jsonschema:"description=Expected state for wait actions,enum=visible,enum=hidden,enum=attached,enum=detached"`
 
-	// Pattern is the URL pattern for waitForUrl actions.
-	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty" jsonschema:"description=URL pattern for waitForUrl actions"`
+	// Pattern is the URL pattern for waitForUrl, waitForRequest, and waitForResponse actions.
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty" jsonschema:"description=URL pattern for waitForUrl/waitForRequest/waitForResponse actions"`
 
 	// LoadState is the load state for waitForLoad actions.
 	LoadState string `json:"loadState,omitempty" yaml:"loadState,omitempty" jsonschema:"description=Load state for waitForLoad actions,enum=load,enum=domcontentloaded,enum=networkidle"`
@@ -161,10 +177,11 @@ const (
 	ActionPress    Action = "press"
 
 	// Form controls
-	ActionCheck    Action = "check"
-	ActionUncheck  Action = "uncheck"
-	ActionSelect   Action = "select"
-	ActionSetFiles Action = "setFiles"
+	ActionCheck      Action = "check"
+	ActionUncheck    Action = "uncheck"
+	ActionSetChecked Action = "setChecked"
+	ActionSelect     Action = "select"
+	ActionSetFiles   Action = "setFiles"
 
 	// Element interactions
 	ActionHover          Action = "hover"
@@ -185,12 +202,18 @@ const (
 	ActionWaitForSelector Action = "waitForSelector"
 	ActionWaitForURL      Action = "waitForUrl"
 	ActionWaitForLoad     Action = "waitForLoad"
+	ActionWaitForRequest  Action = "waitForRequest"
+	ActionWaitForResponse Action = "waitForResponse"
 
 	// Page actions
 	ActionSetViewport Action = "setViewport"
 	ActionNewPage     Action = "newPage"
 	ActionClosePage   Action = "closePage"
 
+	// Storage state
+	ActionSaveStorageState Action = "saveStorageState"
+	ActionLoadStorageState Action = "loadStorageState"
+
 	// Keyboard actions
 	ActionKeyboardPress Action = "keyboardPress"
 	ActionKeyboardType  Action = "keyboardType"
@@ -223,12 +246,14 @@ func AllActions() []Action {
 	return []Action{
 		ActionNavigate, ActionGo, ActionBack, ActionForward, ActionReload,
 		ActionClick, ActionDblClick, ActionType, ActionFill, ActionClear, ActionPress,
-		ActionCheck, ActionUncheck, ActionSelect, ActionSetFiles,
+		ActionCheck, ActionUncheck, ActionSetChecked, ActionSelect, ActionSetFiles,
 		ActionHover, ActionFocus, ActionScrollIntoView, ActionDragTo, ActionTap,
 		ActionScreenshot, ActionPDF,
 		ActionEval,
 		ActionWait, ActionWaitForSelector, ActionWaitForURL, ActionWaitForLoad,
+		ActionWaitForRequest, ActionWaitForResponse,
 		ActionSetViewport, ActionNewPage, ActionClosePage,
+		ActionSaveStorageState, ActionLoadStorageState,
 		ActionKeyboardPress, ActionKeyboardType,
 		ActionMouseClick, ActionMouseMove,
 		ActionAssertText, ActionAssertElement, ActionAssertValue, ActionAssertVisible,
@@ -0,0 +1,425 @@
+package script
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError describes a problem found in a script.
+type ValidationError struct {
+	// Section is "setup" or "teardown" for errors in those step lists,
+	// and empty for the main steps and script-level errors.
+	Section string
+
+	// Step is the 1-based step number within its section, or 0 for
+	// script-level errors.
+	Step int
+
+	// Line is the source line of the offending field or step, or 0 if unknown.
+	Line int
+
+	// Field is the name of the offending field as written in the script.
+	Field string
+
+	// Message describes the problem.
+	Message string
+}
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	var sb strings.Builder
+	if e.Line > 0 {
+		fmt.Fprintf(&sb, "line %d: ", e.Line)
+	}
+	if e.Section != "" {
+		fmt.Fprintf(&sb, "%s ", e.Section)
+	}
+	if e.Step > 0 {
+		fmt.Fprintf(&sb, "step %d: ", e.Step)
+	}
+	if e.Field != "" {
+		fmt.Fprintf(&sb, "%s: ", e.Field)
+	}
+	sb.WriteString(e.Message)
+	return sb.String()
+}
+
+// requiredFields lists the fields each action cannot run without.
+var requiredFields = map[Action][]string{
+	ActionNavigate:         {"url"},
+	ActionGo:               {"url"},
+	ActionClick:            {"selector"},
+	ActionDblClick:         {"selector"},
+	ActionType:             {"selector"},
+	ActionFill:             {"selector"},
+	ActionClear:            {"selector"},
+	ActionPress:            {"selector", "key"},
+	ActionCheck:            {"selector"},
+	ActionUncheck:          {"selector"},
+	ActionSetChecked:       {"selector"},
+	ActionSelect:           {"selector"},
+	ActionSetFiles:         {"selector", "files"},
+	ActionHover:            {"selector"},
+	ActionFocus:            {"selector"},
+	ActionScrollIntoView:   {"selector"},
+	ActionDragTo:           {"selector", "target"},
+	ActionTap:              {"selector"},
+	ActionScreenshot:       {"file"},
+	ActionPDF:              {"file"},
+	ActionEval:             {"script"},
+	ActionWaitForSelector:  {"selector"},
+	ActionWaitForURL:       {"pattern"},
+	ActionWaitForRequest:   {"pattern"},
+	ActionWaitForResponse:  {"pattern"},
+	ActionSetViewport:      {"width", "height"},
+	ActionSaveStorageState: {"file"},
+	ActionLoadStorageState: {"file"},
+	ActionKeyboardPress:    {"key"},
+	ActionAssertText:       {"selector"},
+	ActionAssertElement:    {"selector"},
+	ActionAssertValue:      {"selector"},
+	ActionAssertVisible:    {"selector"},
+	ActionAssertHidden:     {"selector"},
+	ActionAssertAttribute:  {"selector", "attribute"},
+	ActionGetText:          {"selector"},
+	ActionGetValue:         {"selector"},
+	ActionGetAttribute:     {"selector", "attribute"},
+}
+
+// Validate checks a parsed script for missing required fields, unknown
+// actions, out-of-range enum values, and unparsable durations. Action names
+// come from AllActions; enum values come from the embedded JSON schema.
+// The returned errors have no line information; use ValidateBytes for that.
+func Validate(s *Script) []ValidationError {
+	var errs []ValidationError
+
+	if s.Version != 0 && s.Version != 1 {
+		errs = append(errs, ValidationError{Field: "version", Message: fmt.Sprintf("unsupported version %d", s.Version)})
+	}
+	if s.Timeout != "" {
+		if _, err := time.ParseDuration(s.Timeout); err != nil {
+			errs = append(errs, ValidationError{Field: "timeout", Message: fmt.Sprintf("invalid duration %q", s.Timeout)})
+		}
+	}
+	if len(s.Steps) == 0 {
+		errs = append(errs, ValidationError{Field: "steps", Message: "script must have at least one step"})
+	}
+
+	for _, section := range []struct {
+		name  string
+		steps []Step
+	}{{"setup", s.Setup}, {"", s.Steps}, {"teardown", s.Teardown}} {
+		for i, step := range section.steps {
+			for _, e := range validateStep(step) {
+				e.Section = section.name
+				e.Step = i + 1
+				errs = append(errs, e)
+			}
+		}
+	}
+	return errs
+}
+
+// validateStep checks a single step.
+func validateStep(step Step) []ValidationError {
+	var errs []ValidationError
+	add := func(field, format string, args ...interface{}) {
+		errs = append(errs, ValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if step.Action == "" {
+		add("action", "action is required")
+		return errs
+	}
+	if !isKnownAction(step.Action) {
+		add("action", "unknown action %q", step.Action)
+		return errs
+	}
+
+	for _, field := range requiredFields[step.Action] {
+		if !stepHasField(step, field) {
+			add(field, "%s is required for %s", field, step.Action)
+		}
+	}
+	if step.Action == ActionWait && step.Duration == "" && step.Timeout == "" {
+		add("duration", "duration is required for %s", step.Action)
+	}
+
+	checkDuration := func(field, value string) {
+		if value == "" {
+			return
+		}
+		if _, err := time.ParseDuration(value); err != nil {
+			add(field, "invalid duration %q", value)
+		}
+	}
+	checkDuration("timeout", step.Timeout)
+	checkDuration("duration", step.Duration)
+
+	checkEnum := func(def, field, value string) {
+		if value == "" {
+			return
+		}
+		allowed := schemaEnum(def, field)
+		for _, v := range allowed {
+			if v == value {
+				return
+			}
+		}
+		add(field, "invalid value %q (expected one of: %s)", value, strings.Join(allowed, ", "))
+	}
+	checkEnum("Step", "state", step.State)
+	checkEnum("Step", "loadState", step.LoadState)
+	if step.A11y != nil {
+		checkEnum("A11yOptions", "standard", step.A11y.Standard)
+		checkEnum("A11yOptions", "failOn", step.A11y.FailOn)
+	}
+
+	return errs
+}
+
+// isKnownAction reports whether a is one of AllActions.
+func isKnownAction(a Action) bool {
+	for _, known := range AllActions() {
+		if a == known {
+			return true
+		}
+	}
+	return false
+}
+
+// stepHasField reports whether the named field is set on step.
+func stepHasField(step Step, field string) bool {
+	switch field {
+	case "url":
+		return step.URL != ""
+	case "selector":
+		return step.Selector != ""
+	case "key":
+		return step.Key != ""
+	case "files":
+		return len(step.Files) > 0
+	case "target":
+		return step.Target != ""
+	case "file":
+		return step.File != ""
+	case "script":
+		return step.Script != ""
+	case "pattern":
+		return step.Pattern != ""
+	case "width":
+		return step.Width > 0
+	case "height":
+		return step.Height > 0
+	case "attribute":
+		return step.Attribute != ""
+	default:
+		return true
+	}
+}
+
+var (
+	schemaEnumsOnce sync.Once
+	schemaEnums     map[string][]string
+)
+
+// schemaEnum returns the enum values the embedded schema allows for the
+// given definition and property, e.g. ("Step", "loadState").
+func schemaEnum(def, field string) []string {
+	schemaEnumsOnce.Do(func() {
+		schemaEnums = make(map[string][]string)
+		var schema struct {
+			Defs map[string]struct {
+				Properties map[string]struct {
+					Enum []string `json:"enum"`
+				} `json:"properties"`
+			} `json:"$defs"`
+		}
+		if err := json.Unmarshal(SchemaJSON, &schema); err != nil {
+			return
+		}
+		for defName, d := range schema.Defs {
+			for propName, p := range d.Properties {
+				if len(p.Enum) > 0 {
+					schemaEnums[defName+"."+propName] = p.Enum
+				}
+			}
+		}
+	})
+	return schemaEnums[def+"."+field]
+}
+
+// ValidateBytes parses data in the given format ("yaml", "yml", or "json")
+// and validates the result. The returned validation errors carry the source
+// line of the offending field or step. A non-nil error means the data could
+// not be parsed at all.
+func ValidateBytes(data []byte, format string) ([]ValidationError, error) {
+	s, err := Parse(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines sourceLines
+	if format == "json" {
+		lines = jsonSourceLines(data)
+	} else {
+		var root yaml.Node
+		if err := yaml.Unmarshal(data, &root); err == nil {
+			lines = yamlSourceLines(&root)
+		}
+	}
+
+	errs := Validate(s)
+	for i := range errs {
+		errs[i].Line = lines.lookup(errs[i].Section, errs[i].Step, errs[i].Field)
+	}
+	return errs, nil
+}
+
+// stepSections are the top-level keys that hold step lists.
+var stepSections = []string{"setup", "steps", "teardown"}
+
+// sourceLines maps script and step fields to source lines. The "" entry
+// holds top-level fields; each step section ("setup", "steps", "teardown")
+// holds one map per step. The empty field key is the line where the step
+// starts.
+type sourceLines map[string][]map[string]int
+
+func (l sourceLines) lookup(section string, step int, field string) int {
+	if l == nil {
+		return 0
+	}
+	var m map[string]int
+	if step == 0 {
+		if top := l[""]; len(top) > 0 {
+			m = top[0]
+		}
+	} else {
+		if section == "" {
+			section = "steps"
+		}
+		if steps := l[section]; step <= len(steps) {
+			m = steps[step-1]
+		}
+	}
+	if m == nil {
+		return 0
+	}
+	if line, ok := m[field]; ok {
+		return line
+	}
+	return m[""]
+}
+
+// isStepSection reports whether key is one of stepSections.
+func isStepSection(key string) bool {
+	for _, s := range stepSections {
+		if key == s {
+			return true
+		}
+	}
+	return false
+}
+
+// yamlSourceLines records key lines for the top-level mapping and each step.
+func yamlSourceLines(root *yaml.Node) sourceLines {
+	doc := root
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		doc = doc.Content[0]
+	}
+	if doc.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	top := map[string]int{"": doc.Line}
+	lines := sourceLines{"": {top}}
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		key, value := doc.Content[i], doc.Content[i+1]
+		top[key.Value] = key.Line
+		if !isStepSection(key.Value) || value.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, item := range value.Content {
+			stepLines := map[string]int{"": item.Line}
+			if item.Kind == yaml.MappingNode {
+				for j := 0; j+1 < len(item.Content); j += 2 {
+					stepLines[item.Content[j].Value] = item.Content[j].Line
+				}
+			}
+			lines[key.Value] = append(lines[key.Value], stepLines)
+		}
+	}
+	return lines
+}
+
+// jsonSourceLines records key lines for the top-level object and the line
+// where each step object starts.
+func jsonSourceLines(data []byte) sourceLines {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil
+	}
+
+	top := map[string]int{"": 1}
+	lines := sourceLines{"": {top}}
+	for dec.More() {
+		keyOffset := dec.InputOffset()
+		tok, err := dec.Token()
+		if err != nil {
+			return lines
+		}
+		key, _ := tok.(string)
+		top[key] = lineAt(data, skipSpace(data, keyOffset))
+
+		if !isStepSection(key) {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return lines
+			}
+			continue
+		}
+
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+			return lines
+		}
+		for dec.More() {
+			start := skipSpace(data, dec.InputOffset())
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return lines
+			}
+			lines[key] = append(lines[key], map[string]int{"": lineAt(data, start)})
+		}
+		if _, err := dec.Token(); err != nil {
+			return lines
+		}
+	}
+	return lines
+}
+
+// skipSpace advances offset past whitespace, commas, and colons.
+func skipSpace(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) {
+		switch data[offset] {
+		case ' ', '\t', '\n', '\r', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// lineAt returns the 1-based line number of the byte at offset.
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
@@ -0,0 +1,131 @@
+package script
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestValidateBytes_YAML verifies that YAML validation reports missing
+// fields, unknown actions, and bad enum values with source lines.
+func TestValidateBytes_YAML(t *testing.T) {
+	data := []byte(`name: Login
+timeout: 30s
+steps:
+  - action: navigate
+    url: https://example.com
+  - action: click
+  - action: explode
+    selector: "#x"
+  - action: waitForLoad
+    loadState: idle
+  - action: wait
+    duration: soon
+`)
+
+	errs, err := ValidateBytes(data, "yaml")
+	if err != nil {
+		t.Fatalf("ValidateBytes failed: %v", err)
+	}
+
+	want := []ValidationError{
+		{Step: 2, Line: 6, Field: "selector"},
+		{Step: 3, Line: 7, Field: "action"},
+		{Step: 4, Line: 10, Field: "loadState"},
+		{Step: 5, Line: 12, Field: "duration"},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("Expected %d errors, got %d: %v", len(want), len(errs), errs)
+	}
+	for i, w := range want {
+		got := errs[i]
+		if got.Step != w.Step || got.Line != w.Line || got.Field != w.Field {
+			t.Errorf("error %d: expected step %d line %d field %s, got %+v", i, w.Step, w.Line, w.Field, got)
+		}
+	}
+	if !strings.Contains(errs[2].Message, "networkidle") {
+		t.Errorf("Expected enum message to list allowed values, got %q", errs[2].Message)
+	}
+}
+
+// TestValidateBytes_JSON verifies that JSON validation reports the line
+// where the offending step starts.
+func TestValidateBytes_JSON(t *testing.T) {
+	data := []byte(`{
+  "name": "Fill",
+  "steps": [
+    {"action": "navigate", "url": "https://example.com"},
+    {
+      "action": "fill",
+      "value": "hello"
+    }
+  ]
+}`)
+
+	errs, err := ValidateBytes(data, "json")
+	if err != nil {
+		t.Fatalf("ValidateBytes failed: %v", err)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d: %v", len(errs), errs)
+	}
+	if errs[0].Step != 2 || errs[0].Line != 5 || errs[0].Field != "selector" {
+		t.Errorf("Unexpected error: %+v", errs[0])
+	}
+}
+
+// TestValidateBytes_UnknownField verifies that misspelled fields fail parsing.
+func TestValidateBytes_UnknownField(t *testing.T) {
+	data := []byte(`steps:
+  - action: click
+    selecter: "#submit"
+`)
+	if _, err := ValidateBytes(data, "yaml"); err == nil {
+		t.Fatal("Expected parse error for unknown field")
+	}
+}
+
+// TestValidate_Valid verifies that a well-formed script has no errors.
+func TestValidate_Valid(t *testing.T) {
+	s := &Script{
+		Name: "ok",
+		Steps: []Step{
+			{Action: ActionNavigate, URL: "https://example.com"},
+			{Action: ActionWaitForSelector, Selector: "#app", State: "visible", Timeout: "5s"},
+			{Action: ActionFill, Selector: "#q", Value: "w3pilot"},
+			{Action: ActionScreenshot, File: "out.png"},
+		},
+	}
+	if errs := Validate(s); len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+}
+
+// TestValidateBytes_Sections verifies that setup and teardown steps are
+// validated and reported with their section.
+func TestValidateBytes_Sections(t *testing.T) {
+	data := []byte(`name: Cleanup
+setup:
+  - action: navigate
+    url: https://example.com/login
+steps:
+  - action: click
+    selector: "#create"
+teardown:
+  - action: click
+`)
+
+	errs, err := ValidateBytes(data, "yaml")
+	if err != nil {
+		t.Fatalf("ValidateBytes failed: %v", err)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d: %v", len(errs), errs)
+	}
+	e := errs[0]
+	if e.Section != "teardown" || e.Step != 1 || e.Line != 9 {
+		t.Errorf("Unexpected error: %+v", e)
+	}
+	if !strings.HasPrefix(e.Error(), "line 9: teardown step 1: selector:") {
+		t.Errorf("Unexpected message: %q", e.Error())
+	}
+}
@@ -0,0 +1,326 @@
+// Package server exposes the W3Pilot MCP tool set over HTTP+JSON, so that
+// clients in other languages can drive a browser without speaking MCP.
+//
+// Each session owns its own browser and is addressed by ID in the path:
+//
+//	GET    /tools                          list available tools
+//	POST   /sessions                       create a session
+//	GET    /sessions                       list session IDs (requires Config.Token)
+//	DELETE /sessions/{id}                  close a session and its browser
+//	POST   /sessions/{id}/tools/{tool}     call a tool; the body is its arguments
+//
+// Tool names and arguments are the same as the MCP tools (see
+// docs/reference/mcp-tools.md); calls run through the same handlers.
+//
+// Tools run JavaScript and write files, so the server guards against web
+// pages reaching it through the user's browser. POST requests must have
+// Content-Type application/json, which a cross-site form or no-cors fetch
+// cannot send. Without Config.Token, only requests with a loopback Host
+// header are served, which defeats DNS rebinding; with a token, every
+// request must carry it as a bearer token instead.
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/plexusone/w3pilot/mcp"
+)
+
+// maxBodyBytes caps request bodies (tool arguments).
+const maxBodyBytes = 10 << 20
+
+// Config configures the HTTP server.
+type Config struct {
+	// MCP is the configuration each session's tool server starts with.
+	MCP mcp.Config
+
+	// MaxSessions limits concurrent sessions. Default is 0 (unlimited).
+	MaxSessions int
+
+	// Token, if set, must be sent with every request as
+	// "Authorization: Bearer <token>". It is required to serve clients on
+	// other hosts and to list sessions.
+	Token string
+}
+
+// Server is an http.Handler that serves the tool API.
+type Server struct {
+	config Config
+	mux    *http.ServeMux
+	tools  func() (*toolCatalog, error)
+
+	mu       sync.Mutex
+	sessions map[string]*mcp.Server
+}
+
+// New creates a server. Call Close to shut down all sessions.
+func New(config Config) *Server {
+	s := &Server{
+		config:   config,
+		mux:      http.NewServeMux(),
+		sessions: make(map[string]*mcp.Server),
+	}
+	s.tools = sync.OnceValues(func() (*toolCatalog, error) {
+		return newToolCatalog(config.MCP)
+	})
+
+	s.mux.HandleFunc("GET /tools", s.handleListTools)
+	s.mux.HandleFunc("POST /sessions", s.handleCreateSession)
+	if config.Token != "" {
+		// Session IDs are the only credential without a token
+		s.mux.HandleFunc("GET /sessions", s.handleListSessions)
+	}
+	s.mux.HandleFunc("DELETE /sessions/{id}", s.handleDeleteSession)
+	s.mux.HandleFunc("POST /sessions/{id}/tools/{tool}", s.handleCallTool)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.config.Token != "" {
+		auth := r.Header.Get("Authorization")
+		token, ok := strings.CutPrefix(auth, "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.config.Token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+	} else if !isLoopbackHost(r.Host) {
+		writeError(w, http.StatusForbidden, "host %q is not allowed; set a token to serve other hosts", r.Host)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if mediaType != "application/json" {
+			writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+			return
+		}
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// toolCatalog is the set of tools a session serves.
+type toolCatalog struct {
+	list  *mcp.ToolList
+	names map[string]bool
+}
+
+// newToolCatalog lists the tools registered by a tool server built from
+// config. The server never launches a browser; it only answers the listing.
+func newToolCatalog(config mcp.Config) (*toolCatalog, error) {
+	list, err := mcp.NewServer(config).RegisteredTools(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(list.Tools))
+	for _, t := range list.Tools {
+		names[t.Name] = true
+	}
+	return &toolCatalog{list: list, names: names}, nil
+}
+
+// isLoopbackHost reports whether a Host header names this machine:
+// localhost or a loopback IP, with or without a port.
+func isLoopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Close closes every session and its browser.
+func (s *Server) Close(ctx context.Context) error {
+	s.mu.Lock()
+	sessions := s.sessions
+	s.sessions = make(map[string]*mcp.Server)
+	s.mu.Unlock()
+
+	var errs []error
+	for id, sess := range sessions {
+		if err := sess.Close(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("session %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CreateSessionRequest is the optional body of POST /sessions.
+type CreateSessionRequest struct {
+	// Headless overrides the server's default headless setting.
+	Headless *bool `json:"headless,omitempty"`
+}
+
+// SessionResponse is returned by POST /sessions.
+type SessionResponse struct {
+	ID string `json:"id"`
+}
+
+// SessionListResponse is returned by GET /sessions.
+type SessionListResponse struct {
+	Sessions []string `json:"sessions"`
+}
+
+// ErrorResponse is the body of every non-2xx response except failed tool
+// calls, which return a ToolResult.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+func (s *Server) handleListTools(w http.ResponseWriter, r *http.Request) {
+	tools, err := s.tools()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list tools: %v", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tools.list)
+}
+
+func (s *Server) handleCreateSession(w http.ResponseWriter, r *http.Request) {
+	var req CreateSessionRequest
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read body: %v", err)
+		return
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON body: %v", err)
+			return
+		}
+	}
+
+	config := s.config.MCP
+	if req.Headless != nil {
+		config.Headless = *req.Headless
+	}
+
+	id, err := newSessionID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to create session ID: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	if s.config.MaxSessions > 0 && len(s.sessions) >= s.config.MaxSessions {
+		s.mu.Unlock()
+		writeError(w, http.StatusServiceUnavailable, "session limit reached (%d)", s.config.MaxSessions)
+		return
+	}
+	s.sessions[id] = mcp.NewServer(config)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, SessionResponse{ID: id})
+}
+
+func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	ids := make([]string, 0, len(s.sessions))
+	for id := range s.sessions {
+		ids = append(ids, id)
+	}
+	s.mu.Unlock()
+
+	sort.Strings(ids)
+	writeJSON(w, http.StatusOK, SessionListResponse{Sessions: ids})
+}
+
+func (s *Server) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	s.mu.Lock()
+	sess, ok := s.sessions[id]
+	delete(s.sessions, id)
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "session not found: %s", id)
+		return
+	}
+	if err := sess.Close(r.Context()); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to close session: %v", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleCallTool(w http.ResponseWriter, r *http.Request) {
+	id, tool := r.PathValue("id"), r.PathValue("tool")
+
+	s.mu.Lock()
+	sess, ok := s.sessions[id]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "session not found: %s", id)
+		return
+	}
+	tools, err := s.tools()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list tools: %v", err)
+		return
+	}
+	if !tools.names[tool] {
+		writeError(w, http.StatusNotFound, "unknown tool: %s", tool)
+		return
+	}
+
+	args, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read body: %v", err)
+		return
+	}
+	if len(args) > 0 && !json.Valid(args) {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	result, err := sess.CallTool(r.Context(), tool, args)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "tool call failed: %v", err)
+		return
+	}
+
+	status := http.StatusOK
+	if result.IsError {
+		status = http.StatusUnprocessableEntity
+	}
+	writeJSON(w, status, result)
+}
+
+// newSessionID returns a random 128-bit hex ID.
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...any) {
+	writeJSON(w, status, ErrorResponse{Error: fmt.Sprintf(format, args...)})
+}
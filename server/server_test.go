@@ -0,0 +1,186 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/plexusone/w3pilot/mcp"
+)
+
+func do(t *testing.T, h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Host = "localhost:8080"
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_SessionLifecycle(t *testing.T) {
+	s := New(Config{MCP: mcp.DefaultConfig()})
+	defer func() { _ = s.Close(context.Background()) }()
+
+	rec := do(t, s, http.MethodPost, "/sessions", `{"headless": true}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var created SessionResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil || created.ID == "" {
+		t.Fatalf("create: invalid response %s: %v", rec.Body, err)
+	}
+
+	rec = do(t, s, http.MethodPost, "/sessions/"+created.ID+"/tools/no_such_tool", "{}")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown tool: status = %d, want 404", rec.Code)
+	}
+
+	rec = do(t, s, http.MethodPost, "/sessions/"+created.ID+"/tools/page_navigate", "{not json")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid body: status = %d, want 400", rec.Code)
+	}
+
+	rec = do(t, s, http.MethodDelete, "/sessions/"+created.ID, "")
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("delete: status = %d, want 204", rec.Code)
+	}
+
+	rec = do(t, s, http.MethodPost, "/sessions/"+created.ID+"/tools/page_navigate", "{}")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("deleted session: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestServer_MaxSessions(t *testing.T) {
+	s := New(Config{MCP: mcp.DefaultConfig(), MaxSessions: 1})
+	defer func() { _ = s.Close(context.Background()) }()
+
+	if rec := do(t, s, http.MethodPost, "/sessions", ""); rec.Code != http.StatusCreated {
+		t.Fatalf("first session: status = %d", rec.Code)
+	}
+	if rec := do(t, s, http.MethodPost, "/sessions", ""); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("second session: status = %d, want 503", rec.Code)
+	}
+}
+
+func TestServer_ListTools(t *testing.T) {
+	s := New(Config{})
+
+	rec := do(t, s, http.MethodGet, "/tools", "")
+	var list mcp.ToolList
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("list tools: %v", err)
+	}
+	if list.Total == 0 || list.Total != len(list.Tools) {
+		t.Errorf("list tools: total = %d, tools = %d", list.Total, len(list.Tools))
+	}
+}
+
+func TestServer_RegisteredToolsCallable(t *testing.T) {
+	s := New(Config{MCP: mcp.DefaultConfig()})
+	defer func() { _ = s.Close(context.Background()) }()
+
+	want, err := mcp.NewServer(mcp.DefaultConfig()).RegisteredTools(context.Background())
+	if err != nil {
+		t.Fatalf("RegisteredTools: %v", err)
+	}
+
+	rec := do(t, s, http.MethodGet, "/tools", "")
+	var list mcp.ToolList
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("list tools: %v", err)
+	}
+	if list.Total != want.Total {
+		t.Errorf("list tools: total = %d, want %d registered tools", list.Total, want.Total)
+	}
+
+	rec = do(t, s, http.MethodPost, "/sessions", "")
+	var created SessionResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// A canceled request reaches the tool call without running the tool
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, tool := range want.Tools {
+		req := httptest.NewRequest(http.MethodPost, "/sessions/"+created.ID+"/tools/"+tool.Name, strings.NewReader("{}")).WithContext(ctx)
+		req.Host = "localhost:8080"
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		if rec.Code == http.StatusNotFound {
+			t.Errorf("%s: status = 404, body = %s", tool.Name, rec.Body)
+		}
+	}
+}
+
+func TestServer_RejectsCrossSiteRequests(t *testing.T) {
+	s := New(Config{MCP: mcp.DefaultConfig()})
+	defer func() { _ = s.Close(context.Background()) }()
+
+	// DNS rebinding: the page's own host name reaches the loopback server
+	req := httptest.NewRequest(http.MethodGet, "/tools", nil)
+	req.Host = "attacker.example:8080"
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("foreign host: status = %d, want 403", rec.Code)
+	}
+
+	// A cross-site form or no-cors fetch cannot send application/json
+	req = httptest.NewRequest(http.MethodPost, "/sessions", strings.NewReader(`{}`))
+	req.Host = "127.0.0.1:8080"
+	req.Header.Set("Content-Type", "text/plain")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("text/plain body: status = %d, want 415", rec.Code)
+	}
+
+	// Session IDs are not listed without a token
+	if rec := do(t, s, http.MethodGet, "/sessions", ""); rec.Code == http.StatusOK {
+		t.Errorf("list sessions without token: status = %d", rec.Code)
+	}
+}
+
+func TestServer_Token(t *testing.T) {
+	s := New(Config{MCP: mcp.DefaultConfig(), Token: "secret"})
+	defer func() { _ = s.Close(context.Background()) }()
+
+	if rec := do(t, s, http.MethodGet, "/tools", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("no token: status = %d, want 401", rec.Code)
+	}
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Host = "browsers.internal:8080"
+		req.Header.Set("Authorization", "Bearer secret")
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(http.MethodPost, "/sessions", `{"headless": true}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create with token: status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var created SessionResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	rec = send(http.MethodGet, "/sessions", "")
+	var list SessionListResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list.Sessions) != 1 || list.Sessions[0] != created.ID {
+		t.Errorf("list: sessions = %v, want [%s]", list.Sessions, created.ID)
+	}
+}
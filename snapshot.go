@@ -0,0 +1,270 @@
+package w3pilot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SnapshotRefAttribute is the attribute Snapshot stamps on interactable
+// elements to hold their ref.
+const SnapshotRefAttribute = "data-w3pilot-ref"
+
+// DefaultSnapshotMaxNodes is the node limit Snapshot uses when
+// SnapshotOptions.MaxNodes is not positive.
+const DefaultSnapshotMaxNodes = 500
+
+// SnapshotOptions configures Pilot.Snapshot.
+type SnapshotOptions struct {
+	// Root limits the snapshot to the element matching this CSS selector.
+	// Default is the whole page body.
+	Root string
+
+	// IncludeHidden includes elements that are not rendered or are
+	// aria-hidden. By default they are skipped with their descendants.
+	IncludeHidden bool
+
+	// MaxNodes limits the number of nodes in the snapshot. Default is
+	// DefaultSnapshotMaxNodes.
+	MaxNodes int
+}
+
+// PageSnapshot is a compact, role-based view of a page for agents. It holds
+// the interactable elements (buttons, links, inputs, ...) with the landmarks
+// and headings around them, instead of raw HTML.
+type PageSnapshot struct {
+	URL       string         `json:"url"`
+	Title     string         `json:"title"`
+	Nodes     []SnapshotNode `json:"nodes"`
+	Truncated bool           `json:"truncated,omitempty"`
+}
+
+// SnapshotNode is a node in a PageSnapshot.
+type SnapshotNode struct {
+	// Ref identifies an interactable element, e.g. "e42". Pass it to
+	// FindRef or RefSelector to act on the element. Landmarks and headings
+	// have no ref.
+	Ref string `json:"ref,omitempty"`
+
+	// Role is the explicit or implicit ARIA role.
+	Role string `json:"role"`
+
+	// Name is the accessible name (label, aria-label, alt, text, ...).
+	Name string `json:"name,omitempty"`
+
+	// Value is the current value of text inputs; passwords are masked.
+	Value string `json:"value,omitempty"`
+
+	// Level is the heading level.
+	Level int `json:"level,omitempty"`
+
+	// Checked is the state of checkboxes, radios, and switches.
+	Checked *bool `json:"checked,omitempty"`
+
+	Disabled bool           `json:"disabled,omitempty"`
+	Children []SnapshotNode `json:"children,omitempty"`
+}
+
+// RefSelector returns the CSS selector for a snapshot ref.
+func RefSelector(ref string) string {
+	return fmt.Sprintf("[%s=%q]", SnapshotRefAttribute, ref)
+}
+
+// Refs returns the nodes that have refs, keyed by ref.
+func (s *PageSnapshot) Refs() map[string]SnapshotNode {
+	refs := make(map[string]SnapshotNode)
+	var walk func(nodes []SnapshotNode)
+	walk = func(nodes []SnapshotNode) {
+		for _, n := range nodes {
+			if n.Ref != "" {
+				refs[n.Ref] = n
+			}
+			walk(n.Children)
+		}
+	}
+	walk(s.Nodes)
+	return refs
+}
+
+// String renders the snapshot as an indented outline with one node per
+// line, e.g. `- button "Submit" [ref=e3] [disabled]`, children indented two
+// spaces below their parent. This is the compact form to hand to an agent.
+func (s *PageSnapshot) String() string {
+	var sb strings.Builder
+	var write func(nodes []SnapshotNode, depth int)
+	write = func(nodes []SnapshotNode, depth int) {
+		for _, n := range nodes {
+			sb.WriteString(strings.Repeat("  ", depth))
+			sb.WriteString("- ")
+			sb.WriteString(n.Role)
+			if n.Name != "" {
+				fmt.Fprintf(&sb, " %q", n.Name)
+			}
+			if n.Ref != "" {
+				fmt.Fprintf(&sb, " [ref=%s]", n.Ref)
+			}
+			if n.Level > 0 {
+				fmt.Fprintf(&sb, " [level=%d]", n.Level)
+			}
+			if n.Value != "" {
+				fmt.Fprintf(&sb, " [value=%q]", n.Value)
+			}
+			if n.Checked != nil {
+				fmt.Fprintf(&sb, " [checked=%t]", *n.Checked)
+			}
+			if n.Disabled {
+				sb.WriteString(" [disabled]")
+			}
+			sb.WriteByte('\n')
+			write(n.Children, depth+1)
+		}
+	}
+	write(s.Nodes, 0)
+	if s.Truncated {
+		sb.WriteString("- ... (truncated)\n")
+	}
+	return sb.String()
+}
+
+// snapshotScript walks the DOM from the root and returns the snapshot tree
+// as JSON. Roles and names come from ariaRole and accessibleName, so they
+// match Element.AccessibleName. Interactable elements are stamped with a
+// ref attribute; an element that already has one keeps it, so refs stay
+// stable across snapshots for as long as the element exists.
+const snapshotScript = `(rootSelector, includeHidden, maxNodes, refAttr) => {` + accessibleNameFunction + `
+
+	const root = rootSelector ? document.querySelector(rootSelector) : document.body;
+	if (!root) throw new Error('snapshot root not found: ' + rootSelector);
+
+	const interactive = new Set(['button', 'link', 'textbox', 'searchbox', 'checkbox', 'radio', 'switch',
+		'combobox', 'listbox', 'option', 'slider', 'spinbutton', 'tab', 'menuitem', 'menuitemcheckbox',
+		'menuitemradio', 'treeitem']);
+	const structural = new Set(['banner', 'navigation', 'main', 'contentinfo', 'complementary', 'region',
+		'search', 'form', 'dialog', 'alertdialog', 'tablist', 'menu', 'menubar', 'tree', 'heading']);
+
+	const clean = s => (s || '').replace(/\s+/g, ' ').trim().slice(0, 80);
+	const nameOf = el => clean(accessibleName(el));
+
+	let nextRef = window.__w3pilotNextRef || 1;
+	let count = 0;
+	let truncated = false;
+
+	const walk = parent => {
+		const out = [];
+		for (const el of parent.children) {
+			if (count >= maxNodes) { truncated = true; break; }
+			if (!includeHidden && accnameHidden(el)) continue;
+			const role = ariaRole(el);
+			if (interactive.has(role)) {
+				let ref = el.getAttribute(refAttr);
+				if (!ref) {
+					ref = 'e' + nextRef++;
+					el.setAttribute(refAttr, ref);
+				}
+				const node = {ref, role, name: nameOf(el)};
+				if (['textbox', 'searchbox', 'spinbutton', 'slider'].includes(role) && 'value' in el) {
+					node.value = el.type === 'password' ? (el.value ? '***' : '') : String(el.value).slice(0, 100);
+				} else if (role === 'combobox' && el.tagName === 'SELECT' && el.selectedIndex >= 0) {
+					node.value = clean(el.options[el.selectedIndex].text);
+				}
+				if (['checkbox', 'radio', 'switch', 'menuitemcheckbox', 'menuitemradio'].includes(role)) {
+					node.checked = 'checked' in el ? el.checked : el.getAttribute('aria-checked') === 'true';
+				}
+				if (el.disabled || el.getAttribute('aria-disabled') === 'true') node.disabled = true;
+				count++;
+				out.push(node);
+				continue;
+			}
+			if (role === 'heading') {
+				const name = nameOf(el);
+				if (name) {
+					const level = parseInt(el.getAttribute('aria-level') || el.tagName.slice(1), 10) || 2;
+					count++;
+					out.push({role, name, level});
+				}
+				continue;
+			}
+			if (structural.has(role)) {
+				count++;
+				const children = walk(el);
+				if (children.length) {
+					const node = {role, children};
+					const name = nameOf(el);
+					if (name) node.name = name;
+					out.push(node);
+				} else {
+					count--;
+				}
+				continue;
+			}
+			out.push(...walk(el));
+		}
+		return out;
+	};
+
+	const nodes = walk(root);
+	window.__w3pilotNextRef = nextRef;
+	return JSON.stringify({nodes, truncated});
+}`
+
+// Snapshot returns a compact, role-based snapshot of the interactable
+// elements on the page, with the landmarks and headings that give them
+// context. Each interactable element gets a ref (e.g. "e42") that can be
+// passed to FindRef to act on it instead of a CSS selector.
+//
+// Refs are stamped on the elements themselves, so a ref stays valid for as
+// long as its element is in the document, including across later
+// snapshots. Refs do not survive navigation: after the page changes, take a
+// new snapshot.
+func (p *Pilot) Snapshot(ctx context.Context, opts SnapshotOptions) (*PageSnapshot, error) {
+	if p.closed.Load() {
+		return nil, ErrConnectionClosed
+	}
+
+	maxNodes := opts.MaxNodes
+	if maxNodes <= 0 {
+		maxNodes = DefaultSnapshotMaxNodes
+	}
+
+	result, err := p.EvaluateWithArgs(ctx, snapshotScript, opts.Root, opts.IncludeHidden, maxNodes, SnapshotRefAttribute)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot failed: %w", err)
+	}
+	data, ok := result.(string)
+	if !ok {
+		return nil, fmt.Errorf("snapshot failed: unexpected result %T", result)
+	}
+
+	snapshot := &PageSnapshot{}
+	if err := json.Unmarshal([]byte(data), snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	if snapshot.Nodes == nil {
+		snapshot.Nodes = []SnapshotNode{}
+	}
+
+	snapshot.URL, _ = p.URL(ctx)
+	snapshot.Title, _ = p.Title(ctx)
+	return snapshot, nil
+}
+
+// FindRef returns the element with a ref from Snapshot. It returns an error
+// wrapping ErrStaleRef if no element on the page has the ref, e.g. because
+// the element was removed or the page navigated since the snapshot.
+func (p *Pilot) FindRef(ctx context.Context, ref string) (*Element, error) {
+	if p.closed.Load() {
+		return nil, ErrConnectionClosed
+	}
+
+	selector := RefSelector(ref)
+	exists, err := p.EvaluateWithArgs(ctx, `(selector) => document.querySelector(selector) !== null`, selector)
+	if err != nil {
+		return nil, err
+	}
+	if exists != true {
+		return nil, fmt.Errorf("%w: %s", ErrStaleRef, ref)
+	}
+	return p.Find(ctx, selector, &FindOptions{Timeout: time.Second})
+}
@@ -0,0 +1,66 @@
+package w3pilot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestSnapshot verifies that the page tree is parsed and rendered, and
+// that refs are indexed.
+func TestSnapshot(t *testing.T) {
+	tree := `{"nodes":[{"role":"main","children":[` +
+		`{"role":"heading","name":"Sign in","level":1},` +
+		`{"ref":"e1","role":"textbox","name":"Email","value":"a@example.com"},` +
+		`{"ref":"e2","role":"checkbox","name":"Remember me","checked":false},` +
+		`{"ref":"e3","role":"button","name":"Submit","disabled":true}]}],"truncated":false}`
+	value, _ := json.Marshal(tree)
+
+	mock := newMockTransport()
+	mock.methodResponses = map[string]json.RawMessage{
+		"script.callFunction": json.RawMessage(`{"result":{"type":"string","value":` + string(value) + `}}`),
+	}
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	snap, err := pilot.Snapshot(context.Background(), SnapshotOptions{})
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+
+	refs := snap.Refs()
+	if len(refs) != 3 || refs["e3"].Name != "Submit" || !refs["e3"].Disabled {
+		t.Errorf("Unexpected refs: %+v", refs)
+	}
+
+	want := `- main
+  - heading "Sign in" [level=1]
+  - textbox "Email" [ref=e1] [value="a@example.com"]
+  - checkbox "Remember me" [ref=e2] [checked=false]
+  - button "Submit" [ref=e3] [disabled]
+`
+	if got := snap.String(); got != want {
+		t.Errorf("Unexpected outline:\n%s\nwant:\n%s", got, want)
+	}
+
+	args := mock.calls[0].Params.(map[string]interface{})["arguments"].([]interface{})
+	if v := args[2].(map[string]interface{})["value"]; v != DefaultSnapshotMaxNodes {
+		t.Errorf("Expected default max nodes, got %v", v)
+	}
+}
+
+// TestFindRef_Stale verifies that a ref no element carries is reported as stale.
+func TestFindRef_Stale(t *testing.T) {
+	mock := newMockTransport()
+	mock.response = json.RawMessage(`{"result":{"type":"boolean","value":false}}`)
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	_, err := pilot.FindRef(context.Background(), "e42")
+	if !errors.Is(err, ErrStaleRef) {
+		t.Fatalf("Expected ErrStaleRef, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "e42") {
+		t.Errorf("Expected ref in error, got %q", err)
+	}
+}
@@ -0,0 +1,90 @@
+package w3pilot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// stealthScriptTemplate hides the common signs of an automated headless
+// browser from page scripts. %s is the user agent as a JSON string.
+const stealthScriptTemplate = `(() => {
+	const userAgent = %s;
+	const define = (obj, prop, value) => {
+		try {
+			Object.defineProperty(obj, prop, {get: () => value, configurable: true});
+		} catch (e) {}
+	};
+
+	// navigator.webdriver is true under automation
+	define(Navigator.prototype, 'webdriver', false);
+
+	// Headless Chrome reports "HeadlessChrome" in the user agent
+	define(Navigator.prototype, 'userAgent', userAgent);
+	define(Navigator.prototype, 'appVersion', userAgent.replace(/^Mozilla\//, ''));
+
+	// Headless Chrome has an empty language list
+	if (!navigator.languages || navigator.languages.length === 0) {
+		define(Navigator.prototype, 'languages', Object.freeze(['en-US', 'en']));
+	}
+
+	// Headless Chrome has no plugins; desktop Chrome lists the PDF viewers
+	if (!navigator.plugins || navigator.plugins.length === 0) {
+		const mimeType = {type: 'application/pdf', suffixes: 'pdf', description: 'Portable Document Format'};
+		const names = ['PDF Viewer', 'Chrome PDF Viewer', 'Chromium PDF Viewer', 'Microsoft Edge PDF Viewer', 'WebKit built-in PDF'];
+		const plugins = names.map(name => {
+			const plugin = {name, filename: 'internal-pdf-viewer', description: 'Portable Document Format', length: 1, 0: mimeType};
+			plugin.item = i => plugin[i] || null;
+			plugin.namedItem = type => type === mimeType.type ? mimeType : null;
+			Object.setPrototypeOf(plugin, Plugin.prototype);
+			return plugin;
+		});
+		const list = Object.assign(Object.create(PluginArray.prototype), plugins, {
+			length: plugins.length,
+			item: i => plugins[i] || null,
+			namedItem: name => plugins.find(p => p.name === name) || null,
+			refresh: () => {},
+		});
+		list[Symbol.iterator] = function* () { yield* plugins; };
+		define(Navigator.prototype, 'plugins', list);
+	}
+
+	// window.chrome is missing in headless mode
+	if (!window.chrome) {
+		define(window, 'chrome', {runtime: {}, app: {isInstalled: false}});
+	}
+})();`
+
+// stealthScript returns the init script that applies the stealth
+// mitigations, reporting userAgent to page scripts.
+func stealthScript(userAgent string) string {
+	encoded, _ := json.Marshal(userAgent)
+	return fmt.Sprintf(stealthScriptTemplate, encoded)
+}
+
+// applyStealth installs the LaunchOptions.Stealth mitigations: an init
+// script for page-visible signs of automation, and, where the browser
+// supports it, a user agent override so request headers do not say
+// "HeadlessChrome" either.
+func (p *Pilot) applyStealth(ctx context.Context) error {
+	result, err := p.Evaluate(ctx, "navigator.userAgent")
+	if err != nil {
+		return fmt.Errorf("failed to read user agent: %w", err)
+	}
+	userAgent, _ := result.(string)
+	userAgent = strings.Replace(userAgent, "HeadlessChrome/", "Chrome/", 1)
+
+	if err := p.AddInitScript(ctx, stealthScript(userAgent)); err != nil {
+		return fmt.Errorf("failed to add stealth init script: %w", err)
+	}
+
+	// emulation.setUserAgentOverride is only in recent browsers. Without it,
+	// page scripts still see the patched user agent from the init script.
+	if _, err := p.client.Send(ctx, "emulation.setUserAgentOverride", map[string]interface{}{
+		"userAgent": userAgent,
+	}); err != nil {
+		debugLog(ctx, "user agent override not supported (continuing)", "error", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package w3pilot
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestPilotApplyStealth verifies that applyStealth installs an init script
+// with the non-headless user agent and requests a user agent override.
+func TestPilotApplyStealth(t *testing.T) {
+	mock := newMockTransport()
+	mock.setResponse(json.RawMessage(`{"type":"success","result":{"type":"string","value":"Mozilla/5.0 HeadlessChrome/130.0.0.0 Safari/537.36"}}`))
+	mock.setMethodResponse("browser.getUserContexts", json.RawMessage(`{"userContexts":[{"userContext":"default"}]}`))
+	mock.setMethodResponse("vibium:context.addInitScript", json.RawMessage(`{}`))
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	if err := pilot.applyStealth(context.Background()); err != nil {
+		t.Fatalf("applyStealth failed: %v", err)
+	}
+
+	var script, override string
+	for _, call := range mock.getCalls() {
+		params, _ := call.Params.(map[string]interface{})
+		switch call.Method {
+		case "vibium:context.addInitScript":
+			script, _ = params["script"].(string)
+		case "emulation.setUserAgentOverride":
+			override, _ = params["userAgent"].(string)
+		}
+	}
+	const want = "Mozilla/5.0 Chrome/130.0.0.0 Safari/537.36"
+	if !strings.Contains(script, `"`+want+`"`) || !strings.Contains(script, "'webdriver'") {
+		t.Errorf("Expected init script with user agent %q, got %s", want, script)
+	}
+	if override != want {
+		t.Errorf("Expected user agent override %q, got %q", want, override)
+	}
+}
+
+// TestStealthScript_EscapesUserAgent verifies the user agent is embedded as
+// a JavaScript string literal.
+func TestStealthScript_EscapesUserAgent(t *testing.T) {
+	script := stealthScript(`Agent "quoted" \ backslash`)
+	if !strings.Contains(script, `const userAgent = "Agent \"quoted\" \\ backslash";`) {
+		t.Errorf("Expected escaped user agent, got %s", script[:80])
+	}
+}
@@ -0,0 +1,130 @@
+package w3pilot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Web storage areas.
+const (
+	localStorageArea   = "localStorage"
+	sessionStorageArea = "sessionStorage"
+)
+
+// LocalStorage returns the localStorage items for the current page's origin.
+func (p *Pilot) LocalStorage(ctx context.Context) (map[string]string, error) {
+	return p.storageItems(ctx, localStorageArea)
+}
+
+// LocalStorageItem returns the localStorage value for key and whether it is set.
+func (p *Pilot) LocalStorageItem(ctx context.Context, key string) (string, bool, error) {
+	return p.storageItem(ctx, localStorageArea, key)
+}
+
+// SetLocalStorageItem sets a localStorage item for the current page's origin.
+func (p *Pilot) SetLocalStorageItem(ctx context.Context, key, value string) error {
+	return p.setStorageItem(ctx, localStorageArea, key, value)
+}
+
+// RemoveLocalStorageItem removes a localStorage item for the current page's origin.
+func (p *Pilot) RemoveLocalStorageItem(ctx context.Context, key string) error {
+	return p.removeStorageItem(ctx, localStorageArea, key)
+}
+
+// ClearLocalStorage removes every localStorage item for the current page's origin.
+func (p *Pilot) ClearLocalStorage(ctx context.Context) error {
+	return p.clearStorage(ctx, localStorageArea)
+}
+
+// SessionStorage returns the sessionStorage items for the current page's origin.
+func (p *Pilot) SessionStorage(ctx context.Context) (map[string]string, error) {
+	return p.storageItems(ctx, sessionStorageArea)
+}
+
+// SessionStorageItem returns the sessionStorage value for key and whether it is set.
+func (p *Pilot) SessionStorageItem(ctx context.Context, key string) (string, bool, error) {
+	return p.storageItem(ctx, sessionStorageArea, key)
+}
+
+// SetSessionStorageItem sets a sessionStorage item for the current page's origin.
+func (p *Pilot) SetSessionStorageItem(ctx context.Context, key, value string) error {
+	return p.setStorageItem(ctx, sessionStorageArea, key, value)
+}
+
+// RemoveSessionStorageItem removes a sessionStorage item for the current page's origin.
+func (p *Pilot) RemoveSessionStorageItem(ctx context.Context, key string) error {
+	return p.removeStorageItem(ctx, sessionStorageArea, key)
+}
+
+// ClearSessionStorage removes every sessionStorage item for the current page's origin.
+func (p *Pilot) ClearSessionStorage(ctx context.Context) error {
+	return p.clearStorage(ctx, sessionStorageArea)
+}
+
+// storageItems returns every item in the named storage area. The items are
+// returned as a JSON string so values survive BiDi serialization unchanged.
+func (p *Pilot) storageItems(ctx context.Context, area string) (map[string]string, error) {
+	result, err := p.EvaluateWithArgs(ctx, `(area) => {
+		const storage = window[area];
+		const items = {};
+		for (let i = 0; i < storage.length; i++) {
+			const key = storage.key(i);
+			items[key] = storage.getItem(key);
+		}
+		return JSON.stringify(items);
+	}`, area)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", area, err)
+	}
+
+	s, ok := result.(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to read %s: unexpected result %T", area, result)
+	}
+	items := make(map[string]string)
+	if err := json.Unmarshal([]byte(s), &items); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", area, err)
+	}
+	return items, nil
+}
+
+// storageItem returns one item from the named storage area.
+func (p *Pilot) storageItem(ctx context.Context, area, key string) (string, bool, error) {
+	result, err := p.EvaluateWithArgs(ctx, `(area, key) => window[area].getItem(key)`, area, key)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read %s item %q: %w", area, key, err)
+	}
+	if result == nil {
+		return "", false, nil
+	}
+	s, ok := result.(string)
+	if !ok {
+		return "", false, fmt.Errorf("failed to read %s item %q: unexpected result %T", area, key, result)
+	}
+	return s, true, nil
+}
+
+// setStorageItem sets an item in the named storage area.
+func (p *Pilot) setStorageItem(ctx context.Context, area, key, value string) error {
+	if _, err := p.EvaluateWithArgs(ctx, `(area, key, value) => { window[area].setItem(key, value); }`, area, key, value); err != nil {
+		return fmt.Errorf("failed to set %s item %q: %w", area, key, err)
+	}
+	return nil
+}
+
+// removeStorageItem removes an item from the named storage area.
+func (p *Pilot) removeStorageItem(ctx context.Context, area, key string) error {
+	if _, err := p.EvaluateWithArgs(ctx, `(area, key) => { window[area].removeItem(key); }`, area, key); err != nil {
+		return fmt.Errorf("failed to remove %s item %q: %w", area, key, err)
+	}
+	return nil
+}
+
+// clearStorage removes every item from the named storage area.
+func (p *Pilot) clearStorage(ctx context.Context, area string) error {
+	if _, err := p.EvaluateWithArgs(ctx, `(area) => { window[area].clear(); }`, area); err != nil {
+		return fmt.Errorf("failed to clear %s: %w", area, err)
+	}
+	return nil
+}
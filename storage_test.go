@@ -0,0 +1,62 @@
+package w3pilot
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// TestLocalStorage verifies that storage items are read from the page's
+// JSON-encoded result.
+func TestLocalStorage(t *testing.T) {
+	mock := newMockTransport()
+	mock.response = json.RawMessage(`{"result":{"type":"string","value":"{\"theme\":\"dark\",\"remember\":\"1\"}"}}`)
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	items, err := pilot.LocalStorage(context.Background())
+	if err != nil {
+		t.Fatalf("LocalStorage failed: %v", err)
+	}
+	if items["theme"] != "dark" || items["remember"] != "1" || len(items) != 2 {
+		t.Errorf("Unexpected items: %v", items)
+	}
+}
+
+// TestSessionStorageItem_Missing verifies that a null result reports the
+// item as unset.
+func TestSessionStorageItem_Missing(t *testing.T) {
+	mock := newMockTransport()
+	mock.response = json.RawMessage(`{"result":{"type":"null"}}`)
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	value, ok, err := pilot.SessionStorageItem(context.Background(), "flag")
+	if err != nil {
+		t.Fatalf("SessionStorageItem failed: %v", err)
+	}
+	if ok || value != "" {
+		t.Errorf("Expected unset item, got %q, %v", value, ok)
+	}
+}
+
+// TestSetLocalStorageItem verifies that the area, key, and value are passed
+// as arguments rather than interpolated into the script.
+func TestSetLocalStorageItem(t *testing.T) {
+	mock := newMockTransport()
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	if err := pilot.SetLocalStorageItem(context.Background(), "feature", `"on"`); err != nil {
+		t.Fatalf("SetLocalStorageItem failed: %v", err)
+	}
+
+	params := mock.calls[0].Params.(map[string]interface{})
+	args := params["arguments"].([]interface{})
+	want := []string{"localStorage", "feature", `"on"`}
+	if len(args) != len(want) {
+		t.Fatalf("Expected %d arguments, got %v", len(want), args)
+	}
+	for i, w := range want {
+		if got := args[i].(map[string]interface{})["value"]; got != w {
+			t.Errorf("argument %d: expected %q, got %v", i, w, got)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package w3pilot
+
+import (
+	"context"
+	"fmt"
+)
+
+// DefaultSuggestionLimit is the number of selectors SuggestSelectors returns
+// when limit is not positive.
+const DefaultSuggestionLimit = 5
+
+// suggestSelectorsFunction defines suggestSelectors(failed, limit), a fuzzy
+// search over element ids, classes, test ids, names, and the text of
+// interactive elements. Names are compared with the identifiers in the failed
+// selector by edit distance, so "#sumbit-btn" finds "#submit-btn" and
+// "button.login" finds a button labeled "Log in". Every suggestion matches at
+// least one element on the page. It relies on cssPath from cssPathFunction.
+const suggestSelectorsFunction = `function suggestSelectors(failed, limit) {
+	const norm = s => (s || '').toLowerCase().replace(/[^a-z0-9]+/g, '');
+
+	// Identifiers in the failed selector: ids, classes, and attribute values
+	let tokens = [];
+	for (const m of failed.matchAll(/[#.]([\w-]+)|=\s*["']?([^"'\]]+)/g)) {
+		tokens.push(norm(m[1] || m[2]));
+	}
+	if (tokens.length === 0) {
+		tokens = failed.split(/[\s>+~]+/).map(norm);
+	}
+	tokens = tokens.filter(t => t.length >= 2);
+	if (tokens.length === 0) return [];
+
+	const distance = (a, b) => {
+		let prev = Array.from({length: b.length + 1}, (_, i) => i);
+		for (let i = 1; i <= a.length; i++) {
+			const cur = [i];
+			for (let j = 1; j <= b.length; j++) {
+				cur[j] = Math.min(prev[j] + 1, cur[j - 1] + 1, prev[j - 1] + (a[i - 1] === b[j - 1] ? 0 : 1));
+			}
+			prev = cur;
+		}
+		return prev[b.length];
+	};
+	const similarity = name => {
+		const n = norm(name).slice(0, 64);
+		if (n.length < 2) return 0;
+		let best = 0;
+		for (const t of tokens) {
+			let score = 1 - distance(t, n) / Math.max(t.length, n.length);
+			if (n.includes(t) || t.includes(n)) {
+				score = Math.max(score, 0.5 + 0.5 * Math.min(t.length, n.length) / Math.max(t.length, n.length));
+			}
+			best = Math.max(best, score);
+		}
+		return best;
+	};
+
+	const scores = new Map();
+	const consider = (selector, name) => {
+		if (!selector || selector === failed) return;
+		const score = similarity(name);
+		if (score >= 0.5 && score > (scores.get(selector) || 0)) scores.set(selector, score);
+	};
+	const attr = (name, value) => '[' + name + '="' + value.replace(/["\\]/g, '\\$&') + '"]';
+	const interactive = 'a, button, input, select, textarea, [role="button"], [role="link"]';
+
+	const elements = Array.from(document.body ? document.body.querySelectorAll('*') : []).slice(0, 5000);
+	for (const el of elements) {
+		if (el.id) consider('#' + CSS.escape(el.id), el.id);
+		if (typeof el.className === 'string') {
+			for (const cls of el.className.split(/\s+/).filter(Boolean).slice(0, 3)) {
+				consider('.' + CSS.escape(cls), cls);
+			}
+		}
+		const testId = el.getAttribute('data-testid');
+		if (testId) consider(attr('data-testid', testId), testId);
+		const name = el.getAttribute('name');
+		if (name) consider(el.tagName.toLowerCase() + attr('name', name), name);
+		if (el.matches(interactive)) {
+			const text = (el.innerText || el.value || el.getAttribute('aria-label') || '').trim();
+			if (text && text.length <= 40) consider(cssPath(el), text);
+		}
+	}
+
+	return Array.from(scores.entries())
+		.sort((a, b) => b[1] - a[1] || a[0].length - b[0].length)
+		.map(([selector]) => selector)
+		.filter(selector => { try { return document.querySelector(selector) !== null; } catch { return false; } })
+		.slice(0, limit);
+}`
+
+// suggestSelectorsScript returns suggestSelectors(failed, limit).
+const suggestSelectorsScript = `(failed, limit) => {
+	` + cssPathFunction + `
+	` + suggestSelectorsFunction + `
+	return suggestSelectors(failed, limit);
+}`
+
+// SuggestSelectors returns up to limit selectors on the current page that
+// resemble failed, best match first, for "did you mean" hints after a
+// selector matched nothing. Candidates come from element ids, classes,
+// data-testid and name attributes, and the text of interactive elements.
+// If limit is not positive, DefaultSuggestionLimit is used.
+func (p *Pilot) SuggestSelectors(ctx context.Context, failed string, limit int) ([]string, error) {
+	if p.closed.Load() {
+		return nil, ErrConnectionClosed
+	}
+	if limit <= 0 {
+		limit = DefaultSuggestionLimit
+	}
+
+	result, err := p.EvaluateWithArgs(ctx, suggestSelectorsScript, failed, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest selectors: %w", err)
+	}
+
+	items, _ := result.([]interface{})
+	suggestions := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			suggestions = append(suggestions, s)
+		}
+	}
+	return suggestions, nil
+}
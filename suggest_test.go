@@ -0,0 +1,34 @@
+package w3pilot
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// TestSuggestSelectors verifies that the failed selector and limit are
+// passed to the page and the suggestions returned in order.
+func TestSuggestSelectors(t *testing.T) {
+	mock := newMockTransport()
+	mock.response = json.RawMessage(`{"result":{"type":"array","value":[
+		{"type":"string","value":"#submit-btn"},
+		{"type":"string","value":".submit"}
+	]}}`)
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	got, err := pilot.SuggestSelectors(context.Background(), "#sumbit-btn", 0)
+	if err != nil {
+		t.Fatalf("SuggestSelectors failed: %v", err)
+	}
+	if len(got) != 2 || got[0] != "#submit-btn" || got[1] != ".submit" {
+		t.Errorf("Unexpected suggestions: %v", got)
+	}
+
+	args := mock.calls[0].Params.(map[string]interface{})["arguments"].([]interface{})
+	if v := args[0].(map[string]interface{})["value"]; v != "#sumbit-btn" {
+		t.Errorf("Expected failed selector argument, got %v", v)
+	}
+	if v := args[1].(map[string]interface{})["value"]; v != DefaultSuggestionLimit {
+		t.Errorf("Expected default limit %d, got %v", DefaultSuggestionLimit, v)
+	}
+}
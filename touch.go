@@ -2,8 +2,17 @@ package w3pilot
 
 import (
 	"context"
+	"math"
+	"time"
 )
 
+// DefaultLongPressDuration is how long LongPress holds when no duration is given.
+const DefaultLongPressDuration = 800 * time.Millisecond
+
+// doubleTapInterval is the pause between the two taps of a double tap,
+// well within the browser's double-tap recognition window.
+const doubleTapInterval = 100 * time.Millisecond
+
 // Touch provides touch input control.
 type Touch struct {
 	client  *BiDiClient
@@ -57,3 +66,55 @@ func (t *Touch) Pinch(ctx context.Context, x, y float64, scale float64) error {
 	_, err := t.client.Send(ctx, "vibium:touch.pinch", params)
 	return err
 }
+
+// DoubleTap performs two quick taps at the specified coordinates,
+// e.g. to trigger double-tap-to-zoom.
+func (t *Touch) DoubleTap(ctx context.Context, x, y float64) error {
+	return t.performTouch(ctx, x, y, []map[string]interface{}{
+		{"type": "pointerDown", "button": 0},
+		{"type": "pointerUp", "button": 0},
+		{"type": "pause", "duration": doubleTapInterval.Milliseconds()},
+		{"type": "pointerDown", "button": 0},
+		{"type": "pointerUp", "button": 0},
+	})
+}
+
+// LongPress touches the specified coordinates and holds for duration,
+// e.g. to open a context menu. Zero uses DefaultLongPressDuration.
+func (t *Touch) LongPress(ctx context.Context, x, y float64, duration time.Duration) error {
+	if duration <= 0 {
+		duration = DefaultLongPressDuration
+	}
+	return t.performTouch(ctx, x, y, []map[string]interface{}{
+		{"type": "pointerDown", "button": 0},
+		{"type": "pause", "duration": duration.Milliseconds()},
+		{"type": "pointerUp", "button": 0},
+	})
+}
+
+// performTouch moves a touch pointer to (x, y) and performs the given
+// pointer actions there using BiDi input.performActions.
+func (t *Touch) performTouch(ctx context.Context, x, y float64, actions []map[string]interface{}) error {
+	sequence := make([]map[string]interface{}, 0, len(actions)+1)
+	sequence = append(sequence, map[string]interface{}{
+		"type": "pointerMove",
+		"x":    int(math.Round(x)),
+		"y":    int(math.Round(y)),
+	})
+	sequence = append(sequence, actions...)
+
+	params := map[string]interface{}{
+		"context": t.context,
+		"actions": []interface{}{
+			map[string]interface{}{
+				"type":       "pointer",
+				"id":         "touch",
+				"parameters": map[string]interface{}{"pointerType": "touch"},
+				"actions":    sequence,
+			},
+		},
+	}
+
+	_, err := t.client.Send(ctx, "input.performActions", params)
+	return err
+}
@@ -0,0 +1,57 @@
+package w3pilot
+
+import (
+	"context"
+	"fmt"
+)
+
+// Tracer starts a span around every command a Pilot sends to the browser,
+// so browser latency shows up in distributed traces. Spans are started from
+// the context passed to the Pilot method, so they nest under the caller's
+// span.
+//
+// The interface is deliberately small so that this module does not depend
+// on a tracing library. An OpenTelemetry adapter wraps a trace.Tracer in a
+// few lines; see the SDK guide.
+type Tracer interface {
+	// StartSpan starts a span named after the command (for example
+	// "vibium:element.click") with the given attributes, and returns the
+	// context to send the command with.
+	StartSpan(ctx context.Context, name string, attrs map[string]string) (context.Context, Span)
+}
+
+// Span is a single traced command started by a Tracer.
+type Span interface {
+	// End finishes the span, recording err on it if non-nil.
+	End(err error)
+}
+
+// Span attribute keys set by the BiDi client.
+const (
+	SpanAttrCommand  = "w3pilot.command"
+	SpanAttrSelector = "w3pilot.selector"
+	SpanAttrURL      = "w3pilot.url"
+	SpanAttrContext  = "w3pilot.context"
+)
+
+// spanAttributes extracts the attributes worth tracing from command params.
+func spanAttributes(method string, params interface{}) map[string]string {
+	attrs := map[string]string{SpanAttrCommand: method}
+
+	m, ok := params.(map[string]interface{})
+	if !ok {
+		return attrs
+	}
+	for key, attr := range map[string]string{
+		"selector": SpanAttrSelector,
+		"url":      SpanAttrURL,
+		"context":  SpanAttrContext,
+	} {
+		if v, ok := m[key]; ok && v != nil {
+			if s := fmt.Sprint(v); s != "" {
+				attrs[attr] = s
+			}
+		}
+	}
+	return attrs
+}
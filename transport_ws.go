@@ -22,14 +22,20 @@ type wsTransport struct {
 	closed    bool
 	closedMu  sync.RWMutex
 	closeCh   chan struct{}
+
+	// Keepalive: ping every pingInterval (0 disables); lastPong is UnixNano
+	pingInterval time.Duration
+	lastPong     atomic.Int64
 }
 
-// newWSTransport creates a new WebSocket transport.
-func newWSTransport() *wsTransport {
+// newWSTransport creates a new WebSocket transport that pings the server
+// every pingInterval to keep idle connections alive. Zero disables pings.
+func newWSTransport(pingInterval time.Duration) *wsTransport {
 	return &wsTransport{
-		pending:  make(map[int64]chan *BiDiResponse),
-		handlers: make(map[string][]EventHandler),
-		closeCh:  make(chan struct{}),
+		pending:      make(map[int64]chan *BiDiResponse),
+		handlers:     make(map[string][]EventHandler),
+		closeCh:      make(chan struct{}),
+		pingInterval: pingInterval,
 	}
 }
 
@@ -44,12 +50,49 @@ func (t *wsTransport) Connect(ctx context.Context, url string) error {
 
 	t.conn = conn
 
+	// Pongs are delivered by the read loop, so register before starting it
+	t.lastPong.Store(time.Now().UnixNano())
+	conn.SetPongHandler(func(string) error {
+		t.lastPong.Store(time.Now().UnixNano())
+		return nil
+	})
+
 	// Start reading messages
 	go t.readLoop()
 
+	if t.pingInterval > 0 {
+		go t.keepalive()
+	}
+
 	return nil
 }
 
+// keepalive pings the server every pingInterval so proxies and load balancers
+// don't drop the connection while it sits idle. If no pong arrives for two
+// intervals the connection is treated as dead and closed, so pending and
+// future commands fail with ErrConnectionClosed instead of hanging.
+func (t *wsTransport) keepalive() {
+	ticker := time.NewTicker(t.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.closeCh:
+			return
+		case <-ticker.C:
+			if time.Since(time.Unix(0, t.lastPong.Load())) > 2*t.pingInterval {
+				_ = t.Close()
+				return
+			}
+			deadline := time.Now().Add(t.pingInterval)
+			if err := t.conn.WriteControl(websocket.PingMessage, nil, deadline); err != nil {
+				_ = t.Close()
+				return
+			}
+		}
+	}
+}
+
 // WaitForReady waits for the browser to be ready after connecting.
 // In serve mode, clicker sends browsingContext.contextCreated when the browser is ready.
 func (t *wsTransport) WaitForReady(ctx context.Context, timeout time.Duration) error {
@@ -0,0 +1,83 @@
+package w3pilot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// newWSTestServer starts a WebSocket server that reads until the client goes away.
+// If answerPings is false, pings are swallowed to simulate a dead peer.
+func newWSTestServer(t *testing.T, answerPings bool) string {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if !answerPings {
+			conn.SetPingHandler(func(string) error { return nil })
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+// TestWSTransport_KeepaliveClosesOnMissingPong verifies a silent peer is detected.
+func TestWSTransport_KeepaliveClosesOnMissingPong(t *testing.T) {
+	transport := newWSTransport(10 * time.Millisecond)
+	if err := transport.Connect(context.Background(), newWSTestServer(t, false)); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	select {
+	case <-transport.closeCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected transport to close after missing pongs")
+	}
+
+	if _, err := transport.Send(context.Background(), "session.status", nil); err != ErrConnectionClosed {
+		t.Errorf("Expected ErrConnectionClosed, got %v", err)
+	}
+}
+
+// TestWSTransport_KeepaliveStaysOpen verifies answered pings keep the connection open.
+func TestWSTransport_KeepaliveStaysOpen(t *testing.T) {
+	transport := newWSTransport(10 * time.Millisecond)
+	if err := transport.Connect(context.Background(), newWSTestServer(t, true)); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	select {
+	case <-transport.closeCh:
+		t.Fatal("Expected transport to stay open while pongs arrive")
+	case <-time.After(150 * time.Millisecond):
+	}
+}
+
+// TestPingInterval verifies LaunchOptions.PingInterval defaults and disabling.
+func TestPingInterval(t *testing.T) {
+	if got := pingInterval(0); got != DefaultPingInterval {
+		t.Errorf("pingInterval(0) = %v, want %v", got, DefaultPingInterval)
+	}
+	if got := pingInterval(-1); got != 0 {
+		t.Errorf("pingInterval(-1) = %v, want 0", got)
+	}
+	if got := pingInterval(time.Second); got != time.Second {
+		t.Errorf("pingInterval(1s) = %v, want 1s", got)
+	}
+}
@@ -2,7 +2,13 @@
 // It launches Chrome with BiDi support and communicates over WebSocket.
 package w3pilot
 
-import "time"
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+)
 
 // BoundingBox represents the position and size of an element.
 type BoundingBox struct {
@@ -12,11 +18,49 @@ type BoundingBox struct {
 	Height float64 `json:"height"`
 }
 
+// Area returns the area of the box, or 0 for an empty box.
+func (b BoundingBox) Area() float64 {
+	if b.Width <= 0 || b.Height <= 0 {
+		return 0
+	}
+	return b.Width * b.Height
+}
+
+// Contains reports whether the point (x, y) lies within the box.
+// The left and top edges are inclusive; the right and bottom edges are exclusive.
+func (b BoundingBox) Contains(x, y float64) bool {
+	return x >= b.X && x < b.X+b.Width && y >= b.Y && y < b.Y+b.Height
+}
+
+// Intersects reports whether the two boxes overlap with a non-zero area.
+// Boxes that only touch along an edge do not intersect.
+func (b BoundingBox) Intersects(other BoundingBox) bool {
+	return b.Intersection(other).Area() > 0
+}
+
+// Intersection returns the overlapping region of the two boxes.
+// If the boxes do not overlap, it returns the zero BoundingBox.
+func (b BoundingBox) Intersection(other BoundingBox) BoundingBox {
+	x1 := math.Max(b.X, other.X)
+	y1 := math.Max(b.Y, other.Y)
+	x2 := math.Min(b.X+b.Width, other.X+other.Width)
+	y2 := math.Min(b.Y+b.Height, other.Y+other.Height)
+	if x2 <= x1 || y2 <= y1 {
+		return BoundingBox{}
+	}
+	return BoundingBox{X: x1, Y: y1, Width: x2 - x1, Height: y2 - y1}
+}
+
 // ElementInfo contains metadata about a DOM element.
 type ElementInfo struct {
 	Tag  string      `json:"tag"`
 	Text string      `json:"text"`
 	Box  BoundingBox `json:"box"`
+
+	// Role and Label are the ARIA role and accessible label.
+	// They are only populated by TabOrder.
+	Role  string `json:"role,omitempty"`
+	Label string `json:"label,omitempty"`
 }
 
 // LaunchOptions configures browser launch behavior.
@@ -37,6 +81,43 @@ type LaunchOptions struct {
 	// If empty, it will be discovered automatically from PATH or standard locations.
 	ExecutablePath string
 
+	// LogRequests emits a structured slog record for every network request
+	// (method, url, status, duration). Records go to the logger attached via
+	// ContextWithLogger, or slog.Default() if none is present.
+	LogRequests bool
+
+	// LogRequestsLevel is the slog level used for request records.
+	// Default (nil) is slog.LevelDebug, so records are dropped by handlers at Info or above.
+	LogRequestsLevel slog.Leveler
+
+	// SlowMo pauses before every element, mouse, keyboard, and touch action
+	// so a human can follow along in headful mode. Default is 0 (no delay).
+	SlowMo time.Duration
+
+	// OnCommand, if set, is called after every BiDi command completes with its
+	// method, round-trip duration, and error, e.g. to export command latency
+	// metrics. It runs on the calling goroutine, so it should return quickly.
+	OnCommand func(method string, dur time.Duration, err error)
+
+	// Tracer, if set, wraps every BiDi command in a span started from the
+	// caller's context, with the selector and URL as attributes. See Tracer
+	// for wiring it to OpenTelemetry.
+	Tracer Tracer
+
+	// PingInterval is how often to ping the WebSocket server to keep idle
+	// connections alive behind proxies (WebSocket mode only). If no pong
+	// arrives within two intervals, the connection is closed and commands
+	// fail with ErrConnectionClosed. Default (0) is DefaultPingInterval;
+	// a negative value disables pings.
+	PingInterval time.Duration
+
+	// Stealth applies common mitigations against headless and automation
+	// detection: navigator.webdriver, plugins, languages, window.chrome, and
+	// a user agent without "HeadlessChrome", via an init script and, where
+	// the browser supports it, a user agent override. It is best-effort:
+	// sites can still detect automation by other means.
+	Stealth bool
+
 	// Deprecated: UserDataDir is now handled by vibium.
 	UserDataDir string
 
@@ -61,6 +142,16 @@ type FindOptions struct {
 	// Text matches elements containing the specified text.
 	Text string
 
+	// ExactText requires Text and Label to match the whole (trimmed) text
+	// instead of a substring, e.g. "Log in" but not "Log in with Google".
+	// It is shorthand for TextMatch "exact".
+	ExactText bool
+
+	// TextMatch controls how Text and Label are matched: "contains" (default),
+	// "exact", or "regex". Regex patterns use JavaScript syntax and are
+	// compiled by the browser.
+	TextMatch string
+
 	// Label matches elements by their associated label text.
 	Label string
 
@@ -81,6 +172,110 @@ type FindOptions struct {
 
 	// Near finds elements near another element specified by selector.
 	Near string
+
+	// Spatial selectors match elements by their position relative to another
+	// element specified by selector. Candidates are ranked by distance to the
+	// anchor, closest first.
+
+	// Below matches elements positioned below the anchor element.
+	Below string
+
+	// Above matches elements positioned above the anchor element.
+	Above string
+
+	// LeftOf matches elements positioned to the left of the anchor element.
+	LeftOf string
+
+	// RightOf matches elements positioned to the right of the anchor element.
+	RightOf string
+
+	// MaxDistance limits Near and spatial matches to elements within this many
+	// CSS pixels of the anchor element. Zero means no limit.
+	MaxDistance float64
+
+	// Visible restricts matches to elements that are rendered and visible
+	// (not display:none, visibility:hidden, or zero-sized).
+	Visible bool
+
+	// Hidden restricts matches to elements that are not visible.
+	// Visible and Hidden are mutually exclusive.
+	Hidden bool
+}
+
+// addFindOptions adds semantic selector options to a find command's params.
+func addFindOptions(params map[string]interface{}, opts *FindOptions) error {
+	if opts == nil {
+		return nil
+	}
+	if opts.Visible && opts.Hidden {
+		return fmt.Errorf("FindOptions.Visible and FindOptions.Hidden are mutually exclusive")
+	}
+	if opts.Role != "" {
+		params["role"] = opts.Role
+	}
+	if opts.Text != "" {
+		params["text"] = opts.Text
+	}
+	textMatch := opts.TextMatch
+	if opts.ExactText {
+		if textMatch != "" && textMatch != "exact" {
+			return fmt.Errorf("FindOptions.ExactText conflicts with TextMatch %q", textMatch)
+		}
+		textMatch = "exact"
+	}
+	switch textMatch {
+	case "":
+	case "exact":
+		params["exact"] = true
+		params["textMatch"] = textMatch
+	case "contains", "regex":
+		params["textMatch"] = textMatch
+	default:
+		return fmt.Errorf("invalid FindOptions.TextMatch %q: must be contains, exact, or regex", textMatch)
+	}
+	if opts.Label != "" {
+		params["label"] = opts.Label
+	}
+	if opts.Placeholder != "" {
+		params["placeholder"] = opts.Placeholder
+	}
+	if opts.TestID != "" {
+		params["testid"] = opts.TestID
+	}
+	if opts.Alt != "" {
+		params["alt"] = opts.Alt
+	}
+	if opts.Title != "" {
+		params["title"] = opts.Title
+	}
+	if opts.XPath != "" {
+		params["xpath"] = opts.XPath
+	}
+	if opts.Near != "" {
+		params["near"] = opts.Near
+	}
+	if opts.Below != "" {
+		params["below"] = opts.Below
+	}
+	if opts.Above != "" {
+		params["above"] = opts.Above
+	}
+	if opts.LeftOf != "" {
+		params["leftOf"] = opts.LeftOf
+	}
+	if opts.RightOf != "" {
+		params["rightOf"] = opts.RightOf
+	}
+	if opts.MaxDistance > 0 {
+		params["maxDistance"] = opts.MaxDistance
+	}
+	if opts.Visible {
+		params["visible"] = true
+	}
+	if opts.Hidden {
+		params["hidden"] = true
+	}
+	return nil
 }
 
 // SelectOptionValues specifies which options to select in a <select> element.
@@ -93,6 +288,11 @@ type SelectOptionValues struct {
 
 	// Indexes selects options by their zero-based index.
 	Indexes []int
+
+	// AllowNoMatch lets SelectOption succeed when none of the requested
+	// values, labels, or indexes correspond to an option. By default this
+	// returns an *OptionNotFoundError.
+	AllowNoMatch bool
 }
 
 // Viewport represents the browser viewport dimensions.
@@ -120,6 +320,45 @@ type SetWindowOptions struct {
 	State  string // "normal", "minimized", "maximized", "fullscreen"
 }
 
+// EvaluateOptions configures script evaluation.
+type EvaluateOptions struct {
+	// Sandbox runs the script in the named isolated realm instead of the
+	// page's main world. Empty uses the main world.
+	Sandbox string
+}
+
+// ReloadOptions configures page reloads.
+type ReloadOptions struct {
+	// IgnoreCache bypasses the HTTP cache, like a hard reload.
+	IgnoreCache bool
+
+	// WaitUntil is the readiness state to wait for: "none", "interactive",
+	// or "complete". Defaults to "complete".
+	WaitUntil string
+}
+
+// WaitForTextOptions configures Pilot.WaitForTextWithOptions.
+type WaitForTextOptions struct {
+	// Timeout is how long to wait. Default is 30 seconds.
+	Timeout time.Duration
+
+	// Selector limits the search to the first element matching it.
+	// Default is the whole page body.
+	Selector string
+
+	// TextMatch controls how text is matched, as in FindOptions:
+	// "contains" (default), "exact", or "regex". Exact matches an element
+	// whose whole (trimmed) text equals text; regex patterns use JavaScript
+	// syntax and are compiled by the browser.
+	TextMatch string
+}
+
+// ScreenshotOptions configures page screenshots.
+type ScreenshotOptions struct {
+	// FullPage captures the entire scrollable page instead of the viewport.
+	FullPage bool
+}
+
 // PDFOptions configures PDF generation.
 type PDFOptions struct {
 	Path            string
@@ -133,6 +372,13 @@ type PDFOptions struct {
 	Width           string
 	Height          string
 	Margin          *PDFMargin
+
+	// HeaderTemplate and FooterTemplate are HTML used for the page header and
+	// footer when DisplayHeader/DisplayFooter are set. Elements with the classes
+	// pageNumber, totalPages, date, title, or url are filled in per page, e.g.
+	// `<span class="pageNumber"></span> / <span class="totalPages"></span>`.
+	HeaderTemplate string
+	FooterTemplate string
 }
 
 // PDFMargin configures PDF page margins.
@@ -149,6 +395,31 @@ type FrameInfo struct {
 	Name string `json:"name"`
 }
 
+// Frame event types reported to a FrameHandler.
+const (
+	FrameAttached  = "attached"
+	FrameDetached  = "detached"
+	FrameNavigated = "navigated"
+)
+
+// FrameEvent describes a frame being attached to, detached from, or
+// finishing a navigation within a page.
+type FrameEvent struct {
+	// Type is FrameAttached, FrameDetached, or FrameNavigated.
+	Type string
+
+	// Context is the frame's browsing context ID, and Parent the ID of the
+	// context containing it. Parent is empty for the top-level page.
+	Context string
+	Parent  string
+
+	// URL is the frame's URL, when known.
+	URL string
+
+	// Frame is a Pilot bound to the frame. It is nil for FrameDetached.
+	Frame *Pilot
+}
+
 // EmulateMediaOptions configures media emulation for accessibility testing.
 type EmulateMediaOptions struct {
 	Media         string // "screen", "print", or ""
@@ -210,6 +481,51 @@ type ActionOptions struct {
 	// Timeout specifies how long to wait for actionability.
 	// Default is 30 seconds.
 	Timeout time.Duration
+
+	// Delay is the pause between characters when typing.
+	// Use this for debounced inputs such as autocomplete or input masks.
+	// Default is 0 (type the whole string at once). Only honored by Type.
+	Delay time.Duration
+
+	// Position is the point to act on, relative to the top-left corner of
+	// the element's bounding box. Default is the element's center.
+	// Honored by Click, DblClick, and Hover.
+	Position *Point
+
+	// Force skips the actionability checks (visible, stable, receives
+	// events, enabled, editable) and dispatches the action directly.
+	// Use it only when the checks misjudge an element, such as one covered
+	// by a decorative overlay. A forced action can hit a hidden or disabled
+	// element, or land on whatever element is on top at that point, so
+	// it may pass where a real user would be blocked.
+	Force bool
+
+	// Trial runs the actionability checks and returns nil if they pass,
+	// without performing the action. Use it to verify that an element could
+	// be clicked or filled before committing to a state-changing action.
+	Trial bool
+
+	// Masked makes Fill type the value one character at a time, as input
+	// masks (phone numbers, card numbers) expect, and then verify the
+	// field's value. Characters the mask inserts, such as spaces, dashes,
+	// and parentheses, are ignored when comparing, and the value may be
+	// surrounded by fixed mask text such as a "+1" prefix. On a mismatch
+	// the field is cleared and typed once more before Fill returns an
+	// error. Only honored by Fill.
+	Masked bool
+}
+
+// Point is a position in CSS pixels.
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// TypeOptions configures keyboard typing behavior.
+type TypeOptions struct {
+	// Delay is the pause between characters.
+	// Default is 0 (type the whole string at once).
+	Delay time.Duration
 }
 
 // A11yTreeOptions configures accessibility tree retrieval.
@@ -230,10 +546,55 @@ type HighlightOptions struct {
 	// Default is "red".
 	Color string
 
+	// Label is optional text drawn above the highlighted element.
+	Label string
+
 	// Duration is how long to show the highlight in milliseconds.
-	// Default is 2000 (2 seconds). Use 0 for persistent highlight.
+	// Default is 2000 (2 seconds).
 	Duration int
+
+	// Persistent keeps the highlight until Unhighlight is called,
+	// ignoring Duration.
+	Persistent bool
+}
+
+// WaitForNetworkOptions configures WaitForRequest and WaitForResponse.
+type WaitForNetworkOptions struct {
+	// Timeout is the maximum time to wait. Default is DefaultTimeout.
+	Timeout time.Duration
+
+	// Since also matches buffered events observed at or after this time,
+	// so a wait started after an action can still see the request it caused.
+	// Only events seen while network tracking is active are buffered (see TrackNetwork).
+	Since time.Time
 }
 
 // DefaultTimeout is the default timeout for finding elements and waiting for actionability.
 const DefaultTimeout = 30 * time.Second
+
+// DefaultPingInterval is the default WebSocket keepalive ping interval.
+const DefaultPingInterval = 30 * time.Second
+
+// DefaultStableInterval is the default sampling interval for Element.WaitForStable.
+const DefaultStableInterval = 100 * time.Millisecond
+
+// withTimeout derives a context bounded by timeout, never extending ctx's own
+// deadline. It returns the effective timeout, which is the sooner of the two,
+// so callers can pass it on to the browser. It fails without deriving a
+// context if ctx is already done.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc, time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return ctx, func() {}, 0, fmt.Errorf("context done before operation started: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return ctx, func() {}, 0, fmt.Errorf("context done before operation started: %w", context.DeadlineExceeded)
+		}
+		if remaining < timeout {
+			timeout = remaining
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, cancel, timeout, nil
+}
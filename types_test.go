@@ -0,0 +1,48 @@
+package w3pilot
+
+import "testing"
+
+// TestBoundingBox_Geometry verifies Area, Contains, Intersects and Intersection.
+func TestBoundingBox_Geometry(t *testing.T) {
+	header := BoundingBox{X: 0, Y: 0, Width: 800, Height: 60}
+	input := BoundingBox{X: 100, Y: 40, Width: 200, Height: 30}
+	below := BoundingBox{X: 100, Y: 60, Width: 200, Height: 30}
+
+	if got := input.Area(); got != 6000 {
+		t.Errorf("Area() = %v, want 6000", got)
+	}
+	if got := (BoundingBox{Width: -1, Height: 10}).Area(); got != 0 {
+		t.Errorf("Area() of empty box = %v, want 0", got)
+	}
+
+	containsTests := []struct {
+		x, y float64
+		want bool
+	}{
+		{100, 40, true},
+		{299, 69, true},
+		{300, 50, false},
+		{150, 70, false},
+		{99, 50, false},
+	}
+	for _, tt := range containsTests {
+		if got := input.Contains(tt.x, tt.y); got != tt.want {
+			t.Errorf("Contains(%v, %v) = %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+
+	if !header.Intersects(input) || !input.Intersects(header) {
+		t.Error("Expected header to intersect input")
+	}
+	if header.Intersects(below) {
+		t.Error("Expected edge-touching boxes not to intersect")
+	}
+
+	want := BoundingBox{X: 100, Y: 40, Width: 200, Height: 20}
+	if got := header.Intersection(input); got != want {
+		t.Errorf("Intersection() = %+v, want %+v", got, want)
+	}
+	if got := header.Intersection(below); got != (BoundingBox{}) {
+		t.Errorf("Intersection() of disjoint boxes = %+v, want zero", got)
+	}
+}
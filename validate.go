@@ -20,7 +20,7 @@ type SelectorValidation struct {
 // ValidateSelectors checks multiple selectors and returns validation results.
 // This helps AI agents verify selectors before attempting interactions.
 func (p *Pilot) ValidateSelectors(ctx context.Context, selectors []string) ([]SelectorValidation, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return nil, ErrConnectionClosed
 	}
 
@@ -59,55 +59,8 @@ func (p *Pilot) ValidateSelectors(ctx context.Context, selectors []string) ([]Se
 				return !el.disabled && !el.getAttribute('aria-disabled');
 			}
 
-			function findSuggestions(selector) {
-				const suggestions = [];
-
-				// Extract the base name from the selector
-				let baseName = selector;
-				if (baseName.startsWith('#') || baseName.startsWith('.')) {
-					baseName = baseName.substring(1);
-				}
-				// Remove attribute selectors
-				baseName = baseName.replace(/\[.*\]/g, '');
-				// Remove pseudo-selectors
-				baseName = baseName.replace(/:.*$/g, '');
-
-				if (!baseName) return suggestions;
-
-				// Try ID variations
-				['#' + baseName, '#' + baseName + '-btn', '#' + baseName + 'Btn', '#' + baseName + '-button'].forEach(sel => {
-					try { if (document.querySelector(sel)) suggestions.push(sel); } catch {}
-				});
-
-				// Try class variations
-				['.' + baseName, '.' + baseName + '-btn', '.' + baseName + '-button', '.' + baseName.toLowerCase()].forEach(sel => {
-					try { if (document.querySelector(sel)) suggestions.push(sel); } catch {}
-				});
-
-				// Try data-testid
-				try {
-					const testId = document.querySelector('[data-testid="' + baseName + '"]');
-					if (testId) suggestions.push('[data-testid="' + baseName + '"]');
-				} catch {}
-
-				// Find elements with similar text content
-				const lowerBase = baseName.toLowerCase();
-				document.querySelectorAll('button, a, input[type="submit"], [role="button"]').forEach(el => {
-					const text = (el.textContent || el.value || '').toLowerCase();
-					if (text.includes(lowerBase) && suggestions.length < 5) {
-						if (el.id) {
-							const idSel = '#' + el.id;
-							if (!suggestions.includes(idSel)) suggestions.push(idSel);
-						} else if (el.className && typeof el.className === 'string') {
-							const classSel = '.' + el.className.split(' ')[0];
-							if (!suggestions.includes(classSel)) suggestions.push(classSel);
-						}
-					}
-				});
-
-				// Remove the original selector from suggestions
-				return [...new Set(suggestions)].filter(s => s !== selector).slice(0, 5);
-			}
+			%s
+			%s
 
 			for (const selector of selectors) {
 				let found = false;
@@ -128,7 +81,7 @@ func (p *Pilot) ValidateSelectors(ctx context.Context, selectors []string) ([]Se
 						enabled = isEnabled(el);
 						tagName = el.tagName.toLowerCase();
 					} else {
-						suggestions = findSuggestions(selector);
+						suggestions = suggestSelectors(selector, %d);
 					}
 				} catch (e) {
 					// Invalid selector syntax
@@ -149,11 +102,11 @@ func (p *Pilot) ValidateSelectors(ctx context.Context, selectors []string) ([]Se
 
 			return JSON.stringify(results);
 		})()
-	`, string(selectorsJSON))
+	`, string(selectorsJSON), cssPathFunction, suggestSelectorsFunction, DefaultSuggestionLimit)
 
 	// Execute via Evaluate
 	rawResult, err := p.client.Send(ctx, "script.callFunction", map[string]interface{}{
-		"functionDeclaration": "() => { " + script + " }",
+		"functionDeclaration": "() => (" + script + ")",
 		"target":              map[string]interface{}{"context": browsingCtx},
 		"arguments":           []interface{}{},
 		"awaitPromise":        true,
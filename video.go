@@ -32,7 +32,7 @@ type Video struct {
 // StartVideo starts recording video of the page.
 // The video is saved when StopVideo is called or the browser closes.
 func (p *Pilot) StartVideo(ctx context.Context, opts *VideoOptions) (*Video, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return nil, ErrConnectionClosed
 	}
 
@@ -78,7 +78,7 @@ func (p *Pilot) StartVideo(ctx context.Context, opts *VideoOptions) (*Video, err
 
 // StopVideo stops video recording and returns the video path.
 func (p *Pilot) StopVideo(ctx context.Context) (string, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return "", ErrConnectionClosed
 	}
 
@@ -0,0 +1,176 @@
+// Package visual compares screenshots for visual regression testing.
+//
+// It decodes PNG or JPEG images, such as those returned by Pilot.Screenshot,
+// and reports how many pixels differ from a baseline:
+//
+//	actual, _ := pilot.Screenshot(ctx)
+//	baseline, _ := os.ReadFile("testdata/home.png")
+//	diff, err := visual.Compare(baseline, actual, visual.CompareOptions{Threshold: 0.1})
+//	if err == nil && diff.DiffPercent > 0.5 {
+//		os.WriteFile("home.diff.png", diff.DiffImage, 0644)
+//	}
+package visual
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" // register JPEG decoder
+	"image/png"
+
+	"github.com/plexusone/w3pilot"
+)
+
+// ErrSizeMismatch is returned when the baseline and actual images have
+// different dimensions.
+var ErrSizeMismatch = errors.New("image sizes differ")
+
+// CompareOptions configures Compare.
+type CompareOptions struct {
+	// Threshold is the per-pixel color tolerance from 0 to 1. A pixel counts
+	// as changed when any RGBA channel differs by more than this fraction of
+	// its range. Zero requires an exact match; around 0.1 absorbs
+	// anti-aliasing and JPEG noise.
+	Threshold float64
+
+	// IgnoreRegions are areas excluded from comparison, in image pixels
+	// (CSS pixels multiplied by the device scale factor), e.g. timestamps
+	// or ads.
+	IgnoreRegions []w3pilot.BoundingBox
+
+	// DiffImage requests a PNG in DiffResult.DiffImage with changed pixels
+	// in red over a faded copy of the actual image.
+	DiffImage bool
+}
+
+// DiffResult describes the difference between two images.
+type DiffResult struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+
+	// DiffPixels is the number of changed pixels outside ignored regions.
+	DiffPixels int `json:"diff_pixels"`
+
+	// ComparedPixels is the number of pixels outside ignored regions.
+	ComparedPixels int `json:"compared_pixels"`
+
+	// DiffPercent is DiffPixels as a percentage of ComparedPixels.
+	DiffPercent float64 `json:"diff_percent"`
+
+	// DiffImage is the PNG diff image when CompareOptions.DiffImage is set.
+	DiffImage []byte `json:"-"`
+}
+
+// Match reports whether no more than maxPercent of compared pixels changed.
+func (r *DiffResult) Match(maxPercent float64) bool {
+	return r.DiffPercent <= maxPercent
+}
+
+// Compare decodes baseline and actual and compares them pixel by pixel.
+// Both images must have the same dimensions.
+func Compare(baseline, actual []byte, opts CompareOptions) (*DiffResult, error) {
+	base, _, err := image.Decode(bytes.NewReader(baseline))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode baseline: %w", err)
+	}
+	act, _, err := image.Decode(bytes.NewReader(actual))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode actual: %w", err)
+	}
+	return CompareImages(base, act, opts)
+}
+
+// CompareImages compares two decoded images pixel by pixel.
+func CompareImages(baseline, actual image.Image, opts CompareOptions) (*DiffResult, error) {
+	bb, ab := baseline.Bounds(), actual.Bounds()
+	if bb.Dx() != ab.Dx() || bb.Dy() != ab.Dy() {
+		return nil, fmt.Errorf("%w: baseline %dx%d, actual %dx%d",
+			ErrSizeMismatch, bb.Dx(), bb.Dy(), ab.Dx(), ab.Dy())
+	}
+
+	width, height := ab.Dx(), ab.Dy()
+	tolerance := uint32(opts.Threshold * 0xffff)
+
+	ignore := make([]image.Rectangle, len(opts.IgnoreRegions))
+	for i, r := range opts.IgnoreRegions {
+		ignore[i] = image.Rect(int(r.X), int(r.Y), int(r.X+r.Width), int(r.Y+r.Height))
+	}
+
+	var diff *image.RGBA
+	if opts.DiffImage {
+		diff = image.NewRGBA(image.Rect(0, 0, width, height))
+	}
+
+	result := &DiffResult{Width: width, Height: height}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			ac := actual.At(ab.Min.X+x, ab.Min.Y+y)
+
+			if ignored(ignore, x, y) {
+				if diff != nil {
+					diff.Set(x, y, color.RGBA{R: 200, G: 200, B: 200, A: 255})
+				}
+				continue
+			}
+			result.ComparedPixels++
+
+			changed := colorDistance(baseline.At(bb.Min.X+x, bb.Min.Y+y), ac) > tolerance
+			if changed {
+				result.DiffPixels++
+			}
+			if diff != nil {
+				if changed {
+					diff.Set(x, y, color.RGBA{R: 255, A: 255})
+				} else {
+					diff.Set(x, y, fade(ac))
+				}
+			}
+		}
+	}
+
+	if result.ComparedPixels > 0 {
+		result.DiffPercent = float64(result.DiffPixels) * 100 / float64(result.ComparedPixels)
+	}
+
+	if diff != nil {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, diff); err != nil {
+			return nil, fmt.Errorf("failed to encode diff image: %w", err)
+		}
+		result.DiffImage = buf.Bytes()
+	}
+
+	return result, nil
+}
+
+func ignored(regions []image.Rectangle, x, y int) bool {
+	p := image.Pt(x, y)
+	for _, r := range regions {
+		if p.In(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// colorDistance returns the largest per-channel difference on the 16-bit scale.
+func colorDistance(a, b color.Color) uint32 {
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+	return max(delta(ar, br), delta(ag, bg), delta(ab, bb), delta(aa, ba))
+}
+
+func delta(a, b uint32) uint32 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// fade renders an unchanged pixel as a light grayscale for the diff image.
+func fade(c color.Color) color.Color {
+	g := color.GrayModel.Convert(c).(color.Gray)
+	return color.Gray{Y: 192 + g.Y/4}
+}
@@ -0,0 +1,108 @@
+package visual
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/plexusone/w3pilot"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func solid(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestCompare(t *testing.T) {
+	white := color.RGBA{255, 255, 255, 255}
+	baseline := solid(10, 10, white)
+
+	actual := solid(10, 10, white)
+	// 4 clearly changed pixels in the top-left corner
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 2; x++ {
+			actual.Set(x, y, color.RGBA{0, 0, 0, 255})
+		}
+	}
+	// 1 barely changed pixel, within tolerance
+	actual.Set(9, 9, color.RGBA{250, 250, 250, 255})
+
+	result, err := Compare(encodePNG(t, baseline), encodePNG(t, actual), CompareOptions{Threshold: 0.1, DiffImage: true})
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if result.DiffPixels != 4 || result.ComparedPixels != 100 {
+		t.Errorf("Expected 4/100 changed pixels, got %d/%d", result.DiffPixels, result.ComparedPixels)
+	}
+	if result.DiffPercent != 4 {
+		t.Errorf("Expected 4%% difference, got %v", result.DiffPercent)
+	}
+	if result.Match(1) || !result.Match(5) {
+		t.Errorf("Unexpected Match results for %v%%", result.DiffPercent)
+	}
+
+	diff, err := png.Decode(bytes.NewReader(result.DiffImage))
+	if err != nil {
+		t.Fatalf("Failed to decode diff image: %v", err)
+	}
+	if r, g, _, _ := diff.At(0, 0).RGBA(); r != 0xffff || g != 0 {
+		t.Errorf("Expected changed pixel to be red in diff image")
+	}
+
+	// Exact comparison counts the subtle pixel too
+	exact, err := Compare(encodePNG(t, baseline), encodePNG(t, actual), CompareOptions{})
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if exact.DiffPixels != 5 {
+		t.Errorf("Expected 5 changed pixels with zero threshold, got %d", exact.DiffPixels)
+	}
+	if exact.DiffImage != nil {
+		t.Error("Expected no diff image unless requested")
+	}
+}
+
+func TestCompare_IgnoreRegions(t *testing.T) {
+	baseline := solid(10, 10, color.White)
+	actual := solid(10, 10, color.White)
+	actual.Set(1, 1, color.Black)
+
+	result, err := CompareImages(baseline, actual, CompareOptions{
+		IgnoreRegions: []w3pilot.BoundingBox{{X: 0, Y: 0, Width: 5, Height: 2}},
+	})
+	if err != nil {
+		t.Fatalf("CompareImages failed: %v", err)
+	}
+	if result.DiffPixels != 0 || result.ComparedPixels != 90 {
+		t.Errorf("Expected 0/90 changed pixels, got %d/%d", result.DiffPixels, result.ComparedPixels)
+	}
+}
+
+func TestCompare_Errors(t *testing.T) {
+	small := encodePNG(t, solid(5, 5, color.White))
+	large := encodePNG(t, solid(6, 5, color.White))
+
+	if _, err := Compare(small, large, CompareOptions{}); !errors.Is(err, ErrSizeMismatch) {
+		t.Errorf("Expected ErrSizeMismatch, got %v", err)
+	}
+	if _, err := Compare([]byte("not an image"), small, CompareOptions{}); err == nil {
+		t.Error("Expected decode error")
+	}
+}
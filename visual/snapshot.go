@@ -0,0 +1,123 @@
+package visual
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// SnapshotDir is the directory, relative to the test's working directory
+// (the package directory under go test), where Snapshot keeps baselines.
+const SnapshotDir = "testdata/__snapshots__"
+
+// Default snapshot tolerances.
+const (
+	DefaultSnapshotThreshold      = 0.1
+	DefaultSnapshotMaxDiffPercent = 0.0
+)
+
+// Update makes Snapshot overwrite baselines instead of comparing against
+// them. Set it from a test flag, e.g. in a TestMain or package variable:
+//
+//	func init() { flag.BoolVar(&visual.Update, "update", false, "update snapshot baselines") }
+//
+// Baselines are also updated when the test binary already defines an
+// -update flag that is set, or when W3PILOT_UPDATE_SNAPSHOTS is true.
+var Update bool
+
+// UpdateEnv is the environment variable that, when true, updates baselines.
+const UpdateEnv = "W3PILOT_UPDATE_SNAPSHOTS"
+
+// updating reports whether baselines should be overwritten. The -update
+// flag is looked up rather than registered, since the importing test
+// package may define its own and registering it twice panics.
+func updating() bool {
+	if Update {
+		return true
+	}
+	if v, err := strconv.ParseBool(os.Getenv(UpdateEnv)); err == nil && v {
+		return true
+	}
+	f := flag.Lookup("update")
+	if f == nil {
+		return false
+	}
+	v, _ := strconv.ParseBool(f.Value.String())
+	return v
+}
+
+// SnapshotOptions configures SnapshotWithOptions.
+type SnapshotOptions struct {
+	CompareOptions
+
+	// MaxDiffPercent is the percentage of changed pixels tolerated before
+	// the test fails.
+	MaxDiffPercent float64
+}
+
+// Snapshot compares a PNG screenshot against the baseline stored at
+// testdata/__snapshots__/<name>.png using default tolerances.
+//
+// On the first run, or when updating (see Update), the screenshot is
+// written as the new baseline. Otherwise the test fails if it
+// differs from the baseline, and <name>.actual.png and <name>.diff.png are
+// written next to the baseline for inspection.
+func Snapshot(t testing.TB, name string, data []byte) {
+	t.Helper()
+	SnapshotWithOptions(t, name, data, SnapshotOptions{
+		CompareOptions: CompareOptions{Threshold: DefaultSnapshotThreshold},
+		MaxDiffPercent: DefaultSnapshotMaxDiffPercent,
+	})
+}
+
+// SnapshotWithOptions is like Snapshot with custom tolerances and ignored regions.
+func SnapshotWithOptions(t testing.TB, name string, data []byte, opts SnapshotOptions) {
+	t.Helper()
+
+	baselinePath := filepath.Join(SnapshotDir, name+".png")
+	actualPath := filepath.Join(SnapshotDir, name+".actual.png")
+	diffPath := filepath.Join(SnapshotDir, name+".diff.png")
+
+	baseline, err := os.ReadFile(baselinePath)
+	if errors.Is(err, os.ErrNotExist) || updating() {
+		if err := writeSnapshot(baselinePath, data); err != nil {
+			t.Fatalf("visual: failed to write baseline %s: %v", baselinePath, err)
+		}
+		_ = os.Remove(actualPath)
+		_ = os.Remove(diffPath)
+		t.Logf("visual: wrote baseline %s", baselinePath)
+		return
+	}
+	if err != nil {
+		t.Fatalf("visual: failed to read baseline %s: %v", baselinePath, err)
+	}
+
+	opts.DiffImage = true
+	result, err := Compare(baseline, data, opts.CompareOptions)
+	if err != nil {
+		_ = writeSnapshot(actualPath, data)
+		t.Fatalf("visual: snapshot %s: %v (actual saved to %s; rerun with %s=1 to accept)", name, err, actualPath, UpdateEnv)
+	}
+
+	if result.Match(opts.MaxDiffPercent) {
+		_ = os.Remove(actualPath)
+		_ = os.Remove(diffPath)
+		return
+	}
+
+	_ = writeSnapshot(actualPath, data)
+	_ = writeSnapshot(diffPath, result.DiffImage)
+	t.Errorf("visual: snapshot %s differs by %.2f%% (%d of %d pixels, max %.2f%%); see %s and %s, rerun with %s=1 to accept",
+		name, result.DiffPercent, result.DiffPixels, result.ComparedPixels, opts.MaxDiffPercent, actualPath, diffPath, UpdateEnv)
+}
+
+func writeSnapshot(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create snapshot directory: %w", err)
+	}
+	return os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,105 @@
+package visual
+
+import (
+	"flag"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// A golden-file -update flag in the importing package must not clash with
+// the visual package, which only looks the flag up.
+var _ = flag.Bool("update", false, "update golden files")
+
+// recordingTB captures failures so snapshot mismatches can be asserted.
+type recordingTB struct {
+	testing.TB
+	failed bool
+	msg    string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Logf(format string, args ...any) {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.failed = true
+	r.msg = fmt.Sprintf(format, args...)
+}
+
+func (r *recordingTB) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	white := encodePNG(t, solid(10, 10, color.White))
+	changed := solid(10, 10, color.White)
+	changed.Set(0, 0, color.Black)
+	changedPNG := encodePNG(t, changed)
+
+	// First run writes the baseline
+	tb := &recordingTB{TB: t}
+	Snapshot(tb, "home", white)
+	if tb.failed {
+		t.Fatalf("Unexpected failure: %s", tb.msg)
+	}
+	if _, err := os.Stat(filepath.Join(SnapshotDir, "home.png")); err != nil {
+		t.Fatalf("Expected baseline to be written: %v", err)
+	}
+
+	// Identical screenshot passes
+	tb = &recordingTB{TB: t}
+	Snapshot(tb, "home", white)
+	if tb.failed {
+		t.Fatalf("Unexpected failure: %s", tb.msg)
+	}
+
+	// Changed screenshot fails with the diff percentage and leaves artifacts
+	tb = &recordingTB{TB: t}
+	Snapshot(tb, "home", changedPNG)
+	if !tb.failed || !strings.Contains(tb.msg, "1.00%") {
+		t.Fatalf("Expected failure reporting 1.00%%, got %q", tb.msg)
+	}
+	for _, name := range []string{"home.actual.png", "home.diff.png"} {
+		if _, err := os.Stat(filepath.Join(SnapshotDir, name)); err != nil {
+			t.Errorf("Expected %s to be written: %v", name, err)
+		}
+	}
+
+	// Within tolerance passes and cleans up artifacts
+	tb = &recordingTB{TB: t}
+	SnapshotWithOptions(tb, "home", changedPNG, SnapshotOptions{MaxDiffPercent: 2})
+	if tb.failed {
+		t.Fatalf("Unexpected failure: %s", tb.msg)
+	}
+	if _, err := os.Stat(filepath.Join(SnapshotDir, "home.diff.png")); !os.IsNotExist(err) {
+		t.Errorf("Expected diff artifact to be removed, got %v", err)
+	}
+}
+
+func TestSnapshot_Update(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	white := encodePNG(t, solid(10, 10, color.White))
+	black := encodePNG(t, solid(10, 10, color.Black))
+
+	tb := &recordingTB{TB: t}
+	Snapshot(tb, "home", white)
+
+	t.Setenv(UpdateEnv, "1")
+	tb = &recordingTB{TB: t}
+	Snapshot(tb, "home", black)
+	if tb.failed {
+		t.Fatalf("Expected the baseline to be updated, got failure: %s", tb.msg)
+	}
+
+	baseline, err := os.ReadFile(filepath.Join(SnapshotDir, "home.png"))
+	if err != nil || string(baseline) != string(black) {
+		t.Errorf("Expected the new screenshot as baseline, err = %v", err)
+	}
+}
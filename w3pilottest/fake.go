@@ -0,0 +1,465 @@
+// Package w3pilottest provides test doubles for code built on w3pilot.
+//
+// FakePage implements w3pilot.Page without a browser. Program it with the
+// page state and elements your code expects, run the code under test, then
+// inspect the recorded calls and the resulting element state:
+//
+//	page := w3pilottest.NewFakePage()
+//	page.SetTitle("Dashboard")
+//	page.AddElement("#email", &w3pilottest.FakeElement{Tag: "input"})
+//
+//	err := LoginPage{page}.Login(ctx, "user@example.com")
+//
+//	if page.Element("#email").Value != "user@example.com" { ... }
+package w3pilottest
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	w3pilot "github.com/plexusone/w3pilot"
+)
+
+// fakeContext is the browsing context ID used for elements on a FakePage.
+const fakeContext = "fake-context"
+
+// Call is a recorded call on a FakePage or one of its elements.
+type Call struct {
+	// Method is the Page method name ("Go", "Find", ...) or, for element
+	// methods, "element." plus the command ("element.click", "element.fill").
+	Method string
+
+	// Selector is the selector the call targeted, if any.
+	Selector string
+
+	// Args holds the remaining arguments, e.g. "url" for Go, "script" for
+	// Evaluate, "value" for element.fill, and "text" for element.type.
+	Args map[string]interface{}
+}
+
+// FakeElement is the programmable state of an element on a FakePage.
+// Element actions update it: Fill sets Value, Type appends to it, Clear
+// empties it, and Check/Uncheck set Checked.
+type FakeElement struct {
+	Tag        string
+	Text       string
+	Value      string
+	Hidden     bool
+	Disabled   bool
+	Checked    bool
+	Attributes map[string]string
+}
+
+// FakePage is an in-memory w3pilot.Page that records calls and returns
+// programmed responses. Elements it returns are real *w3pilot.Element
+// values whose commands are answered from the matching FakeElement.
+// It is safe for concurrent use.
+type FakePage struct {
+	mu          sync.Mutex
+	url         string
+	title       string
+	content     string
+	screenshot  []byte
+	history     []string
+	historyPos  int
+	evalResults map[string]interface{}
+	errs        map[string]error
+	elements    map[string][]*FakeElement
+	calls       []Call
+
+	client *w3pilot.BiDiClient
+}
+
+var _ w3pilot.Page = (*FakePage)(nil)
+
+// NewFakePage returns an empty FakePage at about:blank.
+func NewFakePage() *FakePage {
+	f := &FakePage{
+		url:         "about:blank",
+		history:     []string{"about:blank"},
+		evalResults: make(map[string]interface{}),
+		errs:        make(map[string]error),
+		elements:    make(map[string][]*FakeElement),
+	}
+	f.client = w3pilot.NewBiDiClient(&fakeTransport{page: f})
+	return f
+}
+
+// SetURL sets the current URL without recording a navigation.
+func (f *FakePage) SetURL(url string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.url = url
+}
+
+// SetTitle sets the value returned by Title.
+func (f *FakePage) SetTitle(title string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.title = title
+}
+
+// SetContent sets the value returned by Content.
+func (f *FakePage) SetContent(html string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.content = html
+}
+
+// SetScreenshot sets the bytes returned by Screenshot.
+func (f *FakePage) SetScreenshot(data []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.screenshot = data
+}
+
+// SetEvalResult sets the value Evaluate returns for script. Unknown
+// scripts evaluate to nil.
+func (f *FakePage) SetEvalResult(script string, result interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.evalResults[script] = result
+}
+
+// SetError makes the named method fail with err. The name is the Call
+// method name, e.g. "Go" or "element.click". Pass nil to clear it.
+func (f *FakePage) SetError(method string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err == nil {
+		delete(f.errs, method)
+		return
+	}
+	f.errs[method] = err
+}
+
+// AddElement adds an element matching selector and returns it. Adding
+// several elements for one selector makes FindAll return all of them;
+// element commands act on the first.
+func (f *FakePage) AddElement(selector string, el *FakeElement) *FakeElement {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.elements[selector] = append(f.elements[selector], el)
+	return el
+}
+
+// RemoveElement removes every element matching selector.
+func (f *FakePage) RemoveElement(selector string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.elements, selector)
+}
+
+// Element returns the first element added for selector, or nil.
+func (f *FakePage) Element(selector string) *FakeElement {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if els := f.elements[selector]; len(els) > 0 {
+		return els[0]
+	}
+	return nil
+}
+
+// Calls returns every recorded call in order.
+func (f *FakePage) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	calls := make([]Call, len(f.calls))
+	copy(calls, f.calls)
+	return calls
+}
+
+// CallsTo returns the recorded calls with the given method name.
+func (f *FakePage) CallsTo(method string) []Call {
+	var calls []Call
+	for _, c := range f.Calls() {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// record appends a call and returns the error programmed for it.
+// The caller must hold f.mu.
+func (f *FakePage) record(method, selector string, args map[string]interface{}) error {
+	f.calls = append(f.calls, Call{Method: method, Selector: selector, Args: args})
+	return f.errs[method]
+}
+
+// Go records the navigation and makes url current.
+func (f *FakePage) Go(ctx context.Context, url string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("Go", "", map[string]interface{}{"url": url}); err != nil {
+		return err
+	}
+	f.history = append(f.history[:f.historyPos+1], url)
+	f.historyPos = len(f.history) - 1
+	f.url = url
+	return nil
+}
+
+// Back moves back in the navigation history, if possible.
+func (f *FakePage) Back(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("Back", "", nil); err != nil {
+		return err
+	}
+	if f.historyPos > 0 {
+		f.historyPos--
+		f.url = f.history[f.historyPos]
+	}
+	return nil
+}
+
+// Forward moves forward in the navigation history, if possible.
+func (f *FakePage) Forward(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("Forward", "", nil); err != nil {
+		return err
+	}
+	if f.historyPos < len(f.history)-1 {
+		f.historyPos++
+		f.url = f.history[f.historyPos]
+	}
+	return nil
+}
+
+// Reload records the reload.
+func (f *FakePage) Reload(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.record("Reload", "", nil)
+}
+
+// URL returns the current URL.
+func (f *FakePage) URL(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("URL", "", nil); err != nil {
+		return "", err
+	}
+	return f.url, nil
+}
+
+// Title returns the programmed title.
+func (f *FakePage) Title(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("Title", "", nil); err != nil {
+		return "", err
+	}
+	return f.title, nil
+}
+
+// Content returns the programmed HTML.
+func (f *FakePage) Content(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("Content", "", nil); err != nil {
+		return "", err
+	}
+	return f.content, nil
+}
+
+// Screenshot returns the programmed screenshot bytes.
+func (f *FakePage) Screenshot(ctx context.Context) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("Screenshot", "", nil); err != nil {
+		return nil, err
+	}
+	return f.screenshot, nil
+}
+
+// Evaluate returns the result programmed for script, or nil.
+func (f *FakePage) Evaluate(ctx context.Context, script string) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("Evaluate", "", map[string]interface{}{"script": script}); err != nil {
+		return nil, err
+	}
+	return f.evalResults[script], nil
+}
+
+// Find returns the first element added for selector, or an
+// *w3pilot.ElementNotFoundError. opts is ignored.
+func (f *FakePage) Find(ctx context.Context, selector string, opts *w3pilot.FindOptions) (*w3pilot.Element, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("Find", selector, nil); err != nil {
+		return nil, err
+	}
+	els := f.elements[selector]
+	if len(els) == 0 {
+		return nil, &w3pilot.ElementNotFoundError{Selector: selector}
+	}
+	return f.newElement(selector, els[0]), nil
+}
+
+// FindAll returns every element added for selector. opts is ignored.
+func (f *FakePage) FindAll(ctx context.Context, selector string, opts *w3pilot.FindOptions) ([]*w3pilot.Element, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("FindAll", selector, nil); err != nil {
+		return nil, err
+	}
+	els := make([]*w3pilot.Element, 0, len(f.elements[selector]))
+	for _, el := range f.elements[selector] {
+		els = append(els, f.newElement(selector, el))
+	}
+	return els, nil
+}
+
+// WaitForSelector succeeds immediately if the element is already in the
+// requested state ("visible" by default, "attached", "hidden", or
+// "detached") and otherwise fails with a *w3pilot.TimeoutError; it never
+// waits. Like the real method, it returns nil for hidden and detached.
+func (f *FakePage) WaitForSelector(ctx context.Context, selector, state string, timeout time.Duration) (*w3pilot.Element, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("WaitForSelector", selector, map[string]interface{}{"state": state}); err != nil {
+		return nil, err
+	}
+
+	var el *FakeElement
+	if els := f.elements[selector]; len(els) > 0 {
+		el = els[0]
+	}
+
+	var ok bool
+	switch state {
+	case "attached":
+		ok = el != nil
+	case "hidden":
+		ok = el == nil || el.Hidden
+	case "detached":
+		ok = el == nil
+	default:
+		ok = el != nil && !el.Hidden
+	}
+	if !ok {
+		return nil, &w3pilot.TimeoutError{Selector: selector, Timeout: timeout.Milliseconds(), Reason: "fake element not in state " + state}
+	}
+	if el == nil || state == "hidden" {
+		return nil, nil
+	}
+	return f.newElement(selector, el), nil
+}
+
+// WaitForURL succeeds if the current URL contains pattern and otherwise
+// fails with a *w3pilot.TimeoutError; it never waits.
+func (f *FakePage) WaitForURL(ctx context.Context, pattern string, timeout time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("WaitForURL", "", map[string]interface{}{"pattern": pattern}); err != nil {
+		return err
+	}
+	if !strings.Contains(f.url, pattern) {
+		return &w3pilot.TimeoutError{Selector: pattern, Timeout: timeout.Milliseconds(), Reason: "current URL is " + f.url}
+	}
+	return nil
+}
+
+// WaitForLoad records the call and succeeds.
+func (f *FakePage) WaitForLoad(ctx context.Context, state string, timeout time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.record("WaitForLoad", "", map[string]interface{}{"state": state})
+}
+
+// newElement wraps el in a *w3pilot.Element backed by the fake transport.
+func (f *FakePage) newElement(selector string, el *FakeElement) *w3pilot.Element {
+	return w3pilot.NewElement(f.client, fakeContext, selector, w3pilot.ElementInfo{Tag: el.Tag, Text: el.Text})
+}
+
+// fakeTransport answers the element commands sent by elements on a
+// FakePage from their FakeElement state.
+type fakeTransport struct {
+	page *FakePage
+}
+
+func (t *fakeTransport) Send(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	f := t.page
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	args := make(map[string]interface{})
+	var selector string
+	if m, ok := params.(map[string]interface{}); ok {
+		for k, v := range m {
+			switch k {
+			case "context", "timeout":
+			case "selector":
+				selector, _ = v.(string)
+			default:
+				args[k] = v
+			}
+		}
+	}
+
+	name := strings.TrimPrefix(method, "vibium:")
+	if err := f.record(name, selector, args); err != nil {
+		return nil, err
+	}
+
+	els := f.elements[selector]
+	if len(els) == 0 {
+		return nil, &w3pilot.ElementNotFoundError{Selector: selector}
+	}
+	el := els[0]
+
+	var resp interface{} = map[string]interface{}{}
+	switch name {
+	case "element.text":
+		resp = map[string]interface{}{"text": el.Text}
+	case "element.value":
+		resp = map[string]interface{}{"value": el.Value}
+	case "element.attr":
+		attrName, _ := args["name"].(string)
+		if v, ok := el.Attributes[attrName]; ok {
+			resp = map[string]interface{}{"value": v}
+		} else {
+			resp = map[string]interface{}{"value": nil}
+		}
+	case "element.isVisible":
+		resp = map[string]interface{}{"visible": !el.Hidden}
+	case "element.isHidden":
+		resp = map[string]interface{}{"hidden": el.Hidden}
+	case "element.isEnabled":
+		resp = map[string]interface{}{"enabled": !el.Disabled}
+	case "element.isChecked":
+		resp = map[string]interface{}{"checked": el.Checked}
+	}
+
+	// Trials run the actionability checks only, so they change nothing
+	if args["trial"] == true {
+		return json.Marshal(resp)
+	}
+	switch name {
+	case "element.fill":
+		el.Value, _ = args["value"].(string)
+	case "element.type":
+		text, _ := args["text"].(string)
+		el.Value += text
+	case "element.clear":
+		el.Value = ""
+	case "element.check":
+		el.Checked = true
+	case "element.uncheck":
+		el.Checked = false
+	}
+	return json.Marshal(resp)
+}
+
+func (t *fakeTransport) OnEvent(method string, handler w3pilot.EventHandler) {}
+
+func (t *fakeTransport) RemoveEventHandlers(method string) {}
+
+func (t *fakeTransport) Close() error { return nil }
@@ -0,0 +1,109 @@
+package w3pilottest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	w3pilot "github.com/plexusone/w3pilot"
+)
+
+// loginPage is a page object of the kind FakePage is meant to test.
+type loginPage struct {
+	page w3pilot.Page
+}
+
+func (l loginPage) login(ctx context.Context, email string) (string, error) {
+	if err := l.page.Go(ctx, "https://example.com/login"); err != nil {
+		return "", err
+	}
+	el, err := l.page.Find(ctx, "#email", nil)
+	if err != nil {
+		return "", err
+	}
+	if err := el.Fill(ctx, email, nil); err != nil {
+		return "", err
+	}
+	btn, err := l.page.Find(ctx, "#submit", nil)
+	if err != nil {
+		return "", err
+	}
+	if err := btn.Click(ctx, nil); err != nil {
+		return "", err
+	}
+	return l.page.Title(ctx)
+}
+
+// TestFakePage_PageObject verifies that a page object can run against a
+// FakePage and that calls and element state are recorded.
+func TestFakePage_PageObject(t *testing.T) {
+	ctx := context.Background()
+	page := NewFakePage()
+	page.SetTitle("Dashboard")
+	page.AddElement("#email", &FakeElement{Tag: "input"})
+	page.AddElement("#submit", &FakeElement{Tag: "button", Text: "Log in"})
+
+	title, err := loginPage{page}.login(ctx, "user@example.com")
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	if title != "Dashboard" {
+		t.Errorf("Expected title Dashboard, got %q", title)
+	}
+	if got := page.Element("#email").Value; got != "user@example.com" {
+		t.Errorf("Expected email value to be filled, got %q", got)
+	}
+	if url, _ := page.URL(ctx); url != "https://example.com/login" {
+		t.Errorf("Expected current URL to be the login page, got %q", url)
+	}
+
+	clicks := page.CallsTo("element.click")
+	if len(clicks) != 1 || clicks[0].Selector != "#submit" {
+		t.Errorf("Expected one click on #submit, got %+v", clicks)
+	}
+}
+
+// TestFakePage_Errors verifies missing elements and programmed errors.
+func TestFakePage_Errors(t *testing.T) {
+	ctx := context.Background()
+	page := NewFakePage()
+
+	_, err := page.Find(ctx, "#missing", nil)
+	var notFound *w3pilot.ElementNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("Expected ElementNotFoundError, got %v", err)
+	}
+
+	page.AddElement("#save", &FakeElement{Tag: "button"})
+	boom := errors.New("intercepted")
+	page.SetError("element.click", boom)
+	el, err := page.Find(ctx, "#save", nil)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if err := el.Click(ctx, nil); !errors.Is(err, boom) {
+		t.Errorf("Expected programmed click error, got %v", err)
+	}
+
+	page.SetError("Go", boom)
+	if err := page.Go(ctx, "https://example.com"); !errors.Is(err, boom) {
+		t.Errorf("Expected programmed Go error, got %v", err)
+	}
+}
+
+// TestFakePage_WaitForSelector verifies the element states it checks.
+func TestFakePage_WaitForSelector(t *testing.T) {
+	ctx := context.Background()
+	page := NewFakePage()
+	page.AddElement("#toast", &FakeElement{Hidden: true})
+
+	if _, err := page.WaitForSelector(ctx, "#toast", "visible", 0); err == nil {
+		t.Error("Expected hidden element to fail a visible wait")
+	}
+	if _, err := page.WaitForSelector(ctx, "#toast", "attached", 0); err != nil {
+		t.Errorf("Expected attached wait to succeed, got %v", err)
+	}
+	if _, err := page.WaitForSelector(ctx, "#gone", "detached", 0); err != nil {
+		t.Errorf("Expected detached wait to succeed, got %v", err)
+	}
+}
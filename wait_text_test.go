@@ -0,0 +1,48 @@
+package w3pilot
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// TestWaitForTextWithOptions verifies that the text, scope, and match mode
+// are passed to the page function.
+func TestWaitForTextWithOptions(t *testing.T) {
+	mock := newMockTransport()
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	err := pilot.WaitForTextWithOptions(context.Background(), "Saved", &WaitForTextOptions{
+		Selector:  "#status",
+		TextMatch: "exact",
+	})
+	if err != nil {
+		t.Fatalf("WaitForTextWithOptions failed: %v", err)
+	}
+
+	if len(mock.calls) != 1 || mock.calls[0].Method != "vibium:page.waitForFunction" {
+		t.Fatalf("Expected one waitForFunction call, got %+v", mock.calls)
+	}
+	params := mock.calls[0].Params.(map[string]interface{})
+	fn, _ := params["fn"].(string)
+	for _, want := range []string{`"#status"`, `"Saved"`, `"exact"`} {
+		if !strings.Contains(fn, want) {
+			t.Errorf("Expected function to contain %s, got %s", want, fn)
+		}
+	}
+}
+
+// TestWaitForText_InvalidMatch verifies that unknown match modes are
+// rejected without contacting the browser.
+func TestWaitForText_InvalidMatch(t *testing.T) {
+	mock := newMockTransport()
+	pilot := &Pilot{client: NewBiDiClient(mock), browsingContext: "ctx-123"}
+
+	err := pilot.WaitForTextWithOptions(context.Background(), "Saved", &WaitForTextOptions{TextMatch: "fuzzy"})
+	if err == nil {
+		t.Fatal("Expected error for invalid match mode")
+	}
+	if len(mock.calls) != 0 {
+		t.Errorf("Expected no calls, got %+v", mock.calls)
+	}
+}
@@ -72,7 +72,7 @@ func (ws *WebSocketInfo) dispatchClose(code int, reason string) {
 
 // OnWebSocket registers a handler that is called when the page opens a WebSocket connection.
 func (p *Pilot) OnWebSocket(ctx context.Context, handler WebSocketHandler) error {
-	if p.closed {
+	if p.closed.Load() {
 		return ErrConnectionClosed
 	}
 
@@ -37,7 +37,7 @@ type LoginResult struct {
 // Login performs an automated login workflow.
 // It fills the username and password fields, submits the form, and waits for success.
 func (p *Pilot) Login(ctx context.Context, opts *LoginOptions) (*LoginResult, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return nil, ErrConnectionClosed
 	}
 
@@ -50,7 +50,10 @@ func (p *Pilot) Login(ctx context.Context, opts *LoginOptions) (*LoginResult, er
 		timeout = 30 * time.Second
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel, timeout, err := withTimeout(ctx, timeout)
+	if err != nil {
+		return nil, err
+	}
 	defer cancel()
 
 	result := &LoginResult{}
@@ -86,6 +89,15 @@ func (p *Pilot) Login(ctx context.Context, opts *LoginOptions) (*LoginResult, er
 		return result, nil
 	}
 
+	// Start watching before the click so a fast navigation isn't missed
+	navStart := time.Now()
+	if opts.SuccessIndicator == "" {
+		if _, err := p.client.navigationWatcher(ctx); err != nil {
+			result.ErrorReason = fmt.Sprintf("failed to watch navigation: %v", err)
+			return result, nil
+		}
+	}
+
 	if err := submitEl.Click(ctx, nil); err != nil {
 		result.ErrorReason = fmt.Sprintf("failed to click submit: %v", err)
 		return result, nil
@@ -112,7 +124,7 @@ func (p *Pilot) Login(ctx context.Context, opts *LoginOptions) (*LoginResult, er
 		}
 	} else {
 		// Default: wait for navigation to complete
-		if err := p.WaitForNavigation(ctx, timeout); err != nil {
+		if err := p.waitForNavigationSince(ctx, timeout, navStart); err != nil {
 			result.ErrorReason = "navigation did not complete"
 			return result, nil
 		}
@@ -151,7 +163,7 @@ type TableResult struct {
 
 // ExtractTable extracts data from an HTML table into structured JSON.
 func (p *Pilot) ExtractTable(ctx context.Context, selector string, opts *ExtractTableOptions) (*TableResult, error) {
-	if p.closed {
+	if p.closed.Load() {
 		return nil, ErrConnectionClosed
 	}
 